@@ -7,7 +7,8 @@ import (
 
 // well known services
 const (
-	ServiceGQL = "gql"
+	ServiceGQL  = "gql"
+	ServiceREST = "rest"
 
 	ServiceSequenceProvider = "sequence"
 	ServiceScripting        = "scripting"
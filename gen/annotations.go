@@ -0,0 +1,336 @@
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AnnotationTarget is set of kinds of items annotation may be applied to
+type AnnotationTarget int
+
+const (
+	AnnotationTargetFile AnnotationTarget = 1 << iota
+	AnnotationTargetEntity
+	AnnotationTargetField
+	AnnotationTargetMethod
+	AnnotationTargetEnum
+	AnnotationTargetEnumField
+	AnnotationTargetUnion
+	AnnotationTargetInterface
+
+	// AnnotationTargetAny - annotation may be applied to anything
+	AnnotationTargetAny = AnnotationTargetFile | AnnotationTargetEntity | AnnotationTargetField | AnnotationTargetMethod |
+		AnnotationTargetEnum | AnnotationTargetEnumField | AnnotationTargetUnion | AnnotationTargetInterface
+)
+
+var annotationTargetNames = []struct {
+	target AnnotationTarget
+	name   string
+}{
+	{AnnotationTargetFile, "File"},
+	{AnnotationTargetEntity, "Entity"},
+	{AnnotationTargetField, "Field"},
+	{AnnotationTargetMethod, "Method"},
+	{AnnotationTargetEnum, "Enum"},
+	{AnnotationTargetEnumField, "EnumField"},
+	{AnnotationTargetUnion, "Union"},
+	{AnnotationTargetInterface, "Interface"},
+}
+
+func (t AnnotationTarget) String() string {
+	if t == AnnotationTargetAny {
+		return "Any"
+	}
+	var names []string
+	for _, tn := range annotationTargetNames {
+		if t&tn.target != 0 {
+			names = append(names, tn.name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// AnnotationTargetOf returns target for item given to Generator.CheckAnnotation (0 for unknown item)
+func AnnotationTargetOf(item interface{}) AnnotationTarget {
+	switch item.(type) {
+	case *File:
+		return AnnotationTargetFile
+	case *Entity:
+		return AnnotationTargetEntity
+	case *Field:
+		return AnnotationTargetField
+	case *Method:
+		return AnnotationTargetMethod
+	case *Enum:
+		return AnnotationTargetEnum
+	case *EnumField:
+		return AnnotationTargetEnumField
+	case *Union:
+		return AnnotationTargetUnion
+	case *Interface:
+		return AnnotationTargetInterface
+	}
+	return 0
+}
+
+// AnnotationTagType is type of annotation tag's value
+type AnnotationTagType int
+
+const (
+	// AnnotationTagAny - value of any type (or no value at all)
+	AnnotationTagAny AnnotationTagType = iota
+	AnnotationTagString
+	AnnotationTagNumber
+	// AnnotationTagBool - bool value; tag without value means true
+	AnnotationTagBool
+)
+
+func (t AnnotationTagType) String() string {
+	switch t {
+	case AnnotationTagString:
+		return "string"
+	case AnnotationTagNumber:
+		return "number"
+	case AnnotationTagBool:
+		return "bool"
+	}
+	return "any"
+}
+
+// AnnotationTagSchema describes tag of annotation
+type AnnotationTagSchema struct {
+	Name string
+	Type AnnotationTagType
+	// Default - value used when tag is not given (for documentation only)
+	Default interface{}
+	// Values - allowed values of string tag (any value if empty)
+	Values []string
+	Doc    string
+}
+
+// AnnotationSchema describes annotation: where it may be used and what tags it accepts
+type AnnotationSchema struct {
+	Name string
+	// Qualified - name may be followed by qualifier (e.g. 'go:json' for 'go')
+	Qualified bool
+	Targets   AnnotationTarget
+	Tags      []AnnotationTagSchema
+	// NameTag - tag that may be given as the first tag without value (e.g. $deprecated("use other") for reason)
+	NameTag string
+	// AnyTags - tags not listed in Tags are allowed too (e.g. names of types)
+	AnyTags bool
+	Doc     string
+	// Plugin - name of the plugin that described the annotation; it is set by Project (empty for standard annotations)
+	Plugin string
+}
+
+// AnnotationsDescriber may be implemented by Generator to describe annotations it accepts;
+//
+//	annotations are validated against schemas before CheckAnnotation is called
+//	(so CheckAnnotation may skip checking of targets and tags types);
+//	described annotations are known for suggestions, completion and annotations reference
+type AnnotationsDescriber interface {
+	AnnotationSchemas() []AnnotationSchema
+}
+
+// standardAnnotations - schemas of annotations processed by Package itself
+var standardAnnotations = []AnnotationSchema{
+	{
+		Name:    AnnotationFind,
+		Targets: AnnotationTargetEntity | AnnotationTargetField,
+		Tags: []AnnotationTagSchema{
+			{
+				Name: AnnFndFieldTag,
+				Type: AnnotationTagString,
+				Doc:  "field of searched type the input field is compared with (name of the input field by default)",
+			},
+			{
+				Name:    AnnFndTypeTag,
+				Type:    AnnotationTagString,
+				Default: AFTEqual,
+				Values: []string{
+					AFTEqual, AFTNotEqual, AFTGreaterThan, AFTGreaterThanOrEqual, AFTLessThan, AFTLessThanOrEqual,
+					AFTStartsWith, AFTContains, AFTStartsWithIgnoreCase, AFTContainsIgnoreCase, AFTIgnore,
+					AFTIsNull, AFTIsNotNull, AFTExists, AFTNotExists,
+				},
+				Doc: "comparison of the input field",
+			},
+		},
+		NameTag: AnnFndFieldTag,
+		AnyTags: true,
+		Doc:     "for type: makes it params of find query for listed types; for field: describes comparison of find param",
+	},
+	{
+		Name:    AnnotationDeprecated,
+		Targets: AnnotationTargetAny &^ AnnotationTargetFile,
+		Tags: []AnnotationTagSchema{
+			{Name: AnnDeprecatedReasonTag, Type: AnnotationTagString, Doc: "reason of deprecation"},
+		},
+		NameTag: AnnDeprecatedReasonTag,
+		AnyTags: true,
+		Doc:     "marks item as deprecated; deprecated type deprecates all its generated operations",
+	},
+	{
+		Name:    AnnotationRefPackage,
+		Targets: AnnotationTargetFile,
+		Tags: []AnnotationTagSchema{
+			{Name: ARFPackageName, Type: AnnotationTagString, Doc: "name of package"},
+			{Name: ARFPackageNames, Type: AnnotationTagString, Doc: "space delimited names of packages"},
+		},
+		AnyTags: true,
+		Doc:     "adds reference to engines of given packages (names may be given as tags without values)",
+	},
+	{
+		Name:    AnnotationEngineless,
+		Targets: AnnotationTargetFile,
+		AnyTags: true,
+		Doc:     "Engine is not generated for the package",
+	},
+	{
+		Name:      AnnotationGo,
+		Qualified: true,
+		Targets:   AnnotationTargetAny,
+		Tags: []AnnotationTagSchema{
+			{Name: AnnGoPackage, Type: AnnotationTagString, Doc: "package of external type"},
+			{Name: cgaNameTag, Type: AnnotationTagString, Doc: "Go name of type or field"},
+			{Name: codeGenAnnoSingletonEngineAttr, Type: AnnotationTagString, Doc: "name of Engine's field for singleton"},
+		},
+		AnyTags: true,
+		Doc:     "Go specific settings (e.g. package of external type or Go struct tags)",
+	},
+}
+
+// Validate checks that annotation may be applied to item and its tags match the schema
+func (s *AnnotationSchema) Validate(ann *Annotation, item interface{}) error {
+	if target := AnnotationTargetOf(item); target != 0 && s.Targets&target == 0 {
+		return fmt.Errorf("at %v: annotation %s can not be used for %s (may be used for: %s)", ann.Pos, ann.Name, target, s.Targets)
+	}
+	for i, tag := range ann.Values {
+		if i == 0 && s.NameTag != "" && tag.Value == nil {
+			continue
+		}
+		ts := s.Tag(tag.Key)
+		if ts == nil {
+			if s.AnyTags {
+				continue
+			}
+			return fmt.Errorf("at %v: unknown tag '%s' of annotation %s (known tags: %s)", tag.Pos, tag.Key, ann.Name, s.tagsNames())
+		}
+		if err := ts.validate(tag); err != nil {
+			return fmt.Errorf("at %v: annotation %s: %w", tag.Pos, ann.Name, err)
+		}
+	}
+	return nil
+}
+
+// Tag returns schema of tag with name or nil
+func (s *AnnotationSchema) Tag(name string) *AnnotationTagSchema {
+	for i := range s.Tags {
+		if s.Tags[i].Name == name {
+			return &s.Tags[i]
+		}
+	}
+	return nil
+}
+
+func (s *AnnotationSchema) tagsNames() string {
+	names := make([]string, len(s.Tags))
+	for i, t := range s.Tags {
+		names[i] = t.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// matches returns true if annotation with name is described by the schema
+func (s *AnnotationSchema) matches(name string) bool {
+	if name == s.Name {
+		return true
+	}
+	base, _, qualified := strings.Cut(name, ":")
+	return qualified && s.Qualified && base == s.Name
+}
+
+func (ts *AnnotationTagSchema) validate(tag *AnnotationTag) error {
+	v := tag.Value
+	switch ts.Type {
+	case AnnotationTagString:
+		if v == nil || v.String == nil {
+			return fmt.Errorf("tag '%s' should be a string", tag.Key)
+		}
+		if len(ts.Values) > 0 {
+			for _, val := range ts.Values {
+				if val == *v.String {
+					return nil
+				}
+			}
+			return fmt.Errorf("invalid value '%s' of tag '%s' (allowed: %s)", *v.String, tag.Key, strings.Join(ts.Values, ", "))
+		}
+	case AnnotationTagNumber:
+		if v == nil || v.Number == nil {
+			return fmt.Errorf("tag '%s' should be a number", tag.Key)
+		}
+	case AnnotationTagBool:
+		if v != nil && v.Bool == nil {
+			return fmt.Errorf("tag '%s' should be bool", tag.Key)
+		}
+	}
+	return nil
+}
+
+// AnnotationSchemas returns schemas of standard annotations and annotations described by project's generators
+// (see AnnotationsDescriber)
+func (p *Project) AnnotationSchemas() []AnnotationSchema {
+	return collectAnnotationSchemas(p.generators)
+}
+
+// AnnotationsReference returns schemas of standard annotations and annotations of all the registered plugins
+func AnnotationsReference() []AnnotationSchema {
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	generators := []Generator{&CodeGenerator{}}
+	for _, name := range names {
+		generators = append(generators, plugins[name]())
+	}
+	ret := collectAnnotationSchemas(generators)
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+func collectAnnotationSchemas(generators []Generator) []AnnotationSchema {
+	ret := append([]AnnotationSchema(nil), standardAnnotations...)
+	for _, g := range generators {
+		if ad, ok := g.(AnnotationsDescriber); ok {
+			for _, s := range ad.AnnotationSchemas() {
+				s.Plugin = g.Name()
+				ret = append(ret, s)
+			}
+		}
+	}
+	return ret
+}
+
+// checkAnnotationSchema validates annotation against schemas with its name;
+// returns true if there are such schemas (so annotation is known)
+func (desc *Package) checkAnnotationSchema(ann *Annotation, item interface{}) (described bool, err error) {
+	if desc.Project.annotationSchemas == nil {
+		desc.Project.annotationSchemas = desc.Project.AnnotationSchemas()
+	}
+	for i := range desc.Project.annotationSchemas {
+		s := &desc.Project.annotationSchemas[i]
+		if !s.matches(ann.Name) {
+			continue
+		}
+		// the same annotation may be described by several plugins (e.g. vue and react): it is enough to match one of them
+		if serr := s.Validate(ann, item); serr == nil {
+			return true, nil
+		} else if err == nil {
+			err = serr
+		}
+		described = true
+	}
+	return
+}
@@ -0,0 +1,170 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+// testAnnotation creates annotation with tags; tag value nil means tag without value
+func testAnnotation(name string, tags ...interface{}) *Annotation {
+	ann := &Annotation{Name: name}
+	for i := 0; i < len(tags); i += 2 {
+		if tags[i+1] == nil {
+			ann.Values = append(ann.Values, &AnnotationTag{Key: tags[i].(string)})
+		} else {
+			ann.SetTag(tags[i].(string), tags[i+1])
+		}
+	}
+	return ann
+}
+
+func TestAnnotationSchemaValidate(t *testing.T) {
+	schema := &AnnotationSchema{
+		Name:    "test",
+		Targets: AnnotationTargetEntity | AnnotationTargetField,
+		Tags: []AnnotationTagSchema{
+			{Name: "title", Type: AnnotationTagString},
+			{Name: "mode", Type: AnnotationTagString, Values: []string{"fast", "slow"}},
+			{Name: "flag", Type: AnnotationTagBool},
+			{Name: "size", Type: AnnotationTagNumber},
+			{Name: "value", Type: AnnotationTagAny},
+		},
+		NameTag: "title",
+	}
+	anyTags := *schema
+	anyTags.AnyTags = true
+	tests := []struct {
+		name   string
+		schema *AnnotationSchema
+		ann    *Annotation
+		item   interface{}
+		err    string
+	}{
+		{"no tags", schema, testAnnotation("test"), &Entity{}, ""},
+		{"field target", schema, testAnnotation("test"), &Field{}, ""},
+		{
+			"target mismatch", schema, testAnnotation("test"), &Method{},
+			"can not be used for Method (may be used for: Entity, Field)",
+		},
+		{"unknown item", schema, testAnnotation("test"), nil, ""},
+		{
+			"unknown tag", schema, testAnnotation("test", "foo", "x"), &Entity{},
+			"unknown tag 'foo' of annotation test (known tags: title, mode, flag, size, value)",
+		},
+		{"unknown tag with any tags", &anyTags, testAnnotation("test", "foo", "bar"), &Entity{}, ""},
+		{"known tag with any tags", &anyTags, testAnnotation("test", "flag", "yes"), &Entity{}, "tag 'flag' should be bool"},
+		{"bool without value", schema, testAnnotation("test", "flag", nil), &Entity{}, ""},
+		{"bool", schema, testAnnotation("test", "flag", false), &Entity{}, ""},
+		{"bool as string", schema, testAnnotation("test", "flag", "true"), &Entity{}, "annotation test: tag 'flag' should be bool"},
+		{"string", schema, testAnnotation("test", "title", "Order"), &Entity{}, ""},
+		{"string without value", schema, testAnnotation("test", "flag", nil, "title", nil), &Entity{}, "tag 'title' should be a string"},
+		{"string as bool", schema, testAnnotation("test", "title", true), &Entity{}, "tag 'title' should be a string"},
+		{"allowed value", schema, testAnnotation("test", "mode", "slow"), &Entity{}, ""},
+		{
+			"not allowed value", schema, testAnnotation("test", "mode", "medium"), &Entity{},
+			"invalid value 'medium' of tag 'mode' (allowed: fast, slow)",
+		},
+		{"number", schema, testAnnotation("test", "size", 10), &Entity{}, ""},
+		{"number as string", schema, testAnnotation("test", "size", "10"), &Entity{}, "tag 'size' should be a number"},
+		{"any", schema, testAnnotation("test", "value", "x", "value", nil), &Entity{}, ""},
+		{"name tag", schema, testAnnotation("test", "Order", nil, "flag", nil), &Entity{}, ""},
+		{"name tag is the first only", schema, testAnnotation("test", "flag", nil, "Order", nil), &Entity{}, "unknown tag 'Order'"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := tt.schema.Validate(tt.ann, tt.item)
+				if tt.err == "" {
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+				} else if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error containing %q expected; got %v", tt.err, err)
+				}
+			},
+		)
+	}
+}
+
+func TestAnnotationSchemaMatches(t *testing.T) {
+	plain := &AnnotationSchema{Name: "vue"}
+	qualified := &AnnotationSchema{Name: "vue", Qualified: true}
+	tests := []struct {
+		name      string
+		plain     bool
+		qualified bool
+	}{
+		{"vue", true, true},
+		{"vue:form", false, true},
+		{"vue-form", false, false},
+		{"vue-form:edit", false, false},
+		{"vu", false, false},
+	}
+	for _, tt := range tests {
+		if got := plain.matches(tt.name); got != tt.plain {
+			t.Errorf("matches(%s) of not qualified schema = %v", tt.name, got)
+		}
+		if got := qualified.matches(tt.name); got != tt.qualified {
+			t.Errorf("matches(%s) of qualified schema = %v", tt.name, got)
+		}
+	}
+}
+
+// TestAnnotationSchemasAcceptCheckedAnnotations checks that schemas accept tags that are accepted (or ignored)
+// by CheckAnnotation of plugins
+func TestAnnotationSchemasAcceptCheckedAnnotations(t *testing.T) {
+	src := `package shop;
+
+$deletable(ignore=false extra) $access(created legacy="yes") $gql(name="ShopOrder" extra=1)
+type Order {
+  orderID: int <id>;
+  title: string <$gql(skip=false extra) $readonly(set=false)>;
+  code: string <$mongo(name="code" sort)>;
+  qty: int <$sort(desc) $lookup(startsWith)>;
+}
+`
+	generateTest(t, src, GQLGeneratorName, mongoGeneratorName)
+}
+
+func TestAnnotationSchemasErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string
+	}{
+		{"target", "$readonly type Order {\n  orderID: int <id>;\n}", "annotation readonly can not be used for Entity"},
+		{"unknown tag", "type Order {\n  orderID: int <id $mongo(extra)>;\n}", "unknown tag 'extra' of annotation mongo"},
+		{"bool tag", "$gql(skip=\"yes\") type Order {\n  orderID: int <id>;\n}", "annotation gql: tag 'skip' should be bool"},
+		{"string tag", "type Order {\n  orderID: int <id $mongo(deleteMethod=true)>;\n}", "tag 'deleteMethod' should be a string"},
+		{"qualified", "type Order {\n  orderID: int <id $go:json(name=1)>;\n}", "annotation go:json: tag 'name' should be a string"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := generateTestError(t, "package shop;\n\n"+tt.src+"\n", GQLGeneratorName, mongoGeneratorName)
+				if !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error containing %q expected; got %v", tt.err, err)
+				}
+			},
+		)
+	}
+}
+
+// TestAnnotationsReference checks that every annotation of registered plugins is described
+func TestAnnotationsReference(t *testing.T) {
+	seen := map[string]bool{}
+	for _, s := range AnnotationsReference() {
+		if s.Targets == 0 || s.Doc == "" {
+			t.Errorf("annotation %s of plugin %s is not described: %+v", s.Name, s.Plugin, s)
+		}
+		seen[s.Name] = true
+	}
+	for _, name := range []string{
+		AnnotationFind, GQLAnnotation, mongoAnnotation, cronGeneratorAnnotation, resourceAnnotation, AnnQualBy,
+		nocacheAnnotation, JSONSchemaAnnotation,
+	} {
+		if !seen[name] {
+			t.Errorf("annotation %s is not in reference", name)
+		}
+	}
+}
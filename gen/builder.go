@@ -12,6 +12,8 @@ type Builder struct {
 	consts    map[string][]*jen.Statement
 	Functions *jen.Statement
 	Generator *jen.Statement
+	// hookStubs - file with stubs of hooks' methods; it is written by WriteToFiles only if it does not exist
+	hookStubs *jen.File
 }
 
 // AddConst adds statement to const section (const word will be added automatically);
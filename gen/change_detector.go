@@ -23,7 +23,7 @@ const (
 )
 
 func init() {
-	RegisterPlugin(&BitSetChangeDetectorGenerator{})
+	RegisterPluginFactory(func() Generator { return &BitSetChangeDetectorGenerator{} })
 }
 
 // BitSetChangeDetectorGenerator generates code for historic fields or whole entities (recording changes)
@@ -71,6 +71,18 @@ func (cdg *BitSetChangeDetectorGenerator) CheckAnnotation(
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (cdg *BitSetChangeDetectorGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    cdAnnotationChangeDetector,
+			Targets: AnnotationTargetEntity | AnnotationTargetField,
+			AnyTags: true,
+			Doc:     "records changes of the field (or of all the fields of type)",
+		},
+	}
+}
+
 // Prepare from Generator interface
 func (cdg *BitSetChangeDetectorGenerator) Prepare(desc *Package) error {
 	cdg.desc = desc
@@ -242,7 +254,7 @@ func (cdg *BitSetChangeDetectorGenerator) OnFieldHook(
 					code = jen.Id("changed").Op(":=").Add(vars.GetObject()).Dot(fieldName).Op("==").Nil().Op("||").Len(jen.Add(vars.GetObject()).Dot(fieldName)).Op("!=").Len(newVal).Line().
 						If(jen.Op("!").Id("changed")).Block(
 						jen.For(jen.Id("i").Op(":=").Lit(0), jen.Id("i").Op("<").Len(newVal), jen.Id("i").Op("++")).Block(
-							jen.If(jen.Add(vars.GetObject()).Dot(fieldName).Index(jen.Id("i")).Op("!=").Add(newVal).Index(jen.Id("i"))).Block(
+							jen.If(cdg.valuesDifferStmt(f.Type.Array, jen.Add(vars.GetObject()).Dot(fieldName).Index(jen.Id("i")), jen.Add(newVal).Index(jen.Id("i")))).Block(
 								jen.Id("changed").Op("=").True(),
 								jen.Break(),
 							),
@@ -250,10 +262,12 @@ func (cdg *BitSetChangeDetectorGenerator) OnFieldHook(
 					).Line().
 						If(jen.Id("changed")).Block(code)
 				} else {
+					old := jen.Add(vars.GetObject()).Dot(fieldName)
 					if f.FB(FeatGoKind, FCGPointer) {
-						cond = jen.Add(vars.GetObject()).Dot(fieldName).Op("==").Nil().Op("||").Op("*")
+						cond = jen.Add(vars.GetObject()).Dot(fieldName).Op("==").Nil().Op("||")
+						old = jen.Parens(jen.Op("*").Add(old))
 					}
-					code = jen.If(cond.Add(vars.GetObject()).Dot(fieldName).Op("!=").Add(newVal)).Block(code)
+					code = jen.If(cond.Add(cdg.valuesDifferStmt(f.Type, old, newVal))).Block(code)
 				}
 			}
 		case HookSetNull:
@@ -274,6 +288,19 @@ func (cdg *BitSetChangeDetectorGenerator) OnMethodHook(
 
 }
 
+// valuesDifferStmt returns condition that values old and new of type t are not equal
+// (Decimal and []byte values can not be compared with '!=')
+func (cdg *BitSetChangeDetectorGenerator) valuesDifferStmt(t *TypeRef, old, new jen.Code) *jen.Statement {
+	switch t.Type {
+	case TipDecimal:
+		return jen.Add(old).Dot("Cmp").Call(new).Op("!=").Lit(0)
+	case TipBytes:
+		return jen.Op("!").Qual("bytes", "Equal").Call(old, new)
+	default:
+		return jen.Add(old).Op("!=").Add(new)
+	}
+}
+
 func (cdg *BitSetChangeDetectorGenerator) getFieldChangedStmt(f *Field, obj *jen.Statement) *jen.Statement {
 	if f.FS(bscdFeatureKind, bcdfFieldBitConst) == "" {
 		return nil
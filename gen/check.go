@@ -0,0 +1,255 @@
+package gen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/participle"
+	"github.com/alecthomas/participle/lexer"
+)
+
+// DiagnosticSeverity is the severity of Diagnostic
+type DiagnosticSeverity string
+
+const (
+	SeverityError   DiagnosticSeverity = "error"
+	SeverityWarning DiagnosticSeverity = "warning"
+)
+
+// Diagnostic describes problem found while checking DSL files
+type Diagnostic struct {
+	Severity DiagnosticSeverity
+	// Pos is position in DSL file; may be empty if unknown
+	Pos     lexer.Position
+	Message string
+	// Suggestions contains possible replacements (e.g. for unknown annotations)
+	Suggestions []string
+}
+
+// diagnosticJSON is the JSON form of Diagnostic; position is omitted if unknown
+type diagnosticJSON struct {
+	Severity    DiagnosticSeverity  `json:"severity"`
+	Pos         *diagnosticPosition `json:"pos,omitempty"`
+	Message     string              `json:"message"`
+	Suggestions []string            `json:"suggestions,omitempty"`
+}
+
+type diagnosticPosition struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// maxAnnotationSuggestions is max number of suggestions for unknown annotation
+const maxAnnotationSuggestions = 3
+
+var (
+	diagnosticPosRE        = regexp.MustCompile(`at (\S+?):(\d+):(\d+):\s*`)
+	diagnosticSuggestionRE = regexp.MustCompile(`; did you mean (.+)\?$`)
+)
+
+// String returns diagnostic in the form "file:line:col: severity: message"
+func (d Diagnostic) String() string {
+	if d.Pos.Line > 0 {
+		return fmt.Sprintf("%v: %s: %s", d.Pos, d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+}
+
+// MarshalJSON encodes diagnostic with position in the form {"file", "line", "column"}
+func (d Diagnostic) MarshalJSON() ([]byte, error) {
+	ret := diagnosticJSON{Severity: d.Severity, Message: d.Message, Suggestions: d.Suggestions}
+	if d.Pos.Line > 0 {
+		ret.Pos = &diagnosticPosition{File: d.Pos.Filename, Line: d.Pos.Line, Column: d.Pos.Column}
+	}
+	return json.Marshal(ret)
+}
+
+// DiagnosticFromError converts error to Diagnostic extracting position (if any)
+func DiagnosticFromError(err error) Diagnostic {
+	var perr participle.Error
+	if errors.As(err, &perr) {
+		return Diagnostic{Severity: SeverityError, Pos: perr.Token().Pos, Message: perr.Message()}
+	}
+	var lerr *lexer.Error
+	if errors.As(err, &lerr) {
+		return Diagnostic{Severity: SeverityError, Pos: lerr.Tok.Pos, Message: lerr.Msg}
+	}
+	return newDiagnostic(SeverityError, err.Error())
+}
+
+// newDiagnostic creates Diagnostic from message in the form "at <pos>: <message>"; position may appear in
+// the middle of message (for wrapped errors), then the text before it is kept; wrapped errors may contain
+// several positions: the last (innermost) one is the most precise, and all of them are removed from message
+func newDiagnostic(severity DiagnosticSeverity, msg string) Diagnostic {
+	d := Diagnostic{Severity: severity, Message: msg}
+	if locs := diagnosticPosRE.FindAllStringSubmatchIndex(msg, -1); locs != nil {
+		loc := locs[len(locs)-1]
+		line, _ := strconv.Atoi(msg[loc[4]:loc[5]])
+		col, _ := strconv.Atoi(msg[loc[6]:loc[7]])
+		d.Pos = lexer.Position{Filename: msg[loc[2]:loc[3]], Line: line, Column: col}
+		d.Message = diagnosticPosRE.ReplaceAllString(msg, "")
+	}
+	if sm := diagnosticSuggestionRE.FindStringSubmatchIndex(d.Message); sm != nil {
+		d.Suggestions = strings.Split(d.Message[sm[2]:sm[3]], ", ")
+	}
+	return d
+}
+
+// CheckFiles parses files and checks them with given project initializer; all the errors and warnings found
+// are returned; files are not generated.
+//
+//	init should create Project for parsed files (with plugins and options); it is not called if files can not be parsed
+func CheckFiles(files []string, init func(files []*File) (*Project, error)) []Diagnostic {
+	_, ret := CheckSources(files, nil, init)
+	return ret
+}
+
+// CheckSources works like CheckFiles but takes content of files from sources (by absolute path) if it is there;
+// checked project is returned as well (nil if files can not be parsed or project can not be created)
+func CheckSources(
+	files []string,
+	sources map[string][]byte,
+	init func(files []*File) (*Project, error),
+) (*Project, []Diagnostic) {
+	parsed, err := ParseSources(files, sources)
+	if err != nil {
+		return nil, parseDiagnostics(files, sources, err)
+	}
+	proj, err := init(parsed)
+	if err != nil {
+		return nil, []Diagnostic{DiagnosticFromError(err)}
+	}
+	return proj, proj.Check()
+}
+
+// parseDiagnostics tries to parse files one by one to find as many parse errors as possible
+func parseDiagnostics(files []string, sources map[string][]byte, err error) (ret []Diagnostic) {
+	found := map[string]bool{}
+	add := func(err error) {
+		d := DiagnosticFromError(err)
+		if key := d.String(); !found[key] {
+			found[key] = true
+			ret = append(ret, d)
+		}
+	}
+	if len(files) > 1 {
+		for _, file := range files {
+			if _, ferr := ParseSources([]string{file}, sources); ferr != nil {
+				add(ferr)
+			}
+		}
+	}
+	if len(ret) == 0 {
+		add(err)
+	}
+	return
+}
+
+// Check runs all the stages before generation and returns all the errors and warnings found;
+// unlike Generate it does not stop on the first failed package and does not generate anything
+func (p *Project) Check() (ret []Diagnostic) {
+	defer p.closeGenerators()
+	defer func() {
+		if r := recover(); r != nil {
+			ret = append(p.Diagnostics(), Diagnostic{Severity: SeverityError, Message: fmt.Sprintf("internal error: %v", r)})
+		}
+	}()
+	_ = p.prepareStages(
+		func(pckg *Package, err error) {
+			p.AddError(fmt.Errorf("package %s: %w", pckg.Name, err))
+		},
+	)
+	return p.Diagnostics()
+}
+
+// Diagnostics returns errors and warnings of the project as Diagnostic list
+func (p *Project) Diagnostics() []Diagnostic {
+	ret := make([]Diagnostic, 0, len(p.Errors)+len(p.Warnings))
+	for _, err := range p.Errors {
+		ret = append(ret, DiagnosticFromError(err))
+	}
+	for _, w := range p.Warnings {
+		ret = append(ret, newDiagnostic(SeverityWarning, w))
+	}
+	return ret
+}
+
+// KnownAnnotations returns names of standard annotations and annotations described by project's generators
+// (see AnnotationsDescriber)
+func (p *Project) KnownAnnotations() []string {
+	known := map[string]bool{}
+	for _, s := range p.AnnotationSchemas() {
+		known[s.Name] = true
+	}
+	ret := make([]string, 0, len(known))
+	for name := range known {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// SuggestAnnotations returns known annotations names similar to the given one
+func (p *Project) SuggestAnnotations(name string) []string {
+	name, tag, _ := strings.Cut(name, ":")
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	maxDistance := len(name)/3 + 1
+	for _, known := range p.KnownAnnotations() {
+		if known == name {
+			continue
+		}
+		if d := editDistance(name, known); d <= maxDistance {
+			candidates = append(candidates, candidate{known, d})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	if len(candidates) > maxAnnotationSuggestions {
+		candidates = candidates[:maxAnnotationSuggestions]
+	}
+	ret := make([]string, len(candidates))
+	for i, c := range candidates {
+		ret[i] = c.name
+		if tag != "" {
+			ret[i] += ":" + tag
+		}
+	}
+	return ret
+}
+
+// editDistance returns Levenshtein distance between strings
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
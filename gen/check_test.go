@@ -0,0 +1,104 @@
+package gen
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const checkTestSource = `package shop;
+
+interface Named {
+  name: string;
+}
+
+type Order implements Named {
+  orderID: int <id>;
+  title: string <$gql(skip="yes")>;
+  qty: int <$mongo(bar=1)>;
+  price: int <$gqll(skip)>;
+}
+
+type Item {
+  itemID: int <id>;
+  name: string <$gql(name=1)>;
+}
+`
+
+// checkSources checks sources (by relative name) as vivgen check does
+func checkSources(t *testing.T, sources map[string]string) []Diagnostic {
+	t.Helper()
+	var files []string
+	abs := map[string][]byte{}
+	for name, src := range sources {
+		path, err := filepath.Abs(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, name)
+		abs[path] = []byte(src)
+	}
+	_, diagnostics := CheckSources(
+		files,
+		abs,
+		func(files []*File) (*Project, error) {
+			proj := New(files, Options(t.TempDir()).With(UnknownAnnotationWarning))
+			for _, pl := range []string{GQLGeneratorName, mongoGeneratorName} {
+				if err := proj.WithPlugin(pl, nil); err != nil {
+					return nil, err
+				}
+			}
+			return proj, nil
+		},
+	)
+	return diagnostics
+}
+
+func TestCheckText(t *testing.T) {
+	diagnostics := checkSources(t, map[string]string{"check.vvf": checkTestSource})
+	var lines []string
+	for _, d := range diagnostics {
+		lines = append(lines, d.String())
+	}
+	want := []string{
+		"check.vvf:9:23: error: package shop: annotation gql: tag 'skip' should be bool",
+		"check.vvf:10:20: error: package shop: unknown tag 'bar' of annotation mongo (known tags: name, ignore, encapsulate, bsonTag, deleteMethod, sort, customQueryGenerator, postProcessQuery, nameMutable)",
+		"check.vvf:16:22: error: package shop: annotation gql: tag 'name' should be a string",
+		"check.vvf:7:1: error: package shop: type Order does not implement Named: field name is missing",
+		"check.vvf:11:15: warning: unknown annotation: gqll; did you mean gql, call?",
+	}
+	if got := strings.Join(lines, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("unexpected diagnostics:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestCheckJSON(t *testing.T) {
+	diagnostics := checkSources(
+		t,
+		map[string]string{"check.vvf": "package shop;\n\ntype Order {\n  orderID: int <id>;\n  qty: int <$gqll(skip)>;\n}\n"},
+	)
+	diagnostics = append(diagnostics, Diagnostic{Severity: SeverityError, Message: "no position"})
+	data, err := json.Marshal(diagnostics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[` +
+		`{"severity":"warning","pos":{"file":"check.vvf","line":5,"column":13},` +
+		`"message":"unknown annotation: gqll; did you mean gql, call?","suggestions":["gql","call"]},` +
+		`{"severity":"error","message":"no position"}` +
+		`]`
+	if string(data) != want {
+		t.Errorf("unexpected JSON:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestNewDiagnosticInnermostPosition(t *testing.T) {
+	d := newDiagnostic(SeverityError, "package b: at b.vvf:8:3: at b.vvf:8:19: unknown tag 'x' of annotation gql")
+	if d.Pos.Filename != "b.vvf" || d.Pos.Line != 8 || d.Pos.Column != 19 {
+		t.Errorf("unexpected position: %v", d.Pos)
+	}
+	if d.Message != "package b: unknown tag 'x' of annotation gql" {
+		t.Errorf("unexpected message: %s", d.Message)
+	}
+}
@@ -134,6 +134,8 @@ type CodeGeneratorOptions struct {
 	GenerateBulkNew bool
 	// GenerateBulkSet - generate bulk set operations for every type (default false)
 	GenerateBulkSet bool
+	// GenerateHookStubs - generate file <file>_hooks.go with stubs of methods for Go hooks if it does not exist
+	GenerateHookStubs bool
 }
 
 // CodeGenerator generates Go code (structs, methods, Engine object  and other)
@@ -142,6 +144,8 @@ type CodeGenerator struct {
 	desc    *Package
 	b       *Builder
 	options CodeGeneratorOptions
+	// hookStubs - stubs of hooks' methods of the file being generated
+	hookStubs *jen.Statement
 	// scriptingRequired bool
 	// scriptingCreated  bool
 }
@@ -182,6 +186,8 @@ const (
 	scriptingEnginePackage       = "github.com/vc2402/vivard/scripting"
 	extendableTypeDescriptorType = "V_%sType"
 	extendedTypeTypeName         = "V_%s_%s"
+	polymorphicHolderTemplate    = "%sHolder"
+	polymorphicMarkerTemplate    = "is%s"
 
 	deletedFieldName    = "DeletedOn"
 	createdFieldName    = "CreatedOn"
@@ -276,6 +282,78 @@ func (cg *CodeGenerator) CheckAnnotation(desc *Package, ann *Annotation, item in
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (cg *CodeGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    codeGeneratorAnnotationTags,
+			Targets: AnnotationTargetField,
+			AnyTags: true,
+			Doc:     "Go struct tags of the field (e.g. $gotags(json=\"id\"))",
+		},
+		{
+			Name:    AnnotationDeletable,
+			Targets: AnnotationTargetEntity,
+			Tags: []AnnotationTagSchema{
+				{Name: deletableAnnotationWithField, Type: AnnotationTagAny, Doc: "name of Deleted field or false to not generate it"},
+				{Name: deletableAnnotationIgnore, Type: AnnotationTagBool, Default: false, Doc: "do not generate Delete operation"},
+			},
+			AnyTags: true,
+			Doc:     "generates Delete operation for type",
+		},
+		{
+			Name:    AnnotationCall,
+			Targets: AnnotationTargetMethod,
+			Tags: []AnnotationTagSchema{
+				{Name: AnnCallName, Type: AnnotationTagString, Doc: "name of function to call"},
+				{Name: AnnCallJS, Type: AnnotationTagBool, Default: false, Doc: "name is name of JS script"},
+			},
+			AnyTags: true,
+			Doc:     "function called by hook method",
+		},
+		{
+			Name:    AnnotationAccess,
+			Targets: AnnotationTargetEntity,
+			Tags: []AnnotationTagSchema{
+				{Name: accessAnnotationCreated, Type: AnnotationTagBool, Default: true, Doc: "log creation time"},
+				{Name: accessAnnotationModified, Type: AnnotationTagBool, Default: true, Doc: "log modification time"},
+				{Name: accessAnnotationUserID, Type: AnnotationTagBool, Default: false, Doc: "log id of user"},
+			},
+			AnyTags: true,
+			Doc:     "logs access to objects of type",
+		},
+		{
+			Name:    AnnotationBulk,
+			Targets: AnnotationTargetEntity,
+			Tags: []AnnotationTagSchema{
+				{Name: AnnotationBulkNew, Type: AnnotationTagBool, Doc: "generate bulk create operation"},
+				{Name: AnnotationBulkSet, Type: AnnotationTagBool, Doc: "generate bulk set operation"},
+				{
+					Name: AnnotationBulkFilter,
+					Type: AnnotationTagString,
+					Doc: fmt.Sprintf(
+						"comma separated filters of bulk operations: %s, %s, %s",
+						AnnotationBulkFilterRaw,
+						AnnotationBulkFilterAll,
+						AnnotationBulkFilterEach,
+					),
+				},
+			},
+			Doc: "bulk operations for type",
+		},
+		{
+			Name:    AnnotationReadonly,
+			Targets: AnnotationTargetField,
+			Tags: []AnnotationTagSchema{
+				{Name: "set", Type: AnnotationTagAny, Doc: "'false' makes field writable"},
+			},
+			NameTag: "set",
+			AnyTags: true,
+			Doc:     "field can not be set via API",
+		},
+	}
+}
+
 // Prepare from Generator interface
 func (cg *CodeGenerator) Prepare(desc *Package) error {
 	cg.desc = desc
@@ -352,6 +430,7 @@ func (cg *CodeGenerator) Prepare(desc *Package) error {
 func (cg *CodeGenerator) Generate(bldr *Builder) (err error) {
 	cg.desc = bldr.Descriptor
 	cg.b = bldr
+	cg.hookStubs = &jen.Statement{}
 	for _, e := range bldr.File.Enums {
 		err = cg.generateEnum(e)
 		if err != nil {
@@ -359,6 +438,20 @@ func (cg *CodeGenerator) Generate(bldr *Builder) (err error) {
 			return
 		}
 	}
+	for _, u := range bldr.File.Unions {
+		err = cg.generateUnion(u)
+		if err != nil {
+			err = fmt.Errorf("while generating union %s (%s): %w", u.Name, bldr.File.FileName, err)
+			return
+		}
+	}
+	for _, i := range bldr.File.Interfaces {
+		err = cg.generateInterfaceType(i)
+		if err != nil {
+			err = fmt.Errorf("while generating interface %s (%s): %w", i.Name, bldr.File.FileName, err)
+			return
+		}
+	}
 	for _, t := range bldr.File.Entries {
 		if t.HasModifier(TypeModifierExternal) {
 			continue
@@ -385,6 +478,7 @@ func (cg *CodeGenerator) Generate(bldr *Builder) (err error) {
 		}
 
 	}
+	cg.prepareHookStubs()
 	if cg.desc.Features.Bool(FeatGoKind, FCGScriptingRequired) &&
 		!cg.desc.Features.Bool(FeatGoKind, FCGScriptingCreated) {
 		cg.desc.Features.Set(FeatGoKind, FCGScriptingCreated, true)
@@ -523,13 +617,13 @@ func (cg *CodeGenerator) ProvideFeature(
 }
 
 func (cg *CodeGenerator) generateEnum(e *Enum) error {
-	cg.b.Types.Add(jen.Type().Id(e.Name).Add(cg.goType(&TypeRef{Type: e.AliasForType})).Line())
+	cg.b.Types.Add(goDoc(withDeprecation(e.Doc, e.Annotations)).Add(cg.b.SourcePos(e.Pos)).Type().Id(e.Name).Add(cg.goType(&TypeRef{Type: e.AliasForType})).Line())
 
 	if len(e.Fields) > 0 {
 		constSection := "const_" + e.Name
 		withIota := e.Fields[0].FloatVal == nil && e.Fields[0].IntVal == nil && e.Fields[0].StringVal == nil
 		for i, field := range e.Fields {
-			expr := jen.Id(field.Name)
+			expr := goDoc(withDeprecation(field.Doc, field.Annotations)).Add(cg.b.SourcePos(field.Pos)).Id(field.Name)
 
 			if withIota {
 				if i == 0 {
@@ -557,9 +651,122 @@ func (cg *CodeGenerator) generateEnum(e *Enum) error {
 	return nil
 }
 
+func (cg *CodeGenerator) generateUnion(u *Union) error {
+	var names []string
+	for _, t := range u.Types {
+		names = append(names, t.Name)
+	}
+	cg.b.Types.Add(
+		goDoc(withDeprecation(u.Doc, u.Annotations)).Commentf("%s may hold one of: %s", u.Name, strings.Join(names, ", ")).Line().
+			Type().Id(u.Name).Interface(jen.Id(polymorphicMarkerName(u.Name)).Params()).Line(),
+	)
+	for _, t := range u.Types {
+		cg.b.Functions.Add(
+			jen.Func().Parens(jen.Op("*").Id(t.FS(FeatGoKind, FCGName))).Id(polymorphicMarkerName(u.Name)).Params().Block().Line(),
+		)
+	}
+	cg.generatePolymorphicHolder(u.Name, u.Types)
+	return nil
+}
+
+func (cg *CodeGenerator) generateInterfaceType(i *Interface) error {
+	methods := []jen.Code{jen.Id(polymorphicMarkerName(i.Name)).Params()}
+	for _, f := range i.Fields {
+		methods = append(methods, goDoc(f.Doc).Id(cg.b.GetMethodName(f, CGGetterMethod)).Params().Add(cg.goType(f.Type)))
+	}
+	var names []string
+	for _, t := range i.Implementations {
+		names = append(names, t.Name)
+	}
+	cg.b.Types.Add(
+		goDoc(withDeprecation(i.Doc, i.Annotations)).Commentf("%s is implemented by: %s", i.Name, strings.Join(names, ", ")).Line().
+			Type().Id(i.Name).Interface(methods...).Line(),
+	)
+	for _, t := range i.Implementations {
+		cg.b.Functions.Add(
+			jen.Func().Parens(jen.Op("*").Id(t.FS(FeatGoKind, FCGName))).Id(polymorphicMarkerName(i.Name)).Params().Block().Line(),
+		)
+	}
+	cg.generatePolymorphicHolder(i.Name, i.Implementations)
+	return nil
+}
+
+// generatePolymorphicHolder generates struct for keeping union or interface value in fields;
+// json representation of the value contains type discriminator
+func (cg *CodeGenerator) generatePolymorphicHolder(name string, types []*Entity) {
+	holder := polymorphicHolderName(name)
+	cg.b.Types.Add(
+		jen.Commentf("%s keeps value of %s (use type switch on Value to get actual type)", holder, name).Line().
+			Type().Id(holder).Struct(jen.Id("Value").Id(name)).Line(),
+	)
+	cg.b.Functions.Add(
+		jen.Comment("TypeName returns name of the type of held value (discriminator)").Line().
+			Func().Parens(jen.Id("h").Id(holder)).Id("TypeName").Params().String().Block(
+			jen.Switch(jen.Id("h").Dot("Value").Assert(jen.Type())).BlockFunc(func(g *jen.Group) {
+				for _, t := range types {
+					g.Case(jen.Op("*").Id(t.FS(FeatGoKind, FCGName))).Block(jen.Return(jen.Lit(t.Name)))
+				}
+			}),
+			jen.Return(jen.Lit("")),
+		).Line(),
+		jen.Comment("NewValue creates empty value for type name (discriminator)").Line().
+			Func().Parens(jen.Id("h").Op("*").Id(holder)).Id("NewValue").Params(jen.Id("typeName").String()).Error().Block(
+			jen.Switch(jen.Id("typeName")).BlockFunc(func(g *jen.Group) {
+				for _, t := range types {
+					g.Case(jen.Lit(t.Name)).Block(
+						jen.Id("h").Dot("Value").Op("=").Op("&").Id(t.FS(FeatGoKind, FCGName)).Values(),
+					)
+				}
+				g.Case(jen.Lit("")).Block(jen.Id("h").Dot("Value").Op("=").Nil())
+				g.Default().Block(
+					jen.Return(jen.Qual("fmt", "Errorf").Params(jen.Lit(name+": unknown type: %s"), jen.Id("typeName"))),
+				)
+			}),
+			jen.Return(jen.Nil()),
+		).Line(),
+		jen.Func().Parens(jen.Id("h").Id(holder)).Id("MarshalJSON").Params().Parens(jen.List(jen.Index().Byte(), jen.Error())).Block(
+			jen.Id("fields").Op(":=").Map(jen.String()).Qual("encoding/json", "RawMessage").Values(),
+			jen.If(jen.Id("h").Dot("Value").Op("!=").Nil()).Block(
+				jen.List(jen.Id("data"), jen.Err()).Op(":=").Qual("encoding/json", "Marshal").Params(jen.Id("h").Dot("Value")),
+				returnIfErrValue(jen.Nil()),
+				jen.If(
+					jen.Err().Op("=").Qual("encoding/json", "Unmarshal").Params(jen.Id("data"), jen.Op("&").Id("fields")),
+					jen.Err().Op("!=").Nil(),
+				).Block(jen.Return(jen.Nil(), jen.Err())),
+			),
+			jen.List(jen.Id("fields").Index(jen.Lit(PolymorphicDiscriminator)), jen.Id("_")).Op("=").
+				Qual("encoding/json", "Marshal").Params(jen.Id("h").Dot("TypeName").Params()),
+			jen.Return(jen.Qual("encoding/json", "Marshal").Params(jen.Id("fields"))),
+		).Line(),
+		jen.Func().Parens(jen.Id("h").Op("*").Id(holder)).Id("UnmarshalJSON").Params(jen.Id("data").Index().Byte()).Error().Block(
+			jen.Var().Id("disc").Struct(
+				jen.Id("TypeName").String().Tag(map[string]string{"json": PolymorphicDiscriminator}),
+			),
+			jen.If(
+				jen.Err().Op(":=").Qual("encoding/json", "Unmarshal").Params(jen.Id("data"), jen.Op("&").Id("disc")),
+				jen.Err().Op("!=").Nil(),
+			).Block(jen.Return(jen.Err())),
+			jen.If(
+				jen.Err().Op(":=").Id("h").Dot("NewValue").Params(jen.Id("disc").Dot("TypeName")),
+				jen.Err().Op("!=").Nil().Op("||").Id("h").Dot("Value").Op("==").Nil(),
+			).Block(jen.Return(jen.Err())),
+			jen.Return(jen.Qual("encoding/json", "Unmarshal").Params(jen.Id("data"), jen.Id("h").Dot("Value"))),
+		).Line(),
+	)
+}
+
+func polymorphicHolderName(name string) string {
+	return fmt.Sprintf(polymorphicHolderTemplate, name)
+}
+
+func polymorphicMarkerName(name string) string {
+	return fmt.Sprintf(polymorphicMarkerTemplate, name)
+}
+
 func (cg *CodeGenerator) generateEntity(ent *Entity) error {
 	fields := jen.Statement{}
 	typeName := ent.Name
+	hooks := cg.typeHooks(ent)
 	if ent.HasModifier(TypeModifierSingleton) {
 		fields.Add(jen.Id(EngineVar).Op("*").Id("Engine"))
 	}
@@ -615,6 +822,7 @@ func (cg *CodeGenerator) generateEntity(ent *Entity) error {
 				}
 			}
 			if !ent.HasModifier(TypeModifierSingleton) && !ent.HasModifier(TypeModifierConfig) {
+				hooks = append(hooks, cg.fieldHooks(d, paramType)...)
 				tn := jen.Id("obj").Op("*").Id(typeName)
 				if ent.HasModifier(TypeModifierExtendable) {
 					tn = jen.Id("o").Id(ent.FS(FeatGoKind, FCGBaseTypeAccessorInterface))
@@ -842,7 +1050,7 @@ func (cg *CodeGenerator) generateEntity(ent *Entity) error {
 		if d.FB(FeatGoKind, FCGCalculated) {
 			continue
 		}
-		t := jen.Id(fieldName).Add(d.Features.Stmt(FeatGoKind, FCGAttrType))
+		t := goDoc(withDeprecation(d.Doc, d.Annotations)).Add(cg.b.SourcePos(d.Pos)).Id(fieldName).Add(d.Features.Stmt(FeatGoKind, FCGAttrType))
 		if d.Tags != nil {
 			t = t.Tag(d.Tags)
 		}
@@ -865,7 +1073,7 @@ func (cg *CodeGenerator) generateEntity(ent *Entity) error {
 				setter.Add(jen.Op("&"))
 			}
 			if pointer || d.HasModifier(AttrModifierOneToMany) || d.HasModifier(AttrModifierEmbedded) || itsManyToMany || d.Type.Array != nil || d.Type.Map != nil {
-				nullFuncs = jen.Func().Parens(jen.Id("o").Op("*").Id(typeName)).Id(
+				nullFuncs = cg.b.SourcePos(d.Pos).Func().Parens(jen.Id("o").Op("*").Id(typeName)).Id(
 					cg.b.GetMethodName(
 						d,
 						CGIsNullMethod,
@@ -873,15 +1081,16 @@ func (cg *CodeGenerator) generateEntity(ent *Entity) error {
 				).Params().Bool().Block(
 					jen.Return(jen.Id("o").Dot(fieldName).Op("==").Nil()),
 				).Line().
-					Func().Parens(jen.Id("o").Op("*").Id(typeName)).Id(cg.b.GetMethodName(d, CGSetNullMethod)).Params().Block(
+					Add(cg.b.SourcePos(d.Pos)).Func().Parens(jen.Id("o").Op("*").Id(typeName)).Id(cg.b.GetMethodName(d, CGSetNullMethod)).Params().Block(
 					jen.Id("o").Dot(fieldName).Op("=").Nil(),
 				).
 					Line()
 			}
 			setter.Add(jen.Id("arg"))
 
+			deprecation := withDeprecation("", d.Annotations)
 			cg.b.Functions.Add(
-				jen.Func().Parens(jen.Id("o").Op("*").Id(typeName)).Id(
+				goDoc(deprecation).Add(cg.b.SourcePos(d.Pos)).Func().Parens(jen.Id("o").Op("*").Id(typeName)).Id(
 					cg.b.GetMethodName(
 						d,
 						CGSetterMethod,
@@ -891,7 +1100,7 @@ func (cg *CodeGenerator) generateEntity(ent *Entity) error {
 					setter,
 					cg.proj.OnHook(HookSet, HMExit, d, &GeneratorHookVars{Ctx: false, Obj: "o"}),
 				).Line(),
-				jen.Func().Parens(jen.Id("o").Op("*").Id(typeName)).Id(
+				goDoc(deprecation).Add(cg.b.SourcePos(d.Pos)).Func().Parens(jen.Id("o").Op("*").Id(typeName)).Id(
 					cg.b.GetMethodName(
 						d,
 						CGGetterMethod,
@@ -901,7 +1110,8 @@ func (cg *CodeGenerator) generateEntity(ent *Entity) error {
 			)
 		}
 	}
-	cg.b.Types.Add(jen.Type().Id(typeName).Struct(fields...).Line())
+	cg.b.Types.Add(goDoc(withDeprecation(ent.Doc, ent.Annotations)).Add(cg.b.SourcePos(ent.Pos)).Type().Id(typeName).Struct(fields...).Line())
+	cg.generateHooksInterface(ent, hooks)
 	if ent.HasModifier(TypeModifierExtendable) && ent.BaseTypeName == "" {
 		tn := ent.FS(FeatGoKind, FCGBaseTypeNameType)
 		cg.b.Types.Add(jen.Type().Id(tn).String()).Line()
@@ -1173,12 +1383,22 @@ func (b *Builder) addType(stmt *jen.Statement, ref *TypeRef, embedded ...bool) (
 		f = stmt.Int()
 	case TipBool:
 		f = stmt.Bool()
-	case TipDate:
+	case TipDate, TipDateTime:
 		f = stmt.Qual("time", "Time")
 	case TipFloat:
 		f = stmt.Float64()
 	case TipAny:
 		f = stmt.Any()
+	case TipDecimal:
+		f = stmt.Qual(VivardPackage, "Decimal")
+	case TipTime:
+		f = stmt.Qual(VivardPackage, "TimeOfDay")
+	case TipDuration:
+		f = stmt.Qual("time", "Duration")
+	case TipUUID:
+		f = stmt.Qual(VivardPackage, "UUID")
+	case TipBytes:
+		f = stmt.Index().Byte()
 	case TipAuto:
 		err = fmt.Errorf("'auto' type can be used only with annotation, changing it")
 		return
@@ -1192,6 +1412,13 @@ func (b *Builder) addType(stmt *jen.Statement, ref *TypeRef, embedded ...bool) (
 				} else {
 					f = stmt.Id(dt.enum.Name)
 				}
+			} else if dt.IsPolymorphic() {
+				f = stmt.Op("*")
+				if b.File.Package != dt.pckg {
+					f = f.Qual(dt.packagePath, polymorphicHolderName(dt.name))
+				} else {
+					f = f.Id(polymorphicHolderName(dt.name))
+				}
 			} else if dt.entry != nil &&
 				!ref.Embedded && (len(embedded) == 0 || !embedded[0]) &&
 				!dt.entry.HasModifier(TypeModifierEmbeddable) &&
@@ -1247,17 +1474,23 @@ func (b *Builder) checkIfEmptyValue(stmt *jen.Statement, ref *TypeRef, inverse b
 	case TipBool:
 		v = false
 
-	case TipAny:
-		f = stmt.Op("==").Nil()
+	case TipAny, TipBytes:
+		op := "=="
+		if inverse {
+			op = "!="
+		}
+		f = stmt.Op(op).Nil()
 		return
-	case TipDate:
-		f = stmt.Dot("Zero").Params()
+	case TipDate, TipDateTime, TipDecimal, TipUUID:
+		f = stmt.Dot("IsZero").Params()
 		if inverse {
 			f.Op("==").Lit(false)
 		}
 		return
 	case TipFloat:
 		v = 0.0
+	case TipTime, TipDuration:
+		v = 0
 	}
 	op := "=="
 	if inverse {
@@ -1279,14 +1512,22 @@ func (b *Builder) goEmptyValue(ref *TypeRef, idForRef ...bool) (f *jen.Statement
 		v = 0
 	case TipBool:
 		v = false
-	case TipDate:
+	case TipDate, TipDateTime:
 		f = jen.Qual("time", "Time").Values()
 		return
-	case TipAny:
+	case TipAny, TipBytes:
 		f = jen.Nil()
 		return
 	case TipFloat:
 		v = 0.0
+	case TipDecimal:
+		f = jen.Qual(VivardPackage, "Decimal").Values()
+		return
+	case TipUUID:
+		f = jen.Qual(VivardPackage, "UUID").Values()
+		return
+	case TipTime, TipDuration:
+		v = 0
 	default:
 		if dt, ok := b.Descriptor.FindType(ref.Type); ok {
 			if dt.enum != nil {
@@ -1411,12 +1652,22 @@ func (cg *CodeGenerator) goType(ref *TypeRef, embedded ...bool) (f *jen.Statemen
 		f = jen.Int()
 	case TipBool:
 		f = jen.Bool()
-	case TipDate:
+	case TipDate, TipDateTime:
 		f = jen.Qual("time", "Time")
 	case TipFloat:
 		f = jen.Float64()
 	case TipAny:
 		f = jen.Any()
+	case TipDecimal:
+		f = jen.Qual(VivardPackage, "Decimal")
+	case TipTime:
+		f = jen.Qual(VivardPackage, "TimeOfDay")
+	case TipDuration:
+		f = jen.Qual("time", "Duration")
+	case TipUUID:
+		f = jen.Qual(VivardPackage, "UUID")
+	case TipBytes:
+		f = jen.Index().Byte()
 	default:
 		f = &jen.Statement{}
 		if dt, ok := cg.desc.FindType(ref.Type); ok {
@@ -1426,6 +1677,13 @@ func (cg *CodeGenerator) goType(ref *TypeRef, embedded ...bool) (f *jen.Statemen
 				} else {
 					f = f.Id(dt.enum.Name)
 				}
+			} else if dt.IsPolymorphic() {
+				f = jen.Op("*")
+				if dt.pckg != cg.desc.Name {
+					f = f.Qual(dt.packagePath, polymorphicHolderName(dt.name))
+				} else {
+					f = f.Id(polymorphicHolderName(dt.name))
+				}
 			} else if dt.entry != nil && dt.entry.HasModifier(TypeModifierExternal) {
 				if cg.desc.fullPackage != dt.packagePath {
 					f = jen.Qual(dt.packagePath, dt.name)
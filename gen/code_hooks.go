@@ -0,0 +1,204 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const (
+	// hooksInterfaceSuffix - suffix of name of interface with hooks methods of type (e.g. 'ClientHooks')
+	hooksInterfaceSuffix = "Hooks"
+	// hookStubsFileSuffix - suffix of file with stubs of hooks (created only if it does not exist)
+	hookStubsFileSuffix = "_hooks.go"
+)
+
+// hookMethod describes method of entity's type that generated code calls for Go hook
+type hookMethod struct {
+	hook    string
+	name    string
+	withEng bool
+	// params - params after ctx and eng
+	params []jen.Code
+	// result - type of returned value (nil if only error or nothing is returned)
+	result jen.Code
+	// withErr - error is returned
+	withErr bool
+}
+
+// goHookName returns name of method that implements Go hook h (def is default name)
+// and whether engine is given to it; ok is false for not Go hooks
+func goHookName(h *Hook, def string) (name string, withEng bool, ok bool) {
+	if h.Spec != "" && h.Spec != HookGoPrefix {
+		return
+	}
+	name = def
+	if h.Value != "" {
+		name = h.Value
+	}
+	if strings.HasSuffix(name, WithoutEngSuffix) {
+		return strings.TrimSuffix(name, WithoutEngSuffix), false, true
+	}
+	return name, true, true
+}
+
+// typeHooks returns methods for Go hooks declared for entity ent itself
+func (cg *CodeGenerator) typeHooks(ent *Entity) (hooks []hookMethod) {
+	typeName := ent.Name
+	singleton := ent.HasModifier(TypeModifierSingleton)
+	for _, m := range ent.Modifiers {
+		h := m.Hook
+		if h == nil {
+			continue
+		}
+		hm := hookMethod{hook: h.Key}
+		var ok bool
+		switch h.Key {
+		case TypeHookChange, TypeHookChanged:
+			hm.params = []jen.Code{jen.Id("newValue").Op("*").Id(typeName)}
+			hm.withErr = true
+		case TypeHookDelete:
+			hm.withErr = true
+		case TypeHookCreate, TypeHookStart:
+			if !singleton {
+				continue
+			}
+		case TypeHookTime:
+			if !singleton || !cg.proj.hasGenerator(cronGeneratorPluginName) || h.Spec == HookJSPrefix {
+				continue
+			}
+			hm.name = cronSingletonDefaultFunctionName
+			if pref := strings.LastIndex(h.Value, cronSingletonFunctionPrefix); pref != -1 {
+				hm.name = strings.Trim(h.Value[(pref+len(cronSingletonFunctionPrefix)):], " \t")
+			}
+			hm.withEng = true
+			hm.result = jen.Interface()
+			hm.withErr = true
+			hooks = append(hooks, hm)
+			continue
+		default:
+			continue
+		}
+		hm.name, hm.withEng, ok = goHookName(h, cg.desc.GetHookName(h.Key, nil))
+		if ok {
+			hooks = append(hooks, hm)
+		}
+	}
+	return
+}
+
+// fieldHooks returns methods for Go hooks of field f that are called by complex accessors;
+// valueType is type of field's value used by accessors
+func (cg *CodeGenerator) fieldHooks(f *Field, valueType jen.Code) (hooks []hookMethod) {
+	if f.FB(FeatGoKind, FCGCalculated) {
+		if h, ok := f.HaveHook(AttrHookCalculate); ok {
+			hm := hookMethod{hook: h.Key, result: valueType, withErr: true}
+			if hm.name, hm.withEng, ok = goHookName(h, cg.desc.GetHookName(h.Key, f)); ok {
+				hooks = append(hooks, hm)
+			}
+		}
+	} else if h, ok := f.HaveHook(AttrHookSet); ok {
+		hm := hookMethod{hook: h.Key, params: []jen.Code{jen.Id("val").Op("*").Add(valueType)}}
+		if hm.name, hm.withEng, ok = goHookName(h, cg.desc.GetHookName(h.Key, f)); ok {
+			hooks = append(hooks, hm)
+		}
+	}
+	return
+}
+
+func (hm hookMethod) signature() *jen.Statement {
+	ret := jen.Id(hm.name).ParamsFunc(
+		func(g *jen.Group) {
+			g.Id("ctx").Qual("context", "Context")
+			if hm.withEng {
+				g.Id(EngineVar).Op("*").Id("Engine")
+			}
+			for _, p := range hm.params {
+				g.Add(p)
+			}
+		},
+	)
+	switch {
+	case hm.result != nil:
+		ret.Parens(jen.List(hm.result, jen.Error()))
+	case hm.withErr:
+		ret.Error()
+	}
+	return ret
+}
+
+// generateHooksInterface generates interface with methods that should be implemented for Go hooks of entity ent
+// and assertion that the type implements it, so missing or mistyped hook is reported at the declaration;
+// stubs of the methods are added to the file's stubs
+func (cg *CodeGenerator) generateHooksInterface(ent *Entity, hooks []hookMethod) {
+	if len(hooks) == 0 {
+		return
+	}
+	typeName := ent.Name
+	iname := typeName + hooksInterfaceSuffix
+	cg.b.Types.Add(
+		jen.Commentf("%s lists methods of %s that should be implemented for hooks declared for it", iname, typeName).Line().
+			Type().Id(iname).InterfaceFunc(
+			func(g *jen.Group) {
+				for _, hm := range hooks {
+					g.Commentf("%s - @%s hook", hm.name, hm.hook)
+					g.Add(hm.signature())
+				}
+			},
+		).Line().
+			Var().Id("_").Id(iname).Op("=").Parens(jen.Op("*").Id(typeName)).Parens(jen.Nil()).Line(),
+	)
+	for _, hm := range hooks {
+		cg.hookStubs.Add(
+			jen.Commentf("%s implements @%s hook of %s", hm.name, hm.hook, typeName).Line().
+				Func().Parens(jen.Id("o").Op("*").Id(typeName)).Add(hm.signature()).BlockFunc(
+				func(g *jen.Group) {
+					g.Comment("TODO: implement")
+					switch {
+					case hm.result != nil:
+						g.Var().Id("ret").Add(hm.result)
+						g.Return(
+							jen.Id("ret"),
+							jen.Qual("errors", "New").Params(jen.Lit(fmt.Sprintf("%s.%s is not implemented", typeName, hm.name))),
+						)
+					case hm.withErr:
+						g.Return(jen.Nil())
+					}
+				},
+			).Line(),
+		)
+	}
+}
+
+// prepareHookStubs prepares file with stubs of hooks' methods of the file if option GenerateHookStubs is set;
+// the file is written by WriteToFiles (see writeHookStubs)
+func (cg *CodeGenerator) prepareHookStubs() {
+	if !cg.options.GenerateHookStubs || len(*cg.hookStubs) == 0 {
+		return
+	}
+	f := jen.NewFile(cg.desc.Name)
+	f.HeaderComment(
+		fmt.Sprintf(
+			"Hooks declared in file %s; the file was created by vivgen and will not be overwritten.",
+			cg.b.File.FileName,
+		),
+	)
+	f.Add(cg.hookStubs)
+	cg.b.hookStubs = f
+}
+
+// writeHookStubs writes prepared stubs of hooks' methods of the file;
+// the file is written only if it does not exist, so it may be edited
+func (p *Project) writeHookStubs(bldr *Builder) error {
+	if bldr.hookStubs == nil {
+		return nil
+	}
+	name := filepath.Join(p.Options.OutputDir, bldr.Descriptor.Name, bldr.File.Name+hookStubsFileSuffix)
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		return err
+	}
+	return p.saveJenFile(bldr.hookStubs, name)
+}
@@ -0,0 +1,74 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const hooksTestSource = `package shop;
+
+@change
+type Order {
+  orderID: int <id>;
+  name: string;
+}
+`
+
+// TestHookStubs checks that stubs of hooks are written only with generated files and are not overwritten
+func TestHookStubs(t *testing.T) {
+	dir := t.TempDir()
+	project := func() *Project {
+		proj := testProjectAt(t, dir, "", hooksTestSource)
+		proj.Options.Custom[CodeGeneratorOptionsName].(map[string]any)["GenerateHookStubs"] = true
+		return proj
+	}
+	name := filepath.Join(dir, "shop", "test"+hookStubsFileSuffix)
+
+	if err := project().Generate(); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Fatalf("stubs are written by Generate: %v", err)
+	}
+
+	files, err := project().GenerateToMemory()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	assertContains(t, string(files[name]), "func (o *Order) OnChange(")
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Fatalf("stubs are written by GenerateToMemory: %v", err)
+	}
+
+	proj := project()
+	if err = proj.Generate(); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if err = proj.WriteToFiles(); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if data, err := os.ReadFile(name); err != nil || string(data) != string(files[name]) {
+		t.Fatalf("stubs are not written: %v\n%s", err, data)
+	}
+	edited := []byte("package shop\n")
+	if err = os.WriteFile(name, edited, 0644); err != nil {
+		t.Fatal(err)
+	}
+	proj = project()
+	if err = proj.Generate(); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if err = proj.WriteToFiles(); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if data, _ := os.ReadFile(name); string(data) != string(edited) {
+		t.Errorf("edited stubs are overwritten:\n%s", data)
+	}
+	if files, err = project().GenerateToMemory(); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if _, ok := files[name]; ok {
+		t.Error("existing stubs are generated")
+	}
+}
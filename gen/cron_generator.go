@@ -24,13 +24,18 @@ type CroneGenerator struct {
 }
 
 func init() {
-	RegisterPlugin(&CroneGenerator{})
+	RegisterPluginFactory(func() Generator { return &CroneGenerator{} })
 }
 
 func (cg *CroneGenerator) Name() string {
 	return cronGeneratorPluginName
 }
 
+// Requires from PluginsRequirer
+func (cg *CroneGenerator) Requires() []string {
+	return []string{serviceGeneratorName}
+}
+
 // CheckAnnotation checks that annotation may be utilized by CodeGeneration
 func (cg *CroneGenerator) CheckAnnotation(desc *Package, ann *Annotation, item interface{}) (bool, error) {
 	cg.desc = desc
@@ -40,6 +45,18 @@ func (cg *CroneGenerator) CheckAnnotation(desc *Package, ann *Annotation, item i
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (cg *CroneGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    cronGeneratorAnnotation,
+			Targets: AnnotationTargetAny,
+			AnyTags: true,
+			Doc:     "reserved for cron settings; schedule is set with @time hook of singleton",
+		},
+	}
+}
+
 // Prepare from Generator interface
 func (cg *CroneGenerator) Prepare(desc *Package) error {
 	cg.desc = desc
@@ -0,0 +1,19 @@
+package gen
+
+import "testing"
+
+// TestCronRequiresService checks that Service plugin providing CRON service is added for Cron plugin
+func TestCronRequiresService(t *testing.T) {
+	files := generateTest(t, `package jobs;
+
+@time = "0 * * * * -> Cleanup"
+singleton type Janitor {
+  runs: int;
+}
+`, cronGeneratorPluginName)
+	assertContains(
+		t, files["jobs/engine.go"],
+		`eng.srvCron.AddNamedFunc("0 * * * *", "jobs.Janitor:Cleanup"`,
+		"return eng.Janitor.Cleanup(ctx, eng)",
+	)
+}
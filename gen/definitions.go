@@ -2,7 +2,8 @@ package gen
 
 import "github.com/dave/jennifer/jen"
 
-// Generator interface can be used for creating custom generators
+// Generator interface can be used for creating custom generators;
+// Generator may implement io.Closer to release resources after generation (or check) is finished
 type Generator interface {
 	// Name returns the unique name of generator (plugin)
 	Name() string
@@ -31,6 +32,22 @@ type OptionsSetter interface {
 	SetOptions(options any) error
 }
 
+// PluginsRequirer may be implemented by Generator that can not work without other plugins;
+//
+//	required plugins are added to the project automatically (with default options) and run before the Generator
+type PluginsRequirer interface {
+	// Requires returns names of required plugins
+	Requires() []string
+}
+
+// PluginsFollower may be implemented by Generator that uses results of other plugins if they are present;
+//
+//	Prepare and Generate of the Generator are called after the ones of listed plugins
+type PluginsFollower interface {
+	// After returns names of plugins that should run before the Generator (absent ones are ignored)
+	After() []string
+}
+
 // ProvideFeatureResult special type for ProvideFeature return value
 type ProvideFeatureResult int
 
@@ -192,10 +209,25 @@ const (
 	TipDate   = "date"
 	TipAny    = "any"
 	TipAuto   = "auto"
+	// TipDateTime is explicit timestamp type (TipDate is kept as timestamp for compatibility)
+	TipDateTime = "datetime"
+	// TipDecimal is exact decimal number (vivard.Decimal)
+	TipDecimal = "decimal"
+	// TipTime is time of day (vivard.TimeOfDay)
+	TipTime = "time"
+	// TipDuration is time.Duration
+	TipDuration = "duration"
+	// TipUUID is vivard.UUID
+	TipUUID = "uuid"
+	// TipBytes is []byte
+	TipBytes = "bytes"
 
 	EngineVar = "eng"
 
 	ExtendableTypeDescriptorFieldName = "V_Type"
+
+	// PolymorphicDiscriminator - name of the field that keeps type name for stored values of unions and interfaces
+	PolymorphicDiscriminator = "_t"
 )
 
 type AttrModifier string
@@ -244,6 +276,14 @@ const (
 	AnnotationEngineless = "engineless"
 )
 
+const (
+	// AnnotationDeprecated marks type, field, method, enum or enum value as deprecated;
+	// deprecated type deprecates all its generated operations
+	AnnotationDeprecated = "deprecated"
+	// AnnDeprecatedReasonTag - reason of deprecation (may be just the nameless parameter of annotation)
+	AnnDeprecatedReasonTag = "reason"
+)
+
 const (
 	//AnnotationFind may be used for defining Find params definition
 	AnnotationFind = "find"
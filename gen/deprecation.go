@@ -0,0 +1,114 @@
+package gen
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+// DeprecationReason returns reason given in AnnotationDeprecated and true if annotations contain it;
+// reason may be empty
+func DeprecationReason(anns Annotations) (reason string, deprecated bool) {
+	a, ok := anns[AnnotationDeprecated]
+	if !ok {
+		return "", false
+	}
+	reason, _ = a.GetNameTag(AnnDeprecatedReasonTag)
+	return reason, true
+}
+
+// Deprecation returns reason of deprecation of the defined type and true if it is deprecated
+func (dt *DefinedType) Deprecation() (reason string, deprecated bool) {
+	switch {
+	case dt.entry != nil:
+		return DeprecationReason(dt.entry.Annotations)
+	case dt.enum != nil:
+		return DeprecationReason(dt.enum.Annotations)
+	case dt.union != nil:
+		return DeprecationReason(dt.union.Annotations)
+	case dt.iface != nil:
+		return DeprecationReason(dt.iface.Annotations)
+	}
+	return "", false
+}
+
+// withDeprecation appends Go style deprecation paragraph to doc
+func withDeprecation(doc string, anns Annotations) string {
+	reason, ok := DeprecationReason(anns)
+	if !ok {
+		return doc
+	}
+	if reason == "" {
+		reason = "do not use"
+	}
+	if doc != "" {
+		doc += "\n\n"
+	}
+	return doc + "Deprecated: " + reason
+}
+
+// checkDeprecatedReferences warns about declarations that are not deprecated but refer to deprecated types
+func (desc *Package) checkDeprecatedReferences(f *File) {
+	check := func(pos lexer.Position, who string, typeName string) {
+		if dt, ok := desc.FindType(typeName); ok {
+			if _, deprecated := dt.Deprecation(); deprecated {
+				desc.AddWarning(fmt.Sprintf("at %v: %s refers to deprecated type %s", pos, who, typeName))
+			}
+		}
+	}
+	checkRef := func(pos lexer.Position, who string, ref *TypeRef) {
+		for ref != nil && (ref.Array != nil || ref.Map != nil) {
+			if ref.Array != nil {
+				ref = ref.Array
+			} else {
+				ref = ref.Map.ValueType
+			}
+		}
+		if ref != nil && ref.Type != "" {
+			check(pos, who, ref.Type)
+		}
+	}
+	for _, e := range f.Entries {
+		if _, deprecated := DeprecationReason(e.Annotations); deprecated {
+			continue
+		}
+		if e.BaseTypeName != "" {
+			check(e.Pos, e.Name, e.BaseTypeName)
+		}
+		for _, in := range e.Implements {
+			check(e.Pos, e.Name, in)
+		}
+		for _, fld := range e.Fields {
+			if _, deprecated := DeprecationReason(fld.Annotations); !deprecated && fld.Type != nil {
+				checkRef(fld.Pos, e.Name+"."+fld.Name, fld.Type)
+			}
+		}
+		for _, m := range e.Methods {
+			if _, deprecated := DeprecationReason(m.Annotations); deprecated {
+				continue
+			}
+			for _, p := range m.Params {
+				checkRef(p.Pos, fmt.Sprintf("%s.%s(%s)", e.Name, m.Name, p.Name), p.Type)
+			}
+			if m.RetValue != nil {
+				checkRef(m.Pos, fmt.Sprintf("%s.%s()", e.Name, m.Name), m.RetValue)
+			}
+		}
+	}
+	for _, u := range f.Unions {
+		if _, deprecated := DeprecationReason(u.Annotations); deprecated {
+			continue
+		}
+		for _, tn := range u.TypeNames {
+			check(u.Pos, "union "+u.Name, tn)
+		}
+	}
+	for _, i := range f.Interfaces {
+		if _, deprecated := DeprecationReason(i.Annotations); deprecated {
+			continue
+		}
+		for _, fld := range i.Fields {
+			checkRef(fld.Pos, i.Name+"."+fld.Name, fld.Type)
+		}
+	}
+}
@@ -0,0 +1,614 @@
+package gen
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	DiagramGeneratorName = "Diagram"
+	diagramOptionsName   = "diagram"
+
+	DiagramFormatMermaid  = "mermaid"
+	DiagramFormatPlantUML = "plantuml"
+	DiagramFormatDOT      = "dot"
+
+	diagramDefaultDir  = "diagrams"
+	diagramDefaultName = "model"
+)
+
+// diagramFormatExtensions - extensions of diagram files by format
+var diagramFormatExtensions = map[string]string{
+	DiagramFormatMermaid:  ".mmd",
+	DiagramFormatPlantUML: ".puml",
+	DiagramFormatDOT:      ".dot",
+}
+
+// diagramTypeModifiers - modifiers of types shown as stereotypes
+var diagramTypeModifiers = []TypeModifier{
+	TypeModifierAbstract,
+	TypeModifierConfig,
+	TypeModifierDictionary,
+	TypeModifierEmbeddable,
+	TypeModifierExtendable,
+	TypeModifierExternal,
+	TypeModifierSingleton,
+	TypeModifierTransient,
+}
+
+type DiagramOptions struct {
+	// Formats - formats of diagrams: mermaid, plantuml, dot; mermaid by default
+	Formats []string
+	// OutputDir - directory for diagram files; <output>/diagrams by default
+	OutputDir string
+	// Name - name of the file (without extension) with the diagram of all shown packages; model by default
+	Name string
+	// Packages - packages that should be shown (all if empty); types of other packages referred by them are shown without fields
+	Packages []string
+	// PerPackage - write diagram <package>.<ext> for each shown package in addition to common diagram
+	PerPackage bool
+	// HideFields - show types without fields
+	HideFields bool
+}
+
+// DiagramGenerator writes entity-relationship diagrams of the project as Mermaid class diagram, PlantUML or Graphviz DOT.
+// Diagrams show types with their modifiers and fields, inheritance, implemented interfaces and union members,
+// references, one-to-many, embedded and object-ref relations
+type DiagramGenerator struct {
+	proj    *Project
+	options DiagramOptions
+	done    bool
+}
+
+type diagramRelation int
+
+const (
+	diagramExtends diagramRelation = iota
+	diagramImplements
+	diagramRef
+	diagramOneToMany
+	diagramEmbedded
+	diagramObjectRef
+)
+
+type diagram struct {
+	nodes []*diagramNode
+	// index - nodes by qualified name of type
+	index map[string]*diagramNode
+	edges []*diagramEdge
+}
+
+type diagramNode struct {
+	id   string
+	pckg string
+	name string
+	// kind - class, enum, interface or union
+	kind        string
+	stereotypes []string
+	fields      []string
+	// foreign - type of not shown package that is referred by shown one (it is drawn without fields)
+	foreign bool
+}
+
+type diagramEdge struct {
+	from     *diagramNode
+	to       *diagramNode
+	relation diagramRelation
+	label    string
+	many     bool
+}
+
+func init() {
+	RegisterPluginFactory(func() Generator { return &DiagramGenerator{} })
+}
+
+func (cg *DiagramGenerator) Name() string {
+	return DiagramGeneratorName
+}
+
+func (cg *DiagramGenerator) SetOptions(opts any) error {
+	return OptionsAnyToStruct(opts, &cg.options)
+}
+
+func (cg *DiagramGenerator) SetDescriptor(proj *Project) {
+	cg.proj = proj
+	if _, err := proj.Options.CustomToStruct(diagramOptionsName, &cg.options); err != nil {
+		proj.AddWarning(fmt.Sprintf("problem while setting custom options for %s: %v", diagramOptionsName, err))
+	}
+}
+
+func (cg *DiagramGenerator) CheckAnnotation(desc *Package, ann *Annotation, item interface{}) (bool, error) {
+	return false, nil
+}
+
+func (cg *DiagramGenerator) Prepare(desc *Package) error {
+	return nil
+}
+
+// Generate writes diagrams on the first call (all the packages are prepared at this moment)
+func (cg *DiagramGenerator) Generate(b *Builder) error {
+	if cg.done {
+		return nil
+	}
+	cg.done = true
+	formats := cg.options.Formats
+	if len(formats) == 0 {
+		formats = []string{DiagramFormatMermaid}
+	}
+	for _, format := range formats {
+		if _, ok := diagramFormatExtensions[format]; !ok {
+			return fmt.Errorf("diagram: unknown format: %s", format)
+		}
+	}
+	packages := cg.packages()
+	name := cg.options.Name
+	if name == "" {
+		name = diagramDefaultName
+	}
+	if err := cg.write(name, formats, cg.build(packages)); err != nil {
+		return err
+	}
+	if cg.options.PerPackage {
+		for _, pckg := range packages {
+			if err := cg.write(pckg.Name, formats, cg.build([]*Package{pckg})); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// packages returns packages that should be shown sorted by name
+func (cg *DiagramGenerator) packages() (ret []*Package) {
+	filter := map[string]bool{}
+	for _, p := range cg.options.Packages {
+		filter[p] = true
+	}
+	for name, pckg := range cg.proj.packages {
+		if len(filter) > 0 && !filter[name] || len(filter) == 0 && name == InternalPackageName {
+			continue
+		}
+		ret = append(ret, pckg)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return
+}
+
+func (cg *DiagramGenerator) write(name string, formats []string, d *diagram) error {
+	dir := cg.options.OutputDir
+	if dir == "" {
+		dir = filepath.Join(cg.proj.Options.OutputDir, diagramDefaultDir)
+	}
+	for _, format := range formats {
+		var text string
+		switch format {
+		case DiagramFormatMermaid:
+			text = d.mermaid()
+		case DiagramFormatPlantUML:
+			text = d.plantUML(name)
+		case DiagramFormatDOT:
+			text = d.dot(name)
+		}
+		if err := cg.proj.WriteFile(filepath.Join(dir, name+diagramFormatExtensions[format]), []byte(text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// build creates diagram of types of the packages and their relations
+func (cg *DiagramGenerator) build(packages []*Package) *diagram {
+	d := &diagram{index: map[string]*diagramNode{}}
+	var referenceables []*Entity
+	for _, pckg := range cg.proj.packages {
+		for _, file := range pckg.Files {
+			for _, e := range file.Entries {
+				if _, ok := e.Annotations[referenceableAnnotation]; ok {
+					referenceables = append(referenceables, e)
+				}
+			}
+		}
+	}
+	sort.Slice(
+		referenceables, func(i, j int) bool {
+			return referenceables[i].Pckg.Name+"."+referenceables[i].Name < referenceables[j].Pckg.Name+"."+referenceables[j].Name
+		},
+	)
+	for _, pckg := range packages {
+		for _, file := range pckg.Files {
+			for _, e := range file.Entries {
+				if dt, ok := pckg.FindType(e.Name); ok {
+					cg.addNode(d, dt, false)
+				}
+			}
+			for _, e := range file.Enums {
+				if dt, ok := pckg.FindType(e.Name); ok {
+					cg.addNode(d, dt, false)
+				}
+			}
+			for _, u := range file.Unions {
+				if dt, ok := pckg.FindType(u.Name); ok {
+					cg.addNode(d, dt, false)
+				}
+			}
+			for _, i := range file.Interfaces {
+				if dt, ok := pckg.FindType(i.Name); ok {
+					cg.addNode(d, dt, false)
+				}
+			}
+		}
+	}
+	for _, pckg := range packages {
+		for _, file := range pckg.Files {
+			for _, e := range file.Entries {
+				cg.addEntityEdges(d, pckg, e, referenceables)
+			}
+			for _, u := range file.Unions {
+				from := d.index[pckg.Name+"."+u.Name]
+				for _, t := range u.Types {
+					if to := cg.entityNode(d, t); to != nil {
+						d.edges = append(d.edges, &diagramEdge{from: to, to: from, relation: diagramImplements})
+					}
+				}
+			}
+		}
+	}
+	d.assignIDs()
+	return d
+}
+
+func (cg *DiagramGenerator) addEntityEdges(d *diagram, pckg *Package, e *Entity, referenceables []*Entity) {
+	from := d.index[pckg.Name+"."+e.Name]
+	if e.BaseTypeName != "" {
+		if to := cg.entityNode(d, e.GetBaseType()); to != nil {
+			d.edges = append(d.edges, &diagramEdge{from: from, to: to, relation: diagramExtends})
+		}
+	}
+	for _, name := range e.Implements {
+		if dt, ok := pckg.FindType(name); ok {
+			d.edges = append(d.edges, &diagramEdge{from: from, to: cg.addNode(d, dt, true), relation: diagramImplements})
+		}
+	}
+	for _, f := range e.Fields {
+		if _, ok := f.Annotations[objectRefAnnotation]; ok {
+			for _, r := range referenceables {
+				if to := cg.entityNode(d, r); to != nil {
+					d.edges = append(d.edges, &diagramEdge{from: from, to: to, relation: diagramObjectRef, label: f.Name})
+				}
+			}
+			continue
+		}
+		ref := f.Type
+		many := false
+		for ref.Array != nil || ref.Map != nil {
+			many = true
+			if ref.Array != nil {
+				ref = ref.Array
+			} else {
+				ref = ref.Map.ValueType
+			}
+		}
+		if IsPrimitiveType(ref.Type) {
+			continue
+		}
+		dt, ok := pckg.FindType(ref.Type)
+		if !ok || dt.enum != nil {
+			continue
+		}
+		edge := &diagramEdge{from: from, to: cg.addNode(d, dt, true), relation: diagramRef, label: f.Name, many: many}
+		switch {
+		case f.HasModifier(AttrModifierOneToMany):
+			edge.relation = diagramOneToMany
+		case f.HasModifier(AttrModifierEmbedded) || ref.Embedded || dt.entry != nil && dt.entry.HasModifier(TypeModifierEmbeddable):
+			edge.relation = diagramEmbedded
+		}
+		d.edges = append(d.edges, edge)
+	}
+}
+
+func (cg *DiagramGenerator) entityNode(d *diagram, e *Entity) *diagramNode {
+	if e == nil || e.Pckg == nil {
+		return nil
+	}
+	dt, ok := e.Pckg.FindType(e.Name)
+	if !ok {
+		return nil
+	}
+	return cg.addNode(d, dt, true)
+}
+
+// addNode returns node of the type creating it if required; foreign nodes are created for types of not shown packages
+func (cg *DiagramGenerator) addNode(d *diagram, dt *DefinedType, foreign bool) *diagramNode {
+	key := dt.pckg + "." + dt.name
+	if n, ok := d.index[key]; ok {
+		return n
+	}
+	n := &diagramNode{pckg: dt.pckg, name: dt.name, kind: "class", foreign: foreign}
+	switch {
+	case dt.entry != nil:
+		for _, mod := range diagramTypeModifiers {
+			if dt.entry.HasModifier(mod) {
+				n.stereotypes = append(n.stereotypes, string(mod))
+			}
+		}
+		if !foreign && !cg.options.HideFields {
+			for _, f := range dt.entry.Fields {
+				n.fields = append(n.fields, diagramField(f))
+			}
+		}
+	case dt.enum != nil:
+		n.kind = "enum"
+		if !foreign && !cg.options.HideFields {
+			for _, f := range dt.enum.Fields {
+				n.fields = append(n.fields, f.Name)
+			}
+		}
+	case dt.union != nil:
+		n.kind = "union"
+	case dt.iface != nil:
+		n.kind = "interface"
+		if !foreign && !cg.options.HideFields {
+			for _, f := range dt.iface.Fields {
+				n.fields = append(n.fields, diagramField(f))
+			}
+		}
+	}
+	d.nodes = append(d.nodes, n)
+	d.index[key] = n
+	return n
+}
+
+// assignIDs sets ids of nodes: name of the type if it is unique in the diagram or package_name otherwise
+func (d *diagram) assignIDs() {
+	count := map[string]int{}
+	for _, n := range d.nodes {
+		count[n.name]++
+	}
+	for _, n := range d.nodes {
+		n.id = n.name
+		if count[n.name] > 1 {
+			n.id = n.pckg + "_" + n.name
+		}
+	}
+}
+
+// packages returns names of packages of nodes in order of their appearance
+func (d *diagram) packages() (ret []string) {
+	seen := map[string]bool{}
+	for _, n := range d.nodes {
+		if !seen[n.pckg] {
+			seen[n.pckg] = true
+			ret = append(ret, n.pckg)
+		}
+	}
+	return
+}
+
+func (d *diagram) mermaid() string {
+	buf := &strings.Builder{}
+	buf.WriteString("%% Code generated by vivgen. DO NOT EDIT.\nclassDiagram\n")
+	for _, p := range d.packages() {
+		fmt.Fprintf(buf, "  namespace %s {\n", p)
+		for _, n := range d.nodes {
+			if n.pckg != p {
+				continue
+			}
+			fmt.Fprintf(buf, "    class %s", n.id)
+			if n.id != n.name {
+				fmt.Fprintf(buf, "[\"%s\"]", n.name)
+			}
+			stereotypes := n.allStereotypes()
+			if len(stereotypes) == 0 && len(n.fields) == 0 {
+				buf.WriteString("\n")
+				continue
+			}
+			buf.WriteString(" {\n")
+			if len(stereotypes) > 0 {
+				fmt.Fprintf(buf, "      <<%s>>\n", strings.Join(stereotypes, ", "))
+			}
+			for _, f := range n.fields {
+				fmt.Fprintf(buf, "      %s\n", f)
+			}
+			buf.WriteString("    }\n")
+		}
+		buf.WriteString("  }\n")
+	}
+	for _, e := range d.edges {
+		from, arrow, to := e.uml()
+		fmt.Fprintf(buf, "  %s %s %s", from, arrow, to)
+		if e.label != "" {
+			fmt.Fprintf(buf, " : %s", e.label)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+func (d *diagram) plantUML(name string) string {
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "' Code generated by vivgen. DO NOT EDIT.\n@startuml %s\n", name)
+	for _, p := range d.packages() {
+		fmt.Fprintf(buf, "package %s {\n", p)
+		for _, n := range d.nodes {
+			if n.pckg != p {
+				continue
+			}
+			kind := n.kind
+			if kind == "union" {
+				kind = "class"
+			}
+			if n.kind == "class" && n.hasStereotype(TypeModifierAbstract) {
+				kind = "abstract class"
+			}
+			fmt.Fprintf(buf, "  %s %s", kind, n.name)
+			if n.id != n.name {
+				fmt.Fprintf(buf, " as %s", n.id)
+			}
+			for _, s := range n.allStereotypes() {
+				if s != string(TypeModifierAbstract) && s != "enum" && s != "interface" {
+					fmt.Fprintf(buf, " <<%s>>", s)
+				}
+			}
+			if len(n.fields) == 0 {
+				buf.WriteString("\n")
+				continue
+			}
+			buf.WriteString(" {\n")
+			for _, f := range n.fields {
+				fmt.Fprintf(buf, "    %s\n", f)
+			}
+			buf.WriteString("  }\n")
+		}
+		buf.WriteString("}\n")
+	}
+	for _, e := range d.edges {
+		from, arrow, to := e.uml()
+		fmt.Fprintf(buf, "%s %s %s", from, arrow, to)
+		if e.label != "" {
+			fmt.Fprintf(buf, " : %s", e.label)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("@enduml\n")
+	return buf.String()
+}
+
+func (d *diagram) dot(name string) string {
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "// Code generated by vivgen. DO NOT EDIT.\ndigraph %q {\n", name)
+	buf.WriteString("  node [shape=record, fontname=\"Helvetica\", fontsize=10];\n")
+	buf.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n")
+	for _, p := range d.packages() {
+		fmt.Fprintf(buf, "  subgraph %q {\n    label=%q;\n", "cluster_"+p, p)
+		for _, n := range d.nodes {
+			if n.pckg != p {
+				continue
+			}
+			label := ""
+			for _, s := range n.allStereotypes() {
+				label += dotEscape("<<"+s+">>") + "\\n"
+			}
+			label += dotEscape(n.name)
+			if len(n.fields) > 0 {
+				label += "|"
+				for _, f := range n.fields {
+					label += dotEscape(f) + "\\l"
+				}
+			}
+			style := ""
+			if n.foreign {
+				style = ", style=dashed"
+			}
+			fmt.Fprintf(buf, "    %q [label=\"{%s}\"%s];\n", n.id, label, style)
+		}
+		buf.WriteString("  }\n")
+	}
+	for _, e := range d.edges {
+		var attrs []string
+		switch e.relation {
+		case diagramExtends:
+			attrs = append(attrs, "arrowhead=empty")
+		case diagramImplements:
+			attrs = append(attrs, "arrowhead=empty", "style=dashed")
+		case diagramOneToMany:
+			attrs = append(attrs, "taillabel=\"1\"", "headlabel=\"*\"")
+		case diagramEmbedded:
+			attrs = append(attrs, "dir=both", "arrowtail=diamond", "arrowhead=none")
+		case diagramObjectRef:
+			attrs = append(attrs, "style=dashed", "arrowhead=open")
+		}
+		if e.many && e.relation != diagramOneToMany {
+			attrs = append(attrs, "headlabel=\"*\"")
+		}
+		if e.label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", e.label))
+		}
+		fmt.Fprintf(buf, "  %q -> %q", e.from.id, e.to.id)
+		if len(attrs) > 0 {
+			fmt.Fprintf(buf, " [%s]", strings.Join(attrs, ", "))
+		}
+		buf.WriteString(";\n")
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// uml returns ends of the edge and arrow between them in notation that is common for Mermaid and PlantUML
+func (e *diagramEdge) uml() (from string, arrow string, to string) {
+	from, to = e.from.id, e.to.id
+	switch e.relation {
+	case diagramExtends:
+		return to, "<|--", from
+	case diagramImplements:
+		return to, "<|..", from
+	case diagramOneToMany:
+		return from, "\"1\" --> \"*\"", to
+	case diagramEmbedded:
+		arrow = "*--"
+	case diagramObjectRef:
+		arrow = "..>"
+	default:
+		arrow = "-->"
+	}
+	if e.many {
+		arrow += " \"*\""
+	}
+	return
+}
+
+// allStereotypes returns kind of the node (for not classes) and its modifiers
+func (n *diagramNode) allStereotypes() []string {
+	if n.kind == "class" {
+		return n.stereotypes
+	}
+	return append([]string{n.kind}, n.stereotypes...)
+}
+
+func (n *diagramNode) hasStereotype(mod TypeModifier) bool {
+	for _, s := range n.stereotypes {
+		if s == string(mod) {
+			return true
+		}
+	}
+	return false
+}
+
+// diagramField returns description of the field: name: type
+func diagramField(f *Field) string {
+	ret := fmt.Sprintf("%s: %s", f.Name, diagramTypeName(f.Type))
+	if f.IsIdField() {
+		ret += " (id)"
+	}
+	return ret
+}
+
+func diagramTypeName(ref *TypeRef) (ret string) {
+	switch {
+	case ref.Array != nil:
+		ret = "[]" + diagramTypeName(ref.Array)
+	case ref.Map != nil:
+		ret = fmt.Sprintf("map[%s]%s", ref.Map.KeyType, diagramTypeName(ref.Map.ValueType))
+	default:
+		ret = ref.Type
+	}
+	if ref.NonNullable {
+		ret += "!"
+	}
+	return
+}
+
+// dotEscape escapes characters that have special meaning in record labels
+func dotEscape(s string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		"\"", "\\\"",
+		"{", "\\{",
+		"}", "\\}",
+		"|", "\\|",
+		"<", "\\<",
+		">", "\\>",
+		" ", "\\ ",
+	).Replace(s)
+}
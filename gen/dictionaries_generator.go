@@ -79,7 +79,7 @@ type indexDescriptor struct {
 }
 
 func init() {
-	RegisterPlugin(&DictionariesGenerator{})
+	RegisterPluginFactory(func() Generator { return &DictionariesGenerator{} })
 }
 
 func (ncg *DictionariesGenerator) Name() string {
@@ -187,6 +187,36 @@ func (ncg *DictionariesGenerator) CheckAnnotation(desc *Package, ann *Annotation
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (ncg *DictionariesGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    AnnQual,
+			Targets: AnnotationTargetField,
+			AnyTags: true,
+			Doc:     "field of dictionary refers to dictionary that qualifies it",
+		},
+		{
+			Name:    AnnQualBy,
+			Targets: AnnotationTargetField,
+			Tags: []AnnotationTagSchema{
+				{Name: aqbConst, Type: AnnotationTagAny, Doc: "constant (int or string) value of qualifier"},
+			},
+			AnyTags: true,
+			Doc:     "field refers to qualified dictionary; the first tag is name of field with qualifier",
+		},
+		{
+			Name:    AnnIndex,
+			Targets: AnnotationTargetField,
+			Tags: []AnnotationTagSchema{
+				{Name: aiUnique, Type: AnnotationTagBool, Default: false, Doc: "index is unique"},
+			},
+			AnyTags: true,
+			Doc:     "builds index of dictionary by the field",
+		},
+	}
+}
+
 func (ncg *DictionariesGenerator) Prepare(desc *Package) error {
 	ncg.desc = desc
 	for _, file := range desc.Files {
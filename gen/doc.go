@@ -0,0 +1,74 @@
+package gen
+
+import (
+	"io"
+	"strings"
+
+	"github.com/alecthomas/participle/lexer"
+	"github.com/dave/jennifer/jen"
+)
+
+// docComments returns doc comments of the file by line of the declaration they precede.
+// Doc comment is a block of '///' or '//' comments on consecutive lines that ends just before declaration;
+// comments that follow some tokens on the same line are not doc comments
+func docComments(r io.Reader) (map[int]string, error) {
+	l, err := lex.Lex(r)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := lexer.ConsumeAll(l)
+	if err != nil {
+		return nil, err
+	}
+	symbols := lex.Symbols()
+	comment := symbols["Comment"]
+	whitespace := symbols["Whitespace"]
+	ret := map[int]string{}
+	var block []string
+	blockEnd := 0
+	lastLine := 0
+	for _, t := range tokens {
+		switch t.Type {
+		case whitespace:
+		case comment:
+			if t.Pos.Line == lastLine || len(block) > 0 && t.Pos.Line != blockEnd+1 {
+				block = nil
+			}
+			if t.Pos.Line != lastLine {
+				block = append(block, commentText(t.Value))
+				blockEnd = t.Pos.Line
+			}
+		default:
+			if len(block) > 0 && t.Pos.Line == blockEnd+1 {
+				ret[t.Pos.Line] = strings.Join(block, "\n")
+			}
+			block = nil
+			lastLine = t.Pos.Line
+		}
+	}
+	return ret, nil
+}
+
+func commentText(c string) string {
+	c = strings.TrimPrefix(c, "//")
+	c = strings.TrimPrefix(c, "/")
+	c = strings.TrimPrefix(c, " ")
+	return strings.TrimRight(c, " \t\r")
+}
+
+// DocLines returns doc comment split by lines; it returns nil for empty doc
+func DocLines(doc string) []string {
+	if doc == "" {
+		return nil
+	}
+	return strings.Split(doc, "\n")
+}
+
+// goDoc returns Go comment lines for doc; result is empty statement for empty doc
+func goDoc(doc string) *jen.Statement {
+	ret := &jen.Statement{}
+	for _, l := range DocLines(doc) {
+		ret.Comment(l).Line()
+	}
+	return ret
+}
@@ -0,0 +1,845 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	docsGeneratorName = "Docs"
+	docsOptionsName   = "docs"
+
+	DocsFormatMarkdown = "markdown"
+	DocsFormatHTML     = "html"
+
+	docsDefaultDir = "docs"
+)
+
+// docsFormatExtensions - extensions of documentation files by format
+var docsFormatExtensions = map[string]string{
+	DocsFormatMarkdown: ".md",
+	DocsFormatHTML:     ".html",
+}
+
+// docsFindConditions - descriptions of $find comparison types
+var docsFindConditions = map[string]string{
+	AFTEqual:                "equal to value",
+	AFTNotEqual:             "not equal to value",
+	AFTGreaterThan:          "greater than value",
+	AFTGreaterThanOrEqual:   "greater than or equal to value",
+	AFTLessThan:             "less than value",
+	AFTLessThanOrEqual:      "less than or equal to value",
+	AFTStartsWith:           "starts with value",
+	AFTContains:             "contains value",
+	AFTStartsWithIgnoreCase: "starts with value ignoring case",
+	AFTContainsIgnoreCase:   "contains value ignoring case",
+	AFTIgnore:               "deleted items are included if true",
+	AFTIsNull:               "is null if true, is not null if false",
+	AFTIsNotNull:            "is not null if true, is null if false",
+	AFTExists:               "exists if true, does not exist if false",
+	AFTNotExists:            "does not exist if true, exists if false",
+}
+
+// docsHooks - descriptions of hooks by key
+var docsHooks = map[string]string{
+	TypeHookCreate:    "called before creation of the singleton",
+	TypeHookChange:    "called before object is saved (old value is nil on create, new value is nil on delete); error cancels the change",
+	TypeHookChanged:   "called after object was saved (old value is nil on create, new value is nil on delete)",
+	TypeHookStart:     "called on singleton after all the objects are created",
+	TypeHookDelete:    "called before object is deleted; error cancels deletion",
+	AttrHookSet:       "called before complex field is saved",
+	AttrHookCalculate: "resolves value of calculated field",
+	TypeHookMethod:    "implements method",
+}
+
+type DocsOptions struct {
+	// Formats - formats of documentation: markdown, html; markdown by default
+	Formats []string
+	// OutputDir - directory for documentation files (<package>.md, <package>.html); <output>/docs by default
+	OutputDir string
+}
+
+// DocsGenerator writes reference documentation for each package: types with fields and annotations, enums,
+// $find parameters with their conditions, GraphQL operations, hooks that should be implemented and cron jobs
+type DocsGenerator struct {
+	proj    *Project
+	desc    *Package
+	options DocsOptions
+	// packages - packages for which documentation is written
+	packages map[string]bool
+}
+
+// docSpan is a part of text that may be a link to the type (of another package if pckg is set)
+type docSpan struct {
+	text   string
+	pckg   string
+	anchor string
+}
+
+type docText []docSpan
+
+// docWriter renders documentation in specific format
+type docWriter interface {
+	heading(level int, anchor string, text string)
+	paragraph(text docText)
+	list(items []docText)
+	table(header []string, rows [][]docText)
+	bytes() []byte
+}
+
+type markdownDocWriter struct {
+	buf *bytes.Buffer
+}
+
+type htmlDocWriter struct {
+	buf *bytes.Buffer
+}
+
+type docOperation struct {
+	name    string
+	request string
+	args    []string
+	returns string
+	doc     string
+}
+
+func init() {
+	RegisterPluginFactory(func() Generator { return &DocsGenerator{} })
+}
+
+func (cg *DocsGenerator) Name() string {
+	return docsGeneratorName
+}
+
+func (cg *DocsGenerator) SetOptions(opts any) error {
+	return OptionsAnyToStruct(opts, &cg.options)
+}
+
+func (cg *DocsGenerator) SetDescriptor(proj *Project) {
+	cg.proj = proj
+	cg.packages = map[string]bool{}
+	if _, err := proj.Options.CustomToStruct(docsOptionsName, &cg.options); err != nil {
+		proj.AddWarning(fmt.Sprintf("problem while setting custom options for %s: %v", docsOptionsName, err))
+	}
+}
+
+func (cg *DocsGenerator) CheckAnnotation(desc *Package, ann *Annotation, item interface{}) (bool, error) {
+	return false, nil
+}
+
+func (cg *DocsGenerator) Prepare(desc *Package) error {
+	cg.desc = desc
+	return nil
+}
+
+func (cg *DocsGenerator) Generate(b *Builder) error {
+	cg.desc = b.Descriptor
+	if cg.packages[b.Descriptor.Name] {
+		return nil
+	}
+	cg.packages[b.Descriptor.Name] = true
+	formats := cg.options.Formats
+	if len(formats) == 0 {
+		formats = []string{DocsFormatMarkdown}
+	}
+	dir := cg.options.OutputDir
+	if dir == "" {
+		dir = filepath.Join(cg.proj.Options.OutputDir, docsDefaultDir)
+	}
+	for _, format := range formats {
+		var w docWriter
+		switch format {
+		case DocsFormatMarkdown:
+			w = &markdownDocWriter{buf: &bytes.Buffer{}}
+		case DocsFormatHTML:
+			w = newHTMLDocWriter(b.Descriptor.Name)
+		default:
+			return fmt.Errorf("docs: unknown format: %s", format)
+		}
+		cg.write(w)
+		fileName := filepath.Join(dir, b.Descriptor.Name+docsFormatExtensions[format])
+		if err := cg.proj.WriteFile(fileName, w.bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write writes documentation of the current package
+func (cg *DocsGenerator) write(w docWriter) {
+	var types, finds []*Entity
+	var enums []*Enum
+	var unions []*Union
+	var ifaces []*Interface
+	for _, file := range cg.desc.Files {
+		for _, t := range file.Entries {
+			if _, ok := t.Features.Get(FeaturesAPIKind, FAPIFindFor); ok {
+				finds = append(finds, t)
+			} else {
+				types = append(types, t)
+			}
+		}
+		enums = append(enums, file.Enums...)
+		unions = append(unions, file.Unions...)
+		ifaces = append(ifaces, file.Interfaces...)
+	}
+	w.heading(1, "", "Package "+cg.desc.Name)
+	w.paragraph(docText{{text: fmt.Sprintf("Reference documentation of package %s generated by vivgen.", cg.desc.Name)}})
+
+	if len(types) > 0 {
+		w.heading(2, "types", "Types")
+		for _, t := range types {
+			cg.writeEntity(w, t)
+		}
+	}
+	if len(ifaces) > 0 || len(unions) > 0 {
+		w.heading(2, "polymorphic", "Interfaces and unions")
+		for _, i := range ifaces {
+			w.heading(3, docsAnchor(i.Name), i.Name)
+			cg.writeDoc(w, i.Doc, i.Annotations)
+			var impls docText
+			for idx, e := range i.Implementations {
+				if idx > 0 {
+					impls = append(impls, docSpan{text: ", "})
+				}
+				impls = append(impls, cg.entityLink(e))
+			}
+			w.list([]docText{append(docText{{text: "Implemented by: "}}, impls...)})
+			cg.writeFields(w, i.Fields, false)
+		}
+		for _, u := range unions {
+			w.heading(3, docsAnchor(u.Name), u.Name)
+			cg.writeDoc(w, u.Doc, u.Annotations)
+			var members docText
+			for idx, e := range u.Types {
+				if idx > 0 {
+					members = append(members, docSpan{text: " | "})
+				}
+				members = append(members, cg.entityLink(e))
+			}
+			w.list([]docText{append(docText{{text: "One of: "}}, members...)})
+		}
+	}
+	if len(enums) > 0 {
+		w.heading(2, "enums", "Enums")
+		for _, e := range enums {
+			w.heading(3, docsAnchor(e.Name), e.Name)
+			cg.writeDoc(w, e.Doc, e.Annotations)
+			w.list([]docText{{{text: "Type: " + e.AliasForType}}})
+			var rows [][]docText
+			for i, f := range e.Fields {
+				val := strconv.Itoa(i)
+				switch {
+				case f.IntVal != nil:
+					val = strconv.Itoa(*f.IntVal)
+				case f.FloatVal != nil:
+					val = strconv.FormatFloat(*f.FloatVal, 'f', -1, 64)
+				case f.StringVal != nil:
+					val = strconv.Quote(*f.StringVal)
+				}
+				rows = append(rows, []docText{{{text: f.Name}}, {{text: val}}, {{text: withDeprecation(f.Doc, f.Annotations)}}})
+			}
+			w.table([]string{"Value", "Code", "Description"}, rows)
+		}
+	}
+	if len(finds) > 0 {
+		w.heading(2, "find", "Find parameters")
+		for _, t := range finds {
+			cg.writeFind(w, t)
+		}
+	}
+	cg.writeOperations(w, types)
+	cg.writeHooks(w, types)
+	cg.writeJobs(w, types)
+}
+
+func (cg *DocsGenerator) writeEntity(w docWriter, t *Entity) {
+	w.heading(3, docsAnchor(t.Name), t.Name)
+	cg.writeDoc(w, t.Doc, t.Annotations)
+	var props []docText
+	var mods []string
+	for _, mod := range diagramTypeModifiers {
+		if t.HasModifier(mod) {
+			mods = append(mods, string(mod))
+		}
+	}
+	if len(mods) > 0 {
+		props = append(props, docText{{text: "Modifiers: " + strings.Join(mods, ", ")}})
+	}
+	if t.BaseTypeName != "" {
+		if bt := t.GetBaseType(); bt != nil {
+			props = append(props, docText{{text: "Extends: "}, cg.entityLink(bt)})
+		}
+	}
+	if len(t.Implements) > 0 {
+		props = append(props, append(docText{{text: "Implements: "}}, cg.typeNames(t.Implements)...))
+	}
+	if name, ok := t.Features.GetString(GQLFeatures, GQLFTypeTag); ok && t.FS(FeaturesAPIKind, FAPILevel) != FAPILIgnore {
+		props = append(props, docText{{text: fmt.Sprintf("GraphQL type: %s (input: %s)", name, t.FS(GQLFeatures, GQLFInputTypeName))}})
+	}
+	if pt, ok := t.Features.GetEntity(FeaturesAPIKind, FAPIFindParamType); ok {
+		props = append(props, docText{{text: "Find parameters: "}, cg.entityLink(pt)})
+	}
+	if anns := docsAnnotations(t.Annotations); anns != "" {
+		props = append(props, docText{{text: "Annotations: " + anns}})
+	}
+	if len(props) > 0 {
+		w.list(props)
+	}
+	cg.writeFields(w, t.Fields, true)
+	if len(t.Methods) > 0 {
+		var rows [][]docText
+		for _, m := range t.Methods {
+			sign := docText{{text: m.Name + "("}}
+			for i, p := range m.Params {
+				if i > 0 {
+					sign = append(sign, docSpan{text: ", "})
+				}
+				sign = append(sign, docSpan{text: p.Name + ": "})
+				sign = append(sign, cg.typeRef(p.Type)...)
+			}
+			sign = append(sign, docSpan{text: ")"})
+			if m.RetValue != nil {
+				sign = append(sign, docSpan{text: ": "})
+				sign = append(sign, cg.typeRef(m.RetValue)...)
+			}
+			rows = append(rows, []docText{sign, {{text: docsAnnotations(m.Annotations)}}, {{text: withDeprecation(m.Doc, m.Annotations)}}})
+		}
+		w.table([]string{"Method", "Annotations", "Description"}, rows)
+	}
+}
+
+// writeFields writes table of fields; withGQL adds name and type of the field in GraphQL schema
+func (cg *DocsGenerator) writeFields(w docWriter, fields []*Field, withGQL bool) {
+	if len(fields) == 0 {
+		return
+	}
+	header := []string{"Field", "Type", "Nullable", "Annotations", "Description"}
+	if withGQL {
+		header = []string{"Field", "Type", "Nullable", "GraphQL", "Annotations", "Description"}
+	}
+	var rows [][]docText
+	for _, f := range fields {
+		name := f.Name
+		if f.IsIdField() {
+			name += " (id)"
+		}
+		var mods []string
+		for _, m := range f.Modifiers {
+			if m.AttrModifier != "" && m.AttrModifier != string(AttrModifierID) {
+				mods = append(mods, string(m.AttrModifier))
+			}
+		}
+		if len(mods) > 0 {
+			name += " <" + strings.Join(mods, " ") + ">"
+		}
+		nullable := "yes"
+		if f.Type.NonNullable || f.IsIdField() {
+			nullable = "no"
+		}
+		row := []docText{{{text: name}}, cg.typeRef(f.Type), {{text: nullable}}}
+		if withGQL {
+			gql := ""
+			if tip, ok := f.Features.GetString(GQLFeatures, GQLFTypeTag); ok && !f.FB(FeaturesAPIKind, FCIgnore) {
+				gql = f.Annotations.GetStringAnnotationDef(GQLAnnotation, GQLAnnotationNameTag, f.Name) + ": " + tip
+			}
+			row = append(row, docText{{text: gql}})
+		}
+		row = append(row, docText{{text: docsAnnotations(f.Annotations)}}, docText{{text: withDeprecation(f.Doc, f.Annotations)}})
+		rows = append(rows, row)
+	}
+	w.table(header, rows)
+}
+
+func (cg *DocsGenerator) writeFind(w docWriter, t *Entity) {
+	w.heading(3, docsAnchor(t.Name), t.Name)
+	cg.writeDoc(w, t.Doc, t.Annotations)
+	ft, _ := t.Features.GetEntity(FeaturesAPIKind, FAPIFindFor)
+	w.list([]docText{{{text: "Parameters of find for "}, cg.entityLink(ft)}})
+	var rows [][]docText
+	for _, f := range t.Fields {
+		op := f.FS(FeaturesAPIKind, FAPIFindParam)
+		cond := docsFindConditions[op]
+		if cond == "" {
+			cond = op
+		}
+		field := f.FS(FeaturesAPIKind, FAPIFindForName)
+		if field == AFFDeleted {
+			field = "(deleted flag)"
+		}
+		rows = append(rows, []docText{{{text: f.Name}}, cg.typeRef(f.Type), {{text: field}}, {{text: op}}, {{text: cond}}})
+	}
+	w.paragraph(docText{{text: "Parameters that are not set (null) are not used for filtering; all set parameters should be satisfied."}})
+	w.table([]string{"Parameter", "Type", "Field", "Condition", "Meaning"}, rows)
+}
+
+func (cg *DocsGenerator) writeOperations(w docWriter, types []*Entity) {
+	var rows [][]docText
+	for _, t := range types {
+		for _, op := range cg.operations(t) {
+			rows = append(
+				rows,
+				[]docText{
+					{{text: op.name}},
+					{{text: op.request}},
+					{{text: strings.Join(op.args, ", ")}},
+					{{text: op.returns}},
+					{cg.entityLink(t)},
+					{{text: op.doc}},
+				},
+			)
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	w.heading(2, "operations", "GraphQL operations")
+	w.table([]string{"Operation", "Kind", "Arguments", "Returns", "Type", "Description"}, rows)
+}
+
+// operations returns GraphQL operations generated for the type
+func (cg *DocsGenerator) operations(e *Entity) (ops []docOperation) {
+	if _, ok := e.Features.GetString(GQLFeatures, GQLFTypeTag); !ok || e.FS(FeaturesAPIKind, FAPILevel) == FAPILIgnore {
+		return
+	}
+	typeName := e.FS(GQLFeatures, GQLFTypeTag)
+	inputType := e.FS(GQLFeatures, GQLFInputTypeName)
+	idArg := ""
+	if idfld := e.GetIdField(); idfld != nil {
+		idArg = idfld.Annotations.GetStringAnnotationDef(GQLAnnotation, GQLAnnotationNameTag, "id") + ": " + idfld.FS(GQLFeatures, GQLFTypeTag)
+	}
+	deprecated := ""
+	if reason, ok := DeprecationReason(e.Annotations); ok {
+		deprecated = "Deprecated"
+		if reason != "" {
+			deprecated += ": " + reason
+		}
+	}
+	for i := GQLOperationGet; i < GQLOperationLast; i++ {
+		if !cg.hasOperation(e, i) {
+			continue
+		}
+		op := docOperation{name: e.FS(GQLFeatures, GQLOperationsAnnotationsTags[i]), request: GQLFMethodTypeQuery, returns: typeName}
+		switch i {
+		case GQLOperationGet:
+			op.doc = "returns object by id"
+			if e.HasModifier(TypeModifierConfig) {
+				op.doc = "returns config"
+			} else if idArg != "" {
+				op.args = []string{idArg}
+			}
+		case GQLOperationSet:
+			op.request = GQLFMethodTypeMutation
+			op.args = []string{"val: " + inputType}
+			op.doc = "updates object"
+		case GQLOperationCreate:
+			op.request = GQLFMethodTypeMutation
+			op.args = []string{"val: " + inputType}
+			op.doc = "creates object"
+		case GQLOperationList:
+			op.returns = "[" + typeName + "]"
+			op.doc = "returns all the objects of dictionary"
+			if e.FB(FeatureDictKind, FDQualified) {
+				op.args = []string{"quals: [ID]"}
+				op.doc += " (filtered by qualifiers if set)"
+			}
+		case GQLOperationLookup:
+			op.returns = "[" + typeName + "]"
+			op.args = []string{"query: String!"}
+			op.doc = "returns objects matching query"
+		case GQLOperationDelete:
+			op.request = GQLFMethodTypeMutation
+			op.returns = "Boolean"
+			if idArg != "" {
+				op.args = []string{idArg}
+			}
+			op.doc = "deletes object by id"
+		case GQLOperationFind:
+			op.returns = "[" + typeName + "]"
+			if it, ok := e.Features.GetEntity(FeaturesAPIKind, FAPIFindParamType); ok {
+				op.args = []string{"query: " + it.FS(GQLFeatures, GQLFInputTypeName)}
+				op.doc = fmt.Sprintf("returns objects matching parameters (see %s)", it.Name)
+			}
+		case GQLOperationBulkCreate:
+			op.request = GQLFMethodTypeMutation
+			op.returns = "[" + typeName + "]"
+			op.args = []string{"val: [" + inputType + "]"}
+			op.doc = "creates objects"
+		case GQLOperationBulkSet:
+			op.request = GQLFMethodTypeMutation
+			op.returns = "[" + typeName + "]"
+			op.args = []string{"val: [" + inputType + "]"}
+			op.doc = "updates objects"
+		}
+		if deprecated != "" {
+			op.doc += "; " + deprecated
+		}
+		ops = append(ops, op)
+	}
+	for _, m := range e.Methods {
+		qn := m.FS(GQLFeatures, GQLFMethodName)
+		if qn == "" {
+			continue
+		}
+		op := docOperation{name: qn, request: m.FS(GQLFeatures, GQLFMethodType), doc: withDeprecation(m.Doc, m.Annotations)}
+		if idfld := e.GetIdField(); idfld != nil {
+			if idt, ok := idfld.Features.GetString(GQLFeatures, GQLFTypeTag); ok {
+				op.args = append(op.args, "id: "+idt)
+			}
+		}
+		for _, p := range m.Params {
+			op.args = append(op.args, p.Name+": "+p.Features.String(GQLFeatures, GQLFInputTypeName))
+		}
+		if ret, ok := cg.proj.GetFeature(m, GQLFeatures, GQLFMethodResultTypeName).(string); ok {
+			op.returns = ret
+		}
+		if op.doc == "" {
+			op.doc = fmt.Sprintf("calls method %s of %s", m.Name, e.Name)
+		}
+		ops = append(ops, op)
+	}
+	return
+}
+
+// hasOperation returns true if GQL operation is available for entity (the same conditions as for clients)
+func (cg *DocsGenerator) hasOperation(e *Entity, op GQLOperationKind) bool {
+	if e.HasModifier(TypeModifierTransient) || e.HasModifier(TypeModifierEmbeddable) || e.HasModifier(TypeModifierSingleton) {
+		return false
+	}
+	if _, ok := e.Features.GetString(GQLFeatures, GQLOperationsAnnotationsTags[op]); !ok {
+		return false
+	}
+	isCfg := e.HasModifier(TypeModifierConfig)
+	switch op {
+	case GQLOperationSet:
+		return !e.FB(FeaturesCommonKind, FCReadonly)
+	case GQLOperationCreate:
+		return !e.FB(FeaturesCommonKind, FCReadonly) && !isCfg
+	case GQLOperationList, GQLOperationLookup, GQLOperationDelete:
+		return !isCfg
+	case GQLOperationFind:
+		_, ok := e.Features.GetEntity(FeaturesAPIKind, FAPIFindParamType)
+		return ok
+	case GQLOperationBulkCreate:
+		return e.FB(FeatGoKind, FCGBulkNew)
+	case GQLOperationBulkSet:
+		return e.FB(FeatGoKind, FCGBulkSet)
+	}
+	return true
+}
+
+// writeHooks writes hooks declared for types, fields and methods that should be implemented
+func (cg *DocsGenerator) writeHooks(w docWriter, types []*Entity) {
+	var rows [][]docText
+	add := func(t *Entity, item string, h *Hook, def string) {
+		rows = append(rows, []docText{{cg.entityLink(t)}, {{text: item}}, {{text: h.Key}}, {{text: docsHookImplementation(h, def)}}, {{text: docsHooks[h.Key]}}})
+	}
+	for _, t := range types {
+		for _, m := range t.Modifiers {
+			if m.Hook != nil && m.Hook.Key != TypeHookTime {
+				add(t, "", m.Hook, cg.desc.GetHookName(m.Hook.Key, nil))
+			}
+		}
+		for _, f := range t.Fields {
+			for _, m := range f.Modifiers {
+				if m.Hook != nil {
+					add(t, f.Name, m.Hook, cg.desc.GetHookName(m.Hook.Key, f))
+				}
+			}
+		}
+		for _, m := range t.Methods {
+			h := &Hook{Key: TypeHookMethod, Value: m.Name}
+			if ann, ok := m.Annotations[AnnotationCall]; ok {
+				h.Value = ann.GetString(AnnCallName, h.Value)
+				if js, ok := ann.GetBoolTag(AnnCallJS); ok && js {
+					h.Spec = HookJSPrefix
+				}
+			}
+			add(t, m.Name+"()", h, m.Name)
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	w.heading(2, "hooks", "Hooks")
+	w.paragraph(docText{{text: "Functions and scripts that should be implemented for the generated code."}})
+	w.table([]string{"Type", "Item", "Hook", "Implementation", "Description"}, rows)
+}
+
+// writeJobs writes cron jobs created for @time hooks
+func (cg *DocsGenerator) writeJobs(w docWriter, types []*Entity) {
+	var rows [][]docText
+	for _, t := range types {
+		if !t.HasModifier(TypeModifierSingleton) {
+			continue
+		}
+		for _, m := range t.Modifiers {
+			if h := m.Hook; h != nil && h.Key == TypeHookTime {
+				fname := cronSingletonDefaultFunctionName
+				spec := h.Value
+				if pref := strings.LastIndex(h.Value, cronSingletonFunctionPrefix); pref != -1 {
+					fname = strings.Trim(h.Value[(pref+len(cronSingletonFunctionPrefix)):], " \t")
+					spec = strings.Trim(h.Value[:pref], " \t")
+				}
+				impl := "Go function " + fname
+				if h.Spec == HookJSPrefix {
+					impl = "JS script " + fname
+				}
+				rows = append(rows, []docText{{cg.entityLink(t)}, {{text: spec}}, {{text: impl}}})
+			}
+		}
+		for _, m := range t.Methods {
+			if h, ok := m.HaveHook(MethodHookTime); ok {
+				rows = append(rows, []docText{{cg.entityLink(t)}, {{text: h.Value}}, {{text: "method " + m.Name}}})
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	w.heading(2, "jobs", "Scheduled jobs")
+	w.table([]string{"Type", "Schedule", "Calls"}, rows)
+}
+
+func (cg *DocsGenerator) writeDoc(w docWriter, doc string, anns Annotations) {
+	if doc = withDeprecation(doc, anns); doc != "" {
+		w.paragraph(docText{{text: doc}})
+	}
+}
+
+// typeRef returns description of the type with link to the defined type
+func (cg *DocsGenerator) typeRef(ref *TypeRef) (ret docText) {
+	switch {
+	case ref.Array != nil:
+		ret = append(docText{{text: "["}}, cg.typeRef(ref.Array)...)
+		ret = append(ret, docSpan{text: "]"})
+	case ref.Map != nil:
+		ret = append(docText{{text: "map[" + ref.Map.KeyType + "]"}}, cg.typeRef(ref.Map.ValueType)...)
+	default:
+		ret = cg.typeNames([]string{ref.Type})
+	}
+	if ref.NonNullable {
+		ret = append(ret, docSpan{text: "!"})
+	}
+	return
+}
+
+func (cg *DocsGenerator) typeNames(names []string) (ret docText) {
+	for i, name := range names {
+		if i > 0 {
+			ret = append(ret, docSpan{text: ", "})
+		}
+		span := docSpan{text: name}
+		if dt, ok := cg.desc.FindType(name); ok && !IsPrimitiveType(name) && !dt.external {
+			span.anchor = docsAnchor(dt.name)
+			if dt.pckg != cg.desc.Name {
+				span.pckg = dt.pckg
+			}
+		}
+		ret = append(ret, span)
+	}
+	return
+}
+
+func (cg *DocsGenerator) entityLink(e *Entity) docSpan {
+	if e == nil {
+		return docSpan{}
+	}
+	span := docSpan{text: e.Name, anchor: docsAnchor(e.Name)}
+	if e.Pckg != nil && e.Pckg != cg.desc {
+		span.pckg = e.Pckg.Name
+		span.text = e.Pckg.Name + "." + e.Name
+	}
+	return span
+}
+
+func docsAnchor(name string) string {
+	return "type-" + strings.ToLower(name)
+}
+
+// docsHookImplementation returns description of function or script that implements hook
+func docsHookImplementation(h *Hook, def string) string {
+	name := h.Value
+	if h.Spec == HookJSPrefix {
+		return "JS script " + name
+	}
+	if name == "" {
+		name = def
+	}
+	if strings.HasSuffix(name, WithoutEngSuffix) {
+		return "Go function " + strings.TrimSuffix(name, WithoutEngSuffix) + " (without engine param)"
+	}
+	return "Go function " + name
+}
+
+// docsAnnotations returns annotations written in DSL (sorted by name) in DSL notation
+func docsAnnotations(anns Annotations) string {
+	var names []string
+	for name, a := range anns {
+		if a.Pos.Line > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var ret []string
+	for _, name := range names {
+		a := anns[name]
+		var tags []string
+		for _, t := range a.Values {
+			if t.Pos.Line == 0 {
+				// tags added by generators
+				continue
+			}
+			tag := t.Key
+			if v := t.Value; v != nil {
+				switch {
+				case v.String != nil:
+					tag += "=" + strconv.Quote(*v.String)
+				case v.Bool != nil:
+					tag += "=" + strconv.FormatBool(bool(*v.Bool))
+				case v.Number != nil:
+					tag += "=" + strconv.FormatFloat(*v.Number, 'f', -1, 64)
+				}
+			}
+			tags = append(tags, tag)
+		}
+		s := "$" + a.Name
+		if len(tags) > 0 {
+			s += "(" + strings.Join(tags, " ") + ")"
+		}
+		ret = append(ret, s)
+	}
+	return strings.Join(ret, " ")
+}
+
+func (w *markdownDocWriter) heading(level int, anchor string, text string) {
+	if anchor != "" {
+		fmt.Fprintf(w.buf, "<a id=\"%s\"></a>\n", anchor)
+	}
+	fmt.Fprintf(w.buf, "%s %s\n\n", strings.Repeat("#", level), text)
+}
+
+func (w *markdownDocWriter) paragraph(text docText) {
+	w.buf.WriteString(w.text(text, false))
+	w.buf.WriteString("\n\n")
+}
+
+func (w *markdownDocWriter) list(items []docText) {
+	for _, item := range items {
+		fmt.Fprintf(w.buf, "- %s\n", w.text(item, false))
+	}
+	w.buf.WriteString("\n")
+}
+
+func (w *markdownDocWriter) table(header []string, rows [][]docText) {
+	fmt.Fprintf(w.buf, "| %s |\n|", strings.Join(header, " | "))
+	w.buf.WriteString(strings.Repeat(" --- |", len(header)))
+	w.buf.WriteString("\n")
+	for _, row := range rows {
+		w.buf.WriteString("|")
+		for _, cell := range row {
+			fmt.Fprintf(w.buf, " %s |", w.text(cell, true))
+		}
+		w.buf.WriteString("\n")
+	}
+	w.buf.WriteString("\n")
+}
+
+func (w *markdownDocWriter) text(text docText, inTable bool) string {
+	var ret strings.Builder
+	for _, s := range text {
+		t := strings.NewReplacer("\\", "\\\\", "*", "\\*", "_", "\\_", "[", "\\[", "]", "\\]", "<", "&lt;", ">", "&gt;", "|", "\\|").Replace(s.text)
+		if inTable {
+			t = strings.ReplaceAll(t, "\n", "<br>")
+		}
+		if s.anchor != "" {
+			link := "#" + s.anchor
+			if s.pckg != "" {
+				link = s.pckg + docsFormatExtensions[DocsFormatMarkdown] + link
+			}
+			t = fmt.Sprintf("[%s](%s)", t, link)
+		}
+		ret.WriteString(t)
+	}
+	return ret.String()
+}
+
+func (w *markdownDocWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
+
+func newHTMLDocWriter(title string) *htmlDocWriter {
+	w := &htmlDocWriter{buf: &bytes.Buffer{}}
+	fmt.Fprintf(
+		w.buf,
+		"<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n"+
+			"body { font-family: sans-serif; margin: 2em; }\n"+
+			"table { border-collapse: collapse; margin-bottom: 1em; }\n"+
+			"th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }\n"+
+			"th { background: #f4f4f4; }\n"+
+			"</style>\n</head>\n<body>\n",
+		html.EscapeString(title),
+	)
+	return w
+}
+
+func (w *htmlDocWriter) heading(level int, anchor string, text string) {
+	id := ""
+	if anchor != "" {
+		id = fmt.Sprintf(" id=\"%s\"", anchor)
+	}
+	fmt.Fprintf(w.buf, "<h%d%s>%s</h%d>\n", level, id, html.EscapeString(text), level)
+}
+
+func (w *htmlDocWriter) paragraph(text docText) {
+	fmt.Fprintf(w.buf, "<p>%s</p>\n", w.text(text))
+}
+
+func (w *htmlDocWriter) list(items []docText) {
+	w.buf.WriteString("<ul>\n")
+	for _, item := range items {
+		fmt.Fprintf(w.buf, "<li>%s</li>\n", w.text(item))
+	}
+	w.buf.WriteString("</ul>\n")
+}
+
+func (w *htmlDocWriter) table(header []string, rows [][]docText) {
+	w.buf.WriteString("<table>\n<tr>")
+	for _, h := range header {
+		fmt.Fprintf(w.buf, "<th>%s</th>", html.EscapeString(h))
+	}
+	w.buf.WriteString("</tr>\n")
+	for _, row := range rows {
+		w.buf.WriteString("<tr>")
+		for _, cell := range row {
+			fmt.Fprintf(w.buf, "<td>%s</td>", w.text(cell))
+		}
+		w.buf.WriteString("</tr>\n")
+	}
+	w.buf.WriteString("</table>\n")
+}
+
+func (w *htmlDocWriter) text(text docText) string {
+	var ret strings.Builder
+	for _, s := range text {
+		t := strings.ReplaceAll(html.EscapeString(s.text), "\n", "<br>")
+		if s.anchor != "" {
+			link := "#" + s.anchor
+			if s.pckg != "" {
+				link = s.pckg + docsFormatExtensions[DocsFormatHTML] + link
+			}
+			t = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(link), t)
+		}
+		ret.WriteString(t)
+	}
+	return ret.String()
+}
+
+func (w *htmlDocWriter) bytes() []byte {
+	return append(w.buf.Bytes(), []byte("</body>\n</html>\n")...)
+}
@@ -0,0 +1,225 @@
+package external
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vc2402/vivard/gen"
+)
+
+// maxMessageSize - max size of the plugin's message (generated files are sent in one message)
+const maxMessageSize = 256 << 20
+
+// Generator is gen.Generator that delegates checking of annotations and generation to plugin process
+type Generator struct {
+	name        string
+	cmd         *exec.Cmd
+	in          io.WriteCloser
+	out         *bufio.Scanner
+	lastID      int
+	annotations map[string]bool
+	proj        *gen.Project
+	generated   bool
+	closed      bool
+}
+
+// Start starts plugin's executable command with args and initializes plugin with options
+func Start(name string, command string, args []string, options any) (*Generator, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("plugin %s: can not start '%s': %w", name, command, err)
+	}
+	g := &Generator{name: name, cmd: cmd, in: in, out: bufio.NewScanner(out), annotations: map[string]bool{}}
+	g.out.Buffer(nil, maxMessageSize)
+	var res InitializeResult
+	err = g.call(MethodInitialize, InitializeParams{ProtocolVersion: ProtocolVersion, Name: name, Options: options}, &res)
+	if err == nil && res.ProtocolVersion != ProtocolVersion {
+		err = fmt.Errorf("plugin %s: unsupported protocol version %d (expected %d)", name, res.ProtocolVersion, ProtocolVersion)
+	}
+	if err != nil {
+		g.kill()
+		return nil, err
+	}
+	for _, ann := range res.Annotations {
+		g.annotations[ann] = true
+	}
+	return g, nil
+}
+
+// Name from gen.Generator
+func (g *Generator) Name() string {
+	return g.name
+}
+
+// SetDescriptor from gen.DescriptorAware
+func (g *Generator) SetDescriptor(proj *gen.Project) {
+	g.proj = proj
+}
+
+// AnnotationSchemas from gen.AnnotationsDescriber; annotations listed by plugin on initialization are checked by plugin,
+// so they may be used for anything and accept any tags
+func (g *Generator) AnnotationSchemas() []gen.AnnotationSchema {
+	names := make([]string, 0, len(g.annotations))
+	for ann := range g.annotations {
+		names = append(names, ann)
+	}
+	sort.Strings(names)
+	ret := make([]gen.AnnotationSchema, len(names))
+	for i, name := range names {
+		ret[i] = gen.AnnotationSchema{
+			Name:      name,
+			Qualified: true,
+			Targets:   gen.AnnotationTargetAny,
+			AnyTags:   true,
+			Doc:       fmt.Sprintf("annotation of plugin %s", g.name),
+		}
+	}
+	return ret
+}
+
+// CheckAnnotation from gen.Generator; plugin is asked only for annotations it listed on initialization
+func (g *Generator) CheckAnnotation(desc *gen.Package, ann *gen.Annotation, item interface{}) (bool, error) {
+	name, _, _ := strings.Cut(ann.Name, ":")
+	if !g.annotations[name] {
+		return false, nil
+	}
+	var res CheckAnnotationResult
+	err := g.call(
+		MethodCheckAnnotation,
+		CheckAnnotationParams{Package: desc.Name, Annotation: newAnnotation(ann), Item: newItem(item)},
+		&res,
+	)
+	if err != nil {
+		return false, err
+	}
+	if res.Error != "" {
+		return true, fmt.Errorf("at %v: %s", ann.Pos, res.Error)
+	}
+	return res.Accepted, nil
+}
+
+// Prepare from gen.Generator
+func (g *Generator) Prepare(desc *gen.Package) error {
+	return nil
+}
+
+// Generate from gen.Generator; the whole project is generated by plugin on the first call
+func (g *Generator) Generate(b *gen.Builder) error {
+	if g.generated {
+		return nil
+	}
+	g.generated = true
+	var res GenerateResult
+	err := g.call(MethodGenerate, GenerateParams{Project: newProject(g.proj)}, &res)
+	if err != nil {
+		return err
+	}
+	for _, w := range res.Warnings {
+		g.proj.AddWarning(fmt.Sprintf("%s: %s", g.name, w))
+	}
+	for _, f := range res.Files {
+		path, err := g.outputPath(f.Path)
+		if err != nil {
+			return err
+		}
+		if err = g.proj.WriteFile(path, []byte(f.Content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outputPath returns path of generated file in output directory; plugin can not write files outside of it
+func (g *Generator) outputPath(name string) (string, error) {
+	path := filepath.FromSlash(name)
+	clean := filepath.Clean(path)
+	if name == "" || filepath.IsAbs(path) || filepath.VolumeName(path) != "" || clean == "." || clean == ".." ||
+		strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("plugin %s: invalid file path '%s': should be relative to output directory", g.name, name)
+	}
+	return filepath.Join(g.proj.Options.OutputDir, clean), nil
+}
+
+// Close asks plugin to shut down and waits for the process exit
+func (g *Generator) Close() error {
+	if g.closed {
+		return nil
+	}
+	err := g.call(MethodShutdown, nil, nil)
+	g.closed = true
+	g.in.Close()
+	if waitErr := g.cmd.Wait(); err == nil && waitErr != nil {
+		err = fmt.Errorf("plugin %s: %w", g.name, waitErr)
+	}
+	return err
+}
+
+// call sends request and waits for response
+func (g *Generator) call(method string, params any, result any) error {
+	if g.closed {
+		return fmt.Errorf("plugin %s: already closed", g.name)
+	}
+	g.lastID++
+	req := Request{ID: g.lastID, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("plugin %s: %s: %w", g.name, method, err)
+		}
+		req.Params = data
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("plugin %s: %s: %w", g.name, method, err)
+	}
+	if _, err = g.in.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("plugin %s: %s: %w", g.name, method, err)
+	}
+	if !g.out.Scan() {
+		err = g.out.Err()
+		if err == nil {
+			err = errors.New("plugin closed output")
+		}
+		return fmt.Errorf("plugin %s: %s: %w", g.name, method, err)
+	}
+	var resp Response
+	if err = json.Unmarshal(g.out.Bytes(), &resp); err != nil {
+		return fmt.Errorf("plugin %s: %s: invalid response: %w", g.name, method, err)
+	}
+	if resp.ID != req.ID {
+		return fmt.Errorf("plugin %s: %s: response id %d does not match request id %d", g.name, method, resp.ID, req.ID)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("plugin %s: %s: %s", g.name, method, resp.Error)
+	}
+	if result != nil && len(resp.Result) > 0 {
+		if err = json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("plugin %s: %s: invalid result: %w", g.name, method, err)
+		}
+	}
+	return nil
+}
+
+func (g *Generator) kill() {
+	g.closed = true
+	g.in.Close()
+	g.cmd.Process.Kill()
+	g.cmd.Wait()
+}
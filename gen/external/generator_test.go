@@ -0,0 +1,195 @@
+package external
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vc2402/vivard/gen"
+)
+
+// fakePluginEnv - if set, test binary works as plugin (see fakePlugin); value selects files it generates
+const fakePluginEnv = "VIVARD_TEST_FAKE_PLUGIN"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(fakePluginEnv); mode != "" {
+		os.Exit(fakePlugin(mode))
+	}
+	os.Exit(m.Run())
+}
+
+// fakePlugin serves protocol over stdin/stdout: it handles $summary annotation (tag 'bad' is an error) and
+// generates file with title from options, names of types and methods received before generate
+func fakePlugin(mode string) int {
+	in := bufio.NewScanner(os.Stdin)
+	in.Buffer(nil, maxMessageSize)
+	out := json.NewEncoder(os.Stdout)
+	var methods []string
+	var title string
+	for in.Scan() {
+		var req Request
+		if err := json.Unmarshal(in.Bytes(), &req); err != nil {
+			fmt.Fprintln(os.Stderr, "fake plugin:", err)
+			return 1
+		}
+		methods = append(methods, req.Method)
+		resp := Response{ID: req.ID}
+		var result any
+		switch req.Method {
+		case MethodInitialize:
+			var params InitializeParams
+			_ = json.Unmarshal(req.Params, &params)
+			if options, ok := params.Options.(map[string]any); ok {
+				title, _ = options["title"].(string)
+			}
+			result = InitializeResult{ProtocolVersion: ProtocolVersion, Annotations: []string{"summary"}}
+		case MethodCheckAnnotation:
+			var params CheckAnnotationParams
+			_ = json.Unmarshal(req.Params, &params)
+			res := CheckAnnotationResult{Accepted: true}
+			for _, tag := range params.Annotation.Values {
+				if tag.Key == "bad" {
+					res.Error = fmt.Sprintf("bad tag for %s %s", params.Item.Kind, params.Item.Name)
+				}
+			}
+			result = res
+		case MethodGenerate:
+			var params GenerateParams
+			_ = json.Unmarshal(req.Params, &params)
+			lines := []string{title}
+			for _, p := range params.Project.Packages {
+				for _, f := range p.Files {
+					for _, t := range f.Types {
+						lines = append(lines, p.Name+"."+t.Name)
+					}
+				}
+			}
+			lines = append(lines, strings.Join(methods, " "))
+			path := "summary/types.txt"
+			switch mode {
+			case "parent":
+				path = "summary/../../types.txt"
+			case "absolute":
+				path = filepath.Join(params.Project.OutputDir, "types.txt")
+			}
+			result = GenerateResult{Files: []File{{Path: path, Content: strings.Join(lines, "\n")}}}
+		case MethodShutdown:
+			_ = out.Encode(resp)
+			return 0
+		default:
+			resp.Error = "unknown method " + req.Method
+		}
+		if result != nil {
+			resp.Result, _ = json.Marshal(result)
+		}
+		if err := out.Encode(resp); err != nil {
+			return 1
+		}
+	}
+	return 0
+}
+
+// generateWithFakePlugin generates source with plugin started in given mode
+func generateWithFakePlugin(t *testing.T, mode string, src string) (string, *Generator, error) {
+	t.Helper()
+	t.Setenv(fakePluginEnv, mode)
+	g, err := Start("summary", os.Args[0], nil, map[string]any{"title": "Types"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	dir := t.TempDir()
+	name := filepath.Join(dir, "shop.vvf")
+	files, err := gen.ParseSources([]string{name}, map[string][]byte{name: []byte(src)})
+	if err != nil {
+		g.Close()
+		t.Fatalf("parse: %v", err)
+	}
+	opts := gen.Options(filepath.Join(dir, "out")).
+		WithCustom(gen.CodeGeneratorOptionsName, map[string]any{"AllowEmbeddedArraysForDictionary": true})
+	proj := gen.New(files, opts).With(g)
+	err = proj.Generate()
+	if !g.closed {
+		t.Error("plugin is not closed after generation")
+	}
+	return dir, g, err
+}
+
+func TestPluginProtocol(t *testing.T) {
+	src := "package shop;\n$summary(short)\ntype Order {\n  orderID: int <id>;\n}\n"
+	dir, g, err := generateWithFakePlugin(t, "default", src)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out", "summary", "types.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Types\nshop.Order\ninitialize checkAnnotation generate"; string(data) != want {
+		t.Errorf("unexpected generated file:\n%s\nwant:\n%s", data, want)
+	}
+	if g.cmd.ProcessState == nil || !g.cmd.ProcessState.Success() {
+		t.Errorf("plugin process did not exit after shutdown: %v", g.cmd.ProcessState)
+	}
+}
+
+func TestPluginAnnotationError(t *testing.T) {
+	src := "package shop;\n$summary(bad)\ntype Order {\n  orderID: int <id>;\n}\n"
+	_, _, err := generateWithFakePlugin(t, "default", src)
+	if err == nil || !strings.Contains(err.Error(), "bad tag for entity Order") || !strings.Contains(err.Error(), ":2:1") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPluginFilesOutsideOutputDir(t *testing.T) {
+	src := "package shop;\ntype Order {\n  orderID: int <id>;\n}\n"
+	for _, mode := range []string{"parent", "absolute"} {
+		t.Run(
+			mode, func(t *testing.T) {
+				dir, _, err := generateWithFakePlugin(t, mode, src)
+				if err == nil || !strings.Contains(err.Error(), "invalid file path") {
+					t.Errorf("unexpected error: %v", err)
+				}
+				for _, path := range []string{filepath.Join(dir, "types.txt"), filepath.Join(dir, "out", "types.txt")} {
+					if _, err := os.Stat(path); err == nil {
+						t.Errorf("file is written: %s", path)
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	g := &Generator{name: "test", proj: gen.New(nil, gen.Options("out"))}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"a.txt", filepath.Join("out", "a.txt")},
+		{"./sub/a.txt", filepath.Join("out", "sub", "a.txt")},
+		{"sub/../a.txt", filepath.Join("out", "a.txt")},
+		{"..a.txt", filepath.Join("out", "..a.txt")},
+		{"", ""},
+		{".", ""},
+		{"..", ""},
+		{"../a.txt", ""},
+		{"sub/../../a.txt", ""},
+		{"/tmp/a.txt", ""},
+	}
+	for _, tt := range tests {
+		got, err := g.outputPath(tt.path)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("outputPath(%q): error expected; got %s", tt.path, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("outputPath(%q) = %s, %v; want %s", tt.path, got, err, tt.want)
+		}
+	}
+}
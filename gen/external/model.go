@@ -0,0 +1,208 @@
+package external
+
+import (
+	"sort"
+
+	"github.com/alecthomas/participle/lexer"
+	"github.com/vc2402/vivard/gen"
+)
+
+// newProject creates model of the project for plugin
+func newProject(proj *gen.Project) Project {
+	ret := Project{OutputDir: proj.Options.OutputDir, ClientOutputDir: proj.Options.ClientOutputDir}
+	packages := map[string]int{}
+	for _, f := range proj.Files {
+		name := f.Package
+		if f.Pckg != nil {
+			name = f.Pckg.Name
+		}
+		idx, ok := packages[name]
+		if !ok {
+			idx = len(ret.Packages)
+			packages[name] = idx
+			ret.Packages = append(ret.Packages, Package{Name: name})
+		}
+		ret.Packages[idx].Files = append(ret.Packages[idx].Files, newSourceFile(f))
+	}
+	return ret
+}
+
+func newSourceFile(f *gen.File) SourceFile {
+	ret := SourceFile{Name: f.Name, Path: f.Path, Annotations: newAnnotations(f.Annotations)}
+	for _, e := range f.Entries {
+		t := Type{
+			Name:        e.Name,
+			Doc:         e.Doc,
+			Pos:         newPos(e.Pos),
+			Extends:     e.BaseTypeName,
+			Implements:  e.Implements,
+			Annotations: newAnnotations(e.Annotations),
+		}
+		for _, m := range e.Modifiers {
+			switch {
+			case m.TypeModifier != nil:
+				t.Modifiers = append(t.Modifiers, string(m.TypeModifier.Modifier))
+			case m.Hook != nil:
+				t.Hooks = append(t.Hooks, newHook(m.Hook))
+			}
+		}
+		for _, fld := range e.Fields {
+			t.Fields = append(t.Fields, newField(fld))
+		}
+		for _, m := range e.Methods {
+			method := Method{
+				Name:        m.Name,
+				Returns:     newTypeRef(m.RetValue),
+				Doc:         m.Doc,
+				Pos:         newPos(m.Pos),
+				Annotations: newAnnotations(m.Annotations),
+				Hooks:       newEntryHooks(m.Modifiers),
+			}
+			for _, p := range m.Params {
+				method.Params = append(method.Params, Param{Name: p.Name, Type: newTypeRef(p.Type)})
+			}
+			t.Methods = append(t.Methods, method)
+		}
+		ret.Types = append(ret.Types, t)
+	}
+	for _, e := range f.Enums {
+		enum := Enum{Name: e.Name, Doc: e.Doc, Pos: newPos(e.Pos), Annotations: newAnnotations(e.Annotations)}
+		for _, ef := range e.Fields {
+			item := EnumItem{Name: ef.Name, Doc: ef.Doc, Annotations: newAnnotations(ef.Annotations)}
+			switch {
+			case ef.IntVal != nil:
+				item.Value = *ef.IntVal
+			case ef.FloatVal != nil:
+				item.Value = *ef.FloatVal
+			case ef.StringVal != nil:
+				item.Value = *ef.StringVal
+			}
+			enum.Items = append(enum.Items, item)
+		}
+		ret.Enums = append(ret.Enums, enum)
+	}
+	for _, u := range f.Unions {
+		ret.Unions = append(
+			ret.Unions,
+			Union{Name: u.Name, Doc: u.Doc, Pos: newPos(u.Pos), Types: u.TypeNames, Annotations: newAnnotations(u.Annotations)},
+		)
+	}
+	for _, i := range f.Interfaces {
+		iface := Interface{Name: i.Name, Doc: i.Doc, Pos: newPos(i.Pos), Annotations: newAnnotations(i.Annotations)}
+		for _, fld := range i.Fields {
+			iface.Fields = append(iface.Fields, newField(fld))
+		}
+		ret.Interfaces = append(ret.Interfaces, iface)
+	}
+	return ret
+}
+
+func newField(f *gen.Field) Field {
+	ret := Field{
+		Name:        f.Name,
+		TypeName:    f.Type.String(),
+		Type:        newTypeRef(f.Type),
+		Doc:         f.Doc,
+		Pos:         newPos(f.Pos),
+		Annotations: newAnnotations(f.Annotations),
+		Hooks:       newEntryHooks(f.Modifiers),
+	}
+	for _, m := range f.Modifiers {
+		if m.AttrModifier != "" {
+			ret.AttrModifiers = append(ret.AttrModifiers, m.AttrModifier)
+		}
+	}
+	return ret
+}
+
+func newTypeRef(t *gen.TypeRef) *TypeRef {
+	if t == nil {
+		return nil
+	}
+	ret := &TypeRef{Name: t.Type, Array: newTypeRef(t.Array), NonNullable: t.NonNullable, Ref: t.Ref}
+	if t.Map != nil {
+		ret.Map = &MapType{Key: t.Map.KeyType, Value: newTypeRef(t.Map.ValueType)}
+	}
+	return ret
+}
+
+// newAnnotations returns annotations sorted by name
+func newAnnotations(anns gen.Annotations) []Annotation {
+	ret := make([]Annotation, 0, len(anns))
+	for _, ann := range anns {
+		ret = append(ret, newAnnotation(ann))
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+func newAnnotation(ann *gen.Annotation) Annotation {
+	ret := Annotation{Name: ann.Name, Pos: newPos(ann.Pos)}
+	for _, tag := range ann.Values {
+		t := Tag{Key: tag.Key}
+		if v := tag.Value; v != nil {
+			switch {
+			case v.String != nil:
+				t.Value = *v.String
+			case v.Bool != nil:
+				t.Value = bool(*v.Bool)
+			case v.Number != nil:
+				t.Value = *v.Number
+			}
+		}
+		ret.Values = append(ret.Values, t)
+	}
+	return ret
+}
+
+func newEntryHooks(mods []*gen.EntryModifier) (ret []Hook) {
+	for _, m := range mods {
+		if m.Hook != nil {
+			ret = append(ret, newHook(m.Hook))
+		}
+	}
+	return
+}
+
+func newHook(h *gen.Hook) Hook {
+	return Hook{Key: h.Key, Spec: h.Spec, Value: h.Value}
+}
+
+func newPos(pos lexer.Position) Pos {
+	return Pos{File: pos.Filename, Line: pos.Line, Column: pos.Column}
+}
+
+// newItem describes item annotation is applied to (see gen.Generator.CheckAnnotation)
+func newItem(item interface{}) Item {
+	switch v := item.(type) {
+	case *gen.File:
+		return Item{Kind: ItemFile, Name: v.Name, Pos: newPos(v.Pos)}
+	case *gen.Entity:
+		return Item{Kind: ItemEntity, Name: v.Name, Pos: newPos(v.Pos)}
+	case *gen.Field:
+		ret := Item{Kind: ItemField, Name: v.Name, Pos: newPos(v.Pos)}
+		if p := v.Parent(); p != nil {
+			ret.Parent = p.Name
+		}
+		return ret
+	case *gen.Method:
+		ret := Item{Kind: ItemMethod, Name: v.Name, Pos: newPos(v.Pos)}
+		if p := v.Parent(); p != nil {
+			ret.Parent = p.Name
+		}
+		return ret
+	case *gen.Enum:
+		return Item{Kind: ItemEnum, Name: v.Name, Pos: newPos(v.Pos)}
+	case *gen.EnumField:
+		ret := Item{Kind: ItemEnumField, Name: v.Name, Pos: newPos(v.Pos)}
+		if v.Parent != nil {
+			ret.Parent = v.Parent.Name
+		}
+		return ret
+	case *gen.Union:
+		return Item{Kind: ItemUnion, Name: v.Name, Pos: newPos(v.Pos)}
+	case *gen.Interface:
+		return Item{Kind: ItemInterface, Name: v.Name, Pos: newPos(v.Pos)}
+	}
+	return Item{}
+}
@@ -0,0 +1,250 @@
+// Package external runs generator plugins as separate processes, so plugins may be written in any language
+// and versioned independently of vivgen.
+//
+// vivgen talks to plugin with JSON messages over plugin's stdin/stdout, one message per line:
+// request {"id":1,"method":"initialize","params":{...}} is answered with {"id":1,"result":{...}}
+// or {"id":1,"error":"message"}. Plugin's stderr is passed through to stderr of vivgen.
+//
+// Methods:
+//   - initialize (InitializeParams -> InitializeResult): the first request; plugin answers with version of protocol
+//     it implements and annotations it handles;
+//   - checkAnnotation (CheckAnnotationParams -> CheckAnnotationResult): sent for every usage of annotation
+//     listed by plugin;
+//   - generate (GenerateParams -> GenerateResult): sent once with the whole model; plugin returns files to write;
+//   - shutdown (no params, null result): the last request; plugin should exit after answering it
+//     (or when its stdin is closed).
+//
+// Plugins are declared in vivgen config with command (and optional args and options):
+//
+//	plugins:
+//	  - name: summary
+//	    command: ./tools/summary-plugin
+//	    args: [--verbose]
+//	    options:
+//	      title: Types
+package external
+
+import "encoding/json"
+
+// ProtocolVersion is version of the protocol; it is increased on incompatible changes
+const ProtocolVersion = 1
+
+// protocol methods
+const (
+	MethodInitialize      = "initialize"
+	MethodCheckAnnotation = "checkAnnotation"
+	MethodGenerate        = "generate"
+	MethodShutdown        = "shutdown"
+)
+
+// kinds of items annotations are applied to
+const (
+	ItemFile      = "file"
+	ItemEntity    = "entity"
+	ItemField     = "field"
+	ItemMethod    = "method"
+	ItemEnum      = "enum"
+	ItemEnumField = "enumField"
+	ItemUnion     = "union"
+	ItemInterface = "interface"
+)
+
+// Request is message sent to plugin
+type Request struct {
+	ID     int             `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is answer of plugin for request with the same ID
+type Response struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type InitializeParams struct {
+	ProtocolVersion int `json:"protocolVersion"`
+	// Name - name of plugin in config
+	Name string `json:"name"`
+	// Options - plugin options from config
+	Options any `json:"options,omitempty"`
+}
+
+type InitializeResult struct {
+	ProtocolVersion int `json:"protocolVersion"`
+	// Annotations - names of annotations plugin handles (without tag part);
+	//  checkAnnotation is sent only for them
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+type CheckAnnotationParams struct {
+	Package    string     `json:"package"`
+	Annotation Annotation `json:"annotation"`
+	Item       Item       `json:"item"`
+}
+
+type CheckAnnotationResult struct {
+	// Accepted - plugin understands the annotation
+	Accepted bool `json:"accepted"`
+	// Error - annotation is understood but contains errors
+	Error string `json:"error,omitempty"`
+}
+
+type GenerateParams struct {
+	Project Project `json:"project"`
+}
+
+type GenerateResult struct {
+	Files    []File   `json:"files,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// File is file generated by plugin; Path is relative to output directory of project (absolute paths and paths
+// leading out of the output directory are rejected)
+type File struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// Item describes object annotation is applied to
+type Item struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Parent - name of type (enum) for field, method (enum field)
+	Parent string `json:"parent,omitempty"`
+	Pos    Pos    `json:"pos"`
+}
+
+// model of project
+
+type Project struct {
+	OutputDir       string    `json:"outputDir"`
+	ClientOutputDir string    `json:"clientOutputDir,omitempty"`
+	Packages        []Package `json:"packages"`
+}
+
+type Package struct {
+	Name  string       `json:"name"`
+	Files []SourceFile `json:"files"`
+}
+
+type SourceFile struct {
+	Name        string       `json:"name"`
+	Path        string       `json:"path"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+	Types       []Type       `json:"types,omitempty"`
+	Enums       []Enum       `json:"enums,omitempty"`
+	Unions      []Union      `json:"unions,omitempty"`
+	Interfaces  []Interface  `json:"interfaces,omitempty"`
+}
+
+type Pos struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+type Annotation struct {
+	Name   string `json:"name"`
+	Values []Tag  `json:"values,omitempty"`
+	Pos    Pos    `json:"pos"`
+}
+
+// Tag is annotation's tag; Value is string, bool, number or null (if value is not given)
+type Tag struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+type Hook struct {
+	Key   string `json:"key"`
+	Spec  string `json:"spec,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+type Type struct {
+	Name        string       `json:"name"`
+	Doc         string       `json:"doc,omitempty"`
+	Pos         Pos          `json:"pos"`
+	Modifiers   []string     `json:"modifiers,omitempty"`
+	Extends     string       `json:"extends,omitempty"`
+	Implements  []string     `json:"implements,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+	Hooks       []Hook       `json:"hooks,omitempty"`
+	Fields      []Field      `json:"fields,omitempty"`
+	Methods     []Method     `json:"methods,omitempty"`
+}
+
+type Field struct {
+	Name string `json:"name"`
+	// TypeName - type in DSL syntax (e.g. [Person]!)
+	TypeName      string       `json:"typeName"`
+	Type          *TypeRef     `json:"type"`
+	Doc           string       `json:"doc,omitempty"`
+	Pos           Pos          `json:"pos"`
+	AttrModifiers []string     `json:"attrModifiers,omitempty"`
+	Annotations   []Annotation `json:"annotations,omitempty"`
+	Hooks         []Hook       `json:"hooks,omitempty"`
+}
+
+type Method struct {
+	Name        string       `json:"name"`
+	Params      []Param      `json:"params,omitempty"`
+	Returns     *TypeRef     `json:"returns,omitempty"`
+	Doc         string       `json:"doc,omitempty"`
+	Pos         Pos          `json:"pos"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+	Hooks       []Hook       `json:"hooks,omitempty"`
+}
+
+type Param struct {
+	Name string   `json:"name"`
+	Type *TypeRef `json:"type"`
+}
+
+// TypeRef is reference to type: one of Name, Array or Map is set
+type TypeRef struct {
+	Name        string   `json:"name,omitempty"`
+	Array       *TypeRef `json:"array,omitempty"`
+	Map         *MapType `json:"map,omitempty"`
+	NonNullable bool     `json:"nonNullable,omitempty"`
+	Ref         bool     `json:"ref,omitempty"`
+}
+
+type MapType struct {
+	Key   string   `json:"key"`
+	Value *TypeRef `json:"value"`
+}
+
+type Enum struct {
+	Name        string       `json:"name"`
+	Doc         string       `json:"doc,omitempty"`
+	Pos         Pos          `json:"pos"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+	Items       []EnumItem   `json:"items"`
+}
+
+// EnumItem is enum's field; Value is int, float, string or null (if value is not given)
+type EnumItem struct {
+	Name        string       `json:"name"`
+	Value       any          `json:"value"`
+	Doc         string       `json:"doc,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+type Union struct {
+	Name        string       `json:"name"`
+	Doc         string       `json:"doc,omitempty"`
+	Pos         Pos          `json:"pos"`
+	Types       []string     `json:"types"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+type Interface struct {
+	Name        string       `json:"name"`
+	Doc         string       `json:"doc,omitempty"`
+	Pos         Pos          `json:"pos"`
+	Fields      []Field      `json:"fields,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
@@ -0,0 +1,453 @@
+package gen
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+// formatIndent is indentation of the declaration body in canonical form
+const formatIndent = "  "
+
+// canonical order of type modifiers
+var formatTypeModifiersOrder = []TypeModifier{
+	TypeModifierExternal,
+	TypeModifierAbstract,
+	TypeModifierExtendable,
+	TypeModifierDictionary,
+	TypeModifierConfig,
+	TypeModifierSingleton,
+	TypeModifierEmbeddable,
+	TypeModifierTransient,
+}
+
+var formatIdentRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z_0-9]*$`)
+
+// Format returns DSL source in canonical form:
+//   - declarations are separated with one empty line and keep their order;
+//   - hooks and annotations of declarations are placed on separate lines before it (hooks first, then annotations
+//     sorted by name; order relative to 'use' is kept as annotations after 'use' override mixin's ones);
+//   - type modifiers are placed before the keyword in the fixed order;
+//   - modifiers of entries are placed in order: attribute modifiers, hooks, annotations sorted by name;
+//   - comments are kept; empty lines inside declarations are collapsed to one.
+//
+// Imports and mixins are not resolved, so every file may be formatted separately.
+func Format(name string, src []byte) ([]byte, error) {
+	ast := &File{}
+	err := parser.Parse(sourceReader{Reader: bytes.NewReader(src), name: name}, ast)
+	if err != nil {
+		return nil, err
+	}
+	f := &formatter{src: src}
+	err = f.lex(name)
+	if err != nil {
+		return nil, err
+	}
+	f.file(ast)
+	return f.out.Bytes(), nil
+}
+
+type formatter struct {
+	src      []byte
+	tokens   []lexer.Token
+	comments []*formatComment
+	out      bytes.Buffer
+	indent   int
+	// lastLine - source line of the last written line
+	lastLine int
+	// blockStart - nothing was written in current block yet
+	blockStart bool
+	// separate - empty line should be written before the next line
+	separate bool
+}
+
+type formatComment struct {
+	line int
+	text string
+	// trailing - there are tokens before comment on the same line
+	trailing bool
+	written  bool
+}
+
+func (f *formatter) lex(name string) error {
+	l, err := lex.Lex(sourceReader{Reader: bytes.NewReader(f.src), name: name})
+	if err != nil {
+		return err
+	}
+	symbols := lex.Symbols()
+	comment := symbols["Comment"]
+	whitespace := symbols["Whitespace"]
+	lastTokenLine := 0
+	for {
+		t, err := l.Next()
+		if err != nil {
+			return err
+		}
+		if t.EOF() {
+			return nil
+		}
+		switch t.Type {
+		case whitespace:
+		case comment:
+			f.comments = append(
+				f.comments,
+				&formatComment{line: t.Pos.Line, text: strings.TrimRight(t.Value, " \t\r"), trailing: lastTokenLine == t.Pos.Line},
+			)
+		default:
+			f.tokens = append(f.tokens, t)
+			lastTokenLine = t.Pos.Line
+		}
+	}
+}
+
+// keywordLine returns line of the first keyword token found after offset
+func (f *formatter) keywordLine(keyword string, offset int) int {
+	for _, t := range f.tokens {
+		if t.Pos.Offset >= offset && t.Value == keyword {
+			return t.Pos.Line
+		}
+	}
+	return 0
+}
+
+// closingLine returns line of the brace that closes the first block opened after offset
+func (f *formatter) closingLine(offset int) int {
+	depth := 0
+	for _, t := range f.tokens {
+		if t.Pos.Offset < offset {
+			continue
+		}
+		switch t.Value {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth == 0 {
+				return t.Pos.Line
+			}
+		}
+	}
+	return 0
+}
+
+// flushComments writes comments that are placed before line
+func (f *formatter) flushComments(line int) {
+	for _, c := range f.comments {
+		if c.line >= line {
+			break
+		}
+		if !c.written {
+			c.written = true
+			f.write(c.line, c.text)
+		}
+	}
+}
+
+// line writes line that corresponds to source line (with comments placed before it and trailing comment)
+func (f *formatter) line(line int, text string) {
+	f.flushComments(line)
+	f.write(line, text)
+}
+
+func (f *formatter) write(line int, text string) {
+	if f.out.Len() > 0 && (f.separate || !f.blockStart && line > f.lastLine+1 && f.lastLine > 0) {
+		f.out.WriteByte('\n')
+	}
+	f.separate = false
+	f.blockStart = false
+	f.out.WriteString(strings.Repeat(formatIndent, f.indent))
+	f.out.WriteString(text)
+	for _, c := range f.comments {
+		if c.line == line && c.trailing && !c.written {
+			c.written = true
+			f.out.WriteString(" ")
+			f.out.WriteString(c.text)
+		}
+	}
+	f.out.WriteByte('\n')
+	if line > f.lastLine {
+		f.lastLine = line
+	}
+}
+
+func (f *formatter) openBlock(line int, header string) {
+	f.line(line, header+" {")
+	f.indent++
+	f.blockStart = true
+}
+
+func (f *formatter) closeBlock(line int) {
+	f.flushComments(line)
+	f.indent--
+	f.blockStart = true
+	f.write(line, "}")
+}
+
+func (f *formatter) file(ast *File) {
+	var pms []*EntityModifier
+	for _, pm := range ast.Modifiers {
+		pms = append(pms, &EntityModifier{Pos: pm.Pos, Hook: pm.Hook, Annotation: pm.Annotation})
+	}
+	f.modifiers(pms)
+	if ast.Package != "" {
+		f.line(f.keywordLine("package", 0), "package "+ast.Package+";")
+	}
+	type declaration struct {
+		offset int
+		format func()
+	}
+	var decls []declaration
+	for _, imp := range ast.Imports {
+		imp := imp
+		decls = append(decls, declaration{imp.Pos.Offset, func() { f.line(imp.Pos.Line, "import "+strconv.Quote(imp.Path)+";") }})
+	}
+	for _, m := range ast.Mixins {
+		decls = append(decls, declaration{m.Pos.Offset, f.mixinFormatter(m)})
+	}
+	for _, m := range ast.Meta {
+		decls = append(decls, declaration{m.Pos.Offset, f.metaFormatter(m)})
+	}
+	for _, e := range ast.Entries {
+		decls = append(decls, declaration{e.Pos.Offset, f.entityFormatter(e)})
+	}
+	for _, e := range ast.Enums {
+		decls = append(decls, declaration{e.Pos.Offset, f.enumFormatter(e)})
+	}
+	for _, u := range ast.Unions {
+		decls = append(decls, declaration{u.Pos.Offset, f.unionFormatter(u)})
+	}
+	for _, i := range ast.Interfaces {
+		decls = append(decls, declaration{i.Pos.Offset, f.interfaceFormatter(i)})
+	}
+	sort.SliceStable(decls, func(i, j int) bool { return decls[i].offset < decls[j].offset })
+	for i, d := range decls {
+		// imports are kept together
+		if i == 0 || !isImportAt(ast, decls[i-1].offset) || !isImportAt(ast, d.offset) {
+			f.separate = true
+		}
+		d.format()
+	}
+	f.flushComments(int(^uint(0) >> 1))
+}
+
+func isImportAt(ast *File, offset int) bool {
+	for _, imp := range ast.Imports {
+		if imp.Pos.Offset == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// modifiers writes hooks, annotations and uses on separate lines and returns type modifiers
+func (f *formatter) modifiers(mods []*EntityModifier) (typeModifiers []string) {
+	var segment []*EntityModifier
+	flush := func() {
+		sort.SliceStable(
+			segment,
+			func(i, j int) bool {
+				a, b := segment[i], segment[j]
+				if (a.Hook != nil) != (b.Hook != nil) {
+					return a.Hook != nil
+				}
+				return a.Annotation != nil && b.Annotation != nil && a.Annotation.Name < b.Annotation.Name
+			},
+		)
+		for _, m := range segment {
+			if m.Hook != nil {
+				f.line(m.Pos.Line, formatHook(m.Hook))
+			} else {
+				f.line(m.Pos.Line, formatAnnotation(m.Annotation))
+			}
+		}
+		segment = nil
+	}
+	used := map[TypeModifier]bool{}
+	for _, m := range mods {
+		switch {
+		case m.TypeModifier != nil:
+			used[m.TypeModifier.Modifier] = true
+		case m.Use != "":
+			flush()
+			f.line(m.Pos.Line, "use "+m.Use)
+		default:
+			segment = append(segment, m)
+		}
+	}
+	flush()
+	for _, tm := range formatTypeModifiersOrder {
+		if used[tm] {
+			typeModifiers = append(typeModifiers, string(tm))
+		}
+	}
+	return
+}
+
+func (f *formatter) entityFormatter(e *Entity) func() {
+	return func() {
+		header := f.modifiers(e.Modifiers)
+		header = append(header, "type", e.Name)
+		if e.BaseTypeName != "" {
+			header = append(header, "extends", e.BaseTypeName)
+		}
+		if len(e.Implements) > 0 {
+			header = append(header, "implements", strings.Join(e.Implements, ", "))
+		}
+		f.openBlock(f.keywordLine("type", e.Pos.Offset), strings.Join(header, " "))
+		f.entries(e.Entries)
+		if e.Incomplete {
+			f.line(f.keywordLine("...", e.Pos.Offset), "...")
+		}
+		f.closeBlock(f.closingLine(e.Pos.Offset))
+	}
+}
+
+func (f *formatter) interfaceFormatter(i *Interface) func() {
+	return func() {
+		header := f.modifiers(i.Modifiers)
+		header = append(header, "interface", i.Name)
+		f.openBlock(f.keywordLine("interface", i.Pos.Offset), strings.Join(header, " "))
+		f.entries(i.Entries)
+		f.closeBlock(f.closingLine(i.Pos.Offset))
+	}
+}
+
+func (f *formatter) entries(entries []*Entry) {
+	for _, e := range entries {
+		var text string
+		if e.Field != nil {
+			text = e.Field.Name + ": " + e.Field.Type.String()
+		} else if e.Method != nil {
+			params := make([]string, len(e.Method.Params))
+			for i, p := range e.Method.Params {
+				params[i] = p.Name + ": " + p.Type.String()
+			}
+			text = e.Method.Name + "(" + strings.Join(params, ", ") + ")"
+			if e.Method.RetValue != nil {
+				text += ": " + e.Method.RetValue.String()
+			}
+		}
+		f.line(e.Pos.Line, text+formatEntryModifiers(e.Modifiers)+";")
+	}
+}
+
+func (f *formatter) enumFormatter(e *Enum) func() {
+	return func() {
+		header := f.modifiers(e.Modifiers)
+		header = append(header, "enum", e.Name)
+		f.openBlock(f.keywordLine("enum", e.Pos.Offset), strings.Join(header, " "))
+		for _, ef := range e.Fields {
+			text := ef.Name
+			switch {
+			case ef.IntVal != nil:
+				text += " = " + strconv.Itoa(*ef.IntVal)
+			case ef.FloatVal != nil:
+				text += " = " + strconv.FormatFloat(*ef.FloatVal, 'f', -1, 64)
+			case ef.StringVal != nil:
+				text += " = " + strconv.Quote(*ef.StringVal)
+			}
+			f.line(ef.Pos.Line, text+formatEntryModifiers(ef.Modifiers)+";")
+		}
+		f.closeBlock(f.closingLine(e.Pos.Offset))
+	}
+}
+
+func (f *formatter) unionFormatter(u *Union) func() {
+	return func() {
+		header := f.modifiers(u.Modifiers)
+		header = append(header, "union", u.Name, "=", strings.Join(u.TypeNames, " | "))
+		f.line(f.keywordLine("union", u.Pos.Offset), strings.Join(header, " ")+";")
+	}
+}
+
+func (f *formatter) mixinFormatter(m *Mixin) func() {
+	return func() {
+		f.openBlock(m.Pos.Line, "mixin "+m.Name)
+		if typeModifiers := f.modifiers(m.Modifiers); len(typeModifiers) > 0 {
+			f.line(m.Pos.Line, strings.Join(typeModifiers, " "))
+		}
+		f.closeBlock(f.closingLine(m.Pos.Offset))
+	}
+}
+
+// metaFormatter writes meta as is: lines of meta have their own syntax
+func (f *formatter) metaFormatter(m *Meta) func() {
+	return func() {
+		lines := strings.Split(string(f.src[m.Pos.Offset:]), "\n")
+		f.line(m.Pos.Line, strings.TrimRight(lines[0], " \t\r"))
+		for i := 1; i < len(lines) && len(m.Lines) > 0; i++ {
+			if !strings.HasPrefix(lines[i], "#") && !strings.HasPrefix(lines[i], "\t") {
+				break
+			}
+			f.out.WriteString(strings.TrimRight(lines[i], " \t\r"))
+			f.out.WriteByte('\n')
+			f.lastLine = m.Pos.Line + i
+		}
+	}
+}
+
+func formatEntryModifiers(mods []*EntryModifier) string {
+	if len(mods) == 0 {
+		return ""
+	}
+	var attrs, hooks, anns []string
+	sorted := append([]*EntryModifier{}, mods...)
+	sort.SliceStable(
+		sorted,
+		func(i, j int) bool {
+			return sorted[i].Annotation != nil && sorted[j].Annotation != nil && sorted[i].Annotation.Name < sorted[j].Annotation.Name
+		},
+	)
+	for _, m := range sorted {
+		switch {
+		case m.AttrModifier != "":
+			attrs = append(attrs, m.AttrModifier)
+		case m.Hook != nil:
+			hooks = append(hooks, formatHook(m.Hook))
+		case m.Annotation != nil:
+			anns = append(anns, formatAnnotation(m.Annotation))
+		}
+	}
+	return " <" + strings.Join(append(append(attrs, hooks...), anns...), " ") + ">"
+}
+
+func formatHook(h *Hook) string {
+	ret := "@" + h.Key
+	if h.Spec != "" {
+		ret += ":" + h.Spec
+	}
+	if h.Value != "" {
+		ret += "=" + strconv.Quote(h.Value)
+	}
+	return ret
+}
+
+func formatAnnotation(a *Annotation) string {
+	ret := "$" + a.Name
+	if len(a.Values) == 0 {
+		return ret
+	}
+	tags := make([]string, len(a.Values))
+	for i, t := range a.Values {
+		tag := t.Key
+		if !formatIdentRE.MatchString(tag) {
+			tag = strconv.Quote(tag)
+		}
+		if v := t.Value; v != nil {
+			switch {
+			case v.String != nil:
+				tag += "=" + strconv.Quote(*v.String)
+			case v.Bool != nil:
+				tag += "=" + strconv.FormatBool(bool(*v.Bool))
+			case v.Number != nil:
+				tag += "=" + strconv.FormatFloat(*v.Number, 'f', -1, 64)
+			}
+		}
+		tags[i] = tag
+	}
+	return ret + "(" + strings.Join(tags, " ") + ")"
+}
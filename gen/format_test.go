@@ -0,0 +1,262 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/alecthomas/participle/lexer"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "comments",
+			src: `// file comment
+package shop; // trailing package comment
+/// Order is an order
+/// of customer
+type Order {
+  // leading field comment
+  orderID: int <id>; // trailing field comment
+
+
+  name: string;
+  // dangling comment
+}
+`,
+			want: `// file comment
+package shop; // trailing package comment
+
+/// Order is an order
+/// of customer
+type Order {
+  // leading field comment
+  orderID: int <id>; // trailing field comment
+
+  name: string;
+  // dangling comment
+}
+`,
+		},
+		{
+			name: "modifiers order",
+			src: `package shop;
+$mongo(name="orders") @change singleton $gql(skip) config type Order {
+  name: string <$gql(skip) @resolve $mongo(index) calculated>;
+  total(a: int, b: string): int <$mongo(skip) @resolve>;
+}
+enum Status { Active = "A"; Closed = "C" <$gql(name="closed")>; }
+union Item = Order | Other;
+`,
+			want: `package shop;
+
+@change
+$gql(skip)
+$mongo(name="orders")
+config singleton type Order {
+  name: string <calculated @resolve $gql(skip) $mongo(index)>;
+  total(a: int, b: string): int <@resolve $mongo(skip)>;
+}
+
+enum Status {
+  Active = "A";
+  Closed = "C" <$gql(name="closed")>;
+}
+
+union Item = Order | Other;
+`,
+		},
+		{
+			name: "imports and mixins",
+			src: `package shop;
+import "b.vvf";
+import "c.vvf";
+mixin Stored { $mongo(name="x") singleton }
+$gql(skip) use Stored $mongo(name="orders") type Order {
+  orderID: int <id>;
+}
+interface Named { name: string; }
+`,
+			want: `package shop;
+
+import "b.vvf";
+import "c.vvf";
+
+mixin Stored {
+  $mongo(name="x")
+  singleton
+}
+
+$gql(skip)
+use Stored
+$mongo(name="orders")
+type Order {
+  orderID: int <id>;
+}
+
+interface Named {
+  name: string;
+}
+`,
+		},
+		{
+			name: "spaces and tags",
+			src: `package   shop ;
+   @time:job="0 * * * *"   $gql( readonly=true "odd-key"=15 name="n" )
+transient  type Job{
+    count :int<auto   id> ;   
+  ...
+}
+// between declarations
+
+// end of file
+`,
+			want: `package shop;
+
+@time:job="0 * * * *"
+$gql(readonly=true "odd-key"=15 name="n")
+transient type Job {
+  count: int <auto id>;
+  ...
+}
+// between declarations
+
+// end of file
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := Format("test.vvf", []byte(tt.src))
+				if err != nil {
+					t.Fatalf("Format: %v", err)
+				}
+				if string(got) != tt.want {
+					t.Errorf("unexpected result:\n%s\nwant:\n%s", got, tt.want)
+				}
+				again, err := Format("test.vvf", got)
+				if err != nil {
+					t.Fatalf("Format of formatted: %v", err)
+				}
+				if !bytes.Equal(again, got) {
+					t.Errorf("Format is not idempotent:\n%s\nsecond pass:\n%s", got, again)
+				}
+				if src, res := formatTestAST(t, []byte(tt.src)), formatTestAST(t, got); src != res {
+					t.Errorf("formatted source is not equivalent to original:\n%s\nformatted:\n%s", src, res)
+				}
+			},
+		)
+	}
+}
+
+func TestFormatInvalid(t *testing.T) {
+	if _, err := Format("test.vvf", []byte("package shop;\ntype Order {\n  name string;\n}\n")); err == nil {
+		t.Error("error expected for invalid source")
+	}
+}
+
+// formatTestAST returns parsed source with doc comments in the form that does not depend on positions and on
+// order of modifiers that Format may change
+func formatTestAST(t *testing.T, src []byte) string {
+	t.Helper()
+	ast := &File{}
+	if err := parser.Parse(bytes.NewReader(src), ast); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	docs, err := docComments(bytes.NewReader(src))
+	if err != nil {
+		t.Fatalf("doc comments: %v", err)
+	}
+	var docValues []string
+	for _, doc := range docs {
+		docValues = append(docValues, doc)
+	}
+	sort.Strings(docValues)
+	normalizeFormatTestAST(reflect.ValueOf(ast))
+	data, err := json.MarshalIndent(struct {
+		AST  *File
+		Docs []string
+	}{ast, docValues}, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+var (
+	positionType = reflect.TypeOf(lexer.Position{})
+	// modifierTypes - types of modifiers that Format may reorder
+	modifierTypes = map[reflect.Type]bool{
+		reflect.TypeOf(&EntityModifier{}):  true,
+		reflect.TypeOf(&EntryModifier{}):   true,
+		reflect.TypeOf(&PackageModifier{}): true,
+	}
+)
+
+// normalizeFormatTestAST clears positions and sorts modifiers; 'use' splits modifiers of type into groups
+// that are sorted separately as annotations after 'use' override the ones of mixin; type modifiers are flags,
+// so they are moved before all the groups
+func normalizeFormatTestAST(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			normalizeFormatTestAST(v.Elem())
+		}
+	case reflect.Struct:
+		if v.Type() == positionType {
+			v.Set(reflect.Zero(positionType))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				normalizeFormatTestAST(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		type item struct {
+			group int
+			key   string
+			value reflect.Value
+		}
+		items := make([]item, v.Len())
+		group := 0
+		for i := range items {
+			el := v.Index(i)
+			normalizeFormatTestAST(el)
+			data, _ := json.Marshal(el.Interface())
+			items[i] = item{group, string(data), reflect.ValueOf(el.Interface())}
+			if m, ok := el.Interface().(*EntityModifier); ok {
+				switch {
+				case m.Use != "":
+					group++
+					items[i] = item{group, "", items[i].value}
+				case m.TypeModifier != nil:
+					items[i].group = -1
+				}
+			}
+		}
+		if !modifierTypes[v.Type().Elem()] {
+			return
+		}
+		sort.SliceStable(
+			items,
+			func(i, j int) bool {
+				if items[i].group != items[j].group {
+					return items[i].group < items[j].group
+				}
+				return items[i].key < items[j].key
+			},
+		)
+		for i, it := range items {
+			v.Index(i).Set(it.value)
+		}
+	}
+}
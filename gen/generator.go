@@ -1,9 +1,10 @@
 package gen
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
-	"os"
+	"io"
 	"path/filepath"
 	"reflect"
 	"regexp"
@@ -79,6 +80,7 @@ type Opts struct {
 	ClientOutputDir     string
 	PackagePrefix       string
 	ExtendableTypeDescr ExtendableTypeDescriptorBehaviour
+	SourcePositions     SourcePositionsMode
 
 	Custom map[string]interface{}
 }
@@ -91,6 +93,8 @@ type DefinedType struct {
 	external    bool
 	entry       *Entity
 	enum        *Enum
+	union       *Union
+	iface       *Interface
 }
 
 type Project struct {
@@ -107,6 +111,15 @@ type Project struct {
 	Errors            []error
 	hooks             []GeneratorHookHolder
 	stage             GenerationStage
+	// errorHandler - handler of errors found by Check (see Package.report); nil while generating
+	errorHandler func(pckg *Package, err error)
+	// filesWritten and filesUnchanged - statistics of WriteFile
+	filesWritten   int
+	filesUnchanged int
+	// memory - files generated by GenerateToMemory
+	memory map[string][]byte
+	// annotationSchemas - schemas annotations are validated against (collected on the first check)
+	annotationSchemas []AnnotationSchema
 }
 
 type EngineDescriptor struct {
@@ -121,13 +134,26 @@ type EngineDescriptor struct {
 	prepAdd        *jen.Statement
 }
 
-var plugins = map[string]Generator{}
+// plugins - factories of registered plugins by name
+var plugins = map[string]func() Generator{}
 
+// RegisterPlugin registers plugin; the same instance is used by all the projects,
+// so plugins that keep state of generation should be registered with RegisterPluginFactory
 func RegisterPlugin(plugin Generator) {
-	if _, ok := plugins[plugin.Name()]; ok {
-		panic(fmt.Sprintf("duplicate plugin name: %s", plugin.Name()))
+	registerPlugin(plugin.Name(), func() Generator { return plugin })
+}
+
+// RegisterPluginFactory registers plugin created by factory; every project gets its own instance of the plugin,
+// so state of generation is not shared between projects (e.g. of language server or watch mode)
+func RegisterPluginFactory(factory func() Generator) {
+	registerPlugin(factory().Name(), factory)
+}
+
+func registerPlugin(name string, factory func() Generator) {
+	if _, ok := plugins[name]; ok {
+		panic(fmt.Sprintf("duplicate plugin name: %s", name))
 	}
-	plugins[plugin.Name()] = plugin
+	plugins[name] = factory
 }
 
 // New creates new Project object
@@ -247,6 +273,17 @@ func (o *Opts) FromAny(options any) error {
 				default:
 					break nameCase
 				}
+			case "SourcePositions", "source_positions", "source-positions":
+				switch opt {
+				case "none":
+					val = NoSourcePositions
+				case "comment", "comments":
+					val = SourcePositionsComments
+				case "line", "lines":
+					val = SourcePositionsLineDirectives
+				default:
+					break nameCase
+				}
 			case "CodeGenerator", "code-generator", "code_generator":
 				setCodeGeneratorOptions := func(opts map[string]any) {
 					if o.Custom == nil {
@@ -307,6 +344,8 @@ func (o *Opts) With(opts ...interface{}) *Opts {
 			o.OutputDir = string(opt)
 		case ClientOutputDirOption:
 			o.ClientOutputDir = string(opt)
+		case SourcePositionsMode:
+			o.SourcePositions = opt
 		default:
 			panic(fmt.Sprintf("undefined option: %#v (%T)", op, op))
 		}
@@ -341,11 +380,12 @@ func (p *Project) With(gen Generator) *Project {
 	return p
 }
 
-// WithPlugin adds registered plugin as generator
+// WithPlugin adds registered plugin as generator (see RegisterPluginFactory)
 func (p *Project) WithPlugin(name string, options interface{}) error {
-	if gen, ok := plugins[name]; ok {
+	if factory, ok := plugins[name]; ok {
+		gen := factory()
 		p.With(gen)
-		if os, ok := gen.(OptionsSetter); ok {
+		if os, ok := gen.(OptionsSetter); ok && options != nil {
 			// from viper options can come as array of maps...
 			if opts, ok := options.([]map[string]any); ok {
 				for _, opt := range opts {
@@ -366,6 +406,17 @@ func (p *Project) WithPlugin(name string, options interface{}) error {
 	return fmt.Errorf("plugin '%s' not found", name)
 }
 
+// closeGenerators releases resources of generators that implement io.Closer (e.g. processes of external plugins)
+func (p *Project) closeGenerators() {
+	for _, g := range p.generators {
+		if c, ok := g.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				p.AddWarning(fmt.Sprintf("while closing generator %s: %v", g.Name(), err))
+			}
+		}
+	}
+}
+
 // WithPluginMust adds registered plugin as generator; panics in case of error
 func (p *Project) WithPluginMust(name string, options interface{}) *Project {
 	err := p.WithPlugin(name, options)
@@ -491,9 +542,21 @@ func (p *Project) CallFeatureHookFunc(
 	panic(fmt.Sprintf("feature %s:%s is not a hook function", kind, name))
 }
 
-func (p *Project) Generate() (err error) {
+// prepareStages runs all the stages before generating;
+//
+//	if onError is nil the first error is returned; otherwise onError is called for every error found
+//	(stages go on with the next entity of the package) and the failed package is not processed at the next stages
+func (p *Project) prepareStages(onError func(pckg *Package, err error)) (err error) {
 	p.Options.OutputDir = filepath.FromSlash(p.Options.OutputDir)
+	p.errorHandler = onError
 	// desc.generators = []Generator{&CodeGenerator{}, &GQLGenerator{}}
+	err = p.resolvePlugins()
+	if err != nil {
+		if onError != nil {
+			p.AddError(err)
+		}
+		return
+	}
 	p.start()
 
 	for _, file := range p.Files {
@@ -504,40 +567,56 @@ func (p *Project) Generate() (err error) {
 		desc := p.GetPackage(pname)
 		desc.Files = append(desc.Files, file)
 	}
-	for _, pckg := range p.packages {
-		err = pckg.postParsed()
-		if err != nil {
-			return
+	failed := map[*Package]bool{}
+	runStage := func(stage GenerationStage, run func(pckg *Package) error) error {
+		p.stage = stage
+		for _, pckg := range p.packages {
+			if failed[pckg] {
+				continue
+			}
+			errorsCount := len(p.Errors)
+			err := run(pckg)
+			if err != nil {
+				if onError == nil {
+					return err
+				}
+				failed[pckg] = true
+				onError(pckg, err)
+			} else if onError != nil && len(p.Errors) > errorsCount {
+				failed[pckg] = true
+			}
 		}
+		return nil
 	}
-
-	p.stage = StageBeforePrepare
-	for _, pckg := range p.packages {
-		err = pckg.beforePrepare()
-		if err != nil {
-			return
-		}
+	err = runStage(StageParsing, (*Package).postParsed)
+	if err != nil {
+		return
 	}
-
-	p.stage = StagePrepare
-	for _, pckg := range p.packages {
-		err = pckg.prepare()
-		if err != nil {
-			return
-		}
+	err = runStage(StageBeforePrepare, (*Package).beforePrepare)
+	if err != nil {
+		return
 	}
+	err = runStage(StagePrepare, (*Package).prepare)
+	if err != nil {
+		return
+	}
+	return runStage(StageMetaProcessing, (*Package).processMetas)
+}
 
-	p.stage = StageMetaProcessing
-	for _, pckg := range p.packages {
-		err := pckg.processMetas()
-		if err != nil {
-			return err
-		}
+func (p *Project) Generate() (err error) {
+	defer p.closeGenerators()
+	err = p.prepareStages(nil)
+	if err != nil {
+		return
 	}
 
 	p.stage = StageGenerating
 	for _, pckg := range p.packages {
 		for _, file := range pckg.Files {
+			if !file.HasDeclarations() {
+				// e.g. file with mixins only
+				continue
+			}
 			bldr := &Builder{
 				File:       file,
 				JenFile:    jen.NewFile(pckg.Name),
@@ -672,21 +751,23 @@ func (p *Project) Print() {
 	}
 }
 
+// WriteToFiles writes generated Go files; files which content is not changed are not touched (see WriteFile);
+// stubs of hooks are written only if they do not exist yet
 func (p *Project) WriteToFiles() (err error) {
 	for _, desc := range p.packages {
 		for _, bldr := range desc.builders {
-			err = os.MkdirAll(filepath.Join(p.Options.OutputDir, desc.Name), os.ModeDir|os.ModePerm)
+			fname := filepath.Join(p.Options.OutputDir, desc.Name, bldr.File.Name+".go")
+			err = p.saveJenFile(bldr.JenFile, fname)
 			if err != nil {
 				return
 			}
-			fname := filepath.Join(p.Options.OutputDir, desc.Name, bldr.File.Name+".go")
-			err = bldr.JenFile.Save(fname)
+			err = p.writeHookStubs(bldr)
 			if err != nil {
-				return
+				return fmt.Errorf("while writing hooks stubs (%s): %w", bldr.File.FileName, err)
 			}
 		}
 		if !desc.engineless {
-			err := desc.Engine.file.Save(filepath.Join(p.Options.OutputDir, desc.Name, "engine.go"))
+			err := p.saveJenFile(desc.Engine.file, filepath.Join(p.Options.OutputDir, desc.Name, "engine.go"))
 			if err != nil {
 				return err
 			}
@@ -695,6 +776,25 @@ func (p *Project) WriteToFiles() (err error) {
 	return nil
 }
 
+// saveJenFile renders jen.File and writes it with WriteFile
+func (p *Project) saveJenFile(f *jen.File, name string) error {
+	out := p.CreateFile(name)
+	if p.Options.SourcePositions != SourcePositionsLineDirectives {
+		if err := f.Render(out); err != nil {
+			return err
+		}
+		return out.Close()
+	}
+	buf := &bytes.Buffer{}
+	if err := f.Render(buf); err != nil {
+		return err
+	}
+	if _, err := out.Write(restoreLinePositions(buf.Bytes(), name)); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
 func (desc *Package) Options() *Opts {
 	return desc.Project.Options
 }
@@ -895,6 +995,21 @@ func AnyToReflect(opt any, to reflect.Value) error {
 				}
 			}
 		}
+	case []interface{}:
+		// e.g. list of strings from config file
+		if to.Type().Kind() != reflect.Slice {
+			return fmt.Errorf("cannot set value from %v to %s", val, to.Type().Name())
+		}
+		slice := reflect.MakeSlice(to.Type(), 0, len(val))
+		for _, opt := range val {
+			elem := reflect.New(to.Type().Elem())
+			err := AnyToReflect(opt, elem)
+			if err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, reflect.Indirect(elem))
+		}
+		to.Set(slice)
 	default:
 		if to.CanSet() {
 			rv := reflect.ValueOf(val)
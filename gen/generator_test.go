@@ -0,0 +1,208 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testProject parses src as file test.vvf in temporary directory and creates project
+// that generates to that directory with given plugins
+func testProject(t *testing.T, src string, plugins ...string) *Project {
+	t.Helper()
+	return testProjectAt(t, t.TempDir(), "", src, plugins...)
+}
+
+// testProjectAt works like testProject but uses dir as input and output directory and prefix as package prefix
+func testProjectAt(t *testing.T, dir string, prefix string, src string, plugins ...string) *Project {
+	t.Helper()
+	opts := testOptions(dir)
+	if prefix != "" {
+		opts.With(PackagePrefixOption(prefix))
+	}
+	return newTestProject(t, opts, map[string]string{"test.vvf": src}, plugins...)
+}
+
+// testOptions returns options of test project that generates to dir
+func testOptions(dir string) *Opts {
+	return Options(dir).
+		With(NullablePointers).
+		WithCustom(CodeGeneratorOptionsName, map[string]any{"AllowEmbeddedArraysForDictionary": true})
+}
+
+// newTestProject parses sources (given by names relative to output dir) and creates project with given plugins
+func newTestProject(t *testing.T, opts *Opts, sources map[string]string, plugins ...string) *Project {
+	t.Helper()
+	var names []string
+	contents := map[string][]byte{}
+	for name, src := range sources {
+		name = filepath.Join(opts.OutputDir, name)
+		names = append(names, name)
+		contents[name] = []byte(src)
+	}
+	sort.Strings(names)
+	files, err := ParseSources(names, contents)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	proj := New(files, opts)
+	for _, pl := range plugins {
+		if err = proj.WithPlugin(pl, nil); err != nil {
+			t.Fatalf("plugin %s: %v", pl, err)
+		}
+	}
+	return proj
+}
+
+// generateTest generates src with given plugins in memory; returns generated files by path relative to output dir
+func generateTest(t *testing.T, src string, plugins ...string) map[string]string {
+	t.Helper()
+	proj := testProject(t, src, plugins...)
+	files, err := proj.GenerateToMemory()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	ret := map[string]string{}
+	for name, content := range files {
+		rel, err := filepath.Rel(proj.Options.OutputDir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret[filepath.ToSlash(rel)] = string(content)
+	}
+	return ret
+}
+
+// generateTestError generates src and returns error that should be returned
+func generateTestError(t *testing.T, src string, plugins ...string) error {
+	t.Helper()
+	_, err := testProject(t, src, plugins...).GenerateToMemory()
+	if err == nil {
+		t.Fatal("error expected")
+	}
+	return err
+}
+
+// assertContains checks that s contains all the substrings (sequences of white spaces are not significant)
+func assertContains(t *testing.T, s string, substrings ...string) {
+	t.Helper()
+	squeezed := strings.Join(strings.Fields(s), " ")
+	for _, sub := range substrings {
+		if !strings.Contains(squeezed, strings.Join(strings.Fields(sub), " ")) {
+			t.Errorf("%q not found in:\n%s", sub, s)
+		}
+	}
+}
+
+// assertGolden compares got with content of testdata/name; the file is rewritten if UPDATE_GOLDEN env is set
+func assertGolden(t *testing.T, name string, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (set UPDATE_GOLDEN=1 to create it): %v", err)
+	}
+	if string(want) != got {
+		t.Errorf("%s differs from golden file:\n%s", name, got)
+	}
+}
+
+// runGenerated generates src with given plugins into temporary directory and runs main with generated package pkg
+// (PREFIX in main is replaced with package prefix); test fails if main fails.
+// Generated files are compiled as if they were inside the module (with -overlay), so nothing is written to the source tree
+func runGenerated(t *testing.T, src string, plugins []string, pkg string, main string) {
+	t.Helper()
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not found")
+	}
+	dir := t.TempDir()
+	// virtual directory inside the module: generated package imports vivard runtime
+	virtual, err := filepath.Abs("zz_generated_" + pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := "github.com/vc2402/vivard/gen/" + filepath.Base(virtual)
+	files, err := testProjectAt(t, dir, prefix, src, plugins...).GenerateToMemory()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	files[filepath.Join(dir, "main", "main.go")] = []byte(strings.ReplaceAll(main, "PREFIX", prefix))
+	overlay := map[string]string{}
+	for name, content := range files {
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pkgDir := filepath.Dir(rel); (pkgDir != pkg && pkgDir != "main") || filepath.Ext(name) != ".go" {
+			continue
+		}
+		if err = os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(name, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		overlay[filepath.Join(virtual, rel)] = name
+	}
+	overlayFile := filepath.Join(dir, "overlay.json")
+	data, err := json.Marshal(map[string]any{"Replace": overlay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(overlayFile, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(goBin, "run", "-overlay", overlayFile, "./"+filepath.Base(virtual)+"/main")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated code failed: %v\n%s", err, out)
+	}
+}
+
+// TestPluginsNotShared checks that state of plugins is not shared by projects generated in the same process
+// (as language server and watch mode do)
+func TestPluginsNotShared(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.vvf")
+	generate := func(collection string) string {
+		src := fmt.Sprintf("package shop;\n$mongo(name=%q)\ntype Order {\n  orderID: int <id>;\n}\n", collection)
+		files, err := ParseSources([]string{name}, map[string][]byte{name: []byte(src)})
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		proj := New(files, Options(dir).WithCustom(CodeGeneratorOptionsName, map[string]any{"AllowEmbeddedArraysForDictionary": true}))
+		for _, pl := range []string{nocacheGeneratorName, mongoGeneratorName} {
+			if err = proj.WithPlugin(pl, nil); err != nil {
+				t.Fatalf("plugin %s: %v", pl, err)
+			}
+		}
+		if err = proj.Generate(); err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		if err = proj.WriteToFiles(); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "shop", "test.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	generate("first")
+	if src := generate("second"); !strings.Contains(src, `ColOrder = "second"`) {
+		t.Errorf("collection of the first project is used:\n%s", src)
+	}
+}
@@ -0,0 +1,655 @@
+package gen
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const (
+	goClientGeneratorName = "GoClient"
+	goClientOptionsName   = "go-client"
+	// goClientPackageSuffix - client package for package 'shop' is 'shopclient'
+	goClientPackageSuffix = "client"
+	goClientFileName      = "client.go"
+	goClientRuntime       = VivardPackage + "/gqlclient"
+	// goClientSelectionDepth - max depth of embedded types in selection sets
+	goClientSelectionDepth = 3
+)
+
+type GoClientOptions struct {
+	// OutputDir - directory for client packages; project's output directory is used by default
+	OutputDir string `json:"output_dir"`
+	// PackagePrefix - import path prefix for client packages; project's package prefix is used by default
+	PackagePrefix string `json:"package_prefix"`
+}
+
+// GoClientGenerator generates typed Go client package (<package>client) for GraphQL API generated by GQLGenerator:
+// structs for output and input types, method of Client for each query and mutation
+type GoClientGenerator struct {
+	proj            *Project
+	desc            *Package
+	options         GoClientOptions
+	clientGenerated map[string]bool
+}
+
+type goClientArg struct {
+	name    string
+	gqlType string
+	goType  jen.Code
+}
+
+type goClientOperation struct {
+	method    string
+	request   string
+	queryName string
+	args      []goClientArg
+	ret       jen.Code
+	selection string
+	doc       string
+}
+
+func init() {
+	RegisterPluginFactory(func() Generator { return &GoClientGenerator{} })
+}
+
+func (cg *GoClientGenerator) Name() string {
+	return goClientGeneratorName
+}
+
+// Requires from PluginsRequirer
+func (cg *GoClientGenerator) Requires() []string {
+	return []string{GQLGeneratorName}
+}
+
+func (cg *GoClientGenerator) SetOptions(opts any) error {
+	return OptionsAnyToStruct(opts, &cg.options)
+}
+
+func (cg *GoClientGenerator) SetDescriptor(proj *Project) {
+	cg.proj = proj
+	cg.clientGenerated = map[string]bool{}
+	if _, err := proj.Options.CustomToStruct(goClientOptionsName, &cg.options); err != nil {
+		proj.AddWarning(fmt.Sprintf("problem while setting custom options for %s: %v", goClientOptionsName, err))
+	}
+}
+
+func (cg *GoClientGenerator) CheckAnnotation(desc *Package, ann *Annotation, item interface{}) (bool, error) {
+	return false, nil
+}
+
+func (cg *GoClientGenerator) Prepare(desc *Package) error {
+	cg.desc = desc
+	return nil
+}
+
+func (cg *GoClientGenerator) Generate(b *Builder) (err error) {
+	cg.desc = b.Descriptor
+	pckg := b.Descriptor.Name
+	f := jen.NewFilePathName(cg.clientPackagePath(pckg), cg.clientPackageName(pckg))
+	f.HeaderComment(fmt.Sprintf("Code generated from file %s by vivgen. DO NOT EDIT.", b.File.FileName))
+
+	for _, e := range b.File.Enums {
+		cg.generateEnum(f, e)
+	}
+	var ops []goClientOperation
+	for _, e := range b.File.Entries {
+		if !cg.hasGQLType(e) {
+			continue
+		}
+		cg.generateTypes(f, e)
+		entityOps, err := cg.getOperations(e)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, entityOps...)
+	}
+	if len(ops) > 0 {
+		f.Const().DefsFunc(
+			func(g *jen.Group) {
+				for _, op := range ops {
+					g.Id(op.queryName + "Request").Op("=").Lit(cg.getRequest(op))
+				}
+			},
+		)
+		for _, op := range ops {
+			f.Add(cg.generateOperation(op))
+		}
+	}
+	if err = cg.proj.saveJenFile(f, filepath.Join(cg.clientOutputDir(pckg), b.File.Name+".go")); err != nil {
+		return
+	}
+	return cg.generateClient(pckg)
+}
+
+// generateClient generates Client type for the package (once)
+func (cg *GoClientGenerator) generateClient(pckg string) error {
+	if cg.clientGenerated[pckg] {
+		return nil
+	}
+	f := jen.NewFilePathName(cg.clientPackagePath(pckg), cg.clientPackageName(pckg))
+	f.HeaderComment("Code generated by vivgen. DO NOT EDIT.")
+	f.Comment(fmt.Sprintf("Client is typed client for GraphQL API of package %s", pckg))
+	f.Type().Id("Client").Struct(jen.Op("*").Qual(goClientRuntime, "Client"))
+	f.Line()
+	f.Comment("NewClient creates client for GraphQL endpoint url; options allow to set http.Client, headers etc.")
+	f.Func().Id("NewClient").Params(
+		jen.Id("url").String(),
+		jen.Id("opts").Op("...").Qual(goClientRuntime, "Option"),
+	).Op("*").Id("Client").Block(
+		jen.Return(
+			jen.Op("&").Id("Client").Values(
+				jen.Dict{jen.Id("Client"): jen.Qual(goClientRuntime, "New").Call(jen.Id("url"), jen.Id("opts").Op("..."))},
+			),
+		),
+	)
+	if err := cg.proj.saveJenFile(f, filepath.Join(cg.clientOutputDir(pckg), goClientFileName)); err != nil {
+		return err
+	}
+	cg.clientGenerated[pckg] = true
+	return nil
+}
+
+func (cg *GoClientGenerator) generateEnum(f *jen.File, e *Enum) {
+	f.Add(goDoc(withDeprecation(e.Doc, e.Annotations))).Type().Id(e.Name).Add(cg.clientType(&TypeRef{Type: e.AliasForType}, false, false))
+	if len(e.Fields) == 0 {
+		return
+	}
+	withIota := e.Fields[0].FloatVal == nil && e.Fields[0].IntVal == nil && e.Fields[0].StringVal == nil
+	f.Const().DefsFunc(
+		func(g *jen.Group) {
+			for i, field := range e.Fields {
+				expr := goDoc(withDeprecation(field.Doc, field.Annotations)).Id(field.Name)
+				if withIota {
+					if i == 0 {
+						expr.Id(e.Name).Op("=").Iota()
+					}
+				} else {
+					expr.Id(e.Name)
+					if field.IntVal != nil {
+						expr.Op("=").Lit(*field.IntVal)
+					} else if field.FloatVal != nil {
+						expr.Op("=").Lit(*field.FloatVal)
+					} else if field.StringVal != nil {
+						expr.Op("=").Lit(*field.StringVal)
+					}
+				}
+				g.Add(expr)
+			}
+		},
+	)
+}
+
+// generateTypes generates struct for output type and for input type of entity;
+// fields are exported (json tags keep GraphQL names) and optional values are pointers
+func (cg *GoClientGenerator) generateTypes(f *jen.File, e *Entity) {
+	var fields, inputFields []jen.Code
+	for _, fld := range e.GetFields(true, true) {
+		name, ok := fld.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag)
+		if !ok || fld.FB(FeaturesAPIKind, FCIgnore) {
+			continue
+		}
+		fieldName := cg.fieldName(fld)
+		asRef := fld.HasModifier(AttrModifierEmbeddedRef) || fld.FB(GQLFeatures, GQLFIDOnly)
+		doc := withDeprecation(fld.Doc, fld.Annotations)
+		t := cg.clientType(fld.Type, false, asRef)
+		tag := name
+		if !fld.Type.NonNullable {
+			tag += ",omitempty"
+			if cg.isValueType(fld.Type, asRef) {
+				t = jen.Op("*").Add(t)
+			}
+		}
+		fields = append(fields, goDoc(doc).Id(fieldName).Add(t).Tag(map[string]string{"json": tag}))
+		if fld.HasModifier(AttrModifierCalculated) || fld.IsPolymorphic() {
+			continue
+		}
+		t = cg.clientType(fld.Type, true, asRef)
+		if cg.isValueType(fld.Type, asRef) {
+			t = jen.Op("*").Add(t)
+		}
+		inputFields = append(inputFields, goDoc(doc).Id(fieldName).Add(t).Tag(map[string]string{"json": name + ",omitempty"}))
+		if setNull := fld.FS(GQLFeatures, GQLFSetNullInputField); setNull != "" {
+			inputFields = append(
+				inputFields,
+				jen.Id(fieldName+"SetNull").Bool().Tag(map[string]string{"json": setNull + ",omitempty"}),
+			)
+		}
+	}
+	f.Add(goDoc(withDeprecation(e.Doc, e.Annotations))).Type().Id(e.Name).Struct(fields...)
+	f.Line()
+	f.Comment(fmt.Sprintf("%sInput is input type for %s", e.Name, e.Name))
+	f.Type().Id(e.Name + "Input").Struct(inputFields...)
+	f.Line()
+}
+
+// fieldName returns exported name of struct field for fld: Go name of field (that may be set with annotation)
+// with the first letter in upper case
+func (cg *GoClientGenerator) fieldName(fld *Field) string {
+	name := fld.FS(FeatGoKind, FCGName)
+	if name == "" {
+		name = fld.Name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// getOperations returns queries and mutations generated for entity by GQLGenerator
+func (cg *GoClientGenerator) getOperations(e *Entity) (ops []goClientOperation, err error) {
+	isCfg := e.HasModifier(TypeModifierConfig)
+	ret := jen.Op("*").Add(cg.typeID(e.Pckg.Name, e.Name))
+	list := jen.Index().Op("*").Add(cg.typeID(e.Pckg.Name, e.Name))
+	selection := cg.selection(e, 0)
+	input := jen.Op("*").Add(cg.typeID(e.Pckg.Name, e.Name+"Input"))
+	inputType := e.FS(GQLFeatures, GQLFInputTypeName)
+	for i := GQLOperationGet; i < GQLOperationLast; i++ {
+		if !cg.hasOperation(e, i) {
+			continue
+		}
+		qn := e.FS(GQLFeatures, GQLOperationsAnnotationsTags[i])
+		op := goClientOperation{
+			method:    strings.ToUpper(qn[:1]) + qn[1:],
+			request:   "query",
+			queryName: qn,
+			ret:       ret,
+			selection: selection,
+		}
+		switch i {
+		case GQLOperationGet:
+			if !isCfg {
+				arg, err := cg.idArg(e)
+				if err != nil {
+					return nil, err
+				}
+				op.args = []goClientArg{arg}
+			}
+		case GQLOperationSet, GQLOperationCreate:
+			op.request = "mutation"
+			op.args = []goClientArg{{name: "val", gqlType: inputType, goType: input}}
+		case GQLOperationList:
+			op.ret = list
+			if e.FB(FeatureDictKind, FDQualified) {
+				if qt, ok := e.Features.GetEntity(FeatureDictKind, FDQualifierType); ok && qt.GetIdField() != nil {
+					qualIDType := qt.GetIdField().Type
+					op.args = []goClientArg{
+						{
+							name:    "quals",
+							gqlType: fmt.Sprintf("[%s]", cg.gqlScalarName(qualIDType.Type)),
+							goType:  jen.Index().Add(cg.clientType(&TypeRef{Type: qualIDType.Type}, true, false)),
+						},
+					}
+				}
+			}
+		case GQLOperationLookup:
+			op.ret = list
+			op.args = []goClientArg{{name: "query", gqlType: "String!", goType: jen.String()}}
+		case GQLOperationDelete:
+			op.request = "mutation"
+			op.ret = jen.Bool()
+			op.selection = ""
+			arg, err := cg.idArg(e)
+			if err != nil {
+				return nil, err
+			}
+			op.args = []goClientArg{arg}
+		case GQLOperationFind:
+			op.ret = list
+			it, _ := e.Features.GetEntity(FeaturesAPIKind, FAPIFindParamType)
+			op.args = []goClientArg{
+				{
+					name:    "query",
+					gqlType: it.FS(GQLFeatures, GQLFInputTypeName),
+					goType:  jen.Op("*").Add(cg.typeID(it.Pckg.Name, it.Name+"Input")),
+				},
+			}
+		case GQLOperationBulkCreate, GQLOperationBulkSet:
+			op.request = "mutation"
+			op.ret = list
+			op.args = []goClientArg{{name: "val", gqlType: "[" + inputType + "]", goType: jen.Index().Add(input)}}
+		}
+		op.doc = fmt.Sprintf("%s calls %s %s", op.method, qn, op.request)
+		ops = append(ops, op)
+	}
+
+	for _, m := range e.Methods {
+		qn := m.FS(GQLFeatures, GQLFMethodName)
+		if qn == "" {
+			continue
+		}
+		op := goClientOperation{
+			method:    e.Name + strings.ToUpper(m.Name[:1]) + m.Name[1:],
+			request:   m.FS(GQLFeatures, GQLFMethodType),
+			queryName: qn,
+			doc:       withDeprecation(m.Doc, m.Annotations),
+		}
+		if idfld := e.GetIdField(); idfld != nil {
+			if idt, ok := idfld.Features.GetString(GQLFeatures, GQLFTypeTag); ok {
+				op.args = append(op.args, goClientArg{name: "id", gqlType: idt, goType: cg.clientType(idfld.Type, true, false)})
+			}
+		}
+		for _, p := range m.Params {
+			op.args = append(
+				op.args,
+				goClientArg{
+					name:    p.Name,
+					gqlType: p.Features.String(GQLFeatures, GQLFInputTypeName),
+					goType:  cg.clientType(p.Type, true, false),
+				},
+			)
+		}
+		if m.RetValue != nil {
+			op.ret = cg.clientType(m.RetValue, false, false)
+			op.selection = cg.fieldSelection("", m.RetValue, false, 0)
+		}
+		if op.doc == "" {
+			op.doc = fmt.Sprintf("%s calls %s %s", op.method, qn, op.request)
+		}
+		ops = append(ops, op)
+	}
+	return
+}
+
+func (cg *GoClientGenerator) idArg(e *Entity) (goClientArg, error) {
+	idfld := e.GetIdField()
+	if idfld == nil {
+		return goClientArg{}, fmt.Errorf("at %v: there is no id field for %s", e.Pos, e.Name)
+	}
+	idt, ok := idfld.Features.GetString(GQLFeatures, GQLFTypeTag)
+	if !ok {
+		return goClientArg{}, fmt.Errorf("no type found for %s", idfld.Name)
+	}
+	return goClientArg{
+		name:    idfld.Annotations.GetStringAnnotationDef(GQLAnnotation, GQLAnnotationNameTag, "id"),
+		gqlType: idt,
+		goType:  cg.clientType(idfld.Type, true, false),
+	}, nil
+}
+
+func (cg *GoClientGenerator) getRequest(op goClientOperation) string {
+	var params, args []string
+	for _, a := range op.args {
+		params = append(params, fmt.Sprintf("$%s: %s", a.name, a.gqlType))
+		args = append(args, fmt.Sprintf("%s: $%s", a.name, a.name))
+	}
+	ret := op.request + " " + op.queryName
+	if len(params) > 0 {
+		ret += "(" + strings.Join(params, ", ") + ")"
+	}
+	ret += " { " + op.queryName
+	if len(args) > 0 {
+		ret += "(" + strings.Join(args, ", ") + ")"
+	}
+	if op.selection != "" {
+		ret += " { " + op.selection + " }"
+	}
+	return ret + " }"
+}
+
+func (cg *GoClientGenerator) generateOperation(op goClientOperation) *jen.Statement {
+	params := []jen.Code{jen.Id("ctx").Qual("context", "Context")}
+	vars := jen.Dict{}
+	for _, a := range op.args {
+		params = append(params, jen.Id(a.name).Add(a.goType))
+		vars[jen.Lit(a.name)] = jen.Id(a.name)
+	}
+	var varsCode jen.Code = jen.Nil()
+	if len(vars) > 0 {
+		varsCode = jen.Map(jen.String()).Interface().Values(vars)
+	}
+	if op.ret == nil {
+		return goDoc(op.doc).Func().Params(jen.Id("c").Op("*").Id("Client")).Id(op.method).Params(params...).Error().Block(
+			jen.Return(jen.Id("c").Dot("Do").Call(jen.Id("ctx"), jen.Id(op.queryName+"Request"), varsCode, jen.Lit(op.queryName), jen.Nil())),
+		).Line()
+	}
+	return goDoc(op.doc).Func().Params(jen.Id("c").Op("*").Id("Client")).Id(op.method).Params(params...).Params(
+		jen.Id("ret").Add(op.ret),
+		jen.Err().Error(),
+	).Block(
+		jen.Err().Op("=").Id("c").Dot("Do").Call(
+			jen.Id("ctx"),
+			jen.Id(op.queryName+"Request"),
+			varsCode,
+			jen.Lit(op.queryName),
+			jen.Op("&").Id("ret"),
+		),
+		jen.Return(),
+	).Line()
+}
+
+// selection returns GraphQL selection set (without braces) for entity: all the fields;
+// referenced (not embedded) types are loaded with their scalar fields only
+func (cg *GoClientGenerator) selection(e *Entity, depth int) string {
+	var parts []string
+	for _, f := range e.GetFields(true, true) {
+		name, ok := f.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag)
+		if !ok || f.FB(FeaturesAPIKind, FCIgnore) {
+			continue
+		}
+		asRef := f.HasModifier(AttrModifierEmbeddedRef) || f.FB(GQLFeatures, GQLFIDOnly)
+		if s := cg.fieldSelection(name, f.Type, asRef, depth); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// fieldSelection returns selection for field of type ref; if name is empty only selection set (without braces) is returned
+func (cg *GoClientGenerator) fieldSelection(name string, ref *TypeRef, asRef bool, depth int) string {
+	withName := func(sel string) string {
+		if name == "" || sel == "" {
+			return sel
+		}
+		return name + " { " + sel + " }"
+	}
+	for ref.Array != nil {
+		ref = ref.Array
+	}
+	if ref.Map != nil {
+		return withName("key val")
+	}
+	if IsPrimitiveType(ref.Type) || asRef {
+		return name
+	}
+	dt, ok := cg.desc.FindType(ref.Type)
+	if !ok || dt.Enum() != nil {
+		return name
+	}
+	if dt.IsPolymorphic() {
+		sel := "__typename"
+		for _, e := range dt.PossibleTypes() {
+			sel += fmt.Sprintf(" ... on %s { %s }", e.FS(GQLFeatures, GQLFTypeTag), cg.scalarSelection(e))
+		}
+		return withName(sel)
+	}
+	e := dt.Entity()
+	if e == nil || depth >= goClientSelectionDepth {
+		return ""
+	}
+	if ref.Embedded || e.HasModifier(TypeModifierEmbeddable) || e.HasModifier(TypeModifierTransient) ||
+		e.HasModifier(TypeModifierConfig) || name == "" {
+		return withName(cg.selection(e, depth+1))
+	}
+	return withName(cg.scalarSelection(e))
+}
+
+// scalarSelection returns selection of fields of entity that do not require selection set
+func (cg *GoClientGenerator) scalarSelection(e *Entity) string {
+	var parts []string
+	for _, f := range e.GetFields(true, true) {
+		name, ok := f.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag)
+		if !ok || f.FB(FeaturesAPIKind, FCIgnore) {
+			continue
+		}
+		ref := f.Type
+		for ref.Array != nil {
+			ref = ref.Array
+		}
+		if ref.Map != nil {
+			continue
+		}
+		if IsPrimitiveType(ref.Type) || f.HasModifier(AttrModifierEmbeddedRef) || f.FB(GQLFeatures, GQLFIDOnly) {
+			parts = append(parts, name)
+		} else if dt, ok := cg.desc.FindType(ref.Type); ok && dt.Enum() != nil {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// clientType returns Go type for type ref in client package; entities are pointers to client structs (to input structs if input is true);
+// if asRef is true id type is used for entity
+func (cg *GoClientGenerator) clientType(ref *TypeRef, input bool, asRef bool) *jen.Statement {
+	if ref.Array != nil {
+		return jen.Index().Add(cg.clientType(ref.Array, input, asRef))
+	}
+	if ref.Map != nil {
+		return jen.Index().Qual(goClientRuntime, "KeyValue")
+	}
+	switch ref.Type {
+	case TipString:
+		return jen.String()
+	case TipInt:
+		return jen.Int()
+	case TipBool:
+		return jen.Bool()
+	case TipDate, TipDateTime:
+		return jen.Qual("time", "Time")
+	case TipFloat:
+		return jen.Float64()
+	case TipAny:
+		return jen.Interface()
+	case TipDecimal:
+		return jen.Qual(VivardPackage, "Decimal")
+	case TipTime:
+		return jen.Qual(VivardPackage, "TimeOfDay")
+	case TipDuration:
+		return jen.Qual(goClientRuntime, "Duration")
+	case TipUUID:
+		return jen.Qual(VivardPackage, "UUID")
+	case TipBytes:
+		return jen.Index().Byte()
+	}
+	dt, ok := cg.desc.FindType(ref.Type)
+	if !ok {
+		return jen.Qual("encoding/json", "RawMessage")
+	}
+	switch {
+	case dt.Enum() != nil:
+		return cg.typeID(dt.pckg, dt.Enum().Name)
+	case dt.Entity() != nil && !dt.Entity().HasModifier(TypeModifierExternal):
+		e := dt.Entity()
+		if asRef {
+			if idfld := e.GetIdField(); idfld != nil {
+				return cg.clientType(idfld.Type, input, false)
+			}
+		}
+		if input {
+			return jen.Op("*").Add(cg.typeID(dt.pckg, e.Name+"Input"))
+		}
+		return jen.Op("*").Add(cg.typeID(dt.pckg, e.Name))
+	}
+	// unions, interfaces and external types are left for decoding by user
+	return jen.Qual("encoding/json", "RawMessage")
+}
+
+// isValueType returns true if Go type returned by clientType for ref is not pointer, slice or interface
+func (cg *GoClientGenerator) isValueType(ref *TypeRef, asRef bool) bool {
+	if ref.Array != nil || ref.Map != nil {
+		return false
+	}
+	switch ref.Type {
+	case TipAny, TipBytes:
+		return false
+	}
+	if IsPrimitiveType(ref.Type) || asRef {
+		return true
+	}
+	dt, ok := cg.desc.FindType(ref.Type)
+	return ok && dt.Enum() != nil
+}
+
+func (cg *GoClientGenerator) gqlScalarName(tip string) string {
+	switch tip {
+	case TipInt:
+		return "Int"
+	case TipFloat:
+		return "Float"
+	case TipBool:
+		return "Boolean"
+	}
+	return "String"
+}
+
+func (cg *GoClientGenerator) typeID(pckg string, name string) *jen.Statement {
+	if strings.Contains(name, ".") {
+		parts := strings.SplitN(name, ".", 2)
+		pckg, name = parts[0], parts[1]
+	}
+	if pckg != cg.desc.Name {
+		return jen.Qual(cg.clientPackagePath(pckg), name)
+	}
+	return jen.Id(name)
+}
+
+func (cg *GoClientGenerator) hasGQLType(e *Entity) bool {
+	if e.HasModifier(TypeModifierSingleton) || e.HasModifier(TypeModifierExternal) {
+		return false
+	}
+	if e.FS(FeaturesAPIKind, FAPILevel) == FAPILIgnore {
+		return false
+	}
+	_, ok := e.Features.GetString(GQLFeatures, GQLFTypeTag)
+	return ok
+}
+
+// hasOperation returns true if GQL operation is available for entity (the same conditions as for TS client)
+func (cg *GoClientGenerator) hasOperation(e *Entity, op GQLOperationKind) bool {
+	if e.HasModifier(TypeModifierTransient) || e.HasModifier(TypeModifierEmbeddable) {
+		return false
+	}
+	if _, ok := e.Features.GetString(GQLFeatures, GQLOperationsAnnotationsTags[op]); !ok {
+		return false
+	}
+	isCfg := e.HasModifier(TypeModifierConfig)
+	switch op {
+	case GQLOperationSet:
+		return !e.FB(FeaturesCommonKind, FCReadonly)
+	case GQLOperationCreate:
+		return !e.FB(FeaturesCommonKind, FCReadonly) && !isCfg
+	case GQLOperationList, GQLOperationLookup, GQLOperationDelete:
+		return !isCfg
+	case GQLOperationFind:
+		_, ok := e.Features.GetEntity(FeaturesAPIKind, FAPIFindParamType)
+		return ok
+	case GQLOperationBulkCreate:
+		return e.FB(FeatGoKind, FCGBulkNew)
+	case GQLOperationBulkSet:
+		return e.FB(FeatGoKind, FCGBulkSet)
+	}
+	return true
+}
+
+func (cg *GoClientGenerator) clientPackageName(pckg string) string {
+	return pckg + goClientPackageSuffix
+}
+
+func (cg *GoClientGenerator) clientPackagePath(pckg string) string {
+	prefix := cg.options.PackagePrefix
+	if prefix == "" {
+		prefix = cg.proj.Options.PackagePrefix
+	}
+	if prefix == "" {
+		return cg.clientPackageName(pckg)
+	}
+	return path.Join(prefix, cg.clientPackageName(pckg))
+}
+
+func (cg *GoClientGenerator) clientOutputDir(pckg string) string {
+	dir := cg.options.OutputDir
+	if dir == "" {
+		dir = cg.proj.Options.OutputDir
+	}
+	return filepath.Join(dir, cg.clientPackageName(pckg))
+}
@@ -0,0 +1,93 @@
+package gen
+
+import (
+	"testing"
+)
+
+const goClientTestSource = `package shop;
+
+type Product {
+  priceID: int <id>;
+  name: string!;
+  price: decimal;
+  createdAt: date;
+  tags: [string];
+}
+`
+
+func TestGoClientTypes(t *testing.T) {
+	files := generateTest(t, goClientTestSource, goClientGeneratorName)
+	assertContains(
+		t, files["shopclient/test.go"],
+		"PriceID int `json:\"priceID\"`",
+		"Name string `json:\"name\"`",
+		"Price *vivard.Decimal `json:\"price,omitempty\"`",
+		"CreatedAt *time.Time `json:\"createdAt,omitempty\"`",
+		"Name *string `json:\"name,omitempty\"`",
+	)
+}
+
+// TestGoClientJSONRoundTrip compiles generated client package and checks that its types are encoded
+// and decoded with GraphQL names of fields
+func TestGoClientJSONRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles generated code")
+	}
+	runGenerated(t, goClientTestSource, []string{goClientGeneratorName}, "shopclient", goClientRoundTripMain)
+}
+
+const goClientRoundTripMain = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vc2402/vivard"
+	"PREFIX/shopclient"
+)
+
+func main() {
+	id := 5
+	created := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	price := vivard.MustParseDecimal("12.50")
+	p := shopclient.Product{PriceID: id, Name: "tea", Price: &price, CreatedAt: &created, Tags: []string{"green"}}
+	data, err := json.Marshal(p)
+	check(err)
+	for _, key := range []string{"\"priceID\":5", "\"name\":\"tea\"", "\"price\":\"12.50\"", "\"createdAt\":", "\"tags\":[\"green\"]"} {
+		if !strings.Contains(string(data), key) {
+			fail("%s not found in %s", key, data)
+		}
+	}
+	var back shopclient.Product
+	check(json.Unmarshal(data, &back))
+	if back.PriceID != id || back.Name != p.Name || back.Price.Cmp(price) != 0 ||
+		!back.CreatedAt.Equal(created) || len(back.Tags) != 1 {
+		fail("decoded value differs: %+v", back)
+	}
+	empty, err := json.Marshal(shopclient.Product{PriceID: id, Name: "tea"})
+	check(err)
+	if string(empty) != "{\"priceID\":5,\"name\":\"tea\"}" {
+		fail("optional fields are not omitted: %s", empty)
+	}
+	name := "tea"
+	input, err := json.Marshal(shopclient.ProductInput{Name: &name})
+	check(err)
+	if string(input) != "{\"name\":\"tea\"}" {
+		fail("input: %s", input)
+	}
+}
+
+func check(err error) {
+	if err != nil {
+		fail("%v", err)
+	}
+}
+
+func fail(format string, args ...any) {
+	fmt.Printf(format+"\n", args...)
+	os.Exit(1)
+}
+`
@@ -97,6 +97,15 @@ const (
 	GQLFSetNullInputField = "set-null-input-field"
 )
 
+// gqlScalarNames maps primitive types to custom scalars registered in vivard.GQLDescriptor
+var gqlScalarNames = map[string]string{
+	TipDecimal:  vivard.GQLScalarDecimal,
+	TipTime:     vivard.GQLScalarTime,
+	TipDuration: vivard.GQLScalarDuration,
+	TipUUID:     vivard.GQLScalarUUID,
+	TipBytes:    vivard.GQLScalarBytes,
+}
+
 var GQLOperationsAnnotationsTags = [GQLOperationLast]string{
 	GQLAnnotationGetTag,
 	GQLAnnotationSetTag,
@@ -119,7 +128,7 @@ type GQLGenerator struct {
 }
 
 func init() {
-	RegisterPlugin(&GQLGenerator{options: GQLOptions{UsePackageNameInTypeNames: true}})
+	RegisterPluginFactory(func() Generator { return &GQLGenerator{options: GQLOptions{UsePackageNameInTypeNames: true}} })
 }
 
 func (cg *GQLGenerator) Name() string {
@@ -134,8 +143,55 @@ func (cg *GQLGenerator) CheckAnnotation(desc *Package, ann *Annotation, item int
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (cg *GQLGenerator) AnnotationSchemas() []AnnotationSchema {
+	tags := []AnnotationTagSchema{
+		{Name: GQLAnnotationNameTag, Type: AnnotationTagString, Doc: "GraphQL name"},
+		{Name: GQLAnnotationSkipTag, Type: AnnotationTagBool, Default: false, Doc: "do not generate GraphQL type or field"},
+	}
+	for _, op := range GQLOperationsAnnotationsTags {
+		if op == "" {
+			continue
+		}
+		tags = append(
+			tags,
+			AnnotationTagSchema{Name: op, Type: AnnotationTagAny, Doc: fmt.Sprintf("name of %s operation or false to skip it", op)},
+		)
+	}
+	tags = append(
+		tags,
+		AnnotationTagSchema{Name: GQLAnnotationQueryTag, Type: AnnotationTagBool, Doc: "method is query"},
+		AnnotationTagSchema{Name: GQLAnnotationMutationTag, Type: AnnotationTagBool, Doc: "method is mutation"},
+		AnnotationTagSchema{Name: GQLAnnotationReadonlyTag, Type: AnnotationTagBool, Doc: "do not generate mutations for type"},
+	)
+	return []AnnotationSchema{
+		{Name: GQLAnnotation, Targets: AnnotationTargetAny, Tags: tags, AnyTags: true, Doc: "GraphQL settings"},
+	}
+}
+
 func (cg *GQLGenerator) Prepare(desc *Package) error {
 	cg.desc = desc
+	for _, file := range desc.Files {
+		for _, u := range file.Unions {
+			name, ok := u.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag)
+			if !ok {
+				name = cg.GetGQLEntityTypeName(u.Name)
+			}
+			u.Features.Set(GQLFeatures, GQLFTypeTag, name)
+		}
+		for _, i := range file.Interfaces {
+			name, ok := i.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag)
+			if !ok {
+				name = cg.GetGQLEntityTypeName(i.Name)
+			}
+			i.Features.Set(GQLFeatures, GQLFTypeTag, name)
+			for _, f := range i.Fields {
+				if _, ok := f.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag); !ok {
+					f.Annotations.AddTag(GQLAnnotation, GQLAnnotationNameTag, cg.GetGQLFieldName(f))
+				}
+			}
+		}
+	}
 	for _, file := range desc.Files {
 		for _, t := range file.Entries {
 			an, ok := t.Annotations[GQLAnnotation]
@@ -210,7 +266,7 @@ func (cg *GQLGenerator) Prepare(desc *Package) error {
 							}
 						}
 						f.Features.Set(GQLFeatures, GQLFArgTypeTag, tip)
-						if !f.Type.NonNullable {
+						if !f.Type.NonNullable && !f.IsPolymorphic() {
 							f.Features.Set(GQLFeatures, GQLFSetNullInputField, fmt.Sprintf(gqlSetNullInputTemplate, fieldName))
 						}
 					}
@@ -249,6 +305,18 @@ func (cg *GQLGenerator) Generate(b *Builder) (err error) {
 			"GQLEngine",
 		),
 	).Dot("Descriptor").Params().Line()
+	for _, u := range b.File.Unions {
+		cg.generateUnionType(b, u.Features.String(GQLFeatures, GQLFTypeTag), u.Doc, u.Types)
+	}
+	for _, i := range b.File.Interfaces {
+		err = cg.generateGQLInterface(i)
+		if err != nil {
+			return err
+		}
+		for _, f := range i.Fields {
+			cg.generateFieldDeprecation(i.Features.String(GQLFeatures, GQLFTypeTag), f)
+		}
+	}
 	for _, t := range b.File.Entries {
 		level, ok := t.Features.GetString(FeaturesAPIKind, FAPILevel)
 		if !ok || level != FAPILIgnore {
@@ -320,10 +388,61 @@ func (cg *GQLGenerator) Generate(b *Builder) (err error) {
 		if err != nil {
 			return err
 		}
+		if !ok || level != FAPILIgnore {
+			cg.generateDeprecations(t)
+		}
 	}
 	return nil
 }
 
+// generateDeprecations registers deprecated fields, methods and operations of the type in GraphQL descriptor;
+// all the generated operations of deprecated type are deprecated
+func (cg *GQLGenerator) generateDeprecations(t *Entity) {
+	typeName := t.FS(GQLFeatures, GQLFTypeTag)
+	for _, f := range t.GetFields(true, true) {
+		if f.FB(FeaturesAPIKind, FCIgnore) {
+			continue
+		}
+		cg.generateFieldDeprecation(typeName, f)
+	}
+	for _, m := range t.Methods {
+		if reason, ok := DeprecationReason(m.Annotations); ok {
+			// methods are generated as mutations only at the moment
+			cg.b.Generator.Id(gqlDescriptorVarName).Dot("DeprecateMutation").Params(
+				jen.Lit(m.FS(GQLFeatures, GQLFMethodName)),
+				jen.Lit(reason),
+			).Line()
+		}
+	}
+	reason, ok := DeprecationReason(t.Annotations)
+	if !ok {
+		return
+	}
+	for i := GQLOperationGet; i < GQLOperationLast; i++ {
+		opername, ok := t.Features.GetString(GQLFeatures, GQLOperationsAnnotationsTags[i])
+		if !ok {
+			continue
+		}
+		method := "DeprecateMutation"
+		switch i {
+		case GQLOperationGet, GQLOperationList, GQLOperationLookup, GQLOperationFind:
+			method = "DeprecateQuery"
+		}
+		cg.b.Generator.Id(gqlDescriptorVarName).Dot(method).Params(jen.Lit(opername), jen.Lit(reason)).Line()
+	}
+}
+
+func (cg *GQLGenerator) generateFieldDeprecation(typeName string, f *Field) {
+	if reason, ok := DeprecationReason(f.Annotations); ok {
+		fieldName, _ := f.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag)
+		cg.b.Generator.Id(gqlDescriptorVarName).Dot("DeprecateField").Params(
+			jen.Lit(typeName),
+			jen.Lit(fieldName),
+			jen.Lit(reason),
+		).Line()
+	}
+}
+
 func (cg *GQLGenerator) generateGQLTypes(e *Entity) error {
 	if e.Pckg.engineless {
 		return fmt.Errorf(
@@ -354,7 +473,7 @@ func (cg *GQLGenerator) generateGQLTypes(e *Entity) error {
 			}
 
 			gqlFields[jen.Lit(fieldName)] = jen.Op("&").Qual(gqlPackage, "Field").Values(
-				jen.Dict{
+				withDescription(jen.Dict{
 					jen.Id("Type"): t,
 					jen.Id("Resolve"): jen.Func().Params(
 						jen.Id("p").Qual(
@@ -409,6 +528,30 @@ func (cg *GQLGenerator) generateGQLTypes(e *Entity) error {
 										jen.Qual(VivardPackage, fn).Params(jen.Id("val")),
 										jen.Nil(),
 									)
+								} else if f.IsPolymorphic() {
+									value := cg.desc.CallCodeFeatureFunc(
+										f,
+										FeaturesCommonKind,
+										FCGetterCode,
+										"obj",
+										jen.Id("p").Dot("Context"),
+										false,
+									)
+									if f.Type.Array != nil {
+										g.Id("items").Op(":=").Add(value)
+										g.Id("result").Op(":=").Make(jen.Index().Interface(), jen.Len(jen.Id("items")))
+										g.For(jen.List(jen.Id("i"), jen.Id("item")).Op(":=").Range().Id("items")).Block(
+											jen.If(jen.Id("item").Op("!=").Nil()).Block(
+												jen.Id("result").Index(jen.Id("i")).Op("=").Id("item").Dot("Value"),
+											),
+										)
+										g.Return(jen.Id("result"), jen.Nil())
+									} else {
+										g.If(jen.Id("h").Op(":=").Add(value), jen.Id("h").Op("!=").Nil()).Block(
+											jen.Return(jen.Id("h").Dot("Value"), jen.Nil()),
+										)
+										g.Return(jen.Nil(), jen.Nil())
+									}
 								} else {
 									var enum *Enum
 									var enumArray *Enum
@@ -471,7 +614,7 @@ func (cg *GQLGenerator) generateGQLTypes(e *Entity) error {
 								}
 							},
 						),
-				},
+				}, f.Doc),
 			)
 			cg.desc.AddTag(f, gqlTagJSON, fieldName)
 		}
@@ -479,10 +622,29 @@ func (cg *GQLGenerator) generateGQLTypes(e *Entity) error {
 			jen.Return(
 				jen.Qual(gqlPackage, "NewObject").Call(
 					jen.Qual(gqlPackage, "ObjectConfig").Values(
-						jen.Dict{
-							jen.Id("Name"):   jen.Lit(name),
-							jen.Id("Fields"): jen.Qual(gqlPackage, "Fields").Values(gqlFields),
-						},
+						jen.DictFunc(func(d jen.Dict) {
+							d[jen.Id("Name")] = jen.Lit(name)
+							d[jen.Id("Fields")] = jen.Qual(gqlPackage, "Fields").Values(gqlFields)
+							withDescription(d, e.Doc)
+							if len(e.Implements) > 0 {
+								var interfaces []jen.Code
+								for _, in := range e.Implements {
+									if dt, ok := cg.desc.FindType(in); ok && dt.iface != nil {
+										interfaces = append(
+											interfaces,
+											cg.generateTypeLookupStatement(cg.getPolymorphicTypeName(dt), false).
+												Assert(jen.Op("*").Qual(gqlPackage, "Interface")),
+										)
+									}
+								}
+								// thunk breaks the cycle object -> interface -> object
+								d[jen.Id("Interfaces")] = jen.Qual(gqlPackage, "InterfacesThunk").Call(
+									jen.Func().Params().Index().Op("*").Qual(gqlPackage, "Interface").Block(
+										jen.Return(jen.Index().Op("*").Qual(gqlPackage, "Interface").Values(interfaces...)),
+									),
+								)
+							}
+						}),
 					),
 				),
 			),
@@ -535,7 +697,7 @@ func (cg *GQLGenerator) generateInputTypeGenerator(e *Entity) error {
 		for _, f := range e.GetFields(true, true) {
 			fieldName, ok := f.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag)
 			// let's leave readonly fields as input for js simplicity
-			if !ok /*|| f.FB(FeaturesCommonKind, FCReadonly)*/ || f.HasModifier(AttrModifierCalculated) {
+			if !ok /*|| f.FB(FeaturesCommonKind, FCReadonly)*/ || f.HasModifier(AttrModifierCalculated) || f.IsPolymorphic() {
 				continue
 			}
 			t, err := cg.getGQLType(
@@ -549,9 +711,7 @@ func (cg *GQLGenerator) generateInputTypeGenerator(e *Entity) error {
 			}
 
 			gqlFields[jen.Lit(fieldName)] = jen.Op("&").Qual(gqlPackage, "InputObjectFieldConfig").Values(
-				jen.Dict{
-					jen.Id("Type"): t,
-				},
+				withDescription(jen.Dict{jen.Id("Type"): t}, f.Doc),
 			)
 			if setNullField := f.FS(GQLFeatures, GQLFSetNullInputField); setNullField != "" {
 				gqlFields[jen.Lit(setNullField)] = jen.Op("&").Qual(gqlPackage, "InputObjectFieldConfig").Values(
@@ -565,10 +725,13 @@ func (cg *GQLGenerator) generateInputTypeGenerator(e *Entity) error {
 			jen.Return(
 				jen.Qual(gqlPackage, "NewInputObject").Call(
 					jen.Qual(gqlPackage, "InputObjectConfig").Values(
-						jen.Dict{
-							jen.Id("Name"):   jen.Lit(typeName),
-							jen.Id("Fields"): jen.Qual(gqlPackage, "InputObjectConfigFieldMap").Values(gqlFields),
-						},
+						withDescription(
+							jen.Dict{
+								jen.Id("Name"):   jen.Lit(typeName),
+								jen.Id("Fields"): jen.Qual(gqlPackage, "InputObjectConfigFieldMap").Values(gqlFields),
+							},
+							e.Doc,
+						),
 					),
 				),
 			),
@@ -693,7 +856,7 @@ func (cg *GQLGenerator) generateGQLInputTypeParser(t *Entity) error {
 				)
 				for _, f := range t.GetFields(true, true) {
 					fieldName, ok := f.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag)
-					if !ok || f.FB(FeaturesCommonKind, FCReadonly) || f.HasModifier(AttrModifierCalculated) {
+					if !ok || f.FB(FeaturesCommonKind, FCReadonly) || f.HasModifier(AttrModifierCalculated) || f.IsPolymorphic() {
 						continue
 					}
 					engVar := cg.desc.CallCodeFeatureFunc(f, FeaturesCommonKind, FCEngineVar)
@@ -1741,13 +1904,13 @@ func (cg *GQLGenerator) generateGQLMethodMutation(m *Method) (err error) {
 	fname := fmt.Sprintf("%sMutationGenerator", opername)
 	idField := m.parent.GetIdField()
 
-	f := jen.Func().Parens(jen.Id(EngineVar).Op("*").Id("Engine")).Id(fname).Params().Op("*").Qual(
+	f := cg.b.SourcePos(m.Pos).Func().Parens(jen.Id(EngineVar).Op("*").Id("Engine")).Id(fname).Params().Op("*").Qual(
 		gqlPackage,
 		"Field",
 	).Block(
 		jen.Return(
 			jen.Op("&").Qual(gqlPackage, "Field").Values(
-				jen.Dict{
+				withDescription(jen.Dict{
 					jen.Id("Type"): cg.desc.CallCodeFeatureFunc(m, GQLFeatures, GQLFMethodResultType),
 					jen.Id("Args"): jen.Qual(gqlPackage, "FieldConfigArgument").ValuesFunc(
 						func(g *jen.Group) {
@@ -1814,7 +1977,7 @@ func (cg *GQLGenerator) generateGQLMethodMutation(m *Method) (err error) {
 							)
 						},
 					),
-				},
+				}, m.Doc),
 			),
 		),
 	).Line()
@@ -1985,10 +2148,12 @@ func (cg *GQLGenerator) getGQLType(ref *TypeRef, skipNotNull ...bool) (ret *jen.
 			ret = jen.Qual(gqlPackage, "String")
 		case TipInt:
 			ret = jen.Qual(gqlPackage, "Int")
-		case TipDate:
+		case TipDate, TipDateTime:
 			ret = jen.Qual(gqlPackage, "DateTime")
 		case TipFloat:
 			ret = jen.Qual(gqlPackage, "Float")
+		case TipDecimal, TipTime, TipDuration, TipUUID, TipBytes:
+			ret = cg.generateTypeLookupStatement(gqlScalarNames[ref.Type], len(skipNotNull) > 2 && skipNotNull[2])
 		default:
 			if len(skipNotNull) > 1 && skipNotNull[1] {
 				if t, ok := cg.desc.FindType(ref.Type); ok && t.entry != nil {
@@ -2017,12 +2182,18 @@ func (cg *GQLGenerator) getGQLType(ref *TypeRef, skipNotNull ...bool) (ret *jen.
 						//TODO create enum type for enums
 						return cg.getGQLType(&TypeRef{Type: e.enum.AliasForType}, skipNotNull...)
 					}
-					if e.entry == nil {
+					if e.IsPolymorphic() {
+						if isInput {
+							return nil, fmt.Errorf("union or interface can not be used as input: %s", ref.Type)
+						}
+						typeName = cg.getPolymorphicTypeName(e)
+					} else if e.entry == nil {
 						return nil, fmt.Errorf("external type can not be used here: %s", ref.Type)
-					}
-					typeName = e.entry.FS(GQLFeatures, GQLFTypeTag)
-					if isInput {
-						typeName = e.entry.FS(GQLFeatures, GQLFInputTypeName)
+					} else {
+						typeName = e.entry.FS(GQLFeatures, GQLFTypeTag)
+						if isInput {
+							typeName = e.entry.FS(GQLFeatures, GQLFInputTypeName)
+						}
 					}
 				}
 				ret = cg.generateTypeLookupStatement(typeName, isInput)
@@ -2248,10 +2419,12 @@ func (cg *GQLGenerator) GetGQLTypeName(ref *TypeRef, forInput ...bool) (ret stri
 			ret = "String"
 		case TipInt:
 			ret = "Int"
-		case TipDate:
+		case TipDate, TipDateTime:
 			ret = "DateTime"
 		case TipFloat:
 			ret = "Float"
+		case TipDecimal, TipTime, TipDuration, TipUUID, TipBytes:
+			ret = gqlScalarNames[ref.Type]
 		default:
 			if dt, ok := cg.desc.FindType(ref.Type); ok {
 				if dt.Entity() != nil {
@@ -2266,6 +2439,8 @@ func (cg *GQLGenerator) GetGQLTypeName(ref *TypeRef, forInput ...bool) (ret stri
 					} else {
 						ret, _ = dt.Enum().Features.GetString(GQLFeatures, GQLFTypeTag)
 					}
+				} else if dt.IsPolymorphic() {
+					ret = cg.getPolymorphicTypeName(dt)
 				}
 			}
 			if ret == "" {
@@ -2381,6 +2556,79 @@ func (cg *GQLGenerator) getMethodResultTypeFunc(m *Method) CodeHelperFunc {
 	}
 }
 
+// withDescription adds Description to config of GraphQL type or field if doc is not empty
+func withDescription(d jen.Dict, doc string) jen.Dict {
+	if doc != "" {
+		d[jen.Id("Description")] = jen.Lit(doc)
+	}
+	return d
+}
+
+func (cg *GQLGenerator) getPolymorphicTypeName(dt *DefinedType) string {
+	if dt.union != nil {
+		return dt.union.Features.String(GQLFeatures, GQLFTypeTag)
+	}
+	return dt.iface.Features.String(GQLFeatures, GQLFTypeTag)
+}
+
+func (cg *GQLGenerator) generateGQLInterface(i *Interface) error {
+	name := i.Features.String(GQLFeatures, GQLFTypeTag)
+	fname := fmt.Sprintf("%sTypeGenerator", name)
+	gqlFields := jen.Dict{}
+	for _, f := range i.Fields {
+		t, err := cg.getGQLType(f.Type, false, f.Type.Complex)
+		if err != nil {
+			return fmt.Errorf("at %v: %w", f.Pos, err)
+		}
+		fieldName, _ := f.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag)
+		gqlFields[jen.Lit(fieldName)] = jen.Op("&").Qual(gqlPackage, "Field").Values(
+			withDescription(jen.Dict{jen.Id("Type"): t}, f.Doc),
+		)
+	}
+	var resolves []jen.Code
+	for _, e := range i.Implementations {
+		resolves = append(
+			resolves,
+			jen.Case(jen.Op("*").Add(cg.b.Pckg.TypeStmt(e))).Block(
+				jen.Return(
+					cg.generateTypeLookupStatement(e.FS(GQLFeatures, GQLFTypeTag), false).
+						Assert(jen.Op("*").Qual(gqlPackage, "Object")),
+				),
+			),
+		)
+	}
+	f := jen.Func().Parens(jen.Id(EngineVar).Op("*").Id("Engine")).Id(fname).Params().Qual(gqlPackage, "Output").Block(
+		jen.Return(
+			jen.Qual(gqlPackage, "NewInterface").Call(
+				jen.Qual(gqlPackage, "InterfaceConfig").Values(
+					withDescription(jen.Dict{
+						jen.Id("Name"): jen.Lit(name),
+						// thunk allows fields that refer to implementations
+						jen.Id("Fields"): jen.Qual(gqlPackage, "FieldsThunk").Call(
+							jen.Func().Params().Qual(gqlPackage, "Fields").Block(
+								jen.Return(jen.Qual(gqlPackage, "Fields").Values(gqlFields)),
+							),
+						),
+						jen.Id("ResolveType"): jen.Func().Params(
+							jen.Id("p").Qual(gqlPackage, "ResolveTypeParams"),
+						).Op("*").Qual(gqlPackage, "Object").Block(
+							jen.Switch(jen.Id("p").Dot("Value").Assert(jen.Type())).Block(resolves...),
+							jen.Return(jen.Nil()),
+						),
+					}, i.Doc),
+				),
+			),
+		),
+	).Line()
+
+	cg.b.Functions.Add(f)
+	cg.b.Generator.Id(gqlDescriptorVarName).Dot("AddTypeGenerator").Params(
+		jen.Lit(name),
+		jen.Id(EngineVar).Dot(fname),
+	).Line()
+	return nil
+}
+
 func (cg *GQLGenerator) getUnionGeneratorFunc(builder *Builder) FeatureFunc {
 	return func(args ...interface{}) (any, error) {
 		//graphql.NewUnion(
@@ -2408,34 +2656,41 @@ func (cg *GQLGenerator) getUnionGeneratorFunc(builder *Builder) FeatureFunc {
 		if !ok {
 			return nil, errors.New("GQLGenerateUnionType feature: first parameter should be a string")
 		}
-		var types []jen.Code
-		var resolves []jen.Code
-		addEntity := func(e *Entity) {
-			//typeRef := e.TypeRef()
-			//typeName := builder.Pckg.GetRealTypeName(typeRef.Type)
-			typeLookup := cg.generateTypeLookupStatement(
-				e.FS(GQLFeatures, GQLFTypeTag),
-				false,
-			).Assert(jen.Op("*").Qual(gqlPackage, "Object"))
-			types = append(types, typeLookup)
-			resolves = append(resolves, jen.Case(builder.Pckg.TypeStmt(e)).Block(jen.Return(typeLookup)))
-		}
+		var entities []*Entity
 		for _, a := range args[1:] {
 			if e, ok := a.(*Entity); ok {
-				addEntity(e)
+				entities = append(entities, e)
 			} else if ents, ok := a.([]*Entity); ok {
-				for _, e := range ents {
-					addEntity(e)
-				}
+				entities = append(entities, ents...)
 			} else {
 				return nil, errors.New("GQLGenerateUnionType feature: parameters from idx 1 should be *Entity")
 			}
 		}
-		fname := fmt.Sprintf("%sTypeGenerator", name)
-		f := jen.Func().Parens(jen.Id(EngineVar).Op("*").Id("Engine")).Id(fname).Params().Qual(gqlPackage, "Output").Block(
-			jen.Return(
-				jen.Qual(gqlPackage, "NewUnion").Call(
-					jen.Qual(gqlPackage, "UnionConfig").Values(
+		cg.generateUnionType(builder, name, "", entities)
+		return nil, nil
+	}
+}
+
+// generateUnionType generates GraphQL union type with given description
+func (cg *GQLGenerator) generateUnionType(builder *Builder, name string, description string, entities []*Entity) {
+	var types []jen.Code
+	var resolves []jen.Code
+	for _, e := range entities {
+		//typeRef := e.TypeRef()
+		//typeName := builder.Pckg.GetRealTypeName(typeRef.Type)
+		typeLookup := cg.generateTypeLookupStatement(
+			e.FS(GQLFeatures, GQLFTypeTag),
+			false,
+		).Assert(jen.Op("*").Qual(gqlPackage, "Object"))
+		types = append(types, typeLookup)
+		resolves = append(resolves, jen.Case(jen.Op("*").Add(builder.Pckg.TypeStmt(e))).Block(jen.Return(typeLookup)))
+	}
+	fname := fmt.Sprintf("%sTypeGenerator", name)
+	f := jen.Func().Parens(jen.Id(EngineVar).Op("*").Id("Engine")).Id(fname).Params().Qual(gqlPackage, "Output").Block(
+		jen.Return(
+			jen.Qual(gqlPackage, "NewUnion").Call(
+				jen.Qual(gqlPackage, "UnionConfig").Values(
+					withDescription(
 						jen.Dict{
 							jen.Id("Name"):  jen.Lit(name),
 							jen.Id("Types"): jen.Index().Op("*").Qual(gqlPackage, "Object").Values(types...),
@@ -2449,16 +2704,16 @@ func (cg *GQLGenerator) getUnionGeneratorFunc(builder *Builder) FeatureFunc {
 								jen.Return(jen.Nil()),
 							),
 						},
+						description,
 					),
 				),
 			),
-		).Line()
+		),
+	).Line()
 
-		builder.Functions.Add(f)
-		builder.Generator.Id(gqlDescriptorVarName).Dot("AddTypeGenerator").Params(
-			jen.Lit(name),
-			jen.Id(EngineVar).Dot(fname),
-		).Line()
-		return nil, nil
-	}
+	builder.Functions.Add(f)
+	builder.Generator.Id(gqlDescriptorVarName).Dot("AddTypeGenerator").Params(
+		jen.Lit(name),
+		jen.Id(EngineVar).Dot(fname),
+	).Line()
 }
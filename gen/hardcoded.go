@@ -221,11 +221,12 @@ func (cg *CodeGenerator) parseHardcoded(m *Meta) (ok bool, err error) {
 			} else {
 				val = jen.Qual(VivardPackage, "Ptr").Params(jen.Lit(bool(*a.Bool)))
 			}
-		case TipDate:
+		case TipDate, TipDateTime, TipDecimal, TipTime, TipDuration, TipUUID, TipBytes:
 			return nil, fmt.Errorf(
-				"at %s:%d: type datetime of field %s can not be used for hardcoded",
+				"at %s:%d: type %s of field %s can not be used for hardcoded",
 				m.Pos.Filename,
 				m.Pos.Line+pos,
+				tr.Type,
 				name,
 			)
 		default:
@@ -63,13 +63,18 @@ const (
 )
 
 func init() {
-	RegisterPlugin(&HistoryGenerator{})
+	RegisterPluginFactory(func() Generator { return &HistoryGenerator{} })
 }
 
 func (hg *HistoryGenerator) Name() string {
 	return historyGeneratorName
 }
 
+// Requires from PluginsRequirer
+func (hg *HistoryGenerator) Requires() []string {
+	return []string{bscdGeneratorName}
+}
+
 // SetDescriptor from DescriptorAware
 func (hg *HistoryGenerator) SetDescriptor(proj *Project) {
 	hg.proj = proj
@@ -81,7 +86,7 @@ func (hg *HistoryGenerator) CheckAnnotation(desc *Package, ann *Annotation, item
 		if field, ok := item.(*Field); ok {
 			cdf := desc.GetFeature(field, FeaturesChangeDetectorKind, FCDChangedHook)
 			if cdf != true {
-				return true, errors.New("history generator requires change detector; try to add BitSetChangeDetectorGenerator")
+				return true, fmt.Errorf("history generator requires change detector (plugin %s)", bscdGeneratorName)
 			}
 			histEntName := fmt.Sprintf("%s%sHistory", field.Parent().Name, field.Name)
 			he := &Entity{
@@ -180,6 +185,32 @@ func (hg *HistoryGenerator) CheckAnnotation(desc *Package, ann *Annotation, item
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (hg *HistoryGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    historyAnn,
+			Targets: AnnotationTargetField,
+			Tags: []AnnotationTagSchema{
+				{
+					Name:    historyAnnFields,
+					Type:    AnnotationTagString,
+					Default: historyAnnFieldTimestamp,
+					Doc: fmt.Sprintf(
+						"comma separated fields of history record: %s, %s, %s, %s",
+						historyAnnFieldTimestamp,
+						historyAnnFieldUserID,
+						historyAnnFieldUserName,
+						historyAnnFieldSource,
+					),
+				},
+			},
+			AnyTags: true,
+			Doc:     "collects history of the field values",
+		},
+	}
+}
+
 // Prepare from Generator interface
 func (hg *HistoryGenerator) Prepare(desc *Package) error {
 	return nil
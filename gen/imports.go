@@ -0,0 +1,239 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileParser parses files along with files they import; every file is parsed only once
+type fileParser struct {
+	// parsed - files by absolute path; nil value means that file is being parsed now
+	parsed map[string]*File
+	// stack - absolute paths of files that are being parsed, used to report import cycles
+	stack []string
+	// files - parsed files in order of dependencies: imported files go before importing ones
+	files []*File
+	// mixins - cache of mixins visible from file
+	mixins map[*File]map[string]*Mixin
+	// sources - contents by absolute path that are used instead of files on disk (e.g. unsaved editor buffers)
+	sources map[string][]byte
+}
+
+// sourceReader is in-memory content of file; Name is used by lexer for positions
+type sourceReader struct {
+	*bytes.Reader
+	name string
+}
+
+func (sr sourceReader) Name() string { return sr.name }
+func (sr sourceReader) Close() error { return nil }
+
+func (fp *fileParser) parseFile(file string, from *Import) (*File, error) {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file name '%s': %w", file, err)
+	}
+	if ast, ok := fp.parsed[path]; ok {
+		if ast == nil {
+			return nil, fmt.Errorf("at %v: import cycle: %s", from.Pos, fp.cycle(path))
+		}
+		return ast, nil
+	}
+	var r io.ReadSeekCloser
+	if src, ok := fp.sources[path]; ok {
+		r = sourceReader{Reader: bytes.NewReader(src), name: file}
+	} else {
+		r, err = os.Open(file)
+		if err != nil {
+			if from != nil {
+				return nil, fmt.Errorf("at %v: can't import '%s': %w", from.Pos, from.Path, err)
+			}
+			return nil, fmt.Errorf("can't open file '%s': %w", file, err)
+		}
+	}
+	ast := &File{}
+	err = parser.Parse(r, ast)
+	if err == nil {
+		_, err = r.Seek(0, io.SeekStart)
+		if err == nil {
+			ast.docs, err = docComments(r)
+		}
+	}
+	r.Close()
+	if err != nil {
+		return nil, fmt.Errorf("while parsing '%s': %w", file, err)
+	}
+	ast.Path = path
+	ast.FileName = filepath.Base(file)
+	ast.Name = ast.FileName
+	if ext := strings.Index(ast.Name, "."); ext != -1 {
+		ast.Name = ast.Name[:ext]
+	}
+	for _, m := range ast.Mixins {
+		m.File = ast
+	}
+
+	fp.parsed[path] = nil
+	fp.stack = append(fp.stack, path)
+	for _, imp := range ast.Imports {
+		name := imp.Path
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(filepath.Dir(path), name)
+		}
+		imp.File, err = fp.parseFile(name, imp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	fp.stack = fp.stack[:len(fp.stack)-1]
+	fp.parsed[path] = ast
+	fp.files = append(fp.files, ast)
+	return ast, nil
+}
+
+// cycle returns chain of imports from path to itself
+func (fp *fileParser) cycle(path string) string {
+	chain := []string{}
+	for i := len(fp.stack) - 1; i >= 0; i-- {
+		chain = append([]string{filepath.Base(fp.stack[i])}, chain...)
+		if fp.stack[i] == path {
+			break
+		}
+	}
+	chain = append(chain, filepath.Base(path))
+	return strings.Join(chain, " -> ")
+}
+
+// visibleMixins returns mixins declared in file and in files it imports (directly or not)
+func (fp *fileParser) visibleMixins(f *File) (map[string]*Mixin, error) {
+	if ret, ok := fp.mixins[f]; ok {
+		return ret, nil
+	}
+	ret := map[string]*Mixin{}
+	add := func(m *Mixin) error {
+		if prev, ok := ret[m.Name]; ok && prev != m {
+			return fmt.Errorf("at %v: mixin %s redeclared; previous declaration at %v", m.Pos, m.Name, prev.Pos)
+		}
+		ret[m.Name] = m
+		return nil
+	}
+	for _, m := range f.Mixins {
+		if err := add(m); err != nil {
+			return nil, err
+		}
+	}
+	for _, imp := range f.Imports {
+		// imports are acyclic, so recursion is finite
+		imported, err := fp.visibleMixins(imp.File)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range imported {
+			if err := add(m); err != nil {
+				return nil, err
+			}
+		}
+	}
+	fp.mixins[f] = ret
+	return ret, nil
+}
+
+// applyMixins replaces 'use Name' modifiers of all declarations with modifiers of the mixin
+func (fp *fileParser) applyMixins() (err error) {
+	fp.mixins = map[*File]map[string]*Mixin{}
+	for _, f := range fp.files {
+		for _, m := range f.Mixins {
+			// check for undefined mixins and cycles even if mixin is never used
+			_, err = fp.expandModifiers(f, m.Modifiers, []*Mixin{m})
+			if err != nil {
+				return
+			}
+		}
+		for _, e := range f.Entries {
+			if e.Modifiers, err = fp.expandModifiers(f, e.Modifiers, nil); err != nil {
+				return
+			}
+		}
+		for _, e := range f.Enums {
+			if e.Modifiers, err = fp.expandModifiers(f, e.Modifiers, nil); err != nil {
+				return
+			}
+		}
+		for _, u := range f.Unions {
+			if u.Modifiers, err = fp.expandModifiers(f, u.Modifiers, nil); err != nil {
+				return
+			}
+		}
+		for _, i := range f.Interfaces {
+			if i.Modifiers, err = fp.expandModifiers(f, i.Modifiers, nil); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// expandModifiers inserts copies of mixin's modifiers in place of 'use' modifier,
+// so annotations that follow 'use' override annotations of mixin
+func (fp *fileParser) expandModifiers(f *File, mods []*EntityModifier, chain []*Mixin) ([]*EntityModifier, error) {
+	ret := make([]*EntityModifier, 0, len(mods))
+	for _, m := range mods {
+		if m.Use == "" {
+			if len(chain) > 0 {
+				m = m.clone()
+			}
+			ret = append(ret, m)
+			continue
+		}
+		mixins, err := fp.visibleMixins(f)
+		if err != nil {
+			return nil, err
+		}
+		mx, ok := mixins[m.Use]
+		if !ok {
+			return nil, fmt.Errorf("at %v: undefined mixin %s", m.Pos, m.Use)
+		}
+		for i, c := range chain {
+			if c == mx {
+				names := []string{}
+				for _, c := range chain[i:] {
+					names = append(names, c.Name)
+				}
+				return nil, fmt.Errorf("at %v: mixin cycle: %s -> %s", m.Pos, strings.Join(names, " -> "), mx.Name)
+			}
+		}
+		expanded, err := fp.expandModifiers(mx.File, mx.Modifiers, append(chain[:len(chain):len(chain)], mx))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, expanded...)
+	}
+	return ret, nil
+}
+
+// clone returns deep copy of modifier, so that types using the same mixin do not share annotations
+func (m *EntityModifier) clone() *EntityModifier {
+	ret := *m
+	if m.Annotation != nil {
+		ret.Annotation = m.Annotation.clone()
+	}
+	return &ret
+}
+
+func (a *Annotation) clone() *Annotation {
+	ret := *a
+	ret.Values = make([]*AnnotationTag, len(a.Values))
+	for i, t := range a.Values {
+		tag := *t
+		if t.Value != nil {
+			v := *t.Value
+			tag.Value = &v
+		}
+		ret.Values[i] = &tag
+	}
+	return &ret
+}
@@ -21,8 +21,8 @@ func (cg *GQLCLientGenerator) generateEnum(wr io.Writer, e *gen.Enum) (err error
 }
 
 const enumTemplate = `
-export type {{EnumName .}} = {{EnumType .}};
+{{TSDoc .Doc .Annotations}}export type {{EnumName .}} = {{EnumType .}};
 export type {{EnumInputName .}} = {{EnumType .}};
 {{range .Fields}}
-export const {{EnumFieldName .}} = {{EnumFieldValue .}}{{end}}
+{{TSDoc .Doc .Annotations}}export const {{EnumFieldName .}} = {{EnumFieldValue .}}{{end}}
 `
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -18,6 +17,15 @@ const (
 	GQLClientOptions         = "gql-ts"
 	GQLClientPathOption      = "path"
 	GQLClientNamespaceOption = "useNamespace"
+	// GQLClientTargetOption - client library the generated code is written for (one of GQLClientTarget...)
+	GQLClientTargetOption = "target"
+
+	// GQLClientTargetApollo2 - apollo-client v2 and graphql-tag (default)
+	GQLClientTargetApollo2 = "apollo2"
+	// GQLClientTargetApollo3 - @apollo/client v3 with typed documents and cache type policies
+	GQLClientTargetApollo3 = "apollo3"
+	// GQLClientTargetFetch - framework-free client based on fetch (see GQLClient in vivard.ts)
+	GQLClientTargetFetch = "fetch"
 
 	Annotation             = "js"
 	AnnotationName         = "name"
@@ -56,7 +64,7 @@ const CodeFragmentActionImport = "import"
 type CodeFragmentContext struct {
 	FileName string
 	File     *gen.File
-	Output   *os.File
+	Output   *gen.OutputFile
 	Error    error
 	Imports
 }
@@ -64,7 +72,7 @@ type CodeFragmentContext struct {
 //TODO ref field is int not an object
 
 func init() {
-	gen.RegisterPlugin(&GQLCLientGenerator{})
+	gen.RegisterPluginFactory(func() gen.Generator { return &GQLCLientGenerator{} })
 }
 
 type GQLCLientGenerator struct {
@@ -72,6 +80,7 @@ type GQLCLientGenerator struct {
 	vivardGenerated bool
 	useNS           bool
 	outputPath      string
+	target          string
 }
 
 type Imports map[string][]string
@@ -96,6 +105,11 @@ func (cg *GQLCLientGenerator) Name() string {
 	return GQLClientGeneratorName
 }
 
+// Requires from gen.PluginsRequirer
+func (cg *GQLCLientGenerator) Requires() []string {
+	return []string{gen.GQLGeneratorName}
+}
+
 func (cg *GQLCLientGenerator) SetOptions(options any) error {
 	if opts, ok := options.(map[string]any); ok {
 		if p, ok := opts[GQLClientNamespaceOption].(bool); ok {
@@ -104,6 +118,14 @@ func (cg *GQLCLientGenerator) SetOptions(options any) error {
 		if p, ok := opts[GQLClientPathOption].(string); ok {
 			cg.outputPath = p
 		}
+		if t, ok := opts[GQLClientTargetOption].(string); ok {
+			switch t {
+			case GQLClientTargetApollo2, GQLClientTargetApollo3, GQLClientTargetFetch:
+				cg.target = t
+			default:
+				return fmt.Errorf("%s: unknown target '%s'", GQLClientOptions, t)
+			}
+		}
 	}
 	return nil
 }
@@ -115,6 +137,33 @@ func (cg *GQLCLientGenerator) CheckAnnotation(desc *gen.Package, ann *gen.Annota
 	return false, nil
 }
 
+// AnnotationSchemas from gen.AnnotationsDescriber
+func (cg *GQLCLientGenerator) AnnotationSchemas() []gen.AnnotationSchema {
+	return annotationSchemas()
+}
+
+// annotationSchemas returns schema of Annotation shared by generators of the package
+func annotationSchemas() []gen.AnnotationSchema {
+	return []gen.AnnotationSchema{
+		{
+			Name:    Annotation,
+			Targets: gen.AnnotationTargetAny,
+			Tags: []gen.AnnotationTagSchema{
+				{Name: AnnotationName, Type: gen.AnnotationTagString, Doc: "TypeScript name of type or field"},
+				{Name: AnnotationInputName, Type: gen.AnnotationTagString, Doc: "TypeScript name of input type or field"},
+				{Name: AnnotationSkip, Type: gen.AnnotationTagBool, Default: false, Doc: "do not generate TypeScript code"},
+				{Name: AnnotationTitle, Type: gen.AnnotationTagBool, Default: false, Doc: "field is title of object"},
+				{Name: AnnotationIcon, Type: gen.AnnotationTagBool, Default: false, Doc: "field is icon of object"},
+				{Name: AnnotationColor, Type: gen.AnnotationTagBool, Default: false, Doc: "field is color of object"},
+				{Name: AnnotationForce, Type: gen.AnnotationTagBool, Default: false, Doc: "array field is requested by all the queries (not only get)"},
+				{Name: AnnotationForceForFind, Type: gen.AnnotationTagBool, Default: false, Doc: "array field is requested by find query"},
+			},
+			AnyTags: true,
+			Doc:     "TypeScript client settings",
+		},
+	}
+}
+
 func (cg *GQLCLientGenerator) Prepare(desc *gen.Package) error {
 	cg.desc = desc
 	if opts, ok := cg.desc.Options().Custom[GQLClientOptions].(map[string]interface{}); ok {
@@ -220,6 +269,14 @@ func (cg *GQLCLientGenerator) Prepare(desc *gen.Package) error {
 			enum.Features.Set(Features, FFilePath, p)
 			enum.Features.Set(Features, FType, cg.GetJSTypeNameByVivardName(enum.AliasForType))
 		}
+		for _, u := range file.Unions {
+			u.Features.Set(Features, FName, cg.GetJSEntityTypeName(u.Name))
+			u.Features.Set(Features, FFilePath, p)
+		}
+		for _, i := range file.Interfaces {
+			i.Features.Set(Features, FName, cg.GetJSEntityTypeName(i.Name))
+			i.Features.Set(Features, FFilePath, p)
+		}
 	}
 	return nil
 }
@@ -227,14 +284,10 @@ func (cg *GQLCLientGenerator) Prepare(desc *gen.Package) error {
 func (cg *GQLCLientGenerator) Generate(b *gen.Builder) (err error) {
 	cg.desc = b.Descriptor
 	fileName := b.File.Name + ".ts"
-	outFile, err := os.Create(cg.getFilePathForName(b.File.Name))
-	if err != nil {
-		return
-	}
+	outFile := cg.createFile(cg.getFilePathForName(b.File.Name))
 	defer outFile.Close()
 	outFile.WriteString(fmt.Sprintf("/*Code generated from file %s by vivgen. DO NOT EDIT.*/\n\n", b.File.FileName))
-	outFile.WriteString(apolloClientInclude)
-	outFile.WriteString(gqlInclude)
+	outFile.WriteString(cg.getIncludes())
 	imports := Imports{}
 	cfc := CodeFragmentContext{
 		FileName: fileName,
@@ -249,6 +302,19 @@ func (cg *GQLCLientGenerator) Generate(b *gen.Builder) (err error) {
 			return err
 		}
 	}
+	addMembersImports := func(types []*gen.Entity) {
+		for _, t := range types {
+			if t.File != b.File {
+				imports.addImport(t.File.Name, cg.GetJSEntityTypeName(t.Name))
+			}
+		}
+	}
+	for _, u := range b.File.Unions {
+		addMembersImports(u.Types)
+	}
+	for _, i := range b.File.Interfaces {
+		addMembersImports(i.Implementations)
+	}
 	for _, t := range b.File.Entries {
 		for _, f := range t.GetFields(true, true) {
 			if t, tf := cg.getTypeForImport(f.Parent().Pckg, f.Type, false); tf != nil && tf != b.File {
@@ -281,12 +347,30 @@ func (cg *GQLCLientGenerator) Generate(b *gen.Builder) (err error) {
 	if cg.useNS {
 		outFile.WriteString(fmt.Sprintf("namespace %s {", b.File.Package))
 	}
+	for _, u := range b.File.Unions {
+		err = cg.generateUnion(outFile, u.Name, u.Doc, u.Annotations, u.Types)
+		if err != nil {
+			return err
+		}
+	}
+	for _, i := range b.File.Interfaces {
+		err = cg.generateUnion(outFile, i.Name, i.Doc, i.Annotations, i.Implementations)
+		if err != nil {
+			return err
+		}
+	}
 	for _, t := range b.File.Entries {
 		err := cg.generateQueriesFile(outFile, t)
 		if err != nil {
 			return err
 		}
 	}
+	if cg.target == GQLClientTargetApollo3 {
+		err = cg.generateTypePolicies(outFile, b.File)
+		if err != nil {
+			return err
+		}
+	}
 	outFile.WriteString(cleanInputFunc)
 	if cg.useNS {
 		outFile.WriteString("}")
@@ -332,7 +416,8 @@ func (cg *GQLCLientGenerator) getTypeForImport(pckg *gen.Package, ref *gen.TypeR
 		return "", nil
 	} else {
 		switch ref.Type {
-		case gen.TipBool, gen.TipString, gen.TipInt, gen.TipFloat, gen.TipDate, gen.TipAny:
+		case gen.TipBool, gen.TipString, gen.TipInt, gen.TipFloat, gen.TipDate, gen.TipAny,
+			gen.TipDateTime, gen.TipDecimal, gen.TipTime, gen.TipDuration, gen.TipUUID, gen.TipBytes:
 			return "", nil
 		default:
 			dt, _ := pckg.FindType(ref.Type)
@@ -355,6 +440,16 @@ func (cg *GQLCLientGenerator) getTypeForImport(pckg *gen.Package, ref *gen.TypeR
 					return cg.GetJSEntityInputTypeName(ref.Type), e.File
 				}
 				return cg.GetJSEntityTypeName(ref.Type), e.File
+			} else if u := dt.Union(); u != nil {
+				if forInput {
+					return "", nil
+				}
+				return cg.GetJSEntityTypeName(ref.Type), u.File
+			} else if i := dt.Interface(); i != nil {
+				if forInput {
+					return "", nil
+				}
+				return cg.GetJSEntityTypeName(ref.Type), i.File
 			} else {
 				cg.desc.AddError(fmt.Errorf("at %v: type is not an Entity", dt.Position()))
 				return "", nil
@@ -375,6 +470,8 @@ type ArgDef struct {
 	ExcessFields []string
 	NotNull      bool
 	Optional     bool
+	// VarJSType - type of the variable if it differs from JSType (value is converted with fill function)
+	VarJSType string
 }
 
 type QueryDef struct {
@@ -387,6 +484,8 @@ type QueryDef struct {
 	Args          []ArgDef
 	Fields        []string
 	FillInputName string
+	// Doc - TSDoc comment for the function
+	Doc string
 }
 
 var gqlFunctionsNamesTemplates = [...]string{
@@ -476,10 +575,11 @@ func (cg *GQLCLientGenerator) generateQueriesFile(wr io.Writer, e *gen.Entity) (
 						}
 						ad = []ArgDef{
 							{
-								Name:    "val",
-								Type:    e.Features.String(gen.GQLFeatures, gen.GQLFInputTypeName),
-								JSType:  jstype,
-								NotNull: true,
+								Name:      "val",
+								Type:      e.Features.String(gen.GQLFeatures, gen.GQLFInputTypeName),
+								JSType:    jstype,
+								NotNull:   true,
+								VarJSType: cg.GetJSEntityInputTypeName(e.Name),
 							},
 						}
 					}
@@ -598,6 +698,7 @@ func (cg *GQLCLientGenerator) generateQueriesFile(wr io.Writer, e *gen.Entity) (
 					Args:          ad,
 					Fields:        make([]string, len(fields)),
 					FillInputName: inputInit,
+					Doc:           tsDoc("", e.Annotations),
 				}
 				if i == gen.GQLOperationDelete {
 					params.Fields = []string{}
@@ -644,10 +745,7 @@ func (cg *GQLCLientGenerator) generateQueriesFile(wr io.Writer, e *gen.Entity) (
 						params.Fields = params.Fields[:j]
 					}
 				}
-				th := &templateHolder{templ: template.New("QUERY_VAR")}
-				th.parse(queryTemplate).
-					parse(queryFunctionTemplate).
-					parse(queryTemplateVar)
+				th := cg.newQueryTemplate()
 				if th.err != nil {
 					return fmt.Errorf("while parsing template for %s: %v", params.FuncName, th.err)
 				}
@@ -714,6 +812,7 @@ func (cg *GQLCLientGenerator) processMethods(wr io.Writer, e *gen.Entity) (err e
 			VarName:   qn + "Request",
 			JSRetType: rt,
 			Args:      ad,
+			Doc:       tsDoc(m.Doc, m.Annotations),
 		}
 		j := 0
 		if m.RetValue != nil && m.RetValue.Complex {
@@ -761,10 +860,7 @@ func (cg *GQLCLientGenerator) processMethods(wr io.Writer, e *gen.Entity) (err e
 		if len(params.Fields) > j {
 			params.Fields = params.Fields[:j]
 		}
-		th := &templateHolder{templ: template.New("QUERY_VAR")}
-		th.parse(queryTemplate).
-			parse(queryFunctionTemplate).
-			parse(queryTemplateVar)
+		th := cg.newQueryTemplate()
 		if th.err != nil {
 			fmt.Printf("Error while parsing template: %v\n", th.err)
 			return nil
@@ -822,7 +918,7 @@ func (cg *GQLCLientGenerator) GetJSTypeNameByVivardName(tip string) string {
 		ret = "string"
 	case gen.TipInt, gen.TipFloat:
 		ret = "number"
-	case gen.TipDate:
+	case gen.TipDate, gen.TipDateTime, gen.TipDecimal, gen.TipTime, gen.TipDuration, gen.TipUUID, gen.TipBytes:
 		ret = "string"
 	case gen.TipAny:
 		ret = "any"
@@ -860,7 +956,7 @@ func (cg *GQLCLientGenerator) GetJSInputTypeName(ref *gen.TypeRef, asRef bool) (
 				ret = "string"
 			case gen.TipInt, gen.TipFloat:
 				ret = "number"
-			case gen.TipDate:
+			case gen.TipDate, gen.TipDateTime, gen.TipDecimal, gen.TipTime, gen.TipDuration, gen.TipUUID, gen.TipBytes:
 				ret = "string"
 			default:
 				ret = cg.GetJSEntityInputTypeName(ref.Type)
@@ -889,8 +985,18 @@ func (cg *GQLCLientGenerator) GetJSEmptyVal(ref *gen.TypeRef) (ret string) {
 				ret = "\"\""
 			case gen.TipInt, gen.TipFloat:
 				ret = "0"
-			case gen.TipDate:
+			case gen.TipDate, gen.TipDateTime:
 				ret = "new Date().toISOString()" //"\"1970-01-01 00:00:00\""
+			case gen.TipDecimal:
+				ret = "\"0\""
+			case gen.TipTime:
+				ret = "\"00:00:00\""
+			case gen.TipDuration:
+				ret = "\"0s\""
+			case gen.TipUUID:
+				ret = "\"00000000-0000-0000-0000-000000000000\""
+			case gen.TipBytes:
+				ret = "\"\""
 			default:
 				t, ok := cg.desc.FindType(initType)
 				if ok && t.Enum() != nil {
@@ -948,6 +1054,11 @@ func (cg *GQLCLientGenerator) getFilePathForName(name string) string {
 	return filepath.Join(cg.getOutputDir(), fileName)
 }
 
+// createFile returns file that is written on Close only if its content is changed
+func (cg *GQLCLientGenerator) createFile(path string) *gen.OutputFile {
+	return cg.desc.Project.CreateFile(path)
+}
+
 func (cg *GQLCLientGenerator) getOutputDir() (ret string) {
 	ret = "./gql-ts"
 	if opt := cg.desc.Options().ClientOutputDir; opt != "" {
@@ -958,7 +1069,6 @@ func (cg *GQLCLientGenerator) getOutputDir() (ret string) {
 	}
 
 	ret = filepath.FromSlash(filepath.Join(ret, "types"))
-	os.MkdirAll(ret, os.ModeDir|os.ModePerm)
 	return
 }
 func (cg *GQLCLientGenerator) getQueryForEmbeddedType(
@@ -984,6 +1094,9 @@ func (cg *GQLCLientGenerator) getQueryForEmbeddedType(
 			ret = field
 			return
 		}
+		if tt.IsPolymorphic() {
+			return cg.getQueryForPolymorphicType(field, tt, baseType)
+		}
 		id := ""
 		title := ""
 		if isConfig && tt.Entity() != nil && tt.Entity().HasModifier(gen.TypeModifierDictionary) {
@@ -1045,6 +1158,38 @@ func (cg *GQLCLientGenerator) getQueryForEmbeddedType(
 	return
 }
 
+// getQueryForPolymorphicType returns selection with inline fragment for each possible type of union or interface
+func (cg *GQLCLientGenerator) getQueryForPolymorphicType(
+	field string,
+	dt *gen.DefinedType,
+	baseType *gen.Entity,
+) (ret string, err error) {
+	ret = fmt.Sprintf("%s { __typename", field)
+	for _, e := range dt.PossibleTypes() {
+		fields := ""
+		for _, ff := range e.GetFields(true, true) {
+			name, ok := ff.Annotations.GetStringAnnotation(Annotation, AnnotationName)
+			if !ok {
+				continue
+			}
+			if skip, ok := ff.Features.GetBool(gen.FeaturesAPIKind, gen.FCIgnore); ok && skip {
+				continue
+			}
+			if ff.Type.Complex {
+				r, e := cg.getQueryForEmbeddedType(name, ff, baseType)
+				if e != nil {
+					return "", e
+				}
+				name = r
+			}
+			fields = fmt.Sprintf("%s %s", fields, name)
+		}
+		ret = fmt.Sprintf("%s ... on %s {%s }", ret, e.FS(gen.GQLFeatures, gen.GQLFTypeTag), fields)
+	}
+	ret += " }"
+	return
+}
+
 // Add adds str to file and append \n
 func (cfc CodeFragmentContext) Add(str string) error {
 	if cfc.Output != nil {
@@ -1070,9 +1215,14 @@ func (cg *GQLCLientGenerator) getOperationFunctionName(operation gen.GQLOperatio
 
 func (cg *GQLCLientGenerator) getFuncsMap() template.FuncMap {
 	return template.FuncMap{
+		"TSDoc":       tsDoc,
+		"TSDocInline": tsDocInline,
 		"TypeName": func(e *gen.Entity) string {
 			return e.Annotations.GetStringAnnotationDef(Annotation, AnnotationName, "")
 		},
+		"GQLTypeName": func(e *gen.Entity) string {
+			return e.FS(gen.GQLFeatures, gen.GQLFTypeTag)
+		},
 		"InstanceGenerator": func(e *gen.Entity) string {
 			return e.FS(Features, FInstanceGenerator)
 		},
@@ -1236,7 +1386,7 @@ const queryTemplate = `
 `
 const queryFunctionTemplate = `
 {{define "FUNCTION"}}
-export async function {{.FuncName}}(apollo: ApolloClient<any>, {{if .JSArgType}}arg: {{.JSArgType}}{{else}}{{range $idx, $arg := .Args}}{{if gt $idx 0}}, {{end}}{{$arg.Name}}{{if $arg.Optional}}?{{end}}:{{$arg.JSType}}{{end}}{{end}}): Promise<{{.JSRetType}}> {
+{{.Doc}}export async function {{.FuncName}}(apollo: ApolloClient<any>, {{if .JSArgType}}arg: {{.JSArgType}}{{else}}{{range $idx, $arg := .Args}}{{if gt $idx 0}}, {{end}}{{$arg.Name}}{{if $arg.Optional}}?{{end}}:{{$arg.JSType}}{{end}}{{end}}): Promise<{{.JSRetType}}> {
   let res = await apollo.query({
       query: {{.VarName}},
       fetchPolicy: "no-cache",
@@ -1250,7 +1400,7 @@ export async function {{.FuncName}}(apollo: ApolloClient<any>, {{if .JSArgType}}
 {{end}}
 `
 const typeTemplate = `
-export type {{TypeName .}} = { {{range GetFields .}} {{if ne (FieldName .) "" }}{{FieldName .}}{{if Nullable .}}?{{end}}: {{FieldType .}},{{end}}{{end}} };
+{{TSDoc .Doc .Annotations}}export type {{TypeName .}} = { {{range GetFields .}} {{if ne (FieldName .) "" }}{{TSDocInline .Doc .Annotations}}{{FieldName .}}{{if Nullable .}}?{{end}}: {{FieldType .}},{{end}}{{end}} };
 export function {{InstanceGenerator .}}(): {{TypeName .}} {
   return {
     {{range GetFields .}}{{if NeedInit . }}{{FieldName .}}: {{Init .}},{{end}}
@@ -1261,7 +1411,7 @@ export function {{InstanceGenerator .}}(): {{TypeName .}} {
 
 const inputTypeTemplate = `
 {{if RequiresInput .}}
-export type {{InputTypeName .}} = { {{range GetFields .}} {{if RequiresInput . }}{{InputFieldName .}}{{if not .IsIdField}}?{{end}}: {{InputFieldType .}},{{end}}{{end}} {{range SetNullFields .}} {{.}}?: boolean, {{end}}};
+{{TSDoc .Doc .Annotations}}export type {{InputTypeName .}} = { {{range GetFields .}} {{if RequiresInput . }}{{TSDocInline .Doc .Annotations}}{{InputFieldName .}}{{if not .IsIdField}}?{{end}}: {{InputFieldType .}},{{end}}{{end}} {{range SetNullFields .}} {{.}}?: boolean, {{end}}};
 export function New{{InputTypeName .}}Instance(): {{InputTypeName .}} {
   return {
     {{range GetFields .}}{{if .IsIdField}}{{FieldName .}}: {{Init .}},{{end}}{{end}}
@@ -1270,3 +1420,40 @@ export function New{{InputTypeName .}}Instance(): {{InputTypeName .}} {
 {{end}}
 `
 const queryTemplateVar = "export const {{.VarName}} = gql`{{template \"QUERY\" .}}`\n {{template \"FUNCTION\" .}}\n"
+
+// tsDoc returns TSDoc comment block (with trailing new line) for doc;
+// @deprecated tag is added if annotations contain deprecation
+func tsDoc(doc string, anns ...gen.Annotations) string {
+	lines := tsDocLines(doc, anns)
+	switch len(lines) {
+	case 0:
+		return ""
+	case 1:
+		return "/** " + lines[0] + " */\n"
+	}
+	return "/**\n * " + strings.Join(lines, "\n * ") + "\n */\n"
+}
+
+// tsDocInline returns one-line TSDoc comment for doc to be used in one-line type declarations
+func tsDocInline(doc string, anns ...gen.Annotations) string {
+	lines := tsDocLines(doc, anns)
+	if len(lines) == 0 {
+		return ""
+	}
+	return "/** " + strings.Join(lines, " ") + " */ "
+}
+
+func tsDocLines(doc string, anns []gen.Annotations) []string {
+	lines := gen.DocLines(strings.ReplaceAll(doc, "*/", "*\\/"))
+	for _, a := range anns {
+		if reason, ok := gen.DeprecationReason(a); ok {
+			deprecated := "@deprecated"
+			if reason != "" {
+				deprecated += " " + strings.ReplaceAll(reason, "*/", "*\\/")
+			}
+			lines = append(lines, deprecated)
+			break
+		}
+	}
+	return lines
+}
@@ -0,0 +1,142 @@
+package js
+
+import (
+	"io"
+	"text/template"
+
+	"github.com/vc2402/vivard/gen"
+)
+
+const apollo3Include = "import { ApolloClient, gql, TypedDocumentNode, TypePolicies } from '@apollo/client/core';\n"
+const fetchInclude = "import { GQLClient, gql } from './vivard';\n"
+
+// getIncludes returns imports of GQL client library for the target
+func (cg *GQLCLientGenerator) getIncludes() string {
+	switch cg.target {
+	case GQLClientTargetApollo3:
+		return apollo3Include
+	case GQLClientTargetFetch:
+		return fetchInclude
+	}
+	return apolloClientInclude + gqlInclude
+}
+
+// newQueryTemplate returns template for query variable and function for the target; template is executed with QueryDef
+func (cg *GQLCLientGenerator) newQueryTemplate() *templateHolder {
+	th := &templateHolder{templ: template.New("QUERY_VAR")}
+	th.parse(queryTemplate)
+	switch cg.target {
+	case GQLClientTargetApollo3:
+		th.parse(apollo3QueryFunctionTemplate).
+			parse(apollo3QueryTemplateVar)
+	case GQLClientTargetFetch:
+		th.parse(fetchQueryFunctionTemplate).
+			parse(queryTemplateVar)
+	default:
+		th.parse(queryFunctionTemplate).
+			parse(queryTemplateVar)
+	}
+	return th
+}
+
+// generateTypePolicies generates Apollo cache type policies for the types of the file:
+// types with id field are normalized by it, other types are stored within their parents
+func (cg *GQLCLientGenerator) generateTypePolicies(wr io.Writer, file *gen.File) error {
+	type policy struct {
+		TypeName string
+		KeyField string
+	}
+	var policies []policy
+	for _, e := range file.Entries {
+		if e.HasModifier(gen.TypeModifierSingleton) || e.HasModifier(gen.TypeModifierExternal) {
+			continue
+		}
+		tn := e.FS(gen.GQLFeatures, gen.GQLFTypeTag)
+		if tn == "" {
+			continue
+		}
+		p := policy{TypeName: tn}
+		if idfld := e.GetIdField(); idfld != nil && !e.HasModifier(gen.TypeModifierConfig) {
+			p.KeyField = idfld.Annotations.GetStringAnnotationDef(Annotation, AnnotationName, "")
+		}
+		policies = append(policies, p)
+	}
+	th := &templateHolder{templ: template.New("TYPE_POLICIES")}
+	th.parse(typePoliciesTemplate)
+	if th.err != nil {
+		return th.err
+	}
+	return th.templ.Execute(wr, policies)
+}
+
+const apollo3QueryFunctionTemplate = `
+{{define "FUNCTION"}}
+{{.Doc}}export async function {{.FuncName}}(apollo: ApolloClient<any>, {{if .JSArgType}}arg: {{.JSArgType}}{{else}}{{range $idx, $arg := .Args}}{{if gt $idx 0}}, {{end}}{{$arg.Name}}{{if $arg.Optional}}?{{end}}:{{$arg.JSType}}{{end}}{{end}}): Promise<{{.JSRetType}}> {
+  const res = await apollo.{{if eq .Request "mutation"}}mutate({
+      mutation: {{.VarName}},{{else}}query({
+      query: {{.VarName}},{{end}}
+      fetchPolicy: "no-cache",
+      variables: {{if .JSArgType}}arg{{else}} { {{range $idx, $arg := .Args}}{{if gt $idx 0}}, {{end}}{{$arg.Name}}:{{if ne $.FillInputName ""}} {{$.FillInputName}}({{$arg.Name}}){{else}}cleanInput({{$arg.Name}}){{end}}{{end}} } {{end}}
+    });
+  if(res.data && res.data.{{.QueryName}} !== undefined)
+    return res.data.{{.QueryName}};
+  else
+    throw res.errors;
+}
+{{end}}
+`
+
+const apollo3QueryTemplateVar = "export const {{.VarName}}: TypedDocumentNode<{ {{.QueryName}}: {{.JSRetType}} }, { {{range $idx, $arg := .Args}}{{if gt $idx 0}}, {{end}}{{$arg.Name}}{{if $arg.Optional}}?{{end}}: {{if $arg.VarJSType}}{{$arg.VarJSType}}{{else}}{{$arg.JSType}}{{end}}{{end}} }> = gql`{{template \"QUERY\" .}}`\n {{template \"FUNCTION\" .}}\n"
+
+const fetchQueryFunctionTemplate = `
+{{define "FUNCTION"}}
+{{.Doc}}export async function {{.FuncName}}(client: GQLClient, {{if .JSArgType}}arg: {{.JSArgType}}{{else}}{{range $idx, $arg := .Args}}{{if gt $idx 0}}, {{end}}{{$arg.Name}}{{if $arg.Optional}}?{{end}}:{{$arg.JSType}}{{end}}{{end}}): Promise<{{.JSRetType}}> {
+  const data = await client.request<{ {{.QueryName}}: {{.JSRetType}} }>(
+    {{.VarName}},
+    {{if .JSArgType}}arg{{else}}{ {{range $idx, $arg := .Args}}{{if gt $idx 0}}, {{end}}{{$arg.Name}}:{{if ne $.FillInputName ""}} {{$.FillInputName}}({{$arg.Name}}){{else}}cleanInput({{$arg.Name}}){{end}}{{end}} }{{end}}
+  );
+  return data.{{.QueryName}};
+}
+{{end}}
+`
+
+const typePoliciesTemplate = `
+export const typePolicies: TypePolicies = { {{range .}}
+  {{.TypeName}}: { keyFields: {{if .KeyField}}["{{.KeyField}}"]{{else}}false{{end}} },{{end}}
+};
+`
+
+var vivardFetchClientContent = `
+export type GQLClientOptions = {
+  url: string,
+  headers?: () => Record<string, string> | Promise<Record<string, string>>,
+  fetch?: typeof fetch,
+};
+
+// GQLClient is a minimal GraphQL client based on fetch; it may be used where there is no Apollo (e.g. in Node scripts)
+export class GQLClient {
+  constructor(public options: GQLClientOptions) {
+  }
+
+  async request<T = any>(query: string, variables?: Record<string, any>): Promise<T> {
+    const doFetch = this.options.fetch || fetch;
+    const headers = this.options.headers ? await this.options.headers() : {};
+    const resp = await doFetch(this.options.url, {
+      method: "POST",
+      headers: {"Content-Type": "application/json", ...headers},
+      body: JSON.stringify({query, variables}),
+    });
+    const res = await resp.json();
+    if(res.errors && res.errors.length)
+      throw res.errors;
+    if(!resp.ok)
+      throw new Error(resp.statusText);
+    return res.data as T;
+  }
+}
+
+// gql returns query text; it is used instead of graphql-tag to keep queries as strings
+export function gql(strings: TemplateStringsArray, ...values: any[]): string {
+  return strings.reduce((res, str, i) => res + str + (i < values.length ? values[i] : ""), "");
+}
+`
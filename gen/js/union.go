@@ -0,0 +1,34 @@
+package js
+
+import (
+	"github.com/vc2402/vivard/gen"
+	"io"
+	"text/template"
+)
+
+type unionDef struct {
+	Name        string
+	Doc         string
+	Annotations gen.Annotations
+	Types       []*gen.Entity
+}
+
+// generateUnion generates discriminated union (by __typename) for DSL union or interface
+func (cg *GQLCLientGenerator) generateUnion(wr io.Writer, name string, doc string, anns gen.Annotations, types []*gen.Entity) (err error) {
+	tip := template.New("UNION").
+		Funcs(cg.getFuncsMap())
+	tip, err = tip.Parse(unionTemplate)
+	if err != nil {
+		return err
+	}
+	err = tip.Execute(wr, unionDef{Name: cg.GetJSEntityTypeName(name), Doc: doc, Annotations: anns, Types: types})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+const unionTemplate = `
+{{TSDoc .Doc .Annotations}}export type {{.Name}} = {{if eq (len .Types) 0}}never{{end}}{{range $idx, $t := .Types}}{{if gt $idx 0}}
+  | {{end}}({{TypeName $t}} & { __typename: "{{GQLTypeName $t}}" }){{end}};
+`
@@ -18,13 +18,18 @@ type TSValidatorGenerator struct {
 }
 
 func init() {
-	gen.RegisterPlugin(&TSValidatorGenerator{})
+	gen.RegisterPluginFactory(func() gen.Generator { return &TSValidatorGenerator{} })
 }
 
 func (cg *TSValidatorGenerator) Name() string {
 	return ValidatorGeneratorName
 }
 
+// After from gen.PluginsFollower
+func (cg *TSValidatorGenerator) After() []string {
+	return []string{gen.ValidatorGeneratorName}
+}
+
 func (cg *TSValidatorGenerator) CheckAnnotation(desc *gen.Package, ann *gen.Annotation, item interface{}) (bool, error) {
 	if ann.Name == Annotation {
 		return true, nil
@@ -32,6 +37,11 @@ func (cg *TSValidatorGenerator) CheckAnnotation(desc *gen.Package, ann *gen.Anno
 	return false, nil
 }
 
+// AnnotationSchemas from gen.AnnotationsDescriber
+func (cg *TSValidatorGenerator) AnnotationSchemas() []gen.AnnotationSchema {
+	return annotationSchemas()
+}
+
 func (cg *TSValidatorGenerator) Prepare(desc *gen.Package) error {
 	cg.desc = desc
 	for _, file := range desc.Files {
@@ -1,7 +1,6 @@
 package js
 
 import (
-	"os"
 	"path/filepath"
 )
 
@@ -12,12 +11,12 @@ func (cg *GQLCLientGenerator) GenerateVivard() (err error) {
 		return
 	}
 	p := filepath.Join(cg.getOutputDir(), vivardFileName)
-	outFile, err := os.Create(p)
-	if err != nil {
-		return
-	}
+	outFile := cg.createFile(p)
 	defer outFile.Close()
 	outFile.WriteString(vivardFileContent)
+	if cg.target == GQLClientTargetFetch {
+		outFile.WriteString(vivardFetchClientContent)
+	}
 
 	cg.vivardGenerated = true
 	return nil
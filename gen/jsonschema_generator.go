@@ -0,0 +1,598 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const (
+	jsonSchemaGeneratorName = "JSONSchema"
+	jsonSchemaOptionsName   = "json-schema"
+
+	// JSONSchemaAnnotation may be used for types: $json-schema(skip) excludes type from schemas;
+	// and for fields: $json-schema(skip) excludes field, other tags are validation keywords added to field's schema,
+	// e.g. $json-schema(minLength=1 maxLength=64 pattern="^[A-Z]") or $json-schema(minimum=0 maxItems=10)
+	JSONSchemaAnnotation        = "json-schema"
+	JSONSchemaAnnotationSkipTag = "skip"
+
+	jsonSchemaDialect       = "https://json-schema.org/draft/2020-12/schema"
+	jsonSchemaDefsRef       = "#/$defs/"
+	jsonSchemaDefaultDir    = "jsonschema"
+	jsonSchemaFileSuffix    = ".schema.json"
+	jsonSchemaInputSuffix   = "Input"
+	jsonSchemaSetVarName    = "JSONSchemas"
+	jsonSchemaTypeNull      = "null"
+	jsonSchemaKeywordString = "string"
+	jsonSchemaKeywordNumber = "number"
+	jsonSchemaKeywordInt    = "int"
+	jsonSchemaKeywordBool   = "bool"
+)
+
+// jsonSchemaKeywords - validation keywords allowed in JSONSchemaAnnotation with kind of their values
+var jsonSchemaKeywords = map[string]string{
+	"minimum":          jsonSchemaKeywordNumber,
+	"maximum":          jsonSchemaKeywordNumber,
+	"exclusiveMinimum": jsonSchemaKeywordNumber,
+	"exclusiveMaximum": jsonSchemaKeywordNumber,
+	"multipleOf":       jsonSchemaKeywordNumber,
+	"minLength":        jsonSchemaKeywordInt,
+	"maxLength":        jsonSchemaKeywordInt,
+	"pattern":          jsonSchemaKeywordString,
+	"format":           jsonSchemaKeywordString,
+	"minItems":         jsonSchemaKeywordInt,
+	"maxItems":         jsonSchemaKeywordInt,
+	"uniqueItems":      jsonSchemaKeywordBool,
+	"minProperties":    jsonSchemaKeywordInt,
+	"maxProperties":    jsonSchemaKeywordInt,
+}
+
+// jsonSchemaContainerKeywords - keywords that are applied to array or map itself (others are applied to its items)
+var jsonSchemaContainerKeywords = map[string]bool{
+	"minItems":      true,
+	"maxItems":      true,
+	"uniqueItems":   true,
+	"minProperties": true,
+	"maxProperties": true,
+}
+
+type JSONSchemaOptions struct {
+	// OutputDir - directory for schema files (%s is replaced with package name); <output>/<package>/jsonschema by default
+	OutputDir string `json:"output_dir"`
+	// IDPrefix - prefix for $id of schemas (e.g. https://example.com/schemas/); $id is not set if empty
+	IDPrefix string `json:"id_prefix"`
+	// Embed - add schemas to generated Go package as vivard.JSONSchemaSet for validation at runtime
+	Embed bool `json:"embed"`
+}
+
+// JSONSchemaGenerator writes JSON Schema (draft 2020-12) of JSON representation of generated Go types:
+// <Type>.schema.json for each type (including $find param types) and <Type>Input.schema.json for input of
+// not readonly types (without readonly fields and with optional auto id). Each schema is self-contained:
+// referred types are put to $defs
+type JSONSchemaGenerator struct {
+	proj    *Project
+	desc    *Package
+	b       *Builder
+	options JSONSchemaOptions
+	// packages - packages for which schemas are written
+	packages map[string]bool
+}
+
+// jsonSchemaBuilder builds one schema document; referred types are collected into defs
+type jsonSchemaBuilder struct {
+	desc  *Package
+	input bool
+	// root - name of the def that is the document itself (it is referred as #)
+	root    string
+	defs    *yamlMap
+	pending []*DefinedType
+	added   map[string]bool
+}
+
+func init() {
+	RegisterPluginFactory(func() Generator { return &JSONSchemaGenerator{} })
+}
+
+func (cg *JSONSchemaGenerator) Name() string {
+	return jsonSchemaGeneratorName
+}
+
+func (cg *JSONSchemaGenerator) SetOptions(opts any) error {
+	return OptionsAnyToStruct(opts, &cg.options)
+}
+
+func (cg *JSONSchemaGenerator) SetDescriptor(proj *Project) {
+	cg.proj = proj
+	cg.packages = map[string]bool{}
+	if _, err := proj.Options.CustomToStruct(jsonSchemaOptionsName, &cg.options); err != nil {
+		proj.AddWarning(fmt.Sprintf("problem while setting custom options for %s: %v", jsonSchemaOptionsName, err))
+	}
+}
+
+func (cg *JSONSchemaGenerator) CheckAnnotation(desc *Package, ann *Annotation, item interface{}) (bool, error) {
+	if ann.Name != JSONSchemaAnnotation {
+		return false, nil
+	}
+	switch item.(type) {
+	case *Entity:
+		for _, tag := range ann.Values {
+			if tag.Key != JSONSchemaAnnotationSkipTag {
+				return true, fmt.Errorf("at %v: annotation %s: unknown tag for type: %s", tag.Pos, JSONSchemaAnnotation, tag.Key)
+			}
+		}
+		return true, nil
+	case *Field:
+		for _, tag := range ann.Values {
+			if tag.Key == JSONSchemaAnnotationSkipTag {
+				continue
+			}
+			kind, ok := jsonSchemaKeywords[tag.Key]
+			if !ok {
+				return true, fmt.Errorf("at %v: annotation %s: unknown keyword: %s", tag.Pos, JSONSchemaAnnotation, tag.Key)
+			}
+			if _, err := jsonSchemaKeywordValue(tag, kind); err != nil {
+				return true, fmt.Errorf("at %v: annotation %s: %s: %w", tag.Pos, JSONSchemaAnnotation, tag.Key, err)
+			}
+		}
+		return true, nil
+	}
+	return true, fmt.Errorf("at %v: annotation %s may be used for types and fields only", ann.Pos, JSONSchemaAnnotation)
+}
+
+// AnnotationSchemas from AnnotationsDescriber
+func (cg *JSONSchemaGenerator) AnnotationSchemas() []AnnotationSchema {
+	tags := []AnnotationTagSchema{
+		{Name: JSONSchemaAnnotationSkipTag, Type: AnnotationTagBool, Default: false, Doc: "exclude type or field from schemas"},
+	}
+	keywords := make([]string, 0, len(jsonSchemaKeywords))
+	for keyword := range jsonSchemaKeywords {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	for _, keyword := range keywords {
+		tag := AnnotationTagSchema{Name: keyword, Doc: "validation keyword of field's schema (fields only)"}
+		switch jsonSchemaKeywords[keyword] {
+		case jsonSchemaKeywordString:
+			tag.Type = AnnotationTagString
+		case jsonSchemaKeywordBool:
+			tag.Type = AnnotationTagBool
+		case jsonSchemaKeywordInt:
+			tag.Type = AnnotationTagNumber
+			tag.Doc = "validation keyword of field's schema (fields only; non negative integer)"
+		default:
+			tag.Type = AnnotationTagNumber
+		}
+		tags = append(tags, tag)
+	}
+	return []AnnotationSchema{
+		{
+			Name:    JSONSchemaAnnotation,
+			Targets: AnnotationTargetEntity | AnnotationTargetField,
+			Tags:    tags,
+			Doc:     "JSON Schema settings: skip for type, skip and validation keywords for field",
+		},
+	}
+}
+
+func (cg *JSONSchemaGenerator) Prepare(desc *Package) error {
+	cg.desc = desc
+	return nil
+}
+
+func (cg *JSONSchemaGenerator) Generate(b *Builder) (err error) {
+	cg.desc = b.Descriptor
+	cg.b = b
+	if cg.packages[b.Descriptor.Name] {
+		return nil
+	}
+	cg.packages[b.Descriptor.Name] = true
+	schemas, err := cg.packageSchemas(b.Descriptor)
+	if err != nil {
+		return err
+	}
+	if err = cg.writeSchemas(b.Descriptor, schemas); err != nil {
+		return err
+	}
+	if cg.options.Embed && len(schemas.keys) > 0 {
+		return cg.generateSchemaSet(schemas)
+	}
+	return nil
+}
+
+// packageSchemas returns schema documents of the package by name (type name or type name with Input suffix)
+func (cg *JSONSchemaGenerator) packageSchemas(desc *Package) (*yamlMap, error) {
+	schemas := newYAMLMap()
+	for _, file := range desc.Files {
+		for _, t := range file.Entries {
+			if t.HasModifier(TypeModifierExternal) || t.Annotations.GetBoolAnnotationDef(JSONSchemaAnnotation, JSONSchemaAnnotationSkipTag, false) {
+				continue
+			}
+			dt, ok := desc.FindType(t.Name)
+			if !ok {
+				return nil, fmt.Errorf("at %v: json schema: type %s not found", t.Pos, t.Name)
+			}
+			schemas.set(t.Name, cg.document(desc, dt, false))
+			_, isFind := t.Features.Get(FeaturesAPIKind, FAPIFindFor)
+			if !isFind && !t.FB(FeaturesCommonKind, FCReadonly) && !t.HasModifier(TypeModifierSingleton) {
+				schemas.set(t.Name+jsonSchemaInputSuffix, cg.document(desc, dt, true))
+			}
+		}
+		for _, e := range file.Enums {
+			if dt, ok := desc.FindType(e.Name); ok {
+				schemas.set(e.Name, cg.document(desc, dt, false))
+			}
+		}
+		for _, u := range file.Unions {
+			if dt, ok := desc.FindType(u.Name); ok {
+				schemas.set(u.Name, cg.document(desc, dt, false))
+			}
+		}
+		for _, i := range file.Interfaces {
+			if dt, ok := desc.FindType(i.Name); ok {
+				schemas.set(i.Name, cg.document(desc, dt, false))
+			}
+		}
+	}
+	return schemas, nil
+}
+
+// document returns self-contained schema of the type
+func (cg *JSONSchemaGenerator) document(desc *Package, dt *DefinedType, input bool) *yamlMap {
+	sb := &jsonSchemaBuilder{desc: desc, input: input, defs: newYAMLMap(), added: map[string]bool{}}
+	sb.root = sb.defName(dt)
+	sb.added[sb.root] = true
+	ret := newYAMLMap().set("$schema", jsonSchemaDialect)
+	if cg.options.IDPrefix != "" {
+		ret.set("$id", cg.options.IDPrefix+sb.root+jsonSchemaFileSuffix)
+	}
+	ret.set("title", sb.root)
+	schema := sb.typeSchema(dt)
+	for _, k := range schema.keys {
+		ret.set(k, schema.vals[k])
+	}
+	if dt.entry != nil && schema.get("description") == nil {
+		if f, ok := dt.entry.Features.GetEntity(FeaturesAPIKind, FAPIFindFor); ok {
+			ret.set("description", fmt.Sprintf("parameters of find for %s", f.Name))
+		}
+	}
+	for len(sb.pending) > 0 {
+		ref := sb.pending[0]
+		sb.pending = sb.pending[1:]
+		sb.defs.set(sb.defName(ref), sb.typeSchema(ref))
+	}
+	if len(sb.defs.keys) > 0 {
+		ret.set("$defs", sb.defs)
+	}
+	return ret
+}
+
+func (cg *JSONSchemaGenerator) outputDir(desc *Package) string {
+	dir := cg.options.OutputDir
+	if dir == "" {
+		return filepath.Join(cg.proj.Options.OutputDir, desc.Name, jsonSchemaDefaultDir)
+	}
+	if strings.Contains(dir, "%s") {
+		dir = fmt.Sprintf(dir, desc.Name)
+	}
+	return dir
+}
+
+func (cg *JSONSchemaGenerator) writeSchemas(desc *Package, schemas *yamlMap) error {
+	if len(schemas.keys) == 0 {
+		return nil
+	}
+	dir := cg.outputDir(desc)
+	for _, name := range schemas.keys {
+		data, err := json.MarshalIndent(schemas.vals[name], "", "  ")
+		if err != nil {
+			return fmt.Errorf("json schema %s: %w", name, err)
+		}
+		if err = cg.proj.WriteFile(filepath.Join(dir, name+jsonSchemaFileSuffix), append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateSchemaSet adds var with schemas of the package (vivard.JSONSchemaSet) to generated code
+func (cg *JSONSchemaGenerator) generateSchemaSet(schemas *yamlMap) error {
+	names := append([]string(nil), schemas.keys...)
+	sort.Strings(names)
+	dict := jen.Dict{}
+	for _, name := range names {
+		data, err := json.Marshal(schemas.vals[name])
+		if err != nil {
+			return fmt.Errorf("json schema %s: %w", name, err)
+		}
+		dict[jen.Lit(name)] = jen.Lit(string(data))
+	}
+	cg.b.Functions.Add(
+		jen.Comment(
+			fmt.Sprintf(
+				"%s - JSON Schemas of the types of the package by type name (<Type>%s for input); use %s.Validate(name, data) for validation of JSON documents",
+				jsonSchemaSetVarName,
+				jsonSchemaInputSuffix,
+				jsonSchemaSetVarName,
+			),
+		).Line().
+			Var().Id(jsonSchemaSetVarName).Op("=").Qual(VivardPackage, "NewJSONSchemaSet").Params(
+			jen.Map(jen.String()).String().Values(dict),
+		).Line(),
+	)
+	return nil
+}
+
+// typeSchema returns schema for entity (object with properties named as json tags of Go struct), enum or polymorphic type
+func (sb *jsonSchemaBuilder) typeSchema(dt *DefinedType) *yamlMap {
+	ret := newYAMLMap()
+	var doc string
+	switch {
+	case dt.enum != nil:
+		e := dt.enum
+		doc = e.Doc
+		ret.set("type", jsonSchemaPrimitive(e.AliasForType).get("type"))
+		var vals []interface{}
+		for i, f := range e.Fields {
+			switch {
+			case f.IntVal != nil:
+				vals = append(vals, *f.IntVal)
+			case f.FloatVal != nil:
+				vals = append(vals, *f.FloatVal)
+			case f.StringVal != nil:
+				vals = append(vals, *f.StringVal)
+			default:
+				vals = append(vals, i)
+			}
+		}
+		ret.set("enum", vals)
+	case dt.IsPolymorphic():
+		// holder of polymorphic value adds discriminator to JSON of the value
+		var oneOf []interface{}
+		for _, e := range dt.PossibleTypes() {
+			oneOf = append(
+				oneOf,
+				sb.entityRef(e).
+					set("properties", newYAMLMap().set(PolymorphicDiscriminator, newYAMLMap().set("const", e.Name))).
+					set("required", []interface{}{PolymorphicDiscriminator}),
+			)
+		}
+		if dt.union != nil {
+			doc = dt.union.Doc
+		} else if dt.iface != nil {
+			doc = dt.iface.Doc
+		}
+		ret.set("oneOf", oneOf)
+	case dt.entry != nil:
+		e := dt.entry
+		doc = e.Doc
+		ret.set("type", "object")
+		props := newYAMLMap()
+		var required []interface{}
+		for _, f := range e.GetFields(true, true) {
+			if f.HasModifier(AttrModifierAuxiliary) ||
+				f.FB(FeaturesCommonKind, FCIgnore) ||
+				f.FB(FeaturesAPIKind, FCIgnore) ||
+				f.FB(FeatGoKind, FCGCalculated) ||
+				f.Annotations.GetBoolAnnotationDef(JSONSchemaAnnotation, JSONSchemaAnnotationSkipTag, false) ||
+				sb.input && f.FB(FeaturesCommonKind, FCReadonly) {
+				continue
+			}
+			name := jsonSchemaFieldName(f)
+			if name == "" {
+				continue
+			}
+			props.set(name, sb.fieldSchema(f))
+			if f.IsIdField() && !(sb.input && f.HasModifier(AttrModifierIDAuto)) || !f.IsIdField() && f.Type.NonNullable {
+				required = append(required, name)
+			}
+		}
+		ret.set("properties", props)
+		if len(required) > 0 {
+			ret.set("required", required)
+		}
+	}
+	if doc != "" {
+		ret.set("description", doc)
+	}
+	if _, ok := dt.Deprecation(); ok {
+		ret.set("deprecated", true)
+	}
+	return ret
+}
+
+// fieldSchema returns schema of the field with validation keywords from annotation; not NonNullable field may be null
+func (sb *jsonSchemaBuilder) fieldSchema(f *Field) *yamlMap {
+	asRef := f.HasModifier(AttrModifierEmbeddedRef) || f.FB(GQLFeatures, GQLFIDOnly)
+	schema := sb.refSchema(f.Type, asRef)
+	if ann, ok := f.Annotations[JSONSchemaAnnotation]; ok {
+		for _, tag := range ann.Values {
+			kind, ok := jsonSchemaKeywords[tag.Key]
+			if !ok {
+				continue
+			}
+			val, _ := jsonSchemaKeywordValue(tag, kind)
+			target := schema
+			if !jsonSchemaContainerKeywords[tag.Key] {
+				for ref := f.Type; ref.Array != nil || ref.Map != nil; {
+					if ref.Array != nil {
+						target, ref = target.get("items").(*yamlMap), ref.Array
+					} else {
+						target, ref = target.get("additionalProperties").(*yamlMap), ref.Map.ValueType
+					}
+				}
+			}
+			target.set(tag.Key, val)
+		}
+	}
+	if !f.Type.NonNullable && !f.IsIdField() {
+		schema = jsonSchemaNullable(schema)
+	}
+	if f.Doc != "" {
+		schema.set("description", f.Doc)
+	}
+	if _, ok := DeprecationReason(f.Annotations); ok {
+		schema.set("deprecated", true)
+	}
+	return schema
+}
+
+// refSchema returns schema for JSON representation of Go type generated for ref
+func (sb *jsonSchemaBuilder) refSchema(ref *TypeRef, asRef bool) *yamlMap {
+	if ref.Array != nil {
+		return newYAMLMap().set("type", "array").set("items", sb.refSchema(ref.Array, asRef))
+	}
+	if ref.Map != nil {
+		return newYAMLMap().set("type", "object").set("additionalProperties", sb.refSchema(ref.Map.ValueType, false))
+	}
+	if IsPrimitiveType(ref.Type) {
+		return jsonSchemaPrimitive(ref.Type)
+	}
+	dt, ok := sb.desc.FindType(ref.Type)
+	if !ok {
+		return newYAMLMap()
+	}
+	if e := dt.entry; e != nil && !dt.IsPolymorphic() {
+		if e.HasModifier(TypeModifierExternal) {
+			return newYAMLMap().set("description", fmt.Sprintf("external type %s", ref.Type))
+		}
+		// references to stored types keep id only (see Builder.GoType)
+		storedRef := !ref.Embedded &&
+			!e.HasModifier(TypeModifierEmbeddable) &&
+			!e.HasModifier(TypeModifierTransient) &&
+			!e.HasModifier(TypeModifierConfig)
+		if (asRef || storedRef) && e.GetIdField() != nil {
+			return sb.refSchema(e.GetIdField().Type, false).set("description", fmt.Sprintf("id of %s", e.Name))
+		}
+	}
+	return sb.ref(dt)
+}
+
+func (sb *jsonSchemaBuilder) entityRef(e *Entity) *yamlMap {
+	name := e.Name
+	if e.Pckg != sb.desc {
+		name = e.Pckg.Name + "." + e.Name
+	}
+	dt, ok := sb.desc.FindType(name)
+	if !ok {
+		return newYAMLMap()
+	}
+	return sb.ref(dt)
+}
+
+func (sb *jsonSchemaBuilder) ref(dt *DefinedType) *yamlMap {
+	name := sb.defName(dt)
+	if name == sb.root {
+		return newYAMLMap().set("$ref", "#")
+	}
+	if !sb.added[name] {
+		sb.added[name] = true
+		sb.pending = append(sb.pending, dt)
+	}
+	return newYAMLMap().set("$ref", jsonSchemaDefsRef+name)
+}
+
+// defName returns name of the type in $defs; entities have separate defs for input
+func (sb *jsonSchemaBuilder) defName(dt *DefinedType) string {
+	name := dt.name
+	if dt.pckg != sb.desc.Name {
+		name = dt.pckg + "." + name
+	}
+	if sb.input && dt.entry != nil && !dt.IsPolymorphic() {
+		name += jsonSchemaInputSuffix
+	}
+	return name
+}
+
+func jsonSchemaPrimitive(tip string) *yamlMap {
+	ret := newYAMLMap()
+	switch tip {
+	case TipString:
+		ret.set("type", "string")
+	case TipInt:
+		ret.set("type", "integer")
+	case TipFloat:
+		ret.set("type", "number")
+	case TipBool:
+		ret.set("type", "boolean")
+	case TipDate, TipDateTime:
+		ret.set("type", "string").set("format", "date-time")
+	case TipDecimal:
+		// vivard.Decimal is encoded as string and may be decoded from number
+		ret.set("type", []interface{}{"string", "number"}).set("format", "decimal")
+	case TipTime:
+		ret.set("type", []interface{}{"string", "integer"}).set("description", "time of day (15:04:05) or number of seconds")
+	case TipDuration:
+		ret.set("type", "integer").set("description", "duration in nanoseconds")
+	case TipUUID:
+		ret.set("type", "string").set("format", "uuid")
+	case TipBytes:
+		ret.set("type", "string").set("contentEncoding", "base64")
+	}
+	return ret
+}
+
+// jsonSchemaNullable returns schema that allows null in addition to values allowed by schema
+func jsonSchemaNullable(schema *yamlMap) *yamlMap {
+	switch t := schema.get("type").(type) {
+	case string:
+		if schema.get("enum") == nil {
+			return schema.set("type", []interface{}{t, jsonSchemaTypeNull})
+		}
+	case []interface{}:
+		return schema.set("type", append(t, jsonSchemaTypeNull))
+	case nil:
+		if len(schema.keys) == 0 || len(schema.keys) == 1 && schema.get("description") != nil {
+			// any value is allowed
+			return schema
+		}
+	}
+	return newYAMLMap().set("anyOf", []interface{}{schema, newYAMLMap().set("type", jsonSchemaTypeNull)})
+}
+
+// jsonSchemaFieldName returns name of the field in JSON representation of Go struct
+func jsonSchemaFieldName(f *Field) string {
+	if tag, ok := f.Tags["json"]; ok {
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	if name, ok := f.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag); ok {
+		return name
+	}
+	return f.FS(FeatGoKind, FCGName)
+}
+
+// jsonSchemaKeywordValue returns value of annotation tag for keyword of the kind
+func jsonSchemaKeywordValue(tag *AnnotationTag, kind string) (interface{}, error) {
+	v := tag.Value
+	switch kind {
+	case jsonSchemaKeywordBool:
+		if v == nil {
+			return true, nil
+		}
+		if v.Bool != nil {
+			return bool(*v.Bool), nil
+		}
+	case jsonSchemaKeywordString:
+		if v != nil && v.String != nil {
+			return *v.String, nil
+		}
+	case jsonSchemaKeywordNumber:
+		if v != nil && v.Number != nil {
+			return *v.Number, nil
+		}
+	case jsonSchemaKeywordInt:
+		if v != nil && v.Number != nil && *v.Number >= 0 && *v.Number == float64(int(*v.Number)) {
+			return int(*v.Number), nil
+		}
+	}
+	return nil, fmt.Errorf("%s value expected", kind)
+}
@@ -57,7 +57,7 @@ const (
 )
 
 func init() {
-	RegisterPlugin(&LoggerGenerator{variant: VariantZap})
+	RegisterPluginFactory(func() Generator { return &LoggerGenerator{variant: VariantZap} })
 }
 
 func (cg *LoggerGenerator) Name() string {
@@ -0,0 +1,393 @@
+package lsp
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/lexer"
+	"github.com/vc2402/vivard/gen"
+)
+
+// declaration keywords
+const (
+	kwType      = "type"
+	kwEnum      = "enum"
+	kwUnion     = "union"
+	kwInterface = "interface"
+	kwMixin     = "mixin"
+	kwPackage   = "package"
+	kwExtends   = "extends"
+	kwImplement = "implements"
+	kwUse       = "use"
+	kwImport    = "import"
+	kwMeta      = "meta"
+)
+
+// analysis is the result of parsing and checking of workspace files
+type analysis struct {
+	files map[string]*fileInfo
+	// decls - declarations by qualified name (package.Name)
+	decls map[string]*declaration
+	// parsed - files parsed and checked with project; nil if parsing failed
+	parsed         []*gen.File
+	project        *gen.Project
+	diagnostics    []gen.Diagnostic
+	defaultPackage string
+	// annotations - known annotations names
+	annotations []string
+	// tags - tags used for annotations in workspace by annotation name
+	tags map[string]map[string]bool
+}
+
+// fileInfo is lexical information of workspace file; it is available even if the file can not be parsed
+type fileInfo struct {
+	path       string
+	text       string
+	lineStarts []int
+	tokens     []tokenInfo
+	// pckg - package of the file ("" if not declared)
+	pckg  string
+	decls []*declaration
+}
+
+type tokenInfo struct {
+	gen.SourceToken
+	rng textRange
+	// depth - depth of braces the token is inside
+	depth int
+}
+
+// declaration is declaration of type, enum, union, interface or mixin
+type declaration struct {
+	kind string
+	name string
+	file *fileInfo
+	// token - index of name token
+	token int
+	// start and end - indexes of tokens of declaration body
+	start, end int
+}
+
+func newAnalysis(
+	files []string,
+	sources map[string][]byte,
+	init func(files []*gen.File) (*gen.Project, error),
+) *analysis {
+	a := &analysis{
+		files: map[string]*fileInfo{},
+		decls: map[string]*declaration{},
+		tags:  map[string]map[string]bool{},
+	}
+	for _, path := range files {
+		text, ok := sources[path]
+		if !ok {
+			var err error
+			text, err = os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+		}
+		a.files[path] = newFileInfo(path, string(text))
+	}
+	a.project, a.diagnostics = gen.CheckSources(files, sources, init)
+	if a.project != nil {
+		a.parsed = a.project.Files
+	} else if proj, err := init(nil); err == nil {
+		a.project = proj
+	}
+	if a.project != nil {
+		a.defaultPackage = a.project.Options.DefaultPackage
+		a.annotations = a.project.KnownAnnotations()
+	}
+	for _, fi := range a.files {
+		for _, d := range fi.decls {
+			a.decls[a.qualified(fi, d.name)] = d
+		}
+	}
+	a.collectTags()
+	return a
+}
+
+func newFileInfo(path string, text string) *fileInfo {
+	fi := &fileInfo{path: path, text: text, lineStarts: []int{0}}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			fi.lineStarts = append(fi.lineStarts, i+1)
+		}
+	}
+	// tokens before lexer error are still useful
+	tokens, _ := gen.Tokenize(path, []byte(text))
+	depth := 0
+	for _, t := range tokens {
+		if t.Kind == "Punct" && t.Value == "}" && depth > 0 {
+			depth--
+		}
+		fi.tokens = append(
+			fi.tokens,
+			tokenInfo{
+				SourceToken: t,
+				rng:         textRange{Start: fi.position(t.Pos.Offset), End: fi.position(t.Pos.Offset + len(t.Value))},
+				depth:       depth,
+			},
+		)
+		if t.Kind == "Punct" && t.Value == "{" {
+			depth++
+		}
+	}
+	fi.findDeclarations()
+	return fi
+}
+
+// findDeclarations looks for package and top level declarations
+func (fi *fileInfo) findDeclarations() {
+	for i := 0; i < len(fi.tokens)-1; i++ {
+		t := fi.tokens[i]
+		if t.depth > 0 || t.Kind != "Ident" {
+			continue
+		}
+		next := fi.tokens[i+1]
+		if next.Kind != "Ident" && next.Kind != "QualifiedName" {
+			continue
+		}
+		switch t.Value {
+		case kwPackage:
+			fi.pckg = next.Value
+		case kwType, kwEnum, kwUnion, kwInterface, kwMixin:
+			d := &declaration{kind: t.Value, name: next.Value, file: fi, token: i + 1, start: i + 2, end: len(fi.tokens)}
+			for j := i + 2; j < len(fi.tokens); j++ {
+				tok := fi.tokens[j]
+				if tok.Kind == "Punct" &&
+					(tok.Value == "}" && tok.depth == 0 || tok.Value == ";" && tok.depth == 0 && t.Value == kwUnion) {
+					d.end = j
+					break
+				}
+			}
+			fi.decls = append(fi.decls, d)
+		}
+	}
+}
+
+// position converts byte offset in text to LSP position
+func (fi *fileInfo) position(offset int) position {
+	if offset > len(fi.text) {
+		offset = len(fi.text)
+	}
+	line := sort.Search(len(fi.lineStarts), func(i int) bool { return fi.lineStarts[i] > offset }) - 1
+	return position{Line: line, Character: utf16Len(fi.text[fi.lineStarts[line]:offset])}
+}
+
+// offset converts LSP position to byte offset in text
+func (fi *fileInfo) offset(pos position) int {
+	if pos.Line >= len(fi.lineStarts) {
+		return len(fi.text)
+	}
+	offset := fi.lineStarts[pos.Line]
+	for units := 0; units < pos.Character && offset < len(fi.text) && fi.text[offset] != '\n'; {
+		r, size := utf8.DecodeRuneInString(fi.text[offset:])
+		units += len(utf16.Encode([]rune{r}))
+		offset += size
+	}
+	return offset
+}
+
+// tokenAt returns index of token that contains pos (token that ends at pos is accepted too)
+func (fi *fileInfo) tokenAt(pos position) int {
+	offset := fi.offset(pos)
+	for i, t := range fi.tokens {
+		if t.Pos.Offset <= offset && offset <= t.Pos.Offset+len(t.Value) {
+			if offset == t.Pos.Offset+len(t.Value) && i+1 < len(fi.tokens) && fi.tokens[i+1].Pos.Offset == offset {
+				// cursor between two tokens: prefer the next one
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// enclosing returns declaration the token belongs to
+func (fi *fileInfo) enclosing(token int) *declaration {
+	for _, d := range fi.decls {
+		if d.token <= token && token <= d.end {
+			return d
+		}
+	}
+	return nil
+}
+
+func (a *analysis) packageOf(fi *fileInfo) string {
+	if fi.pckg != "" {
+		return fi.pckg
+	}
+	return a.defaultPackage
+}
+
+func (a *analysis) qualified(fi *fileInfo, name string) string {
+	return a.packageOf(fi) + "." + name
+}
+
+// resolve looks for declaration of the name used in file fi
+func (a *analysis) resolve(fi *fileInfo, name string) *declaration {
+	if d, ok := a.decls[a.qualified(fi, name)]; ok {
+		return d
+	}
+	if strings.Contains(name, ".") {
+		if d, ok := a.decls[name]; ok {
+			return d
+		}
+	}
+	return nil
+}
+
+// declarationAt returns declaration referenced (or declared) by token at pos
+func (a *analysis) declarationAt(path string, pos position) (*fileInfo, int, *declaration) {
+	fi, ok := a.files[path]
+	if !ok {
+		return nil, -1, nil
+	}
+	idx := fi.tokenAt(pos)
+	if idx == -1 {
+		return fi, -1, nil
+	}
+	t := fi.tokens[idx]
+	if t.Kind != "Ident" && t.Kind != "QualifiedName" {
+		return fi, idx, nil
+	}
+	return fi, idx, a.resolve(fi, t.Value)
+}
+
+func (a *analysis) tokenAt(path string, pos position) (tokenInfo, bool) {
+	if fi, ok := a.files[path]; ok {
+		if idx := fi.tokenAt(pos); idx != -1 {
+			return fi.tokens[idx], true
+		}
+	}
+	return tokenInfo{}, false
+}
+
+// character returns LSP character of diagnostic position (column in lexer.Position is counted in runes)
+func (a *analysis) character(path string, pos lexer.Position) int {
+	fi, ok := a.files[path]
+	if !ok || pos.Line < 1 || pos.Line > len(fi.lineStarts) {
+		return 0
+	}
+	line := fi.text[fi.lineStarts[pos.Line-1]:]
+	if end := strings.IndexByte(line, '\n'); end != -1 {
+		line = line[:end]
+	}
+	runes := []rune(line)
+	col := pos.Column - 1
+	if col < 0 {
+		col = 0
+	}
+	if col > len(runes) {
+		col = len(runes)
+	}
+	return len(utf16.Encode(runes[:col]))
+}
+
+// entity returns parsed declaration (*gen.Entity, *gen.Enum, *gen.Union or *gen.Interface) if it is available
+func (a *analysis) entity(d *declaration) interface{} {
+	for _, f := range a.parsed {
+		if f.Path != d.file.path {
+			continue
+		}
+		switch d.kind {
+		case kwType:
+			for _, e := range f.Entries {
+				if e.Name == d.name {
+					return e
+				}
+			}
+		case kwEnum:
+			for _, e := range f.Enums {
+				if e.Name == d.name {
+					return e
+				}
+			}
+		case kwUnion:
+			for _, u := range f.Unions {
+				if u.Name == d.name {
+					return u
+				}
+			}
+		case kwInterface:
+			for _, i := range f.Interfaces {
+				if i.Name == d.name {
+					return i
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// collectTags collects tags of annotations used in workspace
+func (a *analysis) collectTags() {
+	add := func(ann *gen.Annotation) {
+		if ann == nil {
+			return
+		}
+		name, _, _ := strings.Cut(ann.Name, ":")
+		if a.tags[name] == nil {
+			a.tags[name] = map[string]bool{}
+		}
+		for _, v := range ann.Values {
+			a.tags[name][v.Key] = true
+		}
+	}
+	schemas := gen.AnnotationsReference()
+	if a.project != nil {
+		schemas = a.project.AnnotationSchemas()
+	}
+	for _, s := range schemas {
+		if a.tags[s.Name] == nil {
+			a.tags[s.Name] = map[string]bool{}
+		}
+		for _, tag := range s.Tags {
+			a.tags[s.Name][tag.Name] = true
+		}
+	}
+	for _, f := range a.parsed {
+		for _, m := range f.Modifiers {
+			add(m.Annotation)
+		}
+		for _, e := range f.Entries {
+			for _, m := range e.Modifiers {
+				add(m.Annotation)
+			}
+			for _, entry := range e.Entries {
+				for _, m := range entry.Modifiers {
+					add(m.Annotation)
+				}
+			}
+		}
+		for _, e := range f.Enums {
+			for _, m := range e.Modifiers {
+				add(m.Annotation)
+			}
+		}
+	}
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += len(utf16.Encode([]rune{r}))
+	}
+	return n
+}
+
+// applyChange applies incremental change to text
+func applyChange(text string, rng textRange, newText string) string {
+	fi := &fileInfo{text: text, lineStarts: []int{0}}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			fi.lineStarts = append(fi.lineStarts, i+1)
+		}
+	}
+	return text[:fi.offset(rng.Start)] + newText + text[fi.offset(rng.End):]
+}
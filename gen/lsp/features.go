@@ -0,0 +1,418 @@
+package lsp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vc2402/vivard/gen"
+)
+
+var identRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var primitiveTypes = []string{
+	gen.TipString, gen.TipInt, gen.TipFloat, gen.TipBool, gen.TipDate, gen.TipAny, gen.TipDateTime, gen.TipDecimal,
+	gen.TipTime, gen.TipDuration, gen.TipUUID, gen.TipBytes,
+}
+
+var typeModifiers = []gen.TypeModifier{
+	gen.TypeModifierAbstract, gen.TypeModifierConfig, gen.TypeModifierDictionary, gen.TypeModifierEmbeddable,
+	gen.TypeModifierExtendable, gen.TypeModifierExternal, gen.TypeModifierSingleton, gen.TypeModifierTransient,
+}
+
+// attrModifiers are AttrModifier values accepted by the parser
+var attrModifiers = []string{
+	string(gen.AttrModifierID), string(gen.AttrModifierIDAuto), gen.AnnotationLookup, string(gen.AttrModifierOneToMany),
+	string(gen.AttrModifierEmbedded), string(gen.AttrModifierEmbeddedRef), string(gen.AttrModifierCalculated),
+}
+
+var typeHooks = []string{
+	gen.TypeHookCreate, gen.TypeHookChange, gen.TypeHookChanged, gen.TypeHookStart, gen.TypeHookMethod, gen.TypeHookTime,
+	gen.TypeHookDelete,
+}
+
+var attrHooks = []string{gen.AttrHookSet, gen.AttrHookCalculate, gen.MethodHookTime}
+
+var declarationKeywords = []string{kwType, kwEnum, kwUnion, kwInterface, kwMixin, kwPackage, kwImport, kwMeta, kwUse}
+
+func (s *Server) definition(params *textDocumentPositionParams) interface{} {
+	if s.analysis == nil {
+		return nil
+	}
+	_, _, d := s.analysis.declarationAt(uriToPath(params.TextDocument.URI), params.Position)
+	if d == nil {
+		return nil
+	}
+	return []location{{URI: pathToURI(d.file.path), Range: d.file.tokens[d.token].rng}}
+}
+
+func (s *Server) hover(params *textDocumentPositionParams) interface{} {
+	if s.analysis == nil {
+		return nil
+	}
+	a := s.analysis
+	fi, idx, d := a.declarationAt(uriToPath(params.TextDocument.URI), params.Position)
+	if idx == -1 {
+		return nil
+	}
+	tok := fi.tokens[idx]
+	var text string
+	if d != nil {
+		text = a.describeDeclaration(d)
+	} else if idx+1 < len(fi.tokens) && fi.tokens[idx+1].Kind == "Punct" {
+		if encl := fi.enclosing(idx); encl != nil && tok.depth > 0 {
+			switch fi.tokens[idx+1].Value {
+			case ":":
+				text = a.describeField(encl, tok.Value)
+			case "(":
+				text = a.describeMethod(encl, tok.Value)
+			}
+		}
+	}
+	if text == "" {
+		return nil
+	}
+	return &hover{Contents: markupContent{Kind: "markdown", Value: text}, Range: &tok.rng}
+}
+
+func (a *analysis) describeDeclaration(d *declaration) string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "**%s** `%s.%s`", d.kind, a.packageOf(d.file), d.name)
+	switch e := a.entity(d).(type) {
+	case *gen.Entity:
+		if e.BaseTypeName != "" {
+			fmt.Fprintf(sb, " extends `%s`", e.BaseTypeName)
+		}
+		if len(e.Implements) > 0 {
+			fmt.Fprintf(sb, " implements `%s`", strings.Join(e.Implements, "`, `"))
+		}
+		sb.WriteString("\n\n")
+		writeDoc(sb, e.Doc)
+		var mods []string
+		for _, m := range typeModifiers {
+			if e.HasModifier(m) {
+				mods = append(mods, string(m))
+			}
+		}
+		if len(mods) > 0 {
+			fmt.Fprintf(sb, "modifiers: %s\n\n", strings.Join(mods, ", "))
+		}
+		fmt.Fprintf(sb, "Go: `%s`", goName(e.Features, e.Name))
+		if name, ok := e.Features.GetString(gen.GQLFeatures, gen.GQLFTypeTag); ok {
+			fmt.Fprintf(sb, "\n\nGraphQL: `%s`", name)
+			if input, ok := e.Features.GetString(gen.GQLFeatures, gen.GQLFInputTypeName); ok {
+				fmt.Fprintf(sb, " (input `%s`)", input)
+			}
+		}
+	case *gen.Enum:
+		sb.WriteString("\n\n")
+		writeDoc(sb, e.Doc)
+		values := make([]string, len(e.Fields))
+		for i, f := range e.Fields {
+			values[i] = f.Name
+		}
+		fmt.Fprintf(sb, "values: %s", strings.Join(values, ", "))
+		if name, ok := e.Features.GetString(gen.GQLFeatures, gen.GQLFTypeTag); ok {
+			fmt.Fprintf(sb, "\n\nGraphQL: `%s`", name)
+		}
+	case *gen.Union:
+		fmt.Fprintf(sb, " = `%s`\n\n", strings.Join(e.TypeNames, " | "))
+		writeDoc(sb, e.Doc)
+		if name, ok := e.Features.GetString(gen.GQLFeatures, gen.GQLFTypeTag); ok {
+			fmt.Fprintf(sb, "GraphQL: `%s`", name)
+		}
+	case *gen.Interface:
+		sb.WriteString("\n\n")
+		writeDoc(sb, e.Doc)
+		if name, ok := e.Features.GetString(gen.GQLFeatures, gen.GQLFTypeTag); ok {
+			fmt.Fprintf(sb, "GraphQL: `%s`", name)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func (a *analysis) describeField(encl *declaration, name string) string {
+	var fld *gen.Field
+	switch e := a.entity(encl).(type) {
+	case *gen.Entity:
+		fld = e.FieldsIndex[name]
+	case *gen.Interface:
+		fld = e.FieldsIndex[name]
+	}
+	if fld == nil {
+		return ""
+	}
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "**field** `%s.%s`: `%s`", encl.name, fld.Name, fld.Type.String())
+	if d := a.resolve(encl.file, baseTypeName(fld.Type)); d != nil {
+		fmt.Fprintf(sb, " (%s `%s.%s`)", d.kind, a.packageOf(d.file), d.name)
+	}
+	sb.WriteString("\n\n")
+	writeDoc(sb, fld.Doc)
+	fmt.Fprintf(sb, "Go: `%s`", goName(fld.Features, fld.Name))
+	if t, ok := fld.Features.Get(gen.FeatGoKind, gen.FCGAttrType); ok && t != nil {
+		fmt.Fprintf(sb, " `%#v`", t)
+	}
+	if tip, ok := fld.Features.GetString(gen.GQLFeatures, gen.GQLFTypeTag); ok {
+		fmt.Fprintf(
+			sb,
+			"\n\nGraphQL: `%s: %s`",
+			fld.Annotations.GetStringAnnotationDef(gen.GQLAnnotation, gen.GQLAnnotationNameTag, fld.Name),
+			tip,
+		)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func (a *analysis) describeMethod(encl *declaration, name string) string {
+	e, ok := a.entity(encl).(*gen.Entity)
+	if !ok || e.MethodsIndex[name] == nil {
+		return ""
+	}
+	m := e.MethodsIndex[name]
+	params := make([]string, len(m.Params))
+	for i, p := range m.Params {
+		params[i] = p.Name + ": " + p.Type.String()
+	}
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "**method** `%s.%s(%s)`", encl.name, m.Name, strings.Join(params, ", "))
+	if m.RetValue != nil {
+		fmt.Fprintf(sb, ": `%s`", m.RetValue.String())
+	}
+	sb.WriteString("\n\n")
+	writeDoc(sb, m.Doc)
+	if a.project == nil {
+		return strings.TrimSpace(sb.String())
+	}
+	if tip, ok := a.project.GetFeature(m, gen.GQLFeatures, gen.GQLFMethodResultTypeName).(string); ok {
+		fmt.Fprintf(sb, "GraphQL result: `%s`", tip)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func writeDoc(sb *strings.Builder, doc string) {
+	if doc != "" {
+		sb.WriteString(doc)
+		sb.WriteString("\n\n")
+	}
+}
+
+func goName(features gen.Features, def string) string {
+	if name, ok := features.GetString(gen.FeatGoKind, gen.FCGName); ok && name != "" {
+		return name
+	}
+	return def
+}
+
+func baseTypeName(ref *gen.TypeRef) string {
+	for ref != nil {
+		switch {
+		case ref.Array != nil:
+			ref = ref.Array
+		case ref.Map != nil:
+			ref = ref.Map.ValueType
+		default:
+			return ref.Type
+		}
+	}
+	return ""
+}
+
+func (s *Server) completion(params *textDocumentPositionParams) interface{} {
+	items := []completionItem{}
+	if s.analysis == nil {
+		return items
+	}
+	a := s.analysis
+	fi, ok := a.files[uriToPath(params.TextDocument.URI)]
+	if !ok {
+		return items
+	}
+	if params.Position.Line >= len(fi.lineStarts) {
+		return items
+	}
+	offset := fi.offset(params.Position)
+	lineStart := fi.lineStarts[params.Position.Line]
+	prefix := fi.text[lineStart:offset]
+	word := prefix[len(strings.TrimRightFunc(prefix, isWordRune)):]
+	before := strings.TrimRight(prefix[:len(prefix)-len(word)], " \t")
+	depth := 0
+	for _, t := range fi.tokens {
+		if t.Pos.Offset >= offset {
+			break
+		}
+		depth = t.depth
+		if t.Kind == "Punct" && t.Value == "{" {
+			depth++
+		}
+	}
+	inModifiers := strings.LastIndex(before, "<") > strings.LastIndex(before, ">")
+
+	switch {
+	case strings.HasSuffix(before, "$"):
+		for _, name := range a.annotations {
+			items = append(items, completionItem{Label: name, Kind: completionKindProperty, Detail: "annotation"})
+		}
+	case strings.HasSuffix(before, "@"):
+		hooks := typeHooks
+		if inModifiers {
+			hooks = attrHooks
+		}
+		for _, h := range hooks {
+			items = append(items, completionItem{Label: h, Kind: completionKindEvent, Detail: "hook"})
+		}
+	case annotationInProgress(before) != "":
+		name := annotationInProgress(before)
+		for tag := range a.tags[name] {
+			items = append(items, completionItem{Label: tag, Kind: completionKindProperty, Detail: "$" + name + " tag"})
+		}
+	case inModifiers:
+		for _, m := range attrModifiers {
+			items = append(items, completionItem{Label: m, Kind: completionKindKeyword, Detail: "attribute modifier"})
+		}
+	case strings.HasSuffix(before, kwUse) && depth == 0:
+		for _, d := range a.sortedDeclarations() {
+			if d.kind == kwMixin {
+				items = append(items, completionItem{Label: d.name, Kind: completionKindModule, Detail: "mixin"})
+			}
+		}
+	case depth > 0 && (strings.HasSuffix(before, ":") || strings.HasSuffix(before, "[") || strings.HasSuffix(before, "]")) ||
+		depth == 0 && (strings.HasSuffix(before, kwExtends) || strings.HasSuffix(before, kwImplement) ||
+			strings.HasSuffix(before, ",") || strings.HasSuffix(before, "|") || strings.HasSuffix(before, "=")):
+		items = a.typeCompletions(fi)
+	case depth == 0:
+		for _, kw := range declarationKeywords {
+			items = append(items, completionItem{Label: kw, Kind: completionKindKeyword})
+		}
+		for _, m := range typeModifiers {
+			items = append(items, completionItem{Label: string(m), Kind: completionKindKeyword, Detail: "type modifier"})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+	return items
+}
+
+func (a *analysis) typeCompletions(fi *fileInfo) (items []completionItem) {
+	for _, p := range primitiveTypes {
+		items = append(items, completionItem{Label: p, Kind: completionKindKeyword, Detail: "primitive type"})
+	}
+	pckg := a.packageOf(fi)
+	for _, d := range a.sortedDeclarations() {
+		if d.kind == kwMixin {
+			continue
+		}
+		label := d.name
+		if dp := a.packageOf(d.file); dp != pckg {
+			label = dp + "." + d.name
+		}
+		kind := completionKindClass
+		if d.kind == kwEnum {
+			kind = completionKindEnum
+		}
+		items = append(items, completionItem{Label: label, Kind: kind, Detail: d.kind})
+	}
+	return
+}
+
+func (a *analysis) sortedDeclarations() []*declaration {
+	ret := make([]*declaration, 0, len(a.decls))
+	for _, d := range a.decls {
+		ret = append(ret, d)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].name < ret[j].name })
+	return ret
+}
+
+// annotationInProgress returns name of annotation if text ends inside its parameters list
+func annotationInProgress(text string) string {
+	open := strings.LastIndex(text, "(")
+	if open == -1 || strings.LastIndex(text, ")") > open {
+		return ""
+	}
+	head := text[:open]
+	start := strings.LastIndex(head, "$")
+	if start == -1 || strings.ContainsAny(head[start:], " \t") {
+		return ""
+	}
+	name, _, _ := strings.Cut(head[start+1:], ":")
+	return name
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r == '-' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+func (s *Server) prepareRename(params *textDocumentPositionParams) interface{} {
+	if s.analysis == nil {
+		return nil
+	}
+	fi, idx, d := s.analysis.declarationAt(uriToPath(params.TextDocument.URI), params.Position)
+	if d == nil || strings.Contains(d.name, ".") {
+		return nil
+	}
+	return nameRange(fi.tokens[idx])
+}
+
+func (s *Server) rename(params *renameParams) (interface{}, error) {
+	if s.analysis == nil {
+		return nil, nil
+	}
+	a := s.analysis
+	_, _, target := a.declarationAt(uriToPath(params.TextDocument.URI), params.Position)
+	if target == nil {
+		return nil, &rpcError{Code: rpcRequestFailed, Message: "no type declaration found at position"}
+	}
+	if strings.Contains(target.name, ".") {
+		return nil, &rpcError{Code: rpcRequestFailed, Message: "external types can not be renamed"}
+	}
+	if !identRE.MatchString(params.NewName) {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("invalid type name: %s", params.NewName)}
+	}
+	if d := a.resolve(target.file, params.NewName); d != nil {
+		return nil, &rpcError{
+			Code:    rpcRequestFailed,
+			Message: fmt.Sprintf("%s %s already exists in package %s", d.kind, d.name, a.packageOf(d.file)),
+		}
+	}
+	edit := &workspaceEdit{Changes: map[string][]textEdit{}}
+	for path, fi := range a.files {
+		var edits []textEdit
+		for i, t := range fi.tokens {
+			if t.Kind != "Ident" && t.Kind != "QualifiedName" || !isTypeReference(fi, i) {
+				continue
+			}
+			if a.resolve(fi, t.Value) == target {
+				edits = append(edits, textEdit{Range: *nameRange(t), NewText: params.NewName})
+			}
+		}
+		if len(edits) > 0 {
+			edit.Changes[pathToURI(path)] = edits
+		}
+	}
+	return edit, nil
+}
+
+// isTypeReference returns false for tokens that can not be type names (names of fields, methods, annotation tags, enum values)
+func isTypeReference(fi *fileInfo, idx int) bool {
+	if idx+1 < len(fi.tokens) {
+		next := fi.tokens[idx+1]
+		if next.Kind == "Punct" && (next.Value == ":" || next.Value == "(" || next.Value == "=") {
+			// union declaration is the only case when type name is followed by '='
+			return idx > 0 && fi.tokens[idx-1].Value == kwUnion && next.Value == "="
+		}
+	}
+	if encl := fi.enclosing(idx); encl != nil && encl.kind == kwEnum && idx != encl.token {
+		return false
+	}
+	return true
+}
+
+// nameRange returns range of type name in token (without package qualifier)
+func nameRange(t tokenInfo) *textRange {
+	rng := t.rng
+	if dot := strings.LastIndex(t.Value, "."); dot != -1 {
+		rng.Start.Character += utf16Len(t.Value[:dot+1])
+	}
+	return &rng
+}
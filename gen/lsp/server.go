@@ -0,0 +1,451 @@
+// Package lsp implements Language Server Protocol server for vivard DSL files
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/vc2402/vivard/gen"
+)
+
+// DefaultExtension is the extension of DSL files looked for in workspace if no patterns are given
+const DefaultExtension = ".vvf"
+
+const serverName = "vivard-lsp"
+
+// Options of the Server
+type Options struct {
+	// Patterns - masks of workspace files (relative to workspace root); all the DefaultExtension files are used if empty
+	Patterns []string
+	// InitProject creates project for parsed files (with options and plugins as vivgen does)
+	InitProject func(files []*gen.File) (*gen.Project, error)
+	// Version is reported to client
+	Version string
+	// Log is used for tracing of the server (may be nil)
+	Log io.Writer
+}
+
+// Server is LSP server for vivard DSL; it works over single connection (usually stdin/stdout)
+type Server struct {
+	options  Options
+	root     string
+	out      io.Writer
+	docs     map[string]*document
+	analysis *analysis
+	// published - files with published diagnostics (to clear them later)
+	published map[string]bool
+	shutdown  bool
+}
+
+// document is text document opened in the editor
+type document struct {
+	path    string
+	text    string
+	version int
+}
+
+// NewServer creates LSP server
+func NewServer(options Options) *Server {
+	return &Server{
+		options:   options,
+		docs:      map[string]*document{},
+		published: map[string]bool{},
+	}
+}
+
+// Run serves requests from in writing responses to out until exit notification or end of input
+func (s *Server) Run(in io.Reader, out io.Writer) error {
+	s.out = out
+	r := bufio.NewReader(in)
+	for {
+		msg, err := readMessage(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			var rerr *rpcError
+			if errors.As(err, &rerr) {
+				s.logf(messageTypeError, "invalid message: %v", err)
+				continue
+			}
+			return err
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit without shutdown")
+			}
+			return nil
+		}
+		s.handle(msg)
+	}
+}
+
+func (s *Server) handle(msg *rpcMessage) {
+	defer func() {
+		if r := recover(); r != nil {
+			buf := make([]byte, 4096)
+			buf = buf[:runtime.Stack(buf, false)]
+			s.logf(messageTypeError, "panic while processing %s: %v\n%s", msg.Method, r, buf)
+			if msg.ID != nil {
+				s.replyError(msg.ID, &rpcError{Code: rpcRequestFailed, Message: fmt.Sprintf("internal error: %v", r)})
+			}
+		}
+	}()
+	s.trace("<- %s", msg.Method)
+	result, err := s.dispatch(msg)
+	if msg.ID == nil {
+		if err != nil {
+			s.logf(messageTypeError, "%s: %v", msg.Method, err)
+		}
+		return
+	}
+	if err != nil {
+		var rerr *rpcError
+		if !errors.As(err, &rerr) {
+			rerr = &rpcError{Code: rpcRequestFailed, Message: err.Error()}
+		}
+		s.replyError(msg.ID, rerr)
+		return
+	}
+	s.send(rpcResponse{JSONRPC: "2.0", ID: msg.ID, Result: result})
+}
+
+func (s *Server) dispatch(msg *rpcMessage) (interface{}, error) {
+	switch msg.Method {
+	case "initialize":
+		var params initializeParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(&params), nil
+	case "initialized":
+		s.analyze()
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		s.docs[params.TextDocument.URI] = &document{
+			path:    uriToPath(params.TextDocument.URI),
+			text:    params.TextDocument.Text,
+			version: params.TextDocument.Version,
+		}
+		s.analyze()
+		return nil, nil
+	case "textDocument/didChange":
+		var params didChangeTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		doc, ok := s.docs[params.TextDocument.URI]
+		if !ok {
+			return nil, fmt.Errorf("document is not opened: %s", params.TextDocument.URI)
+		}
+		for _, change := range params.ContentChanges {
+			if change.Range == nil {
+				doc.text = change.Text
+			} else {
+				doc.text = applyChange(doc.text, *change.Range, change.Text)
+			}
+		}
+		s.analyze()
+		return nil, nil
+	case "textDocument/didSave":
+		var params didSaveTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		if doc, ok := s.docs[params.TextDocument.URI]; ok && params.Text != nil {
+			doc.text = *params.Text
+		}
+		s.analyze()
+		return nil, nil
+	case "textDocument/didClose":
+		var params didCloseTextDocumentParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		s.analyze()
+		return nil, nil
+	case "workspace/didChangeWatchedFiles":
+		s.analyze()
+		return nil, nil
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.definition(&params), nil
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(&params), nil
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.completion(&params), nil
+	case "textDocument/prepareRename":
+		var params textDocumentPositionParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.prepareRename(&params), nil
+	case "textDocument/rename":
+		var params renameParams
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+		return s.rename(&params)
+	}
+	if strings.HasPrefix(msg.Method, "$/") || msg.ID == nil {
+		// optional notifications may be ignored
+		return nil, nil
+	}
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method not supported: %s", msg.Method)}
+}
+
+func (s *Server) initialize(params *initializeParams) *initializeResult {
+	switch {
+	case params.RootURI != "":
+		s.root = uriToPath(params.RootURI)
+	case len(params.WorkspaceFolders) > 0:
+		s.root = uriToPath(params.WorkspaceFolders[0].URI)
+	case params.RootPath != "":
+		s.root = params.RootPath
+	default:
+		s.root, _ = os.Getwd()
+	}
+	return &initializeResult{
+		Capabilities: serverCapabilities{
+			TextDocumentSync:   textDocumentSyncOptions{OpenClose: true, Change: syncFull, Save: saveOptions{IncludeText: true}},
+			DefinitionProvider: true,
+			HoverProvider:      true,
+			CompletionProvider: completionOptions{TriggerCharacters: []string{"$", "@", "<", ":", "("}},
+			RenameProvider:     renameOptions{PrepareProvider: true},
+		},
+		ServerInfo: serverInfo{Name: serverName, Version: s.options.Version},
+	}
+}
+
+// workspaceFiles returns absolute paths of workspace files along with opened documents
+func (s *Server) workspaceFiles() []string {
+	found := map[string]bool{}
+	var files []string
+	add := func(path string) {
+		if !found[path] {
+			found[path] = true
+			files = append(files, path)
+		}
+	}
+	if s.root != "" {
+		if len(s.options.Patterns) > 0 {
+			for _, pattern := range s.options.Patterns {
+				if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(s.root, pattern)
+				}
+				matches, err := filepath.Glob(pattern)
+				if err != nil {
+					s.logf(messageTypeWarning, "invalid pattern %s: %v", pattern, err)
+					continue
+				}
+				for _, m := range matches {
+					add(m)
+				}
+			}
+		} else {
+			_ = filepath.WalkDir(
+				s.root,
+				func(path string, d os.DirEntry, err error) error {
+					if err != nil {
+						return nil
+					}
+					if d.IsDir() {
+						if path != s.root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+							return filepath.SkipDir
+						}
+						return nil
+					}
+					if filepath.Ext(path) == DefaultExtension {
+						add(path)
+					}
+					return nil
+				},
+			)
+		}
+	}
+	for _, doc := range s.docs {
+		add(doc.path)
+	}
+	return files
+}
+
+// analyze parses and checks workspace and publishes diagnostics
+func (s *Server) analyze() {
+	sources := map[string][]byte{}
+	for _, doc := range s.docs {
+		sources[doc.path] = []byte(doc.text)
+	}
+	s.analysis = newAnalysis(s.workspaceFiles(), sources, s.options.InitProject)
+	s.publishDiagnostics()
+}
+
+func (s *Server) publishDiagnostics() {
+	byFile := map[string][]diagnostic{}
+	for _, d := range s.analysis.diagnostics {
+		if d.Pos.Filename == "" {
+			s.logf(messageTypeWarning, "%s", d)
+			continue
+		}
+		path := d.Pos.Filename
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(s.root, path)
+		}
+		severity := severityError
+		if d.Severity == gen.SeverityWarning {
+			severity = severityWarning
+		}
+		line := d.Pos.Line - 1
+		if line < 0 {
+			line = 0
+		}
+		start := position{Line: line, Character: s.analysis.character(path, d.Pos)}
+		end := start
+		if tok, ok := s.analysis.tokenAt(path, start); ok {
+			end = tok.rng.End
+		}
+		byFile[path] = append(
+			byFile[path],
+			diagnostic{Range: textRange{Start: start, End: end}, Severity: severity, Source: serverName, Message: d.Message},
+		)
+	}
+	for path := range s.published {
+		if _, ok := byFile[path]; !ok {
+			s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: pathToURI(path), Diagnostics: []diagnostic{}})
+			delete(s.published, path)
+		}
+	}
+	for path, diagnostics := range byFile {
+		s.published[path] = true
+		s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: pathToURI(path), Diagnostics: diagnostics})
+	}
+}
+
+func (s *Server) send(msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		s.trace("can't marshal message: %v", err)
+		return
+	}
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	if err != nil {
+		s.trace("can't write message: %v", err)
+	}
+}
+
+func (s *Server) replyError(id *json.RawMessage, err *rpcError) {
+	s.send(
+		struct {
+			JSONRPC string           `json:"jsonrpc"`
+			ID      *json.RawMessage `json:"id"`
+			Error   *rpcError        `json:"error"`
+		}{"2.0", id, err},
+	)
+}
+
+func (s *Server) notify(method string, params interface{}) {
+	s.send(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (s *Server) logf(messageType int, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	s.trace("%s", msg)
+	s.notify("window/logMessage", logMessageParams{Type: messageType, Message: msg})
+}
+
+func (s *Server) trace(format string, args ...interface{}) {
+	if s.options.Log != nil {
+		fmt.Fprintf(s.options.Log, format+"\n", args...)
+	}
+}
+
+// readMessage reads one message with base protocol header
+func readMessage(r *bufio.Reader) (*rpcMessage, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length: %s", value)
+			}
+		}
+	}
+	if length < 0 {
+		return nil, errors.New("no Content-Length header")
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	msg := &rpcMessage{}
+	if err := json.Unmarshal(data, msg); err != nil {
+		return nil, &rpcError{Code: rpcParseError, Message: err.Error()}
+	}
+	return msg, nil
+}
+
+func unmarshalParams(msg *rpcMessage, params interface{}) error {
+	if len(msg.Params) == 0 {
+		return &rpcError{Code: rpcInvalidParams, Message: "params expected"}
+	}
+	if err := json.Unmarshal(msg.Params, params); err != nil {
+		return &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := filepath.FromSlash(u.Path)
+	if runtime.GOOS == "windows" && strings.HasPrefix(path, `\`) {
+		path = path[1:]
+	}
+	return path
+}
+
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
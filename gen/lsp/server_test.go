@@ -0,0 +1,313 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vc2402/vivard/gen"
+)
+
+func TestApplyChange(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		rng  textRange
+		new  string
+		want string
+	}{
+		{
+			name: "ascii",
+			text: "type A {\n  a: int;\n}\n",
+			rng:  textRange{Start: position{Line: 1, Character: 5}, End: position{Line: 1, Character: 8}},
+			new:  "string",
+			want: "type A {\n  a: string;\n}\n",
+		},
+		{
+			name: "surrogate pair counts as two units",
+			text: "// 😀 B\ntype B {}",
+			rng:  textRange{Start: position{Line: 0, Character: 6}, End: position{Line: 0, Character: 7}},
+			new:  "C",
+			want: "// 😀 C\ntype B {}",
+		},
+		{
+			name: "cyrillic counts as one unit",
+			text: "// заказ\ntype B {}",
+			rng:  textRange{Start: position{Line: 0, Character: 3}, End: position{Line: 0, Character: 8}},
+			new:  "order",
+			want: "// order\ntype B {}",
+		},
+		{
+			name: "multiline",
+			text: "type A {\n  a: int;\n}\ntype B {}\n",
+			rng:  textRange{Start: position{Line: 0, Character: 7}, End: position{Line: 2, Character: 1}},
+			new:  "{}",
+			want: "type A {}\ntype B {}\n",
+		},
+		{
+			name: "character beyond end of line",
+			text: "type A {}\ntype B {}",
+			rng:  textRange{Start: position{Line: 0, Character: 100}, End: position{Line: 0, Character: 100}},
+			new:  " // A",
+			want: "type A {} // A\ntype B {}",
+		},
+		{
+			name: "append after last line",
+			text: "type A {}",
+			rng:  textRange{Start: position{Line: 1, Character: 0}, End: position{Line: 1, Character: 0}},
+			new:  "\n",
+			want: "type A {}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := applyChange(tt.text, tt.rng, tt.new); got != tt.want {
+					t.Errorf("applyChange() = %q, want %q", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestTokenAt(t *testing.T) {
+	fi := newFileInfo("a.vvf", "$gql(name=\"заказ\") type Order {\n  name: string;\n}\n")
+	tests := []struct {
+		pos  position
+		want string
+	}{
+		{position{Line: 0, Character: 0}, "$gql"},
+		// Cyrillic letters take two bytes but one UTF-16 unit, so Order starts at 24
+		{position{Line: 0, Character: 11}, "\"заказ\""},
+		{position{Line: 0, Character: 24}, "Order"},
+		{position{Line: 0, Character: 26}, "Order"},
+		// end of token followed by space
+		{position{Line: 0, Character: 29}, "Order"},
+		{position{Line: 1, Character: 3}, "name"},
+		// between name and ':' the next token wins
+		{position{Line: 1, Character: 6}, ":"},
+		{position{Line: 1, Character: 10}, "string"},
+		{position{Line: 1, Character: 0}, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if idx := fi.tokenAt(tt.pos); idx != -1 {
+			got = fi.tokens[idx].Value
+		}
+		if got != tt.want {
+			t.Errorf("tokenAt(%v) = %q, want %q", tt.pos, got, tt.want)
+		}
+	}
+	want := textRange{Start: position{Line: 0, Character: 24}, End: position{Line: 0, Character: 29}}
+	if idx := fi.tokenAt(position{Line: 0, Character: 24}); idx == -1 || fi.tokens[idx].rng != want {
+		t.Errorf("unexpected range of Order: %+v", fi.tokens[idx].rng)
+	}
+}
+
+const (
+	customerSource = "package shop;\n\ntype Customer {\n  name: string;\n  customerID: int <id>;\n}\n"
+	orderSource    = "package shop;\n\n$find(Customer)\ntransient type CustomerQuery {\n  name: string;\n}\n\ntype Order {\n  customer: Customer;\n  orderID: int <id>;\n}\n"
+)
+
+func TestRename(t *testing.T) {
+	root, s := testWorkspace(t, map[string]string{"a.vvf": customerSource, "b.vvf": orderSource})
+	a, b := pathToURI(filepath.Join(root, "a.vvf")), pathToURI(filepath.Join(root, "b.vvf"))
+	ses := &testSession{}
+	ses.request("initialize", initializeParams{RootURI: pathToURI(root)})
+	ses.notify("initialized", nil)
+	rename := ses.request(
+		"textDocument/rename",
+		renameParams{TextDocument: textDocumentIdentifier{URI: a}, Position: position{Line: 2, Character: 7}, NewName: "Client"},
+	)
+	existing := ses.request(
+		"textDocument/rename",
+		renameParams{TextDocument: textDocumentIdentifier{URI: b}, Position: position{Line: 8, Character: 14}, NewName: "Order"},
+	)
+	msgs := ses.run(t, s)
+
+	var edit workspaceEdit
+	msgs.result(t, rename, &edit)
+	edits := func(line, start, end int) textEdit {
+		return textEdit{
+			Range:   textRange{Start: position{Line: line, Character: start}, End: position{Line: line, Character: end}},
+			NewText: "Client",
+		}
+	}
+	want := map[string][]textEdit{
+		a: {edits(2, 5, 13)},
+		b: {edits(2, 6, 14), edits(8, 12, 20)},
+	}
+	if !reflect.DeepEqual(edit.Changes, want) {
+		t.Errorf("rename: got %+v, want %+v", edit.Changes, want)
+	}
+
+	if err := msgs.error(existing); err == nil || !strings.Contains(err.Message, "type Order already exists") {
+		t.Errorf("rename to existing type: unexpected error %v", err)
+	}
+}
+
+func TestDiagnosticsAfterEdit(t *testing.T) {
+	root, s := testWorkspace(t, map[string]string{"a.vvf": customerSource, "b.vvf": orderSource})
+	b := pathToURI(filepath.Join(root, "b.vvf"))
+	colon := textRange{Start: position{Line: 8, Character: 10}, End: position{Line: 8, Character: 11}}
+	ses := &testSession{}
+	ses.request("initialize", initializeParams{RootURI: pathToURI(root)})
+	ses.notify("initialized", nil)
+	ses.notify("textDocument/didOpen", didOpenTextDocumentParams{TextDocument: textDocumentItem{URI: b, Text: orderSource}})
+	ses.change(b, &colon, "")
+	ses.change(b, &textRange{Start: colon.Start, End: colon.Start}, ":")
+	msgs := ses.run(t, s)
+
+	published := msgs.diagnostics(t)
+	if len(published) != 2 {
+		t.Fatalf("expected diagnostics to be published twice; got %+v", published)
+	}
+	broken := published[0]
+	if broken.URI != b || len(broken.Diagnostics) != 1 {
+		t.Fatalf("unexpected diagnostics for broken edit: %+v", broken)
+	}
+	d := broken.Diagnostics[0]
+	if d.Severity != severityError || !strings.Contains(d.Message, `unexpected token "customer"`) {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+	want := textRange{Start: position{Line: 8, Character: 2}, End: position{Line: 8, Character: 10}}
+	if d.Range != want {
+		t.Errorf("unexpected diagnostic range: %+v", d.Range)
+	}
+	if fixed := published[1]; fixed.URI != b || len(fixed.Diagnostics) != 0 {
+		t.Errorf("diagnostics should be cleared after fix: %+v", fixed)
+	}
+}
+
+// testWorkspace writes files to temporary workspace and creates server for it
+func testWorkspace(t *testing.T, files map[string]string) (string, *Server) {
+	t.Helper()
+	root := t.TempDir()
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root, NewServer(
+		Options{
+			InitProject: func(files []*gen.File) (*gen.Project, error) {
+				return gen.New(files, gen.Options(root)), nil
+			},
+		},
+	)
+}
+
+// testSession collects client messages to be processed by Server.Run
+type testSession struct {
+	in bytes.Buffer
+	id int
+}
+
+func (ts *testSession) write(msg interface{}) {
+	data, _ := json.Marshal(msg)
+	fmt.Fprintf(&ts.in, "Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+func (ts *testSession) request(method string, params interface{}) int {
+	ts.id++
+	ts.write(map[string]interface{}{"jsonrpc": "2.0", "id": ts.id, "method": method, "params": params})
+	return ts.id
+}
+
+func (ts *testSession) notify(method string, params interface{}) {
+	ts.write(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (ts *testSession) change(uri string, rng *textRange, text string) {
+	ts.notify(
+		"textDocument/didChange",
+		map[string]interface{}{
+			"textDocument":   textDocumentIdentifier{URI: uri},
+			"contentChanges": []interface{}{map[string]interface{}{"range": rng, "text": text}},
+		},
+	)
+}
+
+// run sends collected messages to the server and returns its output
+func (ts *testSession) run(t *testing.T, s *Server) testMessages {
+	t.Helper()
+	ts.request("shutdown", nil)
+	ts.notify("exit", nil)
+	var out bytes.Buffer
+	if err := s.Run(&ts.in, &out); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	var msgs testMessages
+	for out.Len() > 0 {
+		var length int
+		if _, err := fmt.Fscanf(&out, "Content-Length: %d\r\n\r\n", &length); err != nil {
+			t.Fatalf("invalid server output: %v", err)
+		}
+		var msg testMessage
+		if err := json.Unmarshal(out.Next(length), &msg); err != nil {
+			t.Fatalf("invalid server output: %v", err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+type testMessage struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type testMessages []testMessage
+
+func (msgs testMessages) response(id int) *testMessage {
+	for i, msg := range msgs {
+		if msg.ID != nil && *msg.ID == id && msg.Method == "" {
+			return &msgs[i]
+		}
+	}
+	return nil
+}
+
+func (msgs testMessages) result(t *testing.T, id int, res interface{}) {
+	t.Helper()
+	msg := msgs.response(id)
+	if msg == nil {
+		t.Fatalf("no response for request %d", id)
+	}
+	if msg.Error != nil {
+		t.Fatalf("request %d failed: %v", id, msg.Error)
+	}
+	if err := json.Unmarshal(msg.Result, res); err != nil {
+		t.Fatalf("invalid result for request %d: %v", id, err)
+	}
+}
+
+func (msgs testMessages) error(id int) *rpcError {
+	if msg := msgs.response(id); msg != nil {
+		return msg.Error
+	}
+	return nil
+}
+
+// diagnostics returns published diagnostics in order of publishing
+func (msgs testMessages) diagnostics(t *testing.T) (ret []publishDiagnosticsParams) {
+	t.Helper()
+	for _, msg := range msgs {
+		if msg.Method == "textDocument/publishDiagnostics" {
+			var params publishDiagnosticsParams
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				t.Fatalf("invalid diagnostics: %v", err)
+			}
+			ret = append(ret, params)
+		}
+	}
+	return
+}
@@ -80,9 +80,13 @@ type MongoGenerator struct {
 }
 
 func init() {
-	plugin := &MongoGenerator{}
-	plugin.init()
-	RegisterPlugin(plugin)
+	RegisterPluginFactory(
+		func() Generator {
+			plugin := &MongoGenerator{}
+			plugin.init()
+			return plugin
+		},
+	)
 }
 
 func (cg *MongoGenerator) Name() string {
@@ -180,6 +184,72 @@ func (cg *MongoGenerator) CheckAnnotation(desc *Package, ann *Annotation, item i
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (cg *MongoGenerator) AnnotationSchemas() []AnnotationSchema {
+	tags := []AnnotationTagSchema{
+		{Name: mongoAnnotationTagName, Type: AnnotationTagString, Doc: "name of collection or document field"},
+		{Name: mongoAnnotationTagIgnore, Type: AnnotationTagBool, Default: false, Doc: "do not store type or field"},
+		{Name: mongoAnnotationTagEncapsulate, Type: AnnotationTagBool, Doc: "store embedded type as subdocument"},
+		{Name: mongoAnnotationTagAddBsonTag, Type: AnnotationTagBool, Default: false, Doc: "generate bson tags for not stored type"},
+		{
+			Name:   mongoAnnotationDeleteMethod,
+			Type:   AnnotationTagString,
+			Values: []string{madmUpdate, madmDelete},
+			Doc:    "how objects are deleted: marked as deleted or removed from collection",
+		},
+		{Name: mongoAnnotationOrder, Type: AnnotationTagAny, Doc: "sort found objects by the field"},
+		{Name: madmCustomQueryGenerator, Type: AnnotationTagBool, Doc: "query of find type is generated by custom function"},
+		{Name: madmProcessQuery, Type: AnnotationTagAny, Doc: "query of find type is passed to function (true or its name)"},
+		{Name: mongoAnnotationTagNameMutable, Type: AnnotationTagBool, Default: false, Doc: "collection name is stored in variable"},
+	}
+	return []AnnotationSchema{
+		{
+			Name:    mongoAnnotation,
+			Targets: AnnotationTargetEntity | AnnotationTargetField,
+			Tags:    tags,
+			Doc:     "MongoDB settings of type or field",
+		},
+		{
+			Name:    dbAnnotation,
+			Targets: AnnotationTargetEntity | AnnotationTargetField,
+			Tags:    tags,
+			Doc:     "the same as mongo",
+		},
+		{
+			Name:    AnnotationConfig,
+			Targets: AnnotationTargetField,
+			Tags: []AnnotationTagSchema{
+				{Name: AnnCfgMutable, Type: AnnotationTagBool, Doc: "config value may be changed (it is stored in DB)"},
+			},
+			Doc: "config field settings",
+		},
+		{
+			Name:    AnnotationSort,
+			Targets: AnnotationTargetField,
+			Tags: []AnnotationTagSchema{
+				{Name: AnnSortAscending, Type: AnnotationTagBool, Doc: "ascending order"},
+				{Name: AnnSortDescending, Type: AnnotationTagBool, Default: false, Doc: "descending order"},
+			},
+			AnyTags: true,
+			Doc:     "sort found objects by the field",
+		},
+		{
+			Name:    AnnotationLookup,
+			Targets: AnnotationTargetField,
+			Tags: []AnnotationTagSchema{
+				{Name: AFTEqual, Type: AnnotationTagAny, Doc: "lookup returns objects with the value of field"},
+				{Name: AFTNotEqual, Type: AnnotationTagAny, Doc: "lookup skips objects with the value of field"},
+				{Name: ALStartsWith, Type: AnnotationTagAny, Doc: "field starts with lookup query"},
+				{Name: ALStartsWithIgnoreCase, Type: AnnotationTagAny, Doc: "field starts with lookup query ignoring case"},
+				{Name: ALContains, Type: AnnotationTagAny, Doc: "field contains lookup query"},
+				{Name: ALContainsIgnoreCase, Type: AnnotationTagAny, Doc: "field contains lookup query ignoring case"},
+			},
+			AnyTags: true,
+			Doc:     "field is used by generated lookup query",
+		},
+	}
+}
+
 // ProvideFeature from FeatureProvider interface
 func (cg *MongoGenerator) ProvideFeature(
 	kind FeatureKind,
@@ -392,6 +462,12 @@ func (cg *MongoGenerator) Generate(bldr *Builder) (err error) {
 			Line()
 		cg.desc.Features.Set(mongoFeatures, mfInited, true)
 	}
+	for _, u := range bldr.File.Unions {
+		cg.generateHolderCodec(u.Name)
+	}
+	for _, i := range bldr.File.Interfaces {
+		cg.generateHolderCodec(i.Name)
+	}
 	for _, t := range bldr.File.Entries {
 		if ignore, ok := t.Features.GetBool(FeaturesDBKind, FCIgnore); !ok || !ignore {
 			cg.generateConst(t)
@@ -484,6 +560,39 @@ func (cg *MongoGenerator) Generate(bldr *Builder) (err error) {
 	return nil
 }
 
+// generateHolderCodec generates bson marshaling for union or interface holder;
+// fields of the value are stored along with type discriminator
+func (cg *MongoGenerator) generateHolderCodec(name string) {
+	holder := polymorphicHolderName(name)
+	cg.b.Functions.Add(
+		jen.Func().Parens(jen.Id("h").Id(holder)).Id("MarshalBSON").Params().Parens(jen.List(jen.Index().Byte(), jen.Error())).Block(
+			jen.Id("doc").Op(":=").Qual(bsonPackage, "D").Values(
+				jen.Values(jen.Dict{jen.Id("Key"): jen.Lit(PolymorphicDiscriminator), jen.Id("Value"): jen.Id("h").Dot("TypeName").Params()}),
+			),
+			jen.If(jen.Id("h").Dot("Value").Op("!=").Nil()).Block(
+				jen.List(jen.Id("data"), jen.Err()).Op(":=").Qual(bsonPackage, "Marshal").Params(jen.Id("h").Dot("Value")),
+				returnIfErrValue(jen.Nil()),
+				jen.Var().Id("fields").Qual(bsonPackage, "D"),
+				jen.If(
+					jen.Err().Op("=").Qual(bsonPackage, "Unmarshal").Params(jen.Id("data"), jen.Op("&").Id("fields")),
+					jen.Err().Op("!=").Nil(),
+				).Block(jen.Return(jen.Nil(), jen.Err())),
+				jen.Id("doc").Op("=").Append(jen.Id("doc"), jen.Id("fields").Op("...")),
+			),
+			jen.Return(jen.Qual(bsonPackage, "Marshal").Params(jen.Id("doc"))),
+		).Line(),
+		jen.Func().Parens(jen.Id("h").Op("*").Id(holder)).Id("UnmarshalBSON").Params(jen.Id("data").Index().Byte()).Error().Block(
+			jen.List(jen.Id("typeName"), jen.Id("_")).Op(":=").Qual(bsonPackage, "Raw").Parens(jen.Id("data")).
+				Dot("Lookup").Params(jen.Lit(PolymorphicDiscriminator)).Dot("StringValueOK").Params(),
+			jen.If(
+				jen.Err().Op(":=").Id("h").Dot("NewValue").Params(jen.Id("typeName")),
+				jen.Err().Op("!=").Nil().Op("||").Id("h").Dot("Value").Op("==").Nil(),
+			).Block(jen.Return(jen.Err())),
+			jen.Return(jen.Qual(bsonPackage, "Unmarshal").Params(jen.Id("data"), jen.Id("h").Dot("Value"))),
+		).Line(),
+	)
+}
+
 func (cg *MongoGenerator) generateConst(e *Entity) {
 	cn := cg.collectionName(e)
 	constName := e.FS(mongoFeatures, mfCollectionConst)
@@ -31,7 +31,7 @@ const (
 )
 
 func init() {
-	RegisterPlugin(&NoCacheGenerator{})
+	RegisterPluginFactory(func() Generator { return &NoCacheGenerator{} })
 }
 
 func (ncg *NoCacheGenerator) Name() string {
@@ -46,6 +46,18 @@ func (ncg *NoCacheGenerator) CheckAnnotation(desc *Package, ann *Annotation, ite
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (ncg *NoCacheGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    nocacheAnnotation,
+			Targets: AnnotationTargetAny,
+			AnyTags: true,
+			Doc:     "objects are not cached (it is the only mode of the generator at the moment)",
+		},
+	}
+}
+
 func (ncg *NoCacheGenerator) Prepare(desc *Package) error {
 	ncg.desc = desc
 	for _, file := range desc.Files {
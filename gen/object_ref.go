@@ -28,13 +28,18 @@ type ObjectRefGenerator struct {
 }
 
 func init() {
-	RegisterPlugin(&ObjectRefGenerator{})
+	RegisterPluginFactory(func() Generator { return &ObjectRefGenerator{} })
 }
 
 func (cg *ObjectRefGenerator) Name() string {
 	return objectRefPluginName
 }
 
+// Requires from PluginsRequirer
+func (cg *ObjectRefGenerator) Requires() []string {
+	return []string{GQLGeneratorName}
+}
+
 func (cg *ObjectRefGenerator) SetDescriptor(proj *Project) {
 	cg.proj = proj
 }
@@ -69,6 +74,24 @@ func (cg *ObjectRefGenerator) CheckAnnotation(desc *Package, ann *Annotation, it
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (cg *ObjectRefGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    objectRefAnnotation,
+			Targets: AnnotationTargetField,
+			AnyTags: true,
+			Doc:     "field contains reference to object of any referenceable type",
+		},
+		{
+			Name:    referenceableAnnotation,
+			Targets: AnnotationTargetEntity,
+			AnyTags: true,
+			Doc:     "objects of storable type may be referenced by object-ref fields",
+		},
+	}
+}
+
 // Prepare from Generator interface
 func (cg *ObjectRefGenerator) Prepare(desc *Package) error {
 	cg.desc = desc
@@ -0,0 +1,452 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	openAPIVersion      = "3.0.3"
+	openAPISchemasRef   = "#/components/schemas/"
+	openAPIErrorSchema  = "Error"
+	openAPIJSONMimeType = "application/json"
+)
+
+// yamlMap is map that keeps order of keys; it is used for building YAML documents (OpenAPI spec)
+type yamlMap struct {
+	keys []string
+	vals map[string]interface{}
+}
+
+// openAPIBuilder collects schemas of the types referred from operations of the package
+type openAPIBuilder struct {
+	desc    *Package
+	schemas *yamlMap
+	// pending - types referred but not added to schemas yet
+	pending []*DefinedType
+	added   map[string]bool
+}
+
+func newYAMLMap() *yamlMap {
+	return &yamlMap{vals: map[string]interface{}{}}
+}
+
+func (m *yamlMap) set(key string, val interface{}) *yamlMap {
+	if _, ok := m.vals[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.vals[key] = val
+	return m
+}
+
+func (m *yamlMap) get(key string) interface{} {
+	return m.vals[key]
+}
+
+// openAPISpec returns OpenAPI spec for REST operations of the package; nil if there are no operations
+func (cg *RESTGenerator) openAPISpec(desc *Package) (*yamlMap, error) {
+	ob := &openAPIBuilder{desc: desc, schemas: newYAMLMap(), added: map[string]bool{}}
+	paths := newYAMLMap()
+	for _, file := range desc.Files {
+		for _, t := range file.Entries {
+			for _, op := range cg.getOperations(t) {
+				pathItem, ok := paths.get(op.pattern).(*yamlMap)
+				if !ok {
+					pathItem = newYAMLMap()
+					paths.set(op.pattern, pathItem)
+				}
+				pathItem.set(strings.ToLower(op.method), ob.operation(t, op))
+			}
+		}
+	}
+	if len(paths.keys) == 0 {
+		return nil, nil
+	}
+	for len(ob.pending) > 0 {
+		dt := ob.pending[0]
+		ob.pending = ob.pending[1:]
+		ob.schemas.set(ob.schemaName(dt), ob.typeSchema(dt))
+	}
+	ob.schemas.set(
+		openAPIErrorSchema,
+		newYAMLMap().set("type", "object").set(
+			"properties",
+			newYAMLMap().set(
+				"errors",
+				newYAMLMap().set("type", "array").set(
+					"items",
+					newYAMLMap().set("type", "object").set(
+						"properties",
+						newYAMLMap().set("message", newYAMLMap().set("type", "string")),
+					),
+				),
+			),
+		),
+	)
+
+	title := cg.options.Title
+	if title == "" {
+		title = fmt.Sprintf("%s REST API", desc.Name)
+	}
+	version := cg.options.Version
+	if version == "" {
+		version = "1.0.0"
+	}
+	spec := newYAMLMap().set("openapi", openAPIVersion)
+	spec.set("info", newYAMLMap().set("title", title).set("version", version))
+	if cg.options.ServerURL != "" {
+		spec.set("servers", []interface{}{newYAMLMap().set("url", cg.options.ServerURL)})
+	}
+	spec.set("paths", paths)
+	spec.set("components", newYAMLMap().set("schemas", ob.schemas))
+	return spec, nil
+}
+
+func (ob *openAPIBuilder) operation(t *Entity, op restOperation) *yamlMap {
+	ret := newYAMLMap().set("operationId", op.name).set("tags", []interface{}{t.Name})
+	entityRef := ob.entityRef(t)
+	list := newYAMLMap().set("type", "array").set("items", entityRef)
+	var summary string
+	var params []interface{}
+	var body, result interface{}
+	switch op.kind {
+	case GQLOperationGet:
+		summary, result = "get "+t.Name, entityRef
+	case GQLOperationSet:
+		summary, body, result = "update "+t.Name, entityRef, entityRef
+	case GQLOperationCreate:
+		summary, body, result = "create "+t.Name, entityRef, entityRef
+	case GQLOperationDelete:
+		summary, result = "delete "+t.Name, newYAMLMap().set("type", "boolean")
+	case GQLOperationList:
+		summary, result = "list all "+t.Name, list
+		if t.FB(FeatureDictKind, FDQualified) {
+			qt, _ := t.Features.GetEntity(FeatureDictKind, FDQualifierType)
+			params = append(
+				params,
+				newYAMLMap().set("name", "qual").set("in", "query").set("required", false).
+					set("description", fmt.Sprintf("ids of %s to filter items by", qt.Name)).
+					set("schema", newYAMLMap().set("type", "array").set("items", ob.fieldSchema(qt.GetIdField().Type, false))),
+			)
+		}
+	case GQLOperationLookup:
+		summary, result = "lookup "+t.Name, list
+		params = append(
+			params,
+			newYAMLMap().set("name", "query").set("in", "query").set("required", true).
+				set("schema", newYAMLMap().set("type", "string")),
+		)
+	case GQLOperationFind:
+		it, _ := t.Features.GetEntity(FeaturesAPIKind, FAPIFindParamType)
+		summary, body, result = "find "+t.Name, ob.entityRef(it), list
+	case GQLOperationBulkCreate:
+		summary, body, result = "create several "+t.Name, list, list
+	case GQLOperationBulkSet:
+		summary, body, result = "update several "+t.Name, list, list
+	}
+	ret.set("summary", summary)
+	if t.Doc != "" {
+		ret.set("description", t.Doc)
+	}
+	if _, ok := DeprecationReason(t.Annotations); ok {
+		ret.set("deprecated", true)
+	}
+	if strings.Contains(op.pattern, "{id}") {
+		params = append(
+			[]interface{}{
+				newYAMLMap().set("name", "id").set("in", "path").set("required", true).
+					set("schema", ob.fieldSchema(t.GetIdField().Type, false)),
+			},
+			params...,
+		)
+	}
+	if len(params) > 0 {
+		ret.set("parameters", params)
+	}
+	if body != nil {
+		ret.set(
+			"requestBody",
+			newYAMLMap().set("required", true).set("content", newYAMLMap().set(openAPIJSONMimeType, newYAMLMap().set("schema", body))),
+		)
+	}
+	ret.set(
+		"responses",
+		newYAMLMap().set(
+			"200",
+			newYAMLMap().set("description", "successful operation").set(
+				"content",
+				newYAMLMap().set(openAPIJSONMimeType, newYAMLMap().set("schema", result)),
+			),
+		).set(
+			"default",
+			newYAMLMap().set("description", "error").set(
+				"content",
+				newYAMLMap().set(openAPIJSONMimeType, newYAMLMap().set("schema", newYAMLMap().set("$ref", openAPISchemasRef+openAPIErrorSchema))),
+			),
+		),
+	)
+	return ret
+}
+
+// typeSchema returns schema for entity (object with the fields having json names), enum or polymorphic type
+func (ob *openAPIBuilder) typeSchema(dt *DefinedType) *yamlMap {
+	ret := newYAMLMap()
+	var doc string
+	switch {
+	case dt.enum != nil:
+		e := dt.enum
+		doc = e.Doc
+		ret.set("type", ob.primitiveSchema(e.AliasForType).get("type"))
+		var vals, names []interface{}
+		for i, f := range e.Fields {
+			switch {
+			case f.IntVal != nil:
+				vals = append(vals, *f.IntVal)
+			case f.FloatVal != nil:
+				vals = append(vals, *f.FloatVal)
+			case f.StringVal != nil:
+				vals = append(vals, *f.StringVal)
+			default:
+				vals = append(vals, i)
+			}
+			names = append(names, f.Name)
+		}
+		ret.set("enum", vals)
+		ret.set("x-enum-varnames", names)
+	case dt.IsPolymorphic():
+		var oneOf []interface{}
+		mapping := newYAMLMap()
+		for _, e := range dt.PossibleTypes() {
+			ref := ob.entityRef(e)
+			oneOf = append(oneOf, ref)
+			mapping.set(e.Name, ref.get("$ref"))
+		}
+		if dt.union != nil {
+			doc = dt.union.Doc
+		} else if dt.iface != nil {
+			doc = dt.iface.Doc
+		}
+		ret.set("oneOf", oneOf)
+		ret.set("discriminator", newYAMLMap().set("propertyName", PolymorphicDiscriminator).set("mapping", mapping))
+	case dt.entry != nil:
+		e := dt.entry
+		doc = e.Doc
+		ret.set("type", "object")
+		props := newYAMLMap()
+		for _, f := range e.GetFields(true, true) {
+			name, ok := restFieldName(ob.desc, f)
+			if !ok {
+				continue
+			}
+			asRef := f.HasModifier(AttrModifierEmbeddedRef) || f.FB(GQLFeatures, GQLFIDOnly)
+			schema := ob.fieldSchema(f.Type, asRef)
+			nullable := f.FB(FeatGoKind, FCGPointer) || f.Type.Array != nil || f.Type.Map != nil
+			if _, isRef := schema.vals["$ref"]; isRef && nullable {
+				schema = newYAMLMap().set("allOf", []interface{}{schema})
+			}
+			if nullable {
+				schema.set("nullable", true)
+			}
+			if f.Doc != "" {
+				schema.set("description", f.Doc)
+			}
+			if restReadonlyField(f) {
+				schema.set("readOnly", true)
+			}
+			if _, ok := DeprecationReason(f.Annotations); ok {
+				schema.set("deprecated", true)
+			}
+			props.set(name, schema)
+		}
+		ret.set("properties", props)
+	}
+	if doc != "" {
+		ret.set("description", doc)
+	}
+	if _, ok := dt.Deprecation(); ok {
+		ret.set("deprecated", true)
+	}
+	return ret
+}
+
+// fieldSchema returns schema for JSON representation of Go type generated for ref
+func (ob *openAPIBuilder) fieldSchema(ref *TypeRef, asRef bool) *yamlMap {
+	if ref.Array != nil {
+		return newYAMLMap().set("type", "array").set("items", ob.fieldSchema(ref.Array, asRef))
+	}
+	if ref.Map != nil {
+		return newYAMLMap().set("type", "object").set("additionalProperties", ob.fieldSchema(ref.Map.ValueType, false))
+	}
+	if IsPrimitiveType(ref.Type) {
+		return ob.primitiveSchema(ref.Type)
+	}
+	dt, ok := ob.desc.FindType(ref.Type)
+	if !ok {
+		return newYAMLMap()
+	}
+	if e := dt.entry; e != nil && !dt.IsPolymorphic() {
+		if e.HasModifier(TypeModifierExternal) {
+			return newYAMLMap().set("description", fmt.Sprintf("external type %s", ref.Type))
+		}
+		// references to stored types keep id only (see Builder.GoType)
+		storedRef := !ref.Embedded &&
+			!e.HasModifier(TypeModifierEmbeddable) &&
+			!e.HasModifier(TypeModifierTransient) &&
+			!e.HasModifier(TypeModifierConfig)
+		if (asRef || storedRef) && e.GetIdField() != nil {
+			return ob.fieldSchema(e.GetIdField().Type, false).set("description", fmt.Sprintf("id of %s", e.Name))
+		}
+	}
+	return ob.ref(dt)
+}
+
+func (ob *openAPIBuilder) primitiveSchema(tip string) *yamlMap {
+	ret := newYAMLMap()
+	switch tip {
+	case TipString:
+		ret.set("type", "string")
+	case TipInt:
+		ret.set("type", "integer")
+	case TipFloat:
+		ret.set("type", "number")
+	case TipBool:
+		ret.set("type", "boolean")
+	case TipDate, TipDateTime:
+		ret.set("type", "string").set("format", "date-time")
+	case TipDecimal:
+		ret.set("type", "string").set("format", "decimal")
+	case TipTime:
+		ret.set("type", "string").set("format", "time").set("example", "15:04:05")
+	case TipDuration:
+		ret.set("type", "integer").set("format", "int64").set("description", "duration in nanoseconds")
+	case TipUUID:
+		ret.set("type", "string").set("format", "uuid")
+	case TipBytes:
+		ret.set("type", "string").set("format", "byte")
+	}
+	return ret
+}
+
+func (ob *openAPIBuilder) entityRef(e *Entity) *yamlMap {
+	name := e.Name
+	if e.Pckg != ob.desc {
+		name = e.Pckg.Name + "." + e.Name
+	}
+	dt, ok := ob.desc.FindType(name)
+	if !ok {
+		return newYAMLMap()
+	}
+	return ob.ref(dt)
+}
+
+func (ob *openAPIBuilder) ref(dt *DefinedType) *yamlMap {
+	name := ob.schemaName(dt)
+	if !ob.added[name] {
+		ob.added[name] = true
+		ob.pending = append(ob.pending, dt)
+	}
+	return newYAMLMap().set("$ref", openAPISchemasRef+name)
+}
+
+func (ob *openAPIBuilder) schemaName(dt *DefinedType) string {
+	if dt.pckg != ob.desc.Name {
+		return dt.pckg + "." + dt.name
+	}
+	return dt.name
+}
+
+var yamlPlainString = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_ ./\-]*$`)
+
+// yaml returns YAML document for the map
+func (m *yamlMap) yaml() []byte {
+	buf := &bytes.Buffer{}
+	m.writeYAML(buf, 0)
+	return buf.Bytes()
+}
+
+// MarshalJSON encodes the map as JSON object keeping order of keys (it is used for JSON Schema)
+func (m *yamlMap) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		val, err := json.Marshal(m.vals[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (m *yamlMap) writeYAML(buf *bytes.Buffer, indent int) {
+	prefix := strings.Repeat("  ", indent)
+	for _, k := range m.keys {
+		buf.WriteString(prefix + yamlScalar(k) + ":")
+		writeYAMLValue(buf, indent, m.vals[k])
+	}
+}
+
+// writeYAMLValue writes value of map's key or list's item (after ':' or '-')
+func writeYAMLValue(buf *bytes.Buffer, indent int, val interface{}) {
+	switch v := val.(type) {
+	case *yamlMap:
+		if len(v.keys) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteString("\n")
+		v.writeYAML(buf, indent+1)
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteString("\n")
+		prefix := strings.Repeat("  ", indent+1)
+		for _, item := range v {
+			if im, ok := item.(*yamlMap); ok && len(im.keys) > 0 {
+				// the first key goes on the line of '-'
+				item := &bytes.Buffer{}
+				im.writeYAML(item, indent+2)
+				buf.WriteString(prefix + "- " + strings.TrimPrefix(item.String(), prefix+"  "))
+				continue
+			}
+			buf.WriteString(prefix + "-")
+			writeYAMLValue(buf, indent+1, item)
+		}
+	default:
+		buf.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+func yamlScalar(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		if strings.Contains(v, "\n") || !yamlPlainString.MatchString(v) || strings.HasSuffix(v, " ") {
+			return strconv.Quote(v)
+		}
+		switch strings.ToLower(v) {
+		case "true", "false", "yes", "no", "on", "off", "null", "y", "n":
+			return strconv.Quote(v)
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return strconv.Quote(fmt.Sprint(val))
+}
@@ -0,0 +1,84 @@
+package gen
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// OutputFile is generated file that is collected in memory and written with Project.WriteFile on Close
+type OutputFile struct {
+	bytes.Buffer
+	name   string
+	proj   *Project
+	closed bool
+}
+
+// CreateFile returns OutputFile for the name; it should be used by generators instead of os.Create
+func (p *Project) CreateFile(name string) *OutputFile {
+	return &OutputFile{name: name, proj: p}
+}
+
+// Name returns name of the file
+func (f *OutputFile) Name() string {
+	return f.name
+}
+
+// Close writes content of the file; subsequent calls do nothing.
+// As Close is usually deferred, write error is added to the project errors too
+func (f *OutputFile) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	err := f.proj.WriteFile(f.name, f.Bytes())
+	if err != nil {
+		f.proj.AddError(fmt.Errorf("writing %s: %w", f.name, err))
+	}
+	return err
+}
+
+// GenerateToMemory generates code like Generate and WriteToFiles do, but nothing is written to disk:
+// content of all the generated files (Go, TS, Vue etc.) is returned by path
+func (p *Project) GenerateToMemory() (files map[string][]byte, err error) {
+	p.memory = map[string][]byte{}
+	defer func() { p.memory = nil }()
+	err = p.Generate()
+	if err != nil {
+		return nil, err
+	}
+	err = p.WriteToFiles()
+	if err != nil {
+		return nil, err
+	}
+	return p.memory, nil
+}
+
+// WriteFile writes generated file (creating directories if necessary) if its content differs from existing one:
+// unchanged files are not touched, so file watchers of build tools are not triggered.
+// Inside GenerateToMemory file is collected in memory instead
+func (p *Project) WriteFile(name string, data []byte) error {
+	if p.memory != nil {
+		p.memory[filepath.Clean(name)] = append([]byte(nil), data...)
+		return nil
+	}
+	if old, err := os.ReadFile(name); err == nil && sha256.Sum256(old) == sha256.Sum256(data) {
+		p.filesUnchanged++
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(name), os.ModeDir|os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(name, data, 0644); err != nil {
+		return err
+	}
+	p.filesWritten++
+	return nil
+}
+
+// WriteStats returns number of files written and number of files left untouched as their content was not changed
+func (p *Project) WriteStats() (written int, unchanged int) {
+	return p.filesWritten, p.filesUnchanged
+}
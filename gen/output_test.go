@@ -0,0 +1,63 @@
+package gen
+
+import (
+	"os"
+	"testing"
+)
+
+const outputTestSource = `package shop;
+
+type Product {
+  ID: int <id>;
+  Name: string;
+}
+`
+
+func TestGenerateToMemory(t *testing.T) {
+	proj := testProject(t, outputTestSource)
+	files, err := proj.GenerateToMemory()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no files generated")
+	}
+	entries, err := os.ReadDir(proj.Options.OutputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() != "test.vvf" {
+			t.Errorf("%s is written to output directory", e.Name())
+		}
+	}
+	generated := generateTest(t, outputTestSource)
+	assertContains(t, generated["shop/test.go"], "type Product struct {", "func (o *Product) GetName() string")
+}
+
+// TestGenerateToMemoryCompiles compiles code generated in memory
+func TestGenerateToMemoryCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles generated code")
+	}
+	runGenerated(t, outputTestSource, nil, "shop", outputMain)
+}
+
+const outputMain = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"PREFIX/shop"
+)
+
+func main() {
+	p := &shop.Product{}
+	p.SetName("tea")
+	if p.GetName() != "tea" {
+		fmt.Printf("GetName() = %q, want tea\n", p.GetName())
+		os.Exit(1)
+	}
+}
+`
@@ -36,6 +36,16 @@ type Package struct {
 	engineless  bool
 }
 
+// report passes err to the error handler of Check and returns nil, so the caller may go on with the next
+// entity or field; while generating there is no handler and err is returned as is
+func (desc *Package) report(err error) error {
+	if desc.errorHandler == nil {
+		return err
+	}
+	desc.errorHandler(desc, err)
+	return nil
+}
+
 func (desc *Package) postParsed() error {
 	desc.types = map[string]*DefinedType{}
 	checkName := func(name string) error {
@@ -55,7 +65,9 @@ func (desc *Package) postParsed() error {
 			e.Pckg = desc
 			err := e.postParsed()
 			if err != nil {
-				return err
+				if err = desc.report(err); err != nil {
+					return err
+				}
 			}
 			for _, field := range e.Fields {
 				field.Parent = e
@@ -68,11 +80,17 @@ func (desc *Package) postParsed() error {
 			typename := e.Name
 			err := checkName(typename)
 			if err != nil {
-				return fmt.Errorf("at %v: %v", e.Pos, err)
+				if err = desc.report(fmt.Errorf("at %v: %v", e.Pos, err)); err != nil {
+					return err
+				}
+				continue
 			}
 			if !e.HasModifier(TypeModifierExternal) {
 				if dt, ok := desc.types[typename]; ok {
-					return fmt.Errorf("duplicate entity found: %s: %#v", typename, dt)
+					if err = desc.report(fmt.Errorf("duplicate entity found: %s: %#v", typename, dt)); err != nil {
+						return err
+					}
+					continue
 				}
 			}
 			if e.HasModifier(TypeModifierAbstract) && !e.HasModifier(TypeModifierExtendable) {
@@ -106,7 +124,10 @@ func (desc *Package) postParsed() error {
 		for _, enum := range f.Enums {
 			err := checkName(enum.Name)
 			if err != nil {
-				return fmt.Errorf("at %v: %v", enum.Pos, err)
+				if err = desc.report(fmt.Errorf("at %v: %v", enum.Pos, err)); err != nil {
+					return err
+				}
+				continue
 			}
 
 			desc.types[enum.Name] = &DefinedType{
@@ -118,6 +139,42 @@ func (desc *Package) postParsed() error {
 				pos:         enum.Pos,
 			}
 		}
+		for _, u := range f.Unions {
+			u.File = f
+			u.Pckg = desc
+			err := checkName(u.Name)
+			if err != nil {
+				if err = desc.report(fmt.Errorf("at %v: %v", u.Pos, err)); err != nil {
+					return err
+				}
+				continue
+			}
+			desc.types[u.Name] = &DefinedType{
+				name:        u.Name,
+				pckg:        desc.Name,
+				union:       u,
+				packagePath: desc.fullPackage,
+				pos:         u.Pos,
+			}
+		}
+		for _, i := range f.Interfaces {
+			i.File = f
+			i.Pckg = desc
+			err := checkName(i.Name)
+			if err != nil {
+				if err = desc.report(fmt.Errorf("at %v: %v", i.Pos, err)); err != nil {
+					return err
+				}
+				continue
+			}
+			desc.types[i.Name] = &DefinedType{
+				name:        i.Name,
+				pckg:        desc.Name,
+				iface:       i,
+				packagePath: desc.fullPackage,
+				pos:         i.Pos,
+			}
+		}
 	}
 	return nil
 }
@@ -144,64 +201,114 @@ func (desc *Package) beforePrepare() error {
 	for _, f := range desc.Files {
 		err := desc.processModifiers(f)
 		if err != nil {
-			return fmt.Errorf("at %v: %w", f.Pos, err)
+			if err = desc.report(fmt.Errorf("at %v: %w", f.Pos, err)); err != nil {
+				return err
+			}
+		}
+		for _, en := range f.Enums {
+			err = desc.processModifiers(en)
+			if err != nil {
+				if err = desc.report(fmt.Errorf("at %v: %w", en.Pos, err)); err != nil {
+					return err
+				}
+			}
+			for _, ef := range en.Fields {
+				err = desc.processModifiers(ef)
+				if err != nil {
+					if err = desc.report(fmt.Errorf("at %v: %w", ef.Pos, err)); err != nil {
+						return err
+					}
+				}
+			}
 		}
 		for _, e := range f.Entries {
 			if e.BaseTypeName != "" {
 				bt := e.GetBaseType()
 				if bt == nil {
-					return fmt.Errorf("at %v: base type not found: %s", e.Pos, e.BaseTypeName)
+					if err = desc.report(fmt.Errorf("at %v: base type not found: %s", e.Pos, e.BaseTypeName)); err != nil {
+						return err
+					}
+					continue
 				}
 				if !bt.HasModifier(TypeModifierExtendable) {
-					return fmt.Errorf("at %v: base type should be extendable: %s", e.Pos, e.BaseTypeName)
+					if err = desc.report(fmt.Errorf("at %v: base type should be extendable: %s", e.Pos, e.BaseTypeName)); err != nil {
+						return err
+					}
+					continue
 				}
 				bt.AddDescendant(e)
 			}
 			err := desc.processModifiers(e)
 			if err != nil {
-				return fmt.Errorf("at %v: %w", e.Pos, err)
+				if err = desc.report(fmt.Errorf("at %v: %w", e.Pos, err)); err != nil {
+					return err
+				}
 			}
 			for _, f := range e.Fields {
 				f.PostProcess()
 				err = desc.processModifiers(f)
 				if err != nil {
-					return fmt.Errorf("at %v: %w", f.Pos, err)
+					if err = desc.report(fmt.Errorf("at %v: %w", f.Pos, err)); err != nil {
+						return err
+					}
 				}
-				if f.HasModifier(AttrModifierCalculated) {
+				// GraphQL does not allow unions and interfaces in input types
+				if f.HasModifier(AttrModifierCalculated) || f.IsPolymorphic() {
 					f.Features.Set(FeaturesCommonKind, FCReadonly, true)
 				}
 			}
 			for _, m := range e.Methods {
 				err = desc.processModifiers(m)
 				if err != nil {
-					return fmt.Errorf("at %v: %w", m.Pos, err)
+					if err = desc.report(fmt.Errorf("at %v: %w", m.Pos, err)); err != nil {
+						return err
+					}
 				}
 			}
 			if e.HasModifier(TypeModifierExternal) {
 				err := desc.Project.addExternal(e, desc)
 				if err != nil {
-					return err
+					if err = desc.report(err); err != nil {
+						return err
+					}
 				}
 			}
 		}
 	}
 	for _, f := range desc.Files {
-		err := desc.processStandardFileAnnotations(f)
+		err := desc.resolvePolymorphicTypes(f)
 		if err != nil {
 			return err
 		}
+	}
+	for _, f := range desc.Files {
+		err := desc.processStandardFileAnnotations(f)
+		if err != nil {
+			if err = desc.report(err); err != nil {
+				return err
+			}
+		}
 		for _, e := range f.Entries {
 			err := desc.checkType(e)
 			if err != nil {
-				return fmt.Errorf("at %v: %w", e.Pos, err)
+				if err = desc.report(fmt.Errorf("at %v: %w", e.Pos, err)); err != nil {
+					return err
+				}
+				continue
 			}
 			err = desc.checkTypeRelations(e)
 			if err != nil {
-				return fmt.Errorf("at %v: %w", e.Pos, err)
+				if err = desc.report(fmt.Errorf("at %v: %w", e.Pos, err)); err != nil {
+					return err
+				}
+				continue
 			}
 			err = desc.processStandardTypeAnnotations(e)
 			if err != nil {
-				return err
+				if err = desc.report(err); err != nil {
+					return err
+				}
+				continue
 			}
 			if codeGenerator != nil {
 				err = codeGenerator.createAdditionalFields(e)
@@ -217,6 +324,10 @@ func (desc *Package) beforePrepare() error {
 
 func (desc *Package) prepare() error {
 	desc.initEngine()
+	// all the packages are processed at this stage, so references to other packages may be checked
+	for _, f := range desc.Files {
+		desc.checkDeprecatedReferences(f)
+	}
 	for _, gen := range desc.Project.generators {
 		err := gen.Prepare(desc)
 		if err != nil {
@@ -226,6 +337,129 @@ func (desc *Package) prepare() error {
 	return nil
 }
 
+// resolvePolymorphicTypes fills members of unions and implementations of interfaces
+func (desc *Package) resolvePolymorphicTypes(f *File) error {
+	for _, u := range f.Unions {
+		err := desc.processModifiers(u)
+		if err != nil {
+			if err = desc.report(fmt.Errorf("at %v: %w", u.Pos, err)); err != nil {
+				return err
+			}
+		}
+		u.Types = nil
+	members:
+		for _, tn := range u.TypeNames {
+			dt, ok := desc.types[tn]
+			if !ok || dt.entry == nil {
+				if err = desc.report(fmt.Errorf("at %v: union %s: type %s not found in package %s", u.Pos, u.Name, tn, desc.Name)); err != nil {
+					return err
+				}
+				continue
+			}
+			if dt.entry.HasModifier(TypeModifierExternal) {
+				if err = desc.report(fmt.Errorf("at %v: union %s: external type %s can not be a member of union", u.Pos, u.Name, tn)); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, t := range u.Types {
+				if t == dt.entry {
+					if err = desc.report(fmt.Errorf("at %v: union %s: type %s is listed twice", u.Pos, u.Name, tn)); err != nil {
+						return err
+					}
+					continue members
+				}
+			}
+			u.Types = append(u.Types, dt.entry)
+		}
+	}
+	for _, i := range f.Interfaces {
+		err := desc.processModifiers(i)
+		if err != nil {
+			if err = desc.report(fmt.Errorf("at %v: %w", i.Pos, err)); err != nil {
+				return err
+			}
+		}
+		for _, fld := range i.Fields {
+			fld.Type.Complex = !IsPrimitiveType(fld.Type.Type)
+			if dt, ok := desc.FindType(fld.Type.Type); ok && dt.enum != nil {
+				fld.Type.Complex = false
+			}
+		}
+	}
+	for _, e := range f.Entries {
+		for _, in := range e.Implements {
+			dt, ok := desc.types[in]
+			if !ok || dt.iface == nil {
+				err := desc.report(fmt.Errorf("at %v: type %s: interface %s not found in package %s", e.Pos, e.Name, in, desc.Name))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			// Go interface requires getters that are not generated for singletons
+			if e.HasModifier(TypeModifierSingleton) {
+				err := desc.report(fmt.Errorf("at %v: singleton %s can not implement interface %s", e.Pos, e.Name, in))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			implemented := true
+			for _, ifld := range dt.iface.Fields {
+				var err error
+				fld := e.GetField(ifld.Name)
+				if fld == nil {
+					err = fmt.Errorf("at %v: type %s does not implement %s: field %s is missing", e.Pos, e.Name, in, ifld.Name)
+				} else if kind := interfaceFieldKindWithoutGetter(fld); kind != "" {
+					err = fmt.Errorf(
+						"at %v: type %s does not implement %s: %s field %s has no getter",
+						fld.Pos,
+						e.Name,
+						in,
+						kind,
+						ifld.Name,
+					)
+				} else if !fld.Type.SameAs(ifld.Type) {
+					err = fmt.Errorf(
+						"at %v: type %s does not implement %s: field %s should be of type %s",
+						fld.Pos,
+						e.Name,
+						in,
+						ifld.Name,
+						ifld.Type.String(),
+					)
+				}
+				if err != nil {
+					implemented = false
+					if err = desc.report(err); err != nil {
+						return err
+					}
+				}
+			}
+			if implemented {
+				dt.iface.Implementations = append(dt.iface.Implementations, e)
+			}
+		}
+	}
+	return nil
+}
+
+// interfaceFieldKindWithoutGetter returns kind of field (e.g. 'calculated') if getter is not generated for it,
+// so it can not be a field of interface; empty string is returned for other fields
+func interfaceFieldKindWithoutGetter(f *Field) string {
+	switch {
+	case f.HasModifier(AttrModifierCalculated):
+		return string(AttrModifierCalculated)
+	case f.HasModifier(AttrModifierAuxiliary):
+		return string(AttrModifierAuxiliary)
+	case f.HasModifier(AttrModifierOneToMany) && !f.HasModifier(AttrModifierEmbedded):
+		// it is ignored by code generator (values are looked up by foreign key)
+		return string(AttrModifierOneToMany)
+	}
+	return ""
+}
+
 func (desc *Package) prepareFileFields(f *File) {
 	f.Annotations = Annotations{}
 }
@@ -251,6 +485,36 @@ func (desc *Package) processModifiers(item interface{}) error {
 				t.Annotations[m.Annotation.Name] = m.Annotation
 			}
 		}
+	} else if u, ok := item.(*Union); ok {
+		for _, m := range u.Modifiers {
+			if m.Annotation != nil {
+				err := desc.checkAnnotation(m.Annotation, item)
+				if err != nil {
+					return err
+				}
+				u.Annotations[m.Annotation.Name] = m.Annotation
+			}
+		}
+	} else if en, ok := item.(*Enum); ok {
+		for _, m := range en.Modifiers {
+			if m.Annotation != nil {
+				err := desc.checkAnnotation(m.Annotation, item)
+				if err != nil {
+					return err
+				}
+				en.Annotations[m.Annotation.Name] = m.Annotation
+			}
+		}
+	} else if i, ok := item.(*Interface); ok {
+		for _, m := range i.Modifiers {
+			if m.Annotation != nil {
+				err := desc.checkAnnotation(m.Annotation, item)
+				if err != nil {
+					return err
+				}
+				i.Annotations[m.Annotation.Name] = m.Annotation
+			}
+		}
 	} else if f, ok := item.(*File); ok {
 		for _, m := range f.Modifiers {
 			if m.Annotation != nil {
@@ -274,6 +538,9 @@ func (desc *Package) processModifiers(item interface{}) error {
 			// t.Annotations = ann
 			ann = t.Annotations
 			m = t.Modifiers
+		case *EnumField:
+			ann = t.Annotations
+			m = t.Modifiers
 		}
 		for _, m := range m {
 			if m.Annotation != nil {
@@ -294,10 +561,15 @@ func (desc *Package) processModifiers(item interface{}) error {
 }
 
 func (desc *Package) checkAnnotation(ann *Annotation, item interface{}) error {
+	described, err := desc.checkAnnotationSchema(ann, item)
+	if err != nil {
+		return err
+	}
 	found, err := desc.checkStandardAnnotation(ann, item)
 	if err != nil {
 		return err
 	}
+	found = found || described
 
 	for _, gen := range desc.generators {
 		ok, err := gen.CheckAnnotation(desc, ann, item)
@@ -307,11 +579,15 @@ func (desc *Package) checkAnnotation(ann *Annotation, item interface{}) error {
 		found = found || ok
 	}
 	if !found {
+		msg := fmt.Sprintf("at %v: unknown annotation: %s", ann.Pos, ann.Name)
+		if suggestions := desc.Project.SuggestAnnotations(ann.Name); len(suggestions) > 0 {
+			msg = fmt.Sprintf("%s; did you mean %s?", msg, strings.Join(suggestions, ", "))
+		}
 		switch desc.Options().UnknownAnnotation {
 		case UnknownAnnotationError:
-			return fmt.Errorf("at %v: unknown annotation: %s", ann.Pos, ann.Name)
+			return errors.New(msg)
 		case UnknownAnnotationWarning:
-			desc.AddWarning(fmt.Sprintf("at %v: unknown annotation: %s", ann.Pos, ann.Name))
+			desc.AddWarning(msg)
 		}
 	}
 	return nil
@@ -321,6 +597,11 @@ func (desc *Package) checkStandardAnnotation(ann *Annotation, item interface{})
 	switch ann.Name {
 	case AnnotationFind:
 		ok = true
+	case AnnotationDeprecated:
+		switch item.(type) {
+		case *Entity, *Field, *Method, *Enum, *EnumField, *Union, *Interface:
+			ok = true
+		}
 	case AnnotationRefPackage, AnnotationEngineless:
 		if _, isFile := item.(*File); isFile {
 			ok = true
@@ -832,6 +1113,31 @@ func (dt *DefinedType) Enum() *Enum {
 	return dt.enum
 }
 
+// Union returns underlying type
+func (dt *DefinedType) Union() *Union {
+	return dt.union
+}
+
+// Interface returns underlying type
+func (dt *DefinedType) Interface() *Interface {
+	return dt.iface
+}
+
+// IsPolymorphic returns true for unions and interfaces
+func (dt *DefinedType) IsPolymorphic() bool {
+	return dt.union != nil || dt.iface != nil
+}
+
+// PossibleTypes returns types that may be held by union or interface
+func (dt *DefinedType) PossibleTypes() []*Entity {
+	if dt.union != nil {
+		return dt.union.Types
+	} else if dt.iface != nil {
+		return dt.iface.Implementations
+	}
+	return nil
+}
+
 func (dt *DefinedType) IdType() (string, error) {
 	if dt.entry != nil {
 		if idField := dt.entry.GetIdField(); idField != nil {
@@ -1008,7 +1314,7 @@ func (desc *Package) findFieldsForParts(e *Entity, parts []string, fields []*Fie
 	if f.Type.Array != nil {
 		typeName = f.Type.Array.Type
 	}
-	if typeName != TipInt && typeName != TipString && typeName != TipBool && typeName != TipDate && typeName != TipFloat {
+	if !IsPrimitiveType(typeName) || typeName == TipAny {
 		dt, ok := desc.FindType(typeName)
 		if !ok {
 			return nil, fmt.Errorf("field '%s': type '%s' not found", parts[0], f.Type.Type)
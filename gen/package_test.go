@@ -0,0 +1,99 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+const interfaceTestSource = `package shop;
+
+interface Named {
+  Name: string;
+}
+
+type Product implements Named {
+  ID: int <id>;
+  Name: string;
+}
+`
+
+func TestInterfaceType(t *testing.T) {
+	files := generateTest(t, interfaceTestSource)
+	assertContains(
+		t, files["shop/test.go"],
+		"type Named interface {",
+		"GetName() string",
+		"func (*Product) isNamed() {}",
+	)
+}
+
+// TestInterfaceImplementation compiles generated package and checks that implementation satisfies the interface
+func TestInterfaceImplementation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles generated code")
+	}
+	runGenerated(t, interfaceTestSource, nil, "shop", interfaceMain)
+}
+
+const interfaceMain = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"PREFIX/shop"
+)
+
+func main() {
+	p := &shop.Product{}
+	p.SetName("tea")
+	var n shop.Named = p
+	if n.GetName() != "tea" {
+		fmt.Printf("GetName() = %q, want tea\n", n.GetName())
+		os.Exit(1)
+	}
+}
+`
+
+func TestInterfaceFieldWithoutGetter(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			"calculated", `package shop;
+interface Named {
+  Name: string;
+}
+type Product implements Named {
+  ID: int <id>;
+  Name: string <calculated>;
+}
+`, "calculated field Name has no getter",
+		},
+		{
+			"singleton", `package shop;
+interface Named {
+  Name: string;
+}
+singleton type Settings implements Named {
+  Name: string;
+}
+`, "singleton Settings can not implement interface Named",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := generateTestError(t, tt.src)
+				if !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("error = %v, want %q", err, tt.want)
+				}
+				if !strings.Contains(err.Error(), "test.vvf:") {
+					t.Errorf("error has no position: %v", err)
+				}
+			},
+		)
+	}
+}
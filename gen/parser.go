@@ -1,10 +1,9 @@
 package gen
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/alecthomas/participle"
@@ -22,16 +21,24 @@ func (b *Boolean) Capture(values []string) error {
 }
 
 type File struct {
-	Pos         lexer.Position
-	Name        string
-	FileName    string
+	Pos      lexer.Position
+	Name     string
+	FileName string
+	// Path - absolute path of the file, used to resolve imports
+	Path        string
 	Modifiers   []*PackageModifier `( (@@)*`
 	Package     string             `"package" @Ident ";")?`
-	Meta        []*Meta            `( @@ `
+	Imports     []*Import          `( @@ `
+	Mixins      []*Mixin           `| @@ `
+	Meta        []*Meta            `| @@ `
 	Entries     []*Entity          `| @@ `
-	Enums       []*Enum            `| @@ )*`
+	Enums       []*Enum            `| @@ `
+	Unions      []*Union           `| @@ `
+	Interfaces  []*Interface       `| @@ )*`
 	Pckg        *Package
 	Annotations Annotations
+	// docs - doc comments by line of declaration they precede
+	docs map[int]string
 }
 
 type PackageModifier struct {
@@ -40,6 +47,21 @@ type PackageModifier struct {
 	Annotation *Annotation `| @@ )`
 }
 
+// Import - import "common.vivard"; path is resolved relative to the importing file
+type Import struct {
+	Pos  lexer.Position
+	Path string `"import" @String ";"`
+	File *File
+}
+
+// Mixin - named set of type modifiers that may be applied to types with 'use Name'
+type Mixin struct {
+	Pos       lexer.Position
+	Name      string            `"mixin" @Ident "{"`
+	Modifiers []*EntityModifier `( @@ )* "}"`
+	File      *File
+}
+
 type Meta struct {
 	Pos      lexer.Position
 	TypeName string   ` "meta" ( "(" @Ident ")" )?`
@@ -57,9 +79,12 @@ type Entity struct {
 	Pos          lexer.Position
 	Modifiers    []*EntityModifier `( (@@)* )? `
 	Name         string            `"type" (@Ident | @QualifiedName)  `
-	BaseTypeName string            `( "extends" (@Ident | @QualifiedName) )? "{"`
+	BaseTypeName string            `( "extends" (@Ident | @QualifiedName) )?`
+	Implements   []string          `( "implements" @Ident ( "," @Ident )* )? "{"`
 	Entries      []*Entry          `( @@ )*`
 	Incomplete   bool              `(@More)? "}"`
+	// Doc - doc comment of the type
+	Doc          string
 	Fields       []*Field
 	Methods      []*Method
 	FieldsIndex  map[string]*Field
@@ -81,19 +106,56 @@ type Enum struct {
 	Modifiers    []*EntityModifier `( (@@)* )? `
 	Name         string            `"enum" @Ident "{"`
 	Fields       []*EnumField      `( @@ )* "}"`
+	Doc          string
+	Annotations  Annotations
 	Pckg         *Package
 	File         *File
 	AliasForType string
 	Features     Features
 }
 
-type EnumField struct {
+// Union - declaration of type that may hold value of one of the listed types: union Name = A | B;
+type Union struct {
 	Pos       lexer.Position
-	Name      string   ` @Ident ( "=" `
-	IntVal    *int     ` ( @Int `
-	FloatVal  *float64 ` | @Number `
-	StringVal *string  ` | @String ) )? ";" `
-	Parent    *Enum
+	Modifiers []*EntityModifier `( (@@)* )? `
+	Name      string            `"union" @Ident "="`
+	TypeNames []string          `@Ident ( "|" @Ident )* ";"`
+	Doc       string
+	// Types - resolved members of union (filled in beforePrepare)
+	Types       []*Entity
+	Annotations Annotations
+	Pckg        *Package
+	File        *File
+	Features    Features
+}
+
+// Interface - declaration of set of fields that should be present in types that implement it
+type Interface struct {
+	Pos         lexer.Position
+	Modifiers   []*EntityModifier `( (@@)* )? `
+	Name        string            `"interface" @Ident "{"`
+	Entries     []*Entry          `( @@ )* "}"`
+	Doc         string
+	Fields      []*Field
+	FieldsIndex map[string]*Field
+	// Implementations - types that implement interface (filled in beforePrepare)
+	Implementations []*Entity
+	Annotations     Annotations
+	Pckg            *Package
+	File            *File
+	Features        Features
+}
+
+type EnumField struct {
+	Pos         lexer.Position
+	Name        string           ` @Ident ( "=" `
+	IntVal      *int             ` ( @Int `
+	FloatVal    *float64         ` | @Number `
+	StringVal   *string          ` | @String ) )? `
+	Modifiers   []*EntryModifier `( "<" ( @@ )* ">" )? ";"`
+	Doc         string
+	Annotations Annotations
+	Parent      *Enum
 }
 
 type Entry struct {
@@ -108,6 +170,7 @@ type Field struct {
 	Modifiers   []*EntryModifier
 	Name        string   `@Ident ":"`
 	Type        *TypeRef `@@`
+	Doc         string
 	Tags        map[string]string
 	Annotations Annotations
 	// Features - generators created values based on Annotations, generators options...
@@ -120,6 +183,7 @@ type Method struct {
 	Name        string         `@Ident "("`
 	Params      []*MethodParam ` (  (@@) ("," @@)* )? `
 	RetValue    *TypeRef       `")" (":" @@)?`
+	Doc         string
 	Annotations Annotations
 	// Features - generators created values based on Annotations, generators options...
 	Features Features
@@ -151,7 +215,8 @@ type EntityModifier struct {
 	Pos          lexer.Position
 	Hook         *Hook             `( @@`
 	Annotation   *Annotation       `| @@`
-	TypeModifier *TypeModifierType `| @@ )`
+	TypeModifier *TypeModifierType `| @@`
+	Use          string            `| "use" @Ident )`
 }
 
 type TypeModifierType struct {
@@ -262,31 +327,66 @@ func annotationMapper(token lexer.Token) (lexer.Token, error) {
 	return token, nil
 }
 
-func Parse(files []string) ([]*File, error) {
-	ret := []*File{}
-	for _, file := range files {
-		ast := &File{}
-		r, err := os.Open(file)
+// SourceToken is a lexical token of DSL source (see Tokenize)
+type SourceToken struct {
+	// Kind is the name of lexer symbol (Ident, QualifiedName, AnnotationTag, HookTag, AttrModifier, String, Punct...)
+	Kind  string
+	Value string
+	Pos   lexer.Position
+}
+
+// Tokenize returns tokens of DSL source skipping comments and whitespaces;
+// in case of lexer error tokens found before it are returned along with the error
+func Tokenize(name string, src []byte) ([]SourceToken, error) {
+	l, err := lex.Lex(sourceReader{Reader: bytes.NewReader(src), name: name})
+	if err != nil {
+		return nil, err
+	}
+	kinds := map[rune]string{}
+	for kind, r := range lex.Symbols() {
+		kinds[r] = kind
+	}
+	var ret []SourceToken
+	for {
+		t, err := l.Next()
 		if err != nil {
-			return nil, fmt.Errorf("can't open file '%s': %w", file, err)
+			return ret, err
+		}
+		if t.EOF() {
+			return ret, nil
+		}
+		kind := kinds[t.Type]
+		if kind == "Comment" || kind == "Whitespace" {
+			continue
 		}
-		err = parser.Parse(r, ast)
-		r.Close()
+		ret = append(ret, SourceToken{Kind: kind, Value: t.Value, Pos: t.Pos})
+	}
+}
+
+func Parse(files []string) ([]*File, error) {
+	return ParseSources(files, nil)
+}
+
+// ParseSources parses files like Parse but takes content of files from sources (by absolute path) if it is there
+func ParseSources(files []string, sources map[string][]byte) ([]*File, error) {
+	fp := &fileParser{parsed: map[string]*File{}, sources: sources}
+	for _, file := range files {
+		_, err := fp.parseFile(file, nil)
 		if err != nil {
-			return nil, fmt.Errorf("while parsing '%s': %w", file, err)
+			return nil, err
 		}
-		ast.FileName = filepath.Base(file)
-		ast.Name = ast.FileName
+	}
+	err := fp.applyMixins()
+	if err != nil {
+		return nil, err
+	}
+	for _, ast := range fp.files {
 		err = ast.postProcess()
 		if err != nil {
-			return nil, fmt.Errorf("while post processing '%s': %w", file, err)
+			return nil, fmt.Errorf("while post processing '%s': %w", ast.Path, err)
 		}
-		if ext := strings.Index(ast.Name, "."); ext != -1 {
-			ast.Name = ast.Name[:ext]
-		}
-		ret = append(ret, ast)
 	}
-	return ret, nil
+	return fp.files, nil
 }
 
 func (f *File) postProcess() error {
@@ -306,10 +406,12 @@ func (f *File) postProcess() error {
 		t.FieldsIndex = map[string]*Field{}
 		t.MethodsIndex = map[string]*Method{}
 		t.Features = Features{}
+		t.Doc = f.docs[t.Pos.Line]
 		// stripAnnotations(t.Modifiers)
 		for _, te := range t.Entries {
 			if te.Field != nil {
 				te.Field.Modifiers = te.Modifiers
+				te.Field.Doc = f.docs[te.Pos.Line]
 				te.Field.Features = Features{}
 				te.Field.parent = t
 				t.Fields = append(t.Fields, te.Field)
@@ -317,6 +419,7 @@ func (f *File) postProcess() error {
 				//te.Field.PostProcess()
 			} else if te.Method != nil {
 				te.Method.Modifiers = te.Modifiers
+				te.Method.Doc = f.docs[te.Pos.Line]
 				te.Method.Features = Features{}
 				te.Method.parent = t
 				if te.Method.RetValue != nil {
@@ -334,12 +437,55 @@ func (f *File) postProcess() error {
 	}
 	for _, enum := range f.Enums {
 		enum.Features = Features{}
+		enum.Annotations = Annotations{}
+		enum.Doc = f.docs[enum.Pos.Line]
+		for _, ef := range enum.Fields {
+			ef.Doc = f.docs[ef.Pos.Line]
+			ef.Annotations = Annotations{}
+		}
+	}
+	for _, u := range f.Unions {
+		u.Features = Features{}
+		u.Annotations = Annotations{}
+		u.Doc = f.docs[u.Pos.Line]
+		for _, tn := range u.TypeNames {
+			if IsPrimitiveType(tn) {
+				return fmt.Errorf("at %v: union %s: primitive type %s can not be a member of union", u.Pos, u.Name, tn)
+			}
+		}
+	}
+	for _, i := range f.Interfaces {
+		i.Features = Features{}
+		i.Annotations = Annotations{}
+		i.FieldsIndex = map[string]*Field{}
+		i.Doc = f.docs[i.Pos.Line]
+		for _, ie := range i.Entries {
+			if ie.Field == nil {
+				return fmt.Errorf("at %v: interface %s: only fields may be declared in interface", ie.Pos, i.Name)
+			}
+			ie.Field.Modifiers = ie.Modifiers
+			ie.Field.Features = Features{}
+			ie.Field.Annotations = Annotations{}
+			ie.Field.Doc = f.docs[ie.Pos.Line]
+			i.Fields = append(i.Fields, ie.Field)
+			i.FieldsIndex[ie.Field.Name] = ie.Field
+		}
 	}
 	return nil
 }
 
+// HasDeclarations returns false for files that contain nothing to generate code for (e.g. mixins only)
+func (f *File) HasDeclarations() bool {
+	return len(f.Entries) > 0 || len(f.Enums) > 0 || len(f.Unions) > 0 || len(f.Interfaces) > 0 || len(f.Meta) > 0
+}
+
 func IsPrimitiveType(name string) bool {
-	return name == TipBool || name == TipDate || name == TipFloat || name == TipInt || name == TipString || name == TipAny
+	switch name {
+	case TipBool, TipDate, TipFloat, TipInt, TipString, TipAny,
+		TipDateTime, TipDecimal, TipTime, TipDuration, TipUUID, TipBytes:
+		return true
+	}
+	return false
 }
 
 // FS is a shortcut to Features.String()
@@ -566,6 +712,10 @@ func (f *Field) PostProcess() {
 		return complex
 	}
 	f.Type.Complex = isTypeComplex(f.Type.Type)
+	// values of unions and interfaces are always stored inside of the owner
+	if f.IsPolymorphic() && !f.HasModifier(AttrModifierEmbedded) {
+		f.Modifiers = append(f.Modifiers, &EntryModifier{AttrModifier: string(AttrModifierEmbedded)})
+	}
 	f.Type.Embedded = f.HasModifier(AttrModifierEmbedded)
 	//trying to fill complex for ref types of arrays...
 	arrType := f.Type.Array
@@ -575,11 +725,67 @@ func (f *Field) PostProcess() {
 	}
 }
 
+// SameAs returns true if ref describes the same type as other
+func (ref *TypeRef) SameAs(other *TypeRef) bool {
+	if ref == nil || other == nil {
+		return ref == other
+	}
+	if ref.Array != nil || other.Array != nil {
+		return ref.Array.SameAs(other.Array)
+	}
+	if ref.Map != nil || other.Map != nil {
+		return ref.Map != nil && other.Map != nil &&
+			ref.Map.KeyType == other.Map.KeyType &&
+			ref.Map.ValueType.SameAs(other.Map.ValueType)
+	}
+	return ref.Type == other.Type
+}
+
+// String returns type in DSL notation
+func (ref *TypeRef) String() string {
+	var ret string
+	if ref.Array != nil {
+		ret = "[" + ref.Array.String() + "]"
+	} else if ref.Map != nil {
+		ret = fmt.Sprintf("map[%s]%s", ref.Map.KeyType, ref.Map.ValueType.String())
+	} else {
+		ret = ref.Type
+		if ref.Ref {
+			ret = "*" + ret
+		}
+	}
+	if ref.NonNullable {
+		ret += "!"
+	}
+	return ret
+}
+
 // Parent returns enclosing entity
 func (f *Field) Parent() *Entity {
 	return f.parent
 }
 
+// PolymorphicType returns union or interface descriptor if field (or array's item) has such type
+func (f *Field) PolymorphicType() (*DefinedType, bool) {
+	ref := f.Type
+	for ref.Array != nil {
+		ref = ref.Array
+	}
+	if ref.Map != nil || IsPrimitiveType(ref.Type) || f.parent == nil {
+		return nil, false
+	}
+	if dt, ok := f.parent.Pckg.FindType(ref.Type); ok && dt.IsPolymorphic() {
+		return dt, true
+	}
+	return nil, false
+}
+
+// IsPolymorphic returns true if field (or array's item) is of union or interface type
+func (f *Field) IsPolymorphic() bool {
+	_, ok := f.PolymorphicType()
+	return ok
+}
+
 // FS shorcut to Features.String()
 func (f *Field) FS(kind FeatureKind, name string) string {
 	return f.Features.String(kind, name)
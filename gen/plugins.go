@@ -0,0 +1,157 @@
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// resolvePlugins adds plugins required by project's generators (see PluginsRequirer) and orders generators
+// so every generator runs after the ones it requires or follows (see PluginsFollower);
+// otherwise the order in which generators were added is kept
+func (p *Project) resolvePlugins() error {
+	for i := 0; i < len(p.generators); i++ {
+		g := p.generators[i]
+		pr, ok := g.(PluginsRequirer)
+		if !ok {
+			continue
+		}
+		for _, name := range pr.Requires() {
+			if p.hasGenerator(name) {
+				continue
+			}
+			if _, ok := plugins[name]; !ok {
+				return fmt.Errorf("plugin %s requires plugin '%s' that is not registered", g.Name(), name)
+			}
+			// added generator is checked for its own requirements later in this loop
+			if err := p.WithPlugin(name, nil); err != nil {
+				return fmt.Errorf("while adding plugin %s required by %s: %w", name, g.Name(), err)
+			}
+		}
+	}
+	order, err := p.generatorsOrder()
+	if err != nil {
+		return err
+	}
+	rank := make(map[any]int, len(order))
+	for i, g := range order {
+		rank[g] = i
+	}
+	p.generators = order
+	reorderAsGenerators(p.featureProviders, rank)
+	reorderAsGenerators(p.metaProcs, rank)
+	reorderAsGenerators(p.hooks, rank)
+	reorderAsGenerators(p.fragmentProviders, rank)
+	return nil
+}
+
+func (p *Project) hasGenerator(name string) bool {
+	for _, g := range p.generators {
+		if g.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// generatorsOrder returns generators sorted topologically by their dependencies;
+// of the generators ready to run the earliest added one is taken first
+func (p *Project) generatorsOrder() ([]Generator, error) {
+	byName := map[string][]int{}
+	for i, g := range p.generators {
+		byName[g.Name()] = append(byName[g.Name()], i)
+	}
+	// deps[i] - indexes of generators that should run before generator i
+	deps := make([][]int, len(p.generators))
+	for i, g := range p.generators {
+		var names []string
+		if pr, ok := g.(PluginsRequirer); ok {
+			names = append(names, pr.Requires()...)
+		}
+		if pf, ok := g.(PluginsFollower); ok {
+			names = append(names, pf.After()...)
+		}
+		for _, name := range names {
+			for _, j := range byName[name] {
+				if j != i {
+					deps[i] = append(deps[i], j)
+				}
+			}
+		}
+	}
+	done := make([]bool, len(p.generators))
+	ret := make([]Generator, 0, len(p.generators))
+	for len(ret) < len(p.generators) {
+		next := -1
+		for i := range p.generators {
+			if !done[i] && allDone(deps[i], done) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return nil, p.orderConflict(deps, done)
+		}
+		done[next] = true
+		ret = append(ret, p.generators[next])
+	}
+	return ret, nil
+}
+
+func allDone(idxs []int, done []bool) bool {
+	for _, i := range idxs {
+		if !done[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// orderConflict returns error describing a cycle among generators that are not ordered yet
+func (p *Project) orderConflict(deps [][]int, done []bool) error {
+	// every not ordered generator waits for another not ordered one, so following them leads to a cycle
+	start := 0
+	for done[start] {
+		start++
+	}
+	visited := map[int]int{}
+	var path []int
+	for i := start; ; {
+		if pos, ok := visited[i]; ok {
+			path = append(path[pos:], i)
+			break
+		}
+		visited[i] = len(path)
+		path = append(path, i)
+		for _, j := range deps[i] {
+			if !done[j] {
+				i = j
+				break
+			}
+		}
+	}
+	names := make([]string, len(path))
+	for i, idx := range path {
+		names[i] = p.generators[idx].Name()
+	}
+	return fmt.Errorf(
+		"plugins order conflict: %s (every plugin should run after the next one)",
+		strings.Join(names, " -> "),
+	)
+}
+
+// reorderAsGenerators sorts items that are generators in the order of rank; other items keep their places
+func reorderAsGenerators[T any](items []T, rank map[any]int) {
+	var slots []int
+	var gens []T
+	for i, item := range items {
+		if _, ok := rank[any(item)]; ok {
+			slots = append(slots, i)
+			gens = append(gens, item)
+		}
+	}
+	sort.SliceStable(gens, func(i, j int) bool { return rank[any(gens[i])] < rank[any(gens[j])] })
+	for i, slot := range slots {
+		items[slot] = gens[i]
+	}
+}
@@ -0,0 +1,246 @@
+package gen
+
+import (
+	"github.com/dave/jennifer/jen"
+)
+
+func (cg *ProtobufGenerator) generateEntityConverters(t *Entity, m *protoMessage) {
+	name := t.FS(FeatGoKind, FCGName)
+	pbType := jen.Qual(cg.goPackage(cg.desc.Name), t.Name)
+	cg.b.Functions.Add(
+		jen.Commentf("%sToProto converts %s to protobuf message", t.Name, name).Line().
+			Func().Id(t.Name+"ToProto").Params(jen.Id("o").Op("*").Id(name)).Op("*").Add(pbType).BlockFunc(
+			func(g *jen.Group) {
+				g.If(jen.Id("o").Op("==").Nil()).Block(jen.Return(jen.Nil()))
+				g.Id("m").Op(":=").Op("&").Add(pbType).Values()
+				// fields of base types are accessible only if base is set
+				for i := 0; i < len(m.fields); {
+					owner := m.fields[i].owner
+					var stmts []jen.Code
+					for ; i < len(m.fields) && m.fields[i].owner == owner; i++ {
+						stmts = append(stmts, cg.fieldToProto(m.fields[i])...)
+					}
+					if cond := cg.baseCondition(t, owner); cond != nil {
+						g.If(cond).Block(stmts...)
+					} else {
+						for _, s := range stmts {
+							g.Add(s)
+						}
+					}
+				}
+				g.Return(jen.Id("m"))
+			},
+		).Line(),
+		jen.Commentf("%sFromProto converts protobuf message to %s", t.Name, name).Line().
+			Func().Id(t.Name+"FromProto").Params(jen.Id("m").Op("*").Add(pbType)).Parens(
+			jen.List(jen.Op("*").Id(name), jen.Error()),
+		).BlockFunc(
+			func(g *jen.Group) {
+				g.If(jen.Id("m").Op("==").Nil()).Block(jen.Return(jen.Nil(), jen.Nil()))
+				g.Id("o").Op(":=").Op("&").Id(name).Values()
+				for bt := t; bt.BaseTypeName != ""; {
+					bt = bt.GetBaseType()
+					bn := bt.FS(FeatGoKind, FCGName)
+					g.Id("o").Dot(bn).Op("=").Op("&").Id(bn).Values()
+				}
+				for _, f := range m.fields {
+					for _, s := range cg.fieldFromProto(f) {
+						g.Add(s)
+					}
+				}
+				g.Return(jen.Id("o"), jen.Nil())
+			},
+		).Line(),
+	)
+}
+
+// baseCondition returns condition for accessing fields of base type owner embedded (by pointer) into t
+func (cg *ProtobufGenerator) baseCondition(t *Entity, owner *Entity) jen.Code {
+	var cond *jen.Statement
+	for bt := t; bt != owner && bt.BaseTypeName != ""; {
+		bt = bt.GetBaseType()
+		c := jen.Id("o").Dot(bt.FS(FeatGoKind, FCGName)).Op("!=").Nil()
+		if cond == nil {
+			cond = c
+		} else {
+			cond = cond.Op("&&").Add(c)
+		}
+	}
+	if cond == nil {
+		return nil
+	}
+	return cond
+}
+
+func (cg *ProtobufGenerator) fieldToProto(f *protoField) []jen.Code {
+	src := jen.Id("o").Dot(f.goName)
+	dst := jen.Id("m").Dot(protoGoName(f.name))
+	v := f.value
+	deref := jen.Op("*").Add(src)
+	if v.method {
+		deref = jen.Parens(deref)
+	}
+	switch {
+	case f.array:
+		body := jen.Add(dst).Op("=").Append(dst, v.toProto(jen.Id("v")))
+		if v.goPointer {
+			body = jen.If(jen.Id("v").Op("!=").Nil()).Block(body)
+		}
+		return []jen.Code{
+			jen.If(jen.Len(src).Op(">").Lit(0)).Block(
+				jen.Add(dst).Op("=").Make(jen.Index().Add(v.pbType), jen.Lit(0), jen.Len(src)),
+				jen.For(jen.List(jen.Id("_"), jen.Id("v")).Op(":=").Range().Add(src)).Block(body),
+			),
+		}
+	case f.mapKey != "":
+		key := jen.Id("k")
+		keyType := jen.String()
+		if f.mapKey == TipInt {
+			key = jen.Int64().Parens(key)
+			keyType = jen.Int64()
+		}
+		return []jen.Code{
+			jen.If(jen.Len(src).Op(">").Lit(0)).Block(
+				jen.Add(dst).Op("=").Make(jen.Map(keyType).Add(v.pbType), jen.Len(src)),
+				jen.For(jen.List(jen.Id("k"), jen.Id("v")).Op(":=").Range().Add(src)).Block(
+					jen.Add(dst).Index(key).Op("=").Add(v.toProto(jen.Id("v"))),
+				),
+			),
+		}
+	case f.pointer && v.nilable:
+		return []jen.Code{jen.If(jen.Add(src).Op("!=").Nil()).Block(jen.Add(dst).Op("=").Add(v.toProto(deref)))}
+	case f.pointer:
+		return []jen.Code{
+			jen.If(jen.Add(src).Op("!=").Nil()).Block(
+				jen.Id("v").Op(":=").Add(v.toProto(deref)),
+				jen.Add(dst).Op("=").Op("&").Id("v"),
+			),
+		}
+	}
+	return []jen.Code{jen.Add(dst).Op("=").Add(v.toProto(src))}
+}
+
+func (cg *ProtobufGenerator) fieldFromProto(f *protoField) []jen.Code {
+	src := jen.Id("m").Dot(protoGoName(f.name))
+	dst := jen.Id("o").Dot(f.goName)
+	v := f.value
+	// assign returns statements that assign converted value of pb with set
+	assign := func(pb jen.Code, set func(val jen.Code) jen.Code) []jen.Code {
+		if !v.withErr {
+			return []jen.Code{set(v.fromProto(pb))}
+		}
+		return []jen.Code{
+			jen.List(jen.Id("val"), jen.Err()).Op(":=").Add(v.fromProto(pb)),
+			jen.If(jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Nil(), jen.Qual("fmt", "Errorf").Call(jen.Lit(f.name+": %w"), jen.Err())),
+			),
+			set(jen.Id("val")),
+		}
+	}
+	// assignPointer assigns pointer to converted value of pb
+	assignPointer := func(pb jen.Code) []jen.Code {
+		if !v.withErr {
+			return []jen.Code{jen.Id("val").Op(":=").Add(v.fromProto(pb)), jen.Add(dst).Op("=").Op("&").Id("val")}
+		}
+		return assign(pb, func(val jen.Code) jen.Code { return jen.Add(dst).Op("=").Op("&").Add(val) })
+	}
+	goType := f.field.Features.Stmt(FeatGoKind, FCGType)
+	switch {
+	case f.array:
+		return []jen.Code{
+			jen.If(jen.Len(src).Op(">").Lit(0)).Block(
+				jen.Add(dst).Op("=").Make(goType, jen.Lit(0), jen.Len(src)),
+				jen.For(jen.List(jen.Id("_"), jen.Id("v")).Op(":=").Range().Add(src)).Block(
+					assign(jen.Id("v"), func(val jen.Code) jen.Code { return jen.Add(dst).Op("=").Append(dst, val) })...,
+				),
+			),
+		}
+	case f.mapKey != "":
+		key := jen.Id("k")
+		if f.mapKey == TipInt {
+			key = jen.Int().Parens(key)
+		}
+		return []jen.Code{
+			jen.If(jen.Len(src).Op(">").Lit(0)).Block(
+				jen.Add(dst).Op("=").Make(goType, jen.Len(src)),
+				jen.For(jen.List(jen.Id("k"), jen.Id("v")).Op(":=").Range().Add(src)).Block(
+					assign(jen.Id("v"), func(val jen.Code) jen.Code { return jen.Add(dst).Index(key).Op("=").Add(val) })...,
+				),
+			),
+		}
+	case f.pointer && v.nilable:
+		return []jen.Code{jen.If(jen.Add(src).Op("!=").Nil()).Block(assignPointer(src)...)}
+	case f.pointer:
+		return []jen.Code{jen.If(jen.Add(src).Op("!=").Nil()).Block(assignPointer(jen.Op("*").Add(src))...)}
+	}
+	stmts := assign(src, func(val jen.Code) jen.Code { return jen.Add(dst).Op("=").Add(val) })
+	switch {
+	case v.isSet != nil:
+		return []jen.Code{jen.If(v.isSet(src)).Block(stmts...)}
+	case v.withErr:
+		return []jen.Code{jen.Block(stmts...)}
+	}
+	return stmts
+}
+
+func (cg *ProtobufGenerator) generatePolymorphicConverters(name string, types []*Entity, m *protoMessage) {
+	holder := polymorphicHolderName(name)
+	pbType := jen.Qual(cg.goPackage(cg.desc.Name), name)
+	cg.b.Functions.Add(
+		jen.Commentf("%sToProto converts %s to protobuf message", name, holder).Line().
+			Func().Id(name+"ToProto").Params(jen.Id("h").Op("*").Id(holder)).Op("*").Add(pbType).Block(
+			jen.If(jen.Id("h").Op("==").Nil()).Block(jen.Return(jen.Nil())),
+			jen.Switch(jen.Id("v").Op(":=").Id("h").Dot("Value").Assert(jen.Type())).BlockFunc(
+				func(g *jen.Group) {
+					for _, f := range m.oneof {
+						dt, _ := cg.desc.FindType(f.typ)
+						g.Case(jen.Op("*").Add(cg.goTypeName(dt, dt.name))).Block(
+							jen.Return(
+								jen.Op("&").Add(pbType).Values(
+									jen.Id("Value").Op(":").Op("&").Qual(cg.goPackage(cg.desc.Name), name+"_"+protoGoName(f.name)).Values(
+										jen.Id(protoGoName(f.name)).Op(":").Add(f.value.toProto(jen.Id("v"))),
+									),
+								),
+							),
+						)
+					}
+				},
+			),
+			jen.Return(jen.Nil()),
+		).Line(),
+		jen.Commentf("%sFromProto converts protobuf message to %s", name, holder).Line().
+			Func().Id(name+"FromProto").Params(jen.Id("m").Op("*").Add(pbType)).Parens(
+			jen.List(jen.Op("*").Id(holder), jen.Error()),
+		).Block(
+			jen.If(jen.Id("m").Op("==").Nil().Op("||").Id("m").Dot("Value").Op("==").Nil()).Block(jen.Return(jen.Nil(), jen.Nil())),
+			jen.Switch(jen.Id("v").Op(":=").Id("m").Dot("Value").Assert(jen.Type())).BlockFunc(
+				func(g *jen.Group) {
+					for _, f := range m.oneof {
+						g.Case(jen.Op("*").Qual(cg.goPackage(cg.desc.Name), name+"_"+protoGoName(f.name))).Block(
+							jen.List(jen.Id("val"), jen.Err()).Op(":=").Add(f.value.fromProto(jen.Id("v").Dot(protoGoName(f.name)))),
+							jen.Return(jen.Op("&").Id(holder).Values(jen.Id("Value").Op(":").Id("val")), jen.Err()),
+						)
+					}
+				},
+			),
+			jen.Return(jen.Nil(), jen.Qual("fmt", "Errorf").Call(jen.Lit(name+": unknown value type: %T"), jen.Id("m").Dot("Value"))),
+		).Line(),
+	)
+}
+
+// pbTypeName returns Go type generated by protoc for defined type
+func (cg *ProtobufGenerator) pbTypeName(dt *DefinedType) *jen.Statement {
+	return jen.Qual(cg.goPackage(dt.pckg), dt.name)
+}
+
+// goTypeName returns name from package of defined type (e.g. type itself or its converter)
+func (cg *ProtobufGenerator) goTypeName(dt *DefinedType, name string) *jen.Statement {
+	if dt.pckg != cg.desc.Name {
+		return jen.Qual(dt.packagePath, name)
+	}
+	return jen.Id(name)
+}
+
+func protoNotNil(v jen.Code) jen.Code {
+	return jen.Add(v).Op("!=").Nil()
+}
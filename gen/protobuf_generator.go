@@ -0,0 +1,744 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const (
+	protobufGeneratorName = "Protobuf"
+	protobufOptionsName   = "protobuf"
+
+	// ProtobufAnnotation may be used for types and fields: $proto(skip) excludes them from .proto
+	ProtobufAnnotation        = "proto"
+	ProtobufAnnotationSkipTag = "skip"
+
+	// protobufPackageSuffix - Go package generated by protoc for package 'shop' is 'shoppb'
+	protobufPackageSuffix = "pb"
+	protobufLockSuffix    = ".proto.lock"
+
+	// field numbers reserved by protobuf implementation
+	protobufFirstReservedNumber = 19000
+	protobufLastReservedNumber  = 19999
+
+	protoTimestamp = "google.protobuf.Timestamp"
+	protoDuration  = "google.protobuf.Duration"
+	protoEmpty     = "google.protobuf.Empty"
+
+	timestamppbPackage = "google.golang.org/protobuf/types/known/timestamppb"
+	durationpbPackage  = "google.golang.org/protobuf/types/known/durationpb"
+	emptypbPackage     = "google.golang.org/protobuf/types/known/emptypb"
+	grpcCodesPackage   = "google.golang.org/grpc/codes"
+	grpcStatusPackage  = "google.golang.org/grpc/status"
+	resourcePackage    = VivardPackage + "/resource"
+
+	grpcServerName = "GRPCServer"
+	grpcErrorFunc  = "grpcError"
+)
+
+type ProtobufOptions struct {
+	// ProtoDir - directory for .proto and lock files; <output>/<package>/<package>pb by default
+	// (so protoc with paths=source_relative puts Go code next to .proto)
+	ProtoDir string `json:"proto_dir"`
+	// GoPackage - import path of Go package generated by protoc (%s is replaced with package name);
+	// <package path>/<package>pb by default
+	GoPackage string `json:"go_package"`
+	// ProtoPackage - prefix for proto package (e.g. 'acme.api' gives 'acme.api.shop')
+	ProtoPackage string `json:"proto_package"`
+	// LockFile - path of the file with field numbers (%s is replaced with package name; it is required
+	// if there are several packages); <proto dir>/<package>.proto.lock by default
+	LockFile string `json:"lock_file"`
+}
+
+// ProtobufGenerator generates .proto file for each package (messages for types, enums and unions,
+// gRPC service for the operations generated by GQLGenerator), functions that convert Go types to and
+// from protobuf messages and GRPCServer that implements the service with Engine's methods.
+// Go code for messages should be generated by protoc with protoc-gen-go and protoc-gen-go-grpc.
+// Field numbers are kept in lock file and never reused, so the file should be kept with the sources
+type ProtobufGenerator struct {
+	proj    *Project
+	desc    *Package
+	b       *Builder
+	options ProtobufOptions
+	// packages - packages for which .proto is already generated
+	packages map[string]*protoPackage
+}
+
+type protoPackage struct {
+	name       string
+	lock       protoLock
+	imports    map[string]bool
+	enums      []*protoEnum
+	messages   []*protoMessage
+	rpcs       []*protoRPC
+	serverDone bool
+}
+
+type protoEnum struct {
+	name       string
+	doc        string
+	deprecated bool
+	values     []protoEnumValue
+}
+
+type protoEnumValue struct {
+	name       string
+	number     int
+	deprecated bool
+}
+
+type protoMessage struct {
+	name       string
+	doc        string
+	deprecated bool
+	fields     []*protoField
+	// oneof - fields of oneof 'value' (for unions and interfaces)
+	oneof    []*protoField
+	reserved []string
+}
+
+type protoField struct {
+	name       string
+	number     int
+	label      string
+	typ        string
+	doc        string
+	deprecated bool
+	// goName - name of the field in Go struct; owner - type that declares the field
+	goName string
+	owner  *Entity
+	field  *Field
+	value  *protoValue
+	// pointer - field of Go struct is pointer
+	pointer bool
+	// array and mapKey - field is repeated or map (mapKey is Go type of key)
+	array  bool
+	mapKey string
+}
+
+type protoRPC struct {
+	kind     GQLOperationKind
+	entity   *Entity
+	name     string
+	request  string
+	response string
+}
+
+// protoValue describes conversion of the value of the scalar (not array or map) type
+type protoValue struct {
+	// typ - type in .proto; pbType - Go type in protoc generated package
+	typ    string
+	pbType jen.Code
+	// nilable - Go type generated by protoc is nilable (message or bytes), so no 'optional' is required
+	nilable bool
+	// goPointer - Go value is pointer (to struct or polymorphic holder)
+	goPointer bool
+	// toProto and fromProto return expressions converting v; method - toProto calls method of v
+	method    bool
+	toProto   func(v jen.Code) jen.Code
+	fromProto func(v jen.Code) jen.Code
+	// withErr - fromProto returns value and error
+	withErr bool
+	// isSet returns condition of v to be converted by fromProto (nil if always)
+	isSet func(v jen.Code) jen.Code
+}
+
+func init() {
+	RegisterPluginFactory(func() Generator { return &ProtobufGenerator{} })
+}
+
+func (cg *ProtobufGenerator) Name() string {
+	return protobufGeneratorName
+}
+
+func (cg *ProtobufGenerator) SetOptions(opts any) error {
+	return OptionsAnyToStruct(opts, &cg.options)
+}
+
+func (cg *ProtobufGenerator) SetDescriptor(proj *Project) {
+	cg.proj = proj
+	cg.packages = map[string]*protoPackage{}
+	if _, err := proj.Options.CustomToStruct(protobufOptionsName, &cg.options); err != nil {
+		proj.AddWarning(fmt.Sprintf("problem while setting custom options for %s: %v", protobufOptionsName, err))
+	}
+}
+
+func (cg *ProtobufGenerator) CheckAnnotation(desc *Package, ann *Annotation, item interface{}) (bool, error) {
+	if ann.Name == ProtobufAnnotation {
+		switch item.(type) {
+		case *Entity, *Field:
+			return true, nil
+		}
+		return true, fmt.Errorf("at %v: annotation %s may be used for types and fields only", ann.Pos, ProtobufAnnotation)
+	}
+	return false, nil
+}
+
+// AnnotationSchemas from AnnotationsDescriber
+func (cg *ProtobufGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    ProtobufAnnotation,
+			Targets: AnnotationTargetEntity | AnnotationTargetField,
+			Tags: []AnnotationTagSchema{
+				{Name: ProtobufAnnotationSkipTag, Type: AnnotationTagBool, Default: false, Doc: "exclude from .proto"},
+			},
+			AnyTags: true,
+			Doc:     "protobuf settings",
+		},
+	}
+}
+
+func (cg *ProtobufGenerator) Prepare(desc *Package) error {
+	cg.desc = desc
+	return nil
+}
+
+func (cg *ProtobufGenerator) Generate(b *Builder) (err error) {
+	cg.desc = b.Descriptor
+	cg.b = b
+	pp, ok := cg.packages[b.Descriptor.Name]
+	if !ok {
+		pp, err = cg.preparePackage(b.Descriptor)
+		if err != nil {
+			return
+		}
+		cg.packages[b.Descriptor.Name] = pp
+		if err = cg.writeProto(pp); err != nil {
+			return
+		}
+	}
+	messages := map[string]*protoMessage{}
+	for _, m := range pp.messages {
+		messages[m.name] = m
+	}
+	for _, t := range b.File.Entries {
+		if m, ok := messages[t.Name]; ok {
+			cg.generateEntityConverters(t, m)
+		}
+	}
+	for _, u := range b.File.Unions {
+		if m, ok := messages[u.Name]; ok {
+			cg.generatePolymorphicConverters(u.Name, u.Types, m)
+		}
+	}
+	for _, i := range b.File.Interfaces {
+		if m, ok := messages[i.Name]; ok {
+			cg.generatePolymorphicConverters(i.Name, i.Implementations, m)
+		}
+	}
+	var rpcs []*protoRPC
+	for _, rpc := range pp.rpcs {
+		if rpc.entity.File == b.File {
+			rpcs = append(rpcs, rpc)
+		}
+	}
+	if len(pp.rpcs) > 0 && !pp.serverDone {
+		pp.serverDone = true
+		cg.generateServer()
+	}
+	for _, rpc := range rpcs {
+		cg.generateRPC(rpc)
+	}
+	return nil
+}
+
+// preparePackage creates descriptors of enums, messages and service of the package
+func (cg *ProtobufGenerator) preparePackage(desc *Package) (pp *protoPackage, err error) {
+	pp = &protoPackage{name: desc.Name, imports: map[string]bool{}}
+	if err = cg.readLock(pp); err != nil {
+		return
+	}
+	for _, file := range desc.Files {
+		for _, e := range file.Enums {
+			pp.enums = append(pp.enums, cg.enum(e))
+		}
+	}
+	for _, file := range desc.Files {
+		for _, t := range file.Entries {
+			if t.HasModifier(TypeModifierExternal) || t.Annotations.GetBoolAnnotationDef(ProtobufAnnotation, ProtobufAnnotationSkipTag, false) {
+				continue
+			}
+			pp.messages = append(pp.messages, cg.entityMessage(pp, t))
+		}
+		for _, u := range file.Unions {
+			pp.messages = append(pp.messages, cg.polymorphicMessage(pp, u.Name, u.Doc, u.Annotations, u.Types))
+		}
+		for _, i := range file.Interfaces {
+			pp.messages = append(pp.messages, cg.polymorphicMessage(pp, i.Name, i.Doc, i.Annotations, i.Implementations))
+		}
+	}
+	skipped := map[string]bool{}
+	for _, file := range desc.Files {
+		for _, t := range file.Entries {
+			if t.Annotations.GetBoolAnnotationDef(ProtobufAnnotation, ProtobufAnnotationSkipTag, false) {
+				skipped[t.Name] = true
+			}
+		}
+	}
+	for _, file := range desc.Files {
+		for _, t := range file.Entries {
+			if !skipped[t.Name] && !t.HasModifier(TypeModifierExternal) {
+				cg.addRPCs(pp, t)
+			}
+		}
+	}
+	for _, m := range pp.messages {
+		cg.addReserved(pp, m)
+	}
+	return
+}
+
+func (cg *ProtobufGenerator) enum(e *Enum) *protoEnum {
+	pe := &protoEnum{name: e.Name, doc: e.Doc}
+	_, pe.deprecated = DeprecationReason(e.Annotations)
+	if e.AliasForType != TipInt {
+		// enums of other types are kept as values of underlying type
+		return pe
+	}
+	prefix := protoSnakeCase(e.Name)
+	hasZero := false
+	for i, f := range e.Fields {
+		v := protoEnumValue{name: strings.ToUpper(prefix + "_" + protoSnakeCase(f.Name)), number: i}
+		if f.IntVal != nil {
+			v.number = *f.IntVal
+		}
+		_, v.deprecated = DeprecationReason(f.Annotations)
+		hasZero = hasZero || v.number == 0
+		pe.values = append(pe.values, v)
+	}
+	if !hasZero {
+		// the first value of proto3 enum should be zero
+		pe.values = append([]protoEnumValue{{name: strings.ToUpper(prefix + "_unspecified")}}, pe.values...)
+	}
+	return pe
+}
+
+func (cg *ProtobufGenerator) entityMessage(pp *protoPackage, t *Entity) *protoMessage {
+	m := &protoMessage{name: t.Name, doc: t.Doc}
+	_, m.deprecated = DeprecationReason(t.Annotations)
+	owners := []*Entity{t}
+	for bt := t; bt.BaseTypeName != ""; {
+		bt = bt.GetBaseType()
+		owners = append([]*Entity{bt}, owners...)
+	}
+	for _, owner := range owners {
+		for _, f := range owner.Fields {
+			if f.HasModifier(AttrModifierAuxiliary) ||
+				f.FB(FeaturesCommonKind, FCIgnore) ||
+				f.FB(FeaturesAPIKind, FCIgnore) ||
+				f.FB(FeatGoKind, FCGCalculated) ||
+				f.Annotations.GetBoolAnnotationDef(ProtobufAnnotation, ProtobufAnnotationSkipTag, false) {
+				continue
+			}
+			pf, err := cg.field(f)
+			if err != nil {
+				cg.desc.AddWarning(fmt.Sprintf("at %v: protobuf: field %s.%s is skipped: %v", f.Pos, t.Name, f.Name, err))
+				continue
+			}
+			pf.owner = owner
+			pf.number = pp.fieldNumber(m.name, pf.name)
+			m.fields = append(m.fields, pf)
+			cg.addImport(pp, pf.typ)
+		}
+	}
+	return m
+}
+
+func (cg *ProtobufGenerator) field(f *Field) (pf *protoField, err error) {
+	pf = &protoField{
+		name:    protoSnakeCase(f.FS(FeatGoKind, FCGName)),
+		doc:     f.Doc,
+		goName:  f.FS(FeatGoKind, FCGName),
+		field:   f,
+		pointer: f.FB(FeatGoKind, FCGPointer),
+	}
+	_, pf.deprecated = DeprecationReason(f.Annotations)
+	ref := f.Type
+	switch {
+	case ref.Array != nil:
+		if ref.Array.Array != nil || ref.Array.Map != nil {
+			return nil, errors.New("arrays of arrays and maps are not supported")
+		}
+		pf.array = true
+		pf.label = "repeated"
+		pf.value, err = cg.value(ref.Array, ref.Embedded)
+		if err != nil {
+			return
+		}
+		pf.typ = pf.value.typ
+	case ref.Map != nil:
+		if ref.Map.ValueType.Array != nil || ref.Map.ValueType.Map != nil {
+			return nil, errors.New("maps of arrays and maps are not supported")
+		}
+		var keyType string
+		switch ref.Map.KeyType {
+		case TipString:
+			keyType = "string"
+		case TipInt:
+			keyType = "int64"
+		default:
+			return nil, fmt.Errorf("maps with key of type %s are not supported", ref.Map.KeyType)
+		}
+		pf.mapKey = ref.Map.KeyType
+		pf.value, err = cg.value(ref.Map.ValueType, ref.Map.ValueType.Embedded)
+		if err != nil {
+			return
+		}
+		pf.typ = fmt.Sprintf("map<%s, %s>", keyType, pf.value.typ)
+	default:
+		pf.value, err = cg.value(ref, ref.Embedded)
+		if err != nil {
+			return
+		}
+		pf.typ = pf.value.typ
+		if pf.pointer && !pf.value.nilable {
+			pf.label = "optional"
+		}
+	}
+	return
+}
+
+// value returns description of the value of type ref in the same form as Builder.GoType does
+func (cg *ProtobufGenerator) value(ref *TypeRef, embedded bool) (*protoValue, error) {
+	same := func(v jen.Code) jen.Code { return v }
+	cast := func(c jen.Code) func(v jen.Code) jen.Code {
+		return func(v jen.Code) jen.Code { return jen.Add(c).Parens(v) }
+	}
+	switch ref.Type {
+	case TipString:
+		return &protoValue{typ: "string", pbType: jen.String(), toProto: same, fromProto: same}, nil
+	case TipBool:
+		return &protoValue{typ: "bool", pbType: jen.Bool(), toProto: same, fromProto: same}, nil
+	case TipFloat:
+		return &protoValue{typ: "double", pbType: jen.Float64(), toProto: same, fromProto: same}, nil
+	case TipInt:
+		return &protoValue{typ: "int64", pbType: jen.Int64(), toProto: cast(jen.Int64()), fromProto: cast(jen.Int())}, nil
+	case TipBytes:
+		return &protoValue{typ: "bytes", pbType: jen.Index().Byte(), nilable: true, toProto: same, fromProto: same}, nil
+	case TipDate, TipDateTime:
+		return &protoValue{
+			typ:     protoTimestamp,
+			pbType:  jen.Op("*").Qual(timestamppbPackage, "Timestamp"),
+			nilable: true,
+			toProto: func(v jen.Code) jen.Code {
+				return jen.Qual(timestamppbPackage, "New").Call(v)
+			},
+			fromProto: func(v jen.Code) jen.Code {
+				return jen.Add(v).Dot("AsTime").Call()
+			},
+			isSet: protoNotNil,
+		}, nil
+	case TipDuration:
+		return &protoValue{
+			typ:     protoDuration,
+			pbType:  jen.Op("*").Qual(durationpbPackage, "Duration"),
+			nilable: true,
+			toProto: func(v jen.Code) jen.Code {
+				return jen.Qual(durationpbPackage, "New").Call(v)
+			},
+			fromProto: func(v jen.Code) jen.Code {
+				return jen.Add(v).Dot("AsDuration").Call()
+			},
+			isSet: protoNotNil,
+		}, nil
+	case TipDecimal, TipUUID:
+		parse := "ParseDecimal"
+		if ref.Type == TipUUID {
+			parse = "ParseUUID"
+		}
+		return &protoValue{
+			typ:    "string",
+			pbType: jen.String(),
+			method: true,
+			toProto: func(v jen.Code) jen.Code {
+				return jen.Add(v).Dot("String").Call()
+			},
+			fromProto: func(v jen.Code) jen.Code {
+				return jen.Qual(VivardPackage, parse).Call(v)
+			},
+			withErr: true,
+			isSet: func(v jen.Code) jen.Code {
+				return jen.Add(v).Op("!=").Lit("")
+			},
+		}, nil
+	case TipTime:
+		return &protoValue{
+			typ:       "int32",
+			pbType:    jen.Int32(),
+			toProto:   cast(jen.Int32()),
+			fromProto: cast(jen.Qual(VivardPackage, "TimeOfDay")),
+		}, nil
+	case TipAny, TipAuto:
+		return nil, fmt.Errorf("type %s is not supported", ref.Type)
+	}
+	dt, ok := cg.desc.FindType(ref.Type)
+	if !ok {
+		return nil, fmt.Errorf("undefined type: %s", ref.Type)
+	}
+	switch {
+	case dt.enum != nil:
+		if dt.enum.AliasForType != TipInt {
+			ret, err := cg.value(&TypeRef{Type: dt.enum.AliasForType}, false)
+			if err != nil {
+				return nil, err
+			}
+			toProto := ret.toProto
+			ret.toProto = func(v jen.Code) jen.Code {
+				return toProto(jen.Id(ret.typ).Parens(v))
+			}
+			ret.fromProto = cast(cg.goTypeName(dt, dt.name))
+			if ret.typ == "double" {
+				ret.toProto = cast(jen.Float64())
+			}
+			return ret, nil
+		}
+		return &protoValue{
+			typ:       cg.protoTypeName(dt),
+			pbType:    cg.pbTypeName(dt),
+			toProto:   cast(cg.pbTypeName(dt)),
+			fromProto: cast(cg.goTypeName(dt, dt.name)),
+		}, nil
+	case dt.IsPolymorphic():
+		return cg.messageValue(dt), nil
+	case dt.entry != nil:
+		e := dt.entry
+		if e.HasModifier(TypeModifierExternal) {
+			return nil, fmt.Errorf("external type %s is not supported", ref.Type)
+		}
+		if e.Annotations.GetBoolAnnotationDef(ProtobufAnnotation, ProtobufAnnotationSkipTag, false) {
+			return nil, fmt.Errorf("type %s is skipped", ref.Type)
+		}
+		if !embedded &&
+			!e.HasModifier(TypeModifierEmbeddable) &&
+			!e.HasModifier(TypeModifierTransient) &&
+			!e.HasModifier(TypeModifierConfig) {
+			// reference to stored type keeps id only (see Builder.GoType)
+			idField := e.GetIdField()
+			if idField == nil {
+				return nil, fmt.Errorf("there is no id field for type: %s", e.Name)
+			}
+			idType := idField.Type.Type
+			if !IsPrimitiveType(idType) && !strings.Contains(idType, ".") && dt.pckg != cg.desc.Name {
+				idType = dt.pckg + "." + idType
+			}
+			return cg.value(&TypeRef{Type: idType}, false)
+		}
+		return cg.messageValue(dt), nil
+	}
+	return nil, fmt.Errorf("type %s is not supported", ref.Type)
+}
+
+// messageValue returns value for type with generated converters (entity, union or interface)
+func (cg *ProtobufGenerator) messageValue(dt *DefinedType) *protoValue {
+	return &protoValue{
+		typ:       cg.protoTypeName(dt),
+		pbType:    jen.Op("*").Add(cg.pbTypeName(dt)),
+		nilable:   true,
+		goPointer: true,
+		toProto: func(v jen.Code) jen.Code {
+			return cg.goTypeName(dt, dt.name+"ToProto").Call(v)
+		},
+		fromProto: func(v jen.Code) jen.Code {
+			return cg.goTypeName(dt, dt.name+"FromProto").Call(v)
+		},
+		withErr: true,
+		isSet:   protoNotNil,
+	}
+}
+
+func (cg *ProtobufGenerator) polymorphicMessage(pp *protoPackage, name string, doc string, anns Annotations, types []*Entity) *protoMessage {
+	m := &protoMessage{name: name, doc: doc}
+	_, m.deprecated = DeprecationReason(anns)
+	for _, t := range types {
+		dt, ok := cg.desc.FindType(cg.entityTypeName(t))
+		if !ok {
+			continue
+		}
+		pf := &protoField{name: protoSnakeCase(t.Name), goName: t.Name, value: cg.messageValue(dt)}
+		pf.typ = pf.value.typ
+		pf.number = pp.fieldNumber(m.name, pf.name)
+		m.oneof = append(m.oneof, pf)
+		cg.addImport(pp, pf.typ)
+	}
+	return m
+}
+
+// addRPCs adds methods of gRPC service for operations generated by GQLGenerator for the type
+func (cg *ProtobufGenerator) addRPCs(pp *protoPackage, t *Entity) {
+	if level, ok := t.Features.GetString(FeaturesAPIKind, FAPILevel); ok && level != FAPILAll {
+		return
+	}
+	if _, ok := t.Features.GetString(GQLFeatures, GQLOperationsAnnotationsTags[GQLOperationGet]); !ok {
+		return
+	}
+	add := func(kind GQLOperationKind, name string, request string, response string) {
+		if _, ok := t.Features.GetString(GQLFeatures, GQLOperationsAnnotationsTags[kind]); ok {
+			pp.rpcs = append(pp.rpcs, &protoRPC{kind: kind, entity: t, name: name, request: request, response: response})
+		}
+	}
+	message := func(name string, fields ...*protoField) string {
+		m := &protoMessage{name: name}
+		for _, f := range fields {
+			f.number = pp.fieldNumber(name, f.name)
+			m.fields = append(m.fields, f)
+		}
+		pp.messages = append(pp.messages, m)
+		return name
+	}
+	readonly := t.FB(FeaturesCommonKind, FCReadonly)
+	if t.HasModifier(TypeModifierConfig) {
+		pp.imports["google/protobuf/empty.proto"] = true
+		add(GQLOperationGet, "Get"+t.Name, protoEmpty, t.Name)
+		if !readonly {
+			add(GQLOperationSet, "Set"+t.Name, t.Name, t.Name)
+		}
+		return
+	}
+	idField := t.GetIdField()
+	if idField == nil {
+		return
+	}
+	if idField.Type.Type != TipInt && idField.Type.Type != TipString {
+		cg.desc.AddWarning(
+			fmt.Sprintf("at %v: protobuf: id of type %s is not supported; operations of type %s are skipped", t.Pos, idField.Type.Type, t.Name),
+		)
+		return
+	}
+	idValue, _ := cg.value(idField.Type, false)
+	idParam := func() *protoField {
+		return &protoField{name: "id", typ: idValue.typ, goName: "Id", value: idValue}
+	}
+	list := t.Name + "List"
+	message(list, &protoField{name: "items", label: "repeated", typ: t.Name, goName: "Items", array: true})
+	add(GQLOperationGet, "Get"+t.Name, message("Get"+t.Name+"Request", idParam()), t.Name)
+	add(
+		GQLOperationLookup,
+		"Lookup"+t.Name,
+		message("Lookup"+t.Name+"Request", &protoField{name: "query", typ: "string", goName: "Query"}),
+		list,
+	)
+	if it, ok := t.Features.GetEntity(FeaturesAPIKind, FAPIFindParamType); ok {
+		if dt, ok := cg.desc.FindType(cg.entityTypeName(it)); ok {
+			add(GQLOperationFind, "Find"+t.Name, cg.protoTypeName(dt), list)
+			cg.addImport(pp, cg.protoTypeName(dt))
+		}
+	}
+	if t.IsDictionary() {
+		var fields []*protoField
+		if t.HasModifier(TypeModifierDictionary) && t.FB(FeatureDictKind, FDQualified) {
+			qt, _ := t.Features.GetEntity(FeatureDictKind, FDQualifierType)
+			if qid := qt.GetIdField(); qid != nil {
+				if qv, err := cg.value(qid.Type, false); err == nil {
+					fields = append(fields, &protoField{name: "qual", label: "repeated", typ: qv.typ, goName: "Qual", value: qv, array: true})
+				}
+			}
+		}
+		add(GQLOperationList, "List"+t.Name, message("List"+t.Name+"Request", fields...), list)
+	}
+	if !readonly {
+		add(GQLOperationSet, "Set"+t.Name, t.Name, t.Name)
+		if !t.HasModifier(TypeModifierAbstract) {
+			add(GQLOperationCreate, "Create"+t.Name, t.Name, t.Name)
+		}
+		pp.imports["google/protobuf/empty.proto"] = true
+		add(GQLOperationDelete, "Delete"+t.Name, message("Delete"+t.Name+"Request", idParam()), protoEmpty)
+	}
+}
+
+// protoTypeName returns name of the message or enum for defined type (qualified for types from other packages)
+func (cg *ProtobufGenerator) protoTypeName(dt *DefinedType) string {
+	if dt.pckg != cg.desc.Name {
+		return cg.protoPackage(dt.pckg) + "." + dt.name
+	}
+	return dt.name
+}
+
+func (cg *ProtobufGenerator) entityTypeName(e *Entity) string {
+	if e.Pckg != nil && e.Pckg != cg.desc {
+		return e.Pckg.Name + "." + e.Name
+	}
+	return e.Name
+}
+
+func (cg *ProtobufGenerator) serviceName(pckg string) string {
+	return strings.ToUpper(pckg[:1]) + pckg[1:] + "Service"
+}
+
+func (cg *ProtobufGenerator) protoPackage(pckg string) string {
+	if cg.options.ProtoPackage != "" {
+		return cg.options.ProtoPackage + "." + pckg
+	}
+	return pckg
+}
+
+func (cg *ProtobufGenerator) goPackageName(pckg string) string {
+	return pckg + protobufPackageSuffix
+}
+
+func (cg *ProtobufGenerator) goPackage(pckg string) string {
+	if cg.options.GoPackage != "" {
+		if strings.Contains(cg.options.GoPackage, "%s") {
+			return fmt.Sprintf(cg.options.GoPackage, pckg)
+		}
+		return cg.options.GoPackage
+	}
+	return path.Join(cg.proj.GetPackage(pckg).fullPackage, cg.goPackageName(pckg))
+}
+
+func (cg *ProtobufGenerator) protoDir(pckg string) string {
+	if cg.options.ProtoDir != "" {
+		if strings.Contains(cg.options.ProtoDir, "%s") {
+			return fmt.Sprintf(cg.options.ProtoDir, pckg)
+		}
+		return cg.options.ProtoDir
+	}
+	return filepath.Join(cg.proj.Options.OutputDir, pckg, cg.goPackageName(pckg))
+}
+
+// protoSnakeCase converts Go name to name of proto field (e.g. OrderID to order_id)
+func protoSnakeCase(name string) string {
+	ret := &strings.Builder{}
+	isUpper := func(c byte) bool { return c >= 'A' && c <= 'Z' }
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if isUpper(c) {
+			if i > 0 && (!isUpper(name[i-1]) || i+1 < len(name) && !isUpper(name[i+1]) && name[i+1] != '_') && name[i-1] != '_' {
+				ret.WriteByte('_')
+			}
+			c += 'a' - 'A'
+		}
+		ret.WriteByte(c)
+	}
+	return ret.String()
+}
+
+// protoGoName returns name of Go field generated by protoc-gen-go for proto field (the same as protogen.GoCamelCase)
+func protoGoName(name string) string {
+	isLower := func(c byte) bool { return c >= 'a' && c <= 'z' }
+	var b []byte
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_' && i == 0:
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(name) && isLower(name[i+1]):
+		case c >= '0' && c <= '9':
+			b = append(b, c)
+		default:
+			if isLower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(name) && isLower(name[i+1]); i++ {
+				b = append(b, name[i+1])
+			}
+		}
+	}
+	return string(b)
+}
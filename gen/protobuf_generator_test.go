@@ -0,0 +1,106 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var protobufTestPlugins = []string{GQLGeneratorName, nocacheGeneratorName, mongoGeneratorName, protobufGeneratorName}
+
+const protobufTestSource = `package shop;
+
+enum Status {
+  Active = "A";
+  Closed = "C";
+}
+
+embeddable type Address {
+  city: string;
+  zip: string!;
+}
+
+type Category {
+  categoryID: int <id>;
+  title: string!;
+}
+
+// Order is a sample order
+type Order {
+  orderID: int <id>;
+  name: string!;
+  status: Status;
+  amount: decimal;
+  placed: date;
+  tags: [string];
+  category: Category;
+  addr: Address <embedded>;
+}
+`
+
+func TestProtobufProto(t *testing.T) {
+	files := generateTest(t, protobufTestSource, protobufTestPlugins...)
+	assertGolden(t, "protobuf/shop.proto", files["shop/shoppb/shop.proto"])
+}
+
+// TestProtobufFieldNumbers checks that field numbers kept in lock file do not change when fields are added
+// and removed and that numbers of removed fields are reserved
+func TestProtobufFieldNumbers(t *testing.T) {
+	dir := t.TempDir()
+	generate := func(src string) string {
+		t.Helper()
+		files, err := testProjectAt(t, dir, "", src, protobufTestPlugins...).GenerateToMemory()
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		// lock file is kept with the sources
+		lock := filepath.Join(dir, "shop", "shoppb", "shop.proto.lock")
+		if err = os.MkdirAll(filepath.Dir(lock), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(lock, files[lock], 0644); err != nil {
+			t.Fatal(err)
+		}
+		return string(files[filepath.Join(dir, "shop", "shoppb", "shop.proto")])
+	}
+	generate(protobufTestSource)
+
+	src := strings.Replace(protobufTestSource, "  status: Status;\n", "", 1)
+	src = strings.Replace(src, "  name: string!;\n", "  name: string!;\n  note: string;\n", 1)
+	proto := generate(src)
+	assertContains(
+		t, proto,
+		"reserved 3;",
+		`reserved "status";`,
+		"int64 order_id = 1;",
+		"string name = 2;",
+		"optional string note = 9;",
+		"optional string amount = 4;",
+		"Address addr = 8;",
+	)
+
+	// the field that is back gets its old number
+	proto = generate(protobufTestSource)
+	assertContains(t, proto, "optional string status = 3;", "reserved 9;", `reserved "note";`)
+}
+
+// TestProtobufSharedLockFile checks that a lock file without package name is not shared by several packages
+func TestProtobufSharedLockFile(t *testing.T) {
+	sources := map[string]string{
+		"shop.vvf":  "package shop;\ntype Order {\n  orderID: int <id>;\n}\n",
+		"stock.vvf": "package stock;\ntype Item {\n  itemID: int <id>;\n}\n",
+	}
+	generate := func(lockFile string) error {
+		dir := t.TempDir()
+		opts := testOptions(dir).WithCustom(protobufOptionsName, map[string]any{"LockFile": filepath.Join(dir, lockFile)})
+		_, err := newTestProject(t, opts, sources, protobufTestPlugins...).GenerateToMemory()
+		return err
+	}
+	if err := generate("api.lock"); err == nil || !strings.Contains(err.Error(), "should contain %s") {
+		t.Errorf("error = %v, want error about %%s", err)
+	}
+	if err := generate("%s.lock"); err != nil {
+		t.Errorf("generate: %v", err)
+	}
+}
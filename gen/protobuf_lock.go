@@ -0,0 +1,105 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// protoLock is the content of lock file
+type protoLock struct {
+	// Messages - numbers of fields by field name by message name
+	Messages map[string]map[string]int `json:"messages"`
+}
+
+// fieldNumber returns number of the field kept in lock file or the next free number for the new field
+func (pp *protoPackage) fieldNumber(message string, field string) int {
+	fields, ok := pp.lock.Messages[message]
+	if !ok {
+		fields = map[string]int{}
+		pp.lock.Messages[message] = fields
+	}
+	if n, ok := fields[field]; ok {
+		return n
+	}
+	n := 1
+	for _, num := range fields {
+		if num >= n {
+			n = num + 1
+		}
+	}
+	if n >= protobufFirstReservedNumber && n <= protobufLastReservedNumber {
+		n = protobufLastReservedNumber + 1
+	}
+	fields[field] = n
+	return n
+}
+
+// addReserved reserves numbers and names of the fields that were removed from the message
+func (cg *ProtobufGenerator) addReserved(pp *protoPackage, m *protoMessage) {
+	used := map[string]bool{}
+	for _, f := range append(m.fields, m.oneof...) {
+		used[f.name] = true
+	}
+	var names []string
+	for name := range pp.lock.Messages[m.name] {
+		if !used[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(
+		names, func(i, j int) bool {
+			return pp.lock.Messages[m.name][names[i]] < pp.lock.Messages[m.name][names[j]]
+		},
+	)
+	m.reserved = names
+}
+
+// readLock reads field numbers from lock file (if any)
+func (cg *ProtobufGenerator) readLock(pp *protoPackage) error {
+	pp.lock.Messages = map[string]map[string]int{}
+	if cg.options.LockFile != "" && !strings.Contains(cg.options.LockFile, "%s") && len(cg.proj.packages) > 1 {
+		// lock is read and written per package, so packages would overwrite numbers of each other
+		return fmt.Errorf(
+			"protobuf: LockFile '%s' should contain %%s for package name as there are several packages",
+			cg.options.LockFile,
+		)
+	}
+	data, err := os.ReadFile(cg.lockFile(pp.name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err = json.Unmarshal(data, &pp.lock); err != nil {
+		return fmt.Errorf("protobuf: invalid lock file %s: %w", cg.lockFile(pp.name), err)
+	}
+	if pp.lock.Messages == nil {
+		pp.lock.Messages = map[string]map[string]int{}
+	}
+	return nil
+}
+
+// writeLock writes lock file with numbers of all the fields ever generated for the package
+func (cg *ProtobufGenerator) writeLock(pp *protoPackage) error {
+	data, err := json.MarshalIndent(pp.lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	return cg.proj.WriteFile(cg.lockFile(pp.name), append(data, '\n'))
+}
+
+// lockFile returns path of lock file of the package (a single file given without %s is allowed for one package only)
+func (cg *ProtobufGenerator) lockFile(pckg string) string {
+	if cg.options.LockFile != "" {
+		if strings.Contains(cg.options.LockFile, "%s") {
+			return fmt.Sprintf(cg.options.LockFile, pckg)
+		}
+		return cg.options.LockFile
+	}
+	return filepath.Join(cg.protoDir(pckg), pckg+protobufLockSuffix)
+}
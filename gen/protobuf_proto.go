@@ -0,0 +1,125 @@
+package gen
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// writeProto writes .proto file of the package and its lock file with field numbers
+func (cg *ProtobufGenerator) writeProto(pp *protoPackage) error {
+	dir := cg.protoDir(pp.name)
+	if err := cg.proj.WriteFile(filepath.Join(dir, pp.name+".proto"), cg.protoFile(pp)); err != nil {
+		return err
+	}
+	return cg.writeLock(pp)
+}
+
+// protoFile returns content of .proto file of the package
+func (cg *ProtobufGenerator) protoFile(pp *protoPackage) []byte {
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "// Code generated from package %s by vivgen. DO NOT EDIT.\n", pp.name)
+	fmt.Fprintf(buf, "// Field numbers are kept in %s; do not remove it.\n\n", filepath.Base(cg.lockFile(pp.name)))
+	buf.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(buf, "package %s;\n\n", cg.protoPackage(pp.name))
+	var imports []string
+	for imp := range pp.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	for _, imp := range imports {
+		fmt.Fprintf(buf, "import %q;\n", imp)
+	}
+	if len(imports) > 0 {
+		buf.WriteString("\n")
+	}
+	fmt.Fprintf(buf, "option go_package = %q;\n", cg.goPackage(pp.name)+";"+cg.goPackageName(pp.name))
+	for _, e := range pp.enums {
+		if len(e.values) == 0 {
+			continue
+		}
+		buf.WriteString("\n")
+		writeProtoDoc(buf, "", e.doc)
+		fmt.Fprintf(buf, "enum %s {\n", e.name)
+		if e.deprecated {
+			buf.WriteString("  option deprecated = true;\n")
+		}
+		for _, v := range e.values {
+			fmt.Fprintf(buf, "  %s = %d%s;\n", v.name, v.number, protoDeprecatedOption(v.deprecated))
+		}
+		buf.WriteString("}\n")
+	}
+	for _, m := range pp.messages {
+		buf.WriteString("\n")
+		writeProtoDoc(buf, "", m.doc)
+		fmt.Fprintf(buf, "message %s {\n", m.name)
+		if m.deprecated {
+			buf.WriteString("  option deprecated = true;\n")
+		}
+		if len(m.reserved) > 0 {
+			var nums, names []string
+			for _, name := range m.reserved {
+				nums = append(nums, fmt.Sprint(pp.lock.Messages[m.name][name]))
+				names = append(names, fmt.Sprintf("%q", name))
+			}
+			fmt.Fprintf(buf, "  reserved %s;\n", strings.Join(nums, ", "))
+			fmt.Fprintf(buf, "  reserved %s;\n", strings.Join(names, ", "))
+		}
+		for _, f := range m.fields {
+			writeProtoDoc(buf, "  ", f.doc)
+			label := ""
+			if f.label != "" {
+				label = f.label + " "
+			}
+			fmt.Fprintf(buf, "  %s%s %s = %d%s;\n", label, f.typ, f.name, f.number, protoDeprecatedOption(f.deprecated))
+		}
+		if len(m.oneof) > 0 {
+			buf.WriteString("  oneof value {\n")
+			for _, f := range m.oneof {
+				fmt.Fprintf(buf, "    %s %s = %d;\n", f.typ, f.name, f.number)
+			}
+			buf.WriteString("  }\n")
+		}
+		buf.WriteString("}\n")
+	}
+	if len(pp.rpcs) > 0 {
+		buf.WriteString("\n")
+		fmt.Fprintf(buf, "service %s {\n", cg.serviceName(pp.name))
+		for _, rpc := range pp.rpcs {
+			fmt.Fprintf(buf, "  rpc %s(%s) returns (%s);\n", rpc.name, rpc.request, rpc.response)
+		}
+		buf.WriteString("}\n")
+	}
+	return []byte(buf.String())
+}
+
+// addImport adds import of .proto that declares typ (if it is not from the package)
+func (cg *ProtobufGenerator) addImport(pp *protoPackage, typ string) {
+	switch typ {
+	case protoTimestamp:
+		pp.imports["google/protobuf/timestamp.proto"] = true
+	case protoDuration:
+		pp.imports["google/protobuf/duration.proto"] = true
+	default:
+		if strings.HasPrefix(typ, cg.options.ProtoPackage) {
+			typ = strings.TrimPrefix(strings.TrimPrefix(typ, cg.options.ProtoPackage), ".")
+		}
+		if parts := strings.SplitN(typ, ".", 2); len(parts) == 2 && parts[0] != pp.name {
+			pp.imports[parts[0]+".proto"] = true
+		}
+	}
+}
+
+func writeProtoDoc(buf *strings.Builder, indent string, doc string) {
+	for _, l := range DocLines(doc) {
+		buf.WriteString(indent + "// " + l + "\n")
+	}
+}
+
+func protoDeprecatedOption(deprecated bool) string {
+	if deprecated {
+		return " [deprecated = true]"
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+package gen
+
+import (
+	"github.com/dave/jennifer/jen"
+)
+
+// generateServer generates GRPCServer type and error conversion function (once for package)
+func (cg *ProtobufGenerator) generateServer() {
+	pb := cg.goPackage(cg.desc.Name)
+	service := cg.serviceName(cg.desc.Name)
+	cg.b.Types.Add(
+		jen.Commentf("%s implements %s of package %s with Engine's methods;", grpcServerName, service, cg.goPackageName(cg.desc.Name)).Line().
+			Commentf("register it with %s.Register%sServer(server, eng.%s())", cg.goPackageName(cg.desc.Name), service, grpcServerName).Line().
+			Type().Id(grpcServerName).Struct(
+			jen.Qual(pb, "Unimplemented"+service+"Server"),
+			jen.Id(EngineVar).Op("*").Id("Engine"),
+		).Line(),
+	)
+	cg.b.Functions.Add(
+		jen.Func().Parens(jen.Id(EngineVar).Op("*").Id("Engine")).Id(grpcServerName).Params().Op("*").Id(grpcServerName).Block(
+			jen.Return(jen.Op("&").Id(grpcServerName).Values(jen.Id(EngineVar).Op(":").Id(EngineVar))),
+		).Line(),
+		jen.Comment("grpcError converts error returned by Engine to gRPC status error").Line().
+			Func().Id(grpcErrorFunc).Params(jen.Err().Error()).Error().Block(
+			jen.Switch().Block(
+				jen.Case(jen.Qual("errors", "Is").Call(jen.Err(), jen.Qual(VivardPackage, "ErrItemNotFound"))).Block(
+					jen.Return(jen.Qual(grpcStatusPackage, "Error").Call(jen.Qual(grpcCodesPackage, "NotFound"), jen.Err().Dot("Error").Call())),
+				),
+				jen.Case(jen.Qual("errors", "Is").Call(jen.Err(), jen.Qual(resourcePackage, "ErrForbidden"))).Block(
+					jen.Return(jen.Qual(grpcStatusPackage, "Error").Call(jen.Qual(grpcCodesPackage, "PermissionDenied"), jen.Err().Dot("Error").Call())),
+				),
+				jen.Case(jen.Qual("errors", "Is").Call(jen.Err(), jen.Qual(resourcePackage, "ErrDuplicate"))).Block(
+					jen.Return(jen.Qual(grpcStatusPackage, "Error").Call(jen.Qual(grpcCodesPackage, "AlreadyExists"), jen.Err().Dot("Error").Call())),
+				),
+			),
+			jen.Return(jen.Err()),
+		).Line(),
+	)
+}
+
+func (cg *ProtobufGenerator) generateRPC(rpc *protoRPC) {
+	t := rpc.entity
+	name := t.Name
+	pb := cg.goPackage(cg.desc.Name)
+	ctx := jen.Id("ctx")
+	returnErr := func() jen.Code {
+		return jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Nil(), jen.Id(grpcErrorFunc).Call(jen.Err())))
+	}
+	fromProto := func(val string, conv jen.Code) []jen.Code {
+		return []jen.Code{
+			jen.List(jen.Id(val), jen.Err()).Op(":=").Add(conv).Call(jen.Id("req")),
+			jen.If(jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Nil(), jen.Qual(grpcStatusPackage, "Error").Call(jen.Qual(grpcCodesPackage, "InvalidArgument"), jen.Err().Dot("Error").Call())),
+			),
+		}
+	}
+	var id jen.Code
+	if idField := t.GetIdField(); idField != nil {
+		if v, err := cg.value(idField.Type, false); err == nil {
+			id = v.fromProto(jen.Id("req").Dot("Id"))
+		}
+	}
+	returnList := func() []jen.Code {
+		return []jen.Code{
+			returnErr(),
+			jen.Id("ret").Op(":=").Op("&").Qual(pb, name+"List").Values(
+				jen.Id("Items").Op(":").Make(jen.Index().Op("*").Qual(pb, name), jen.Lit(0), jen.Len(jen.Id("list"))),
+			),
+			jen.For(jen.List(jen.Id("_"), jen.Id("obj")).Op(":=").Range().Id("list")).Block(
+				jen.Id("ret").Dot("Items").Op("=").Append(jen.Id("ret").Dot("Items"), jen.Id(name+"ToProto").Call(jen.Id("obj"))),
+			),
+			jen.Return(jen.Id("ret"), jen.Nil()),
+		}
+	}
+	var body []jen.Code
+	switch rpc.kind {
+	case GQLOperationGet:
+		args := []jen.Code{ctx}
+		if !t.HasModifier(TypeModifierConfig) {
+			args = append(args, id)
+		}
+		body = []jen.Code{
+			jen.List(jen.Id("obj"), jen.Err()).Op(":=").Id("s").Dot(EngineVar).Dot(cg.desc.GetMethodName(MethodGet, name)).Call(args...),
+			returnErr(),
+			jen.If(jen.Id("obj").Op("==").Nil()).Block(jen.Return(jen.Nil(), jen.Id(grpcErrorFunc).Call(jen.Qual(VivardPackage, "ErrItemNotFound")))),
+			jen.Return(jen.Id(name+"ToProto").Call(jen.Id("obj")), jen.Nil()),
+		}
+	case GQLOperationSet, GQLOperationCreate:
+		method := MethodSet
+		if rpc.kind == GQLOperationCreate {
+			method = MethodNew
+		}
+		body = append(
+			fromProto("obj", jen.Id(name+"FromProto")),
+			jen.List(jen.Id("obj"), jen.Err()).Op("=").Id("s").Dot(EngineVar).Dot(cg.desc.GetMethodName(method, name)).Call(ctx, jen.Id("obj")),
+			returnErr(),
+			jen.Return(jen.Id(name+"ToProto").Call(jen.Id("obj")), jen.Nil()),
+		)
+	case GQLOperationDelete:
+		body = []jen.Code{
+			jen.If(
+				jen.Err().Op(":=").Id("s").Dot(EngineVar).Dot(cg.desc.GetMethodName(MethodDelete, name)).Call(ctx, id),
+				jen.Err().Op("!=").Nil(),
+			).Block(jen.Return(jen.Nil(), jen.Id(grpcErrorFunc).Call(jen.Err()))),
+			jen.Return(jen.Op("&").Qual(emptypbPackage, "Empty").Values(), jen.Nil()),
+		}
+	case GQLOperationLookup:
+		body = append(
+			[]jen.Code{
+				jen.List(jen.Id("list"), jen.Err()).Op(":=").Id("s").Dot(EngineVar).Dot(cg.desc.GetMethodName(MethodLookup, name)).Call(
+					ctx,
+					jen.Id("req").Dot("Query"),
+				),
+			},
+			returnList()...,
+		)
+	case GQLOperationFind:
+		it, _ := t.Features.GetEntity(FeaturesAPIKind, FAPIFindParamType)
+		dt, _ := cg.desc.FindType(cg.entityTypeName(it))
+		body = append(
+			fromProto("query", cg.goTypeName(dt, dt.name+"FromProto")),
+			jen.List(jen.Id("list"), jen.Err()).Op(":=").Id("s").Dot(EngineVar).Dot(cg.desc.GetMethodName(MethodFind, name)).Call(ctx, jen.Id("query")),
+		)
+		body = append(body, returnList()...)
+	case GQLOperationList:
+		listMethod := MethodList
+		if t.HasModifier(TypeModifierDictionary) {
+			listMethod = MethodGetAll
+		}
+		args := []jen.Code{ctx}
+		if listMethod == MethodGetAll && t.FB(FeatureDictKind, FDQualified) {
+			qt, _ := t.Features.GetEntity(FeatureDictKind, FDQualifierType)
+			if qv, err := cg.value(qt.GetIdField().Type, false); err == nil {
+				body = append(
+					body,
+					jen.Id("quals").Op(":=").Make(jen.Index().Add(cg.b.GoType(qt.GetIdField().Type)), jen.Len(jen.Id("req").Dot("Qual"))),
+					jen.For(jen.List(jen.Id("i"), jen.Id("q")).Op(":=").Range().Id("req").Dot("Qual")).Block(
+						jen.Id("quals").Index(jen.Id("i")).Op("=").Add(qv.fromProto(jen.Id("q"))),
+					),
+				)
+				args = append(args, jen.Id("quals").Op("..."))
+			}
+		}
+		body = append(
+			body,
+			jen.List(jen.Id("list"), jen.Err()).Op(":=").Id("s").Dot(EngineVar).Dot(cg.desc.GetMethodName(listMethod, name)).Call(args...),
+		)
+		body = append(body, returnList()...)
+	}
+	cg.b.Functions.Add(
+		jen.Func().Parens(jen.Id("s").Op("*").Id(grpcServerName)).Id(rpc.name).Params(
+			jen.Id("ctx").Qual("context", "Context"),
+			jen.Id("req").Add(cg.rpcGoType(rpc.request)),
+		).Parens(jen.List(cg.rpcGoType(rpc.response), jen.Error())).Block(body...).Line(),
+	)
+}
+
+// rpcGoType returns Go type generated by protoc for request or response of rpc
+func (cg *ProtobufGenerator) rpcGoType(typ string) jen.Code {
+	if typ == protoEmpty {
+		return jen.Op("*").Qual(emptypbPackage, "Empty")
+	}
+	if dt, ok := cg.desc.FindType(typ); ok {
+		return jen.Op("*").Add(cg.pbTypeName(dt))
+	}
+	return jen.Op("*").Qual(cg.goPackage(cg.desc.Name), typ)
+}
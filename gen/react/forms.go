@@ -0,0 +1,359 @@
+package react
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/vc2402/vivard/gen"
+	"github.com/vc2402/vivard/gen/js"
+)
+
+const (
+	inputText     = "text"
+	inputNumber   = "number"
+	inputCheckbox = "checkbox"
+	inputDateTime = "datetime"
+	inputTime     = "time"
+	inputTextArea = "textarea"
+	inputStrings  = "strings"
+	inputSelect   = "select"
+	inputForm     = "form"
+
+	defaultOrder = 1000
+)
+
+type formField struct {
+	Name  string
+	Label string
+	Hint  string
+	Input string
+	// Component - select or form component for complex fields
+	Component string
+	// Attrs - additional attributes for component
+	Attrs    string
+	Disabled string
+	Style    string
+	Lines    int
+	tab      string
+	row      int
+	order    int
+}
+
+type formTab struct {
+	ID    string
+	Label string
+	Rows  [][]*formField
+}
+
+type formData struct {
+	Name       string
+	TypeName   string
+	Imports    []tsImport
+	Tabs       []*formTab
+	DefaultTab string
+}
+
+type selectData struct {
+	Name     string
+	TypeName string
+	IDType   string
+	IDField  string
+	Title    string
+	Hook     string
+	Imports  []tsImport
+	Options  []selectOption
+}
+
+type selectOption struct {
+	Label string
+	Value string
+}
+
+// annotationSet - annotations to look for layout tags in (ui first, then vue)
+type annotationSet []*gen.Annotation
+
+func layoutAnnotations(anns gen.Annotations) (ret annotationSet) {
+	for _, name := range []string{uiAnnotation, vueAnnotation} {
+		if a, ok := anns[name]; ok {
+			ret = append(ret, a)
+		}
+	}
+	return
+}
+
+func (as annotationSet) getString(name string, def string) string {
+	for _, a := range as {
+		if ret, ok := a.GetStringTag(name); ok {
+			return ret
+		}
+	}
+	return def
+}
+
+func (as annotationSet) getInt(name string, def int) int {
+	for _, a := range as {
+		if ret, ok := a.GetIntTag(name); ok {
+			return ret
+		}
+	}
+	return def
+}
+
+func (as annotationSet) getBool(name string) bool {
+	for _, a := range as {
+		if ret, ok := a.GetBoolTag(name); ok {
+			return ret
+		}
+	}
+	return false
+}
+
+func (as annotationSet) getInterface(name string) (interface{}, bool) {
+	for _, a := range as {
+		if tag := a.GetTag(name); tag != nil {
+			if tag.Value == nil {
+				return true, true
+			}
+			switch {
+			case tag.Value.Bool != nil:
+				return bool(*tag.Value.Bool), true
+			case tag.Value.Number != nil:
+				return *tag.Value.Number, true
+			}
+			return true, true
+		}
+	}
+	return nil, false
+}
+
+func (cg *ClientGenerator) generateForm(outDir string, e *gen.Entity) error {
+	fd := &formData{
+		Name:     e.FS(featureReactKind, fRKFormComponent),
+		TypeName: e.Annotations.GetStringAnnotationDef(js.Annotation, js.AnnotationName, ""),
+	}
+	imports := tsImports{}
+	imports.add(cg.importPath(outDir, e.FS(js.Features, js.FFilePath)), fd.TypeName)
+
+	tabsAnn := e.GetAnnotation(uiTabsAnnotation)
+	if tabsAnn == nil {
+		tabsAnn = e.GetAnnotation(vueTabsAnnotation)
+	}
+	tabs := map[string]*formTab{}
+	if tabsAnn != nil {
+		for _, a := range tabsAnn.Values {
+			tab := &formTab{ID: a.Key, Label: a.Key}
+			if a.Value != nil && a.Value.String != nil {
+				tab.Label = *a.Value.String
+			}
+			tabs[tab.ID] = tab
+			fd.Tabs = append(fd.Tabs, tab)
+		}
+	}
+	if len(fd.Tabs) == 0 {
+		fd.Tabs = []*formTab{{}}
+	}
+	fd.DefaultTab = fd.Tabs[0].ID
+
+	var fields []*formField
+	for _, f := range e.GetFields(true, true) {
+		ff := cg.getFormField(outDir, f, imports)
+		if ff == nil {
+			continue
+		}
+		if _, ok := tabs[ff.tab]; !ok {
+			ff.tab = fd.DefaultTab
+		}
+		fields = append(fields, ff)
+	}
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].order < fields[j].order })
+	for _, tab := range fd.Tabs {
+		rows := map[int][]*formField{}
+		var rowNums []int
+		for _, ff := range fields {
+			if ff.tab != tab.ID {
+				continue
+			}
+			if _, ok := rows[ff.row]; !ok {
+				rowNums = append(rowNums, ff.row)
+			}
+			rows[ff.row] = append(rows[ff.row], ff)
+		}
+		// fields without row are placed in the last (wrapped) row
+		sort.Slice(rowNums, func(i, j int) bool {
+			return rowNums[j] == 0 || rowNums[i] != 0 && rowNums[i] < rowNums[j]
+		})
+		for _, r := range rowNums {
+			tab.Rows = append(tab.Rows, rows[r])
+		}
+	}
+	fd.Imports = imports.list()
+	if len(fd.Tabs) == 1 {
+		fd.Tabs[0].Label = ""
+	}
+
+	templ, err := template.New("FORM").Delims("[[", "]]").Parse(formTemplate)
+	if err != nil {
+		return err
+	}
+	return cg.writeFile(filepath.Join(outDir, fd.Name+".tsx"), templ, fd)
+}
+
+// getFormField returns descriptor of form input for field or nil if there is no input for it
+func (cg *ClientGenerator) getFormField(outDir string, f *gen.Field, imports tsImports) *formField {
+	if skip, ok := f.Annotations.GetBoolAnnotation(gen.GQLAnnotation, gen.GQLAnnotationSkipTag); ok && skip {
+		return nil
+	}
+	name, ok := f.Annotations.GetStringAnnotation(js.Annotation, js.AnnotationName)
+	if !ok || f.IsPolymorphic() || f.Type.Map != nil {
+		return nil
+	}
+	as := layoutAnnotations(f.Annotations)
+	if as.getBool(uiaIgnore) {
+		return nil
+	}
+	ff := &formField{
+		Name:     name,
+		Label:    as.getString(uiaLabel, f.Name),
+		Disabled: "disabled",
+		tab:      as.getString(uiaTab, ""),
+		row:      as.getInt(uiaRow, 0),
+		order:    as.getInt(uiaOrder, defaultOrder),
+	}
+	if f.Doc != "" {
+		ff.Hint = fmt.Sprintf("%q", f.Doc)
+	}
+	if as.getBool(uiaReadonly) || f.FB(gen.FeaturesCommonKind, gen.FCReadonly) || f.HasModifier(gen.AttrModifierCalculated) {
+		ff.Disabled = "true"
+	} else if f.IsIdField() {
+		if f.HasModifier(gen.AttrModifierIDAuto) {
+			ff.Disabled = "true"
+		} else {
+			ff.Disabled = "disabled || !isNew"
+		}
+	}
+	ff.Style = `{ flex: "1 1 200px" }`
+	for _, a := range as {
+		if w, ok := a.GetIntTag(uiaWidth); ok {
+			ff.Style = fmt.Sprintf(`{ flex: "%d 1 0", minWidth: 0 }`, w)
+			break
+		} else if w, ok := a.GetStringTag(uiaWidth); ok {
+			ff.Style = fmt.Sprintf(`{ width: %q }`, w)
+			break
+		}
+	}
+
+	if f.Type.Array != nil {
+		if f.Type.Array.Type != gen.TipString || f.Type.Array.Complex {
+			return nil
+		}
+		ff.Input = inputStrings
+		return ff
+	}
+	switch f.Type.Type {
+	case gen.TipString, gen.TipUUID, gen.TipDecimal, gen.TipDuration:
+		ff.Input = inputText
+		if ta, ok := as.getInterface(uiaTextArea); ok {
+			ff.Input = inputTextArea
+			ff.Lines = 3
+			if lines, ok := ta.(float64); ok {
+				ff.Lines = int(lines)
+			} else if b, ok := ta.(bool); ok && !b {
+				ff.Input = inputText
+			}
+		}
+	case gen.TipInt, gen.TipFloat:
+		ff.Input = inputNumber
+	case gen.TipBool:
+		ff.Input = inputCheckbox
+	case gen.TipDate, gen.TipDateTime:
+		ff.Input = inputDateTime
+	case gen.TipTime:
+		ff.Input = inputTime
+	default:
+		t, ok := cg.desc.FindType(f.Type.Type)
+		if !ok {
+			return nil
+		}
+		var comp, dir string
+		if enum := t.Enum(); enum != nil {
+			ff.Input = inputSelect
+			comp = enum.Features.String(featureReactKind, fRKSelectComponent)
+			dir = enum.Features.String(featureReactKind, fRKOutDir)
+		} else if ent := t.Entity(); ent != nil {
+			if c, ok := ent.Features.GetString(featureReactKind, fRKSelectComponent); ok {
+				ff.Input = inputSelect
+				if f.HasModifier(gen.AttrModifierEmbeddedRef) || f.FB(gen.GQLFeatures, gen.GQLFIDOnly) {
+					ff.Attrs = " returnObject={false}"
+				}
+				comp = c
+			} else if c, ok := ent.Features.GetString(featureReactKind, fRKFormComponent); ok && ent.HasModifier(gen.TypeModifierEmbeddable) {
+				ff.Input = inputForm
+				comp = c
+			}
+			dir = ent.FS(featureReactKind, fRKOutDir)
+		}
+		if comp == "" {
+			return nil
+		}
+		ff.Component = comp
+		imports.add(cg.importPath(outDir, filepath.Join(dir, comp)), comp)
+	}
+	return ff
+}
+
+func (cg *ClientGenerator) generateSelect(outDir string, e *gen.Entity) error {
+	idfld := e.GetIdField()
+	if idfld == nil {
+		return fmt.Errorf("at %v: there is no id field for select component", e.Pos)
+	}
+	sd := &selectData{
+		Name:     e.FS(featureReactKind, fRKSelectComponent),
+		TypeName: e.Annotations.GetStringAnnotationDef(js.Annotation, js.AnnotationName, ""),
+		IDType:   e.FS(js.Features, js.FIDType),
+		IDField:  idfld.Annotations.GetStringAnnotationDef(js.Annotation, js.AnnotationName, ""),
+		Hook:     "useList" + plural(e.Name),
+	}
+	sd.Title = sd.IDField
+	if tf, ok := e.Annotations.GetStringAnnotation(js.Annotation, js.AnnotationTitle); ok {
+		if f := e.GetField(tf); f != nil {
+			sd.Title = f.Annotations.GetStringAnnotationDef(js.Annotation, js.AnnotationName, sd.IDField)
+		}
+	}
+	imports := tsImports{}
+	imports.add(cg.importPath(outDir, e.FS(js.Features, js.FFilePath)), sd.TypeName)
+	imports.add(cg.importPath(outDir, filepath.Join(outDir, hooksFileName)), sd.Hook)
+	sd.Imports = imports.list()
+
+	templ, err := template.New("SELECT").Delims("[[", "]]").Parse(selectTemplate)
+	if err != nil {
+		return err
+	}
+	return cg.writeFile(filepath.Join(outDir, sd.Name+".tsx"), templ, sd)
+}
+
+func (cg *ClientGenerator) generateEnumSelect(outDir string, enum *gen.Enum) error {
+	sd := &selectData{
+		Name:     enum.Features.String(featureReactKind, fRKSelectComponent),
+		TypeName: enum.Features.String(js.Features, js.FName),
+	}
+	for _, ef := range enum.Fields {
+		sd.Options = append(
+			sd.Options,
+			selectOption{
+				Label: fmt.Sprintf("%q", layoutAnnotations(ef.Annotations).getString(uiaLabel, ef.Name)),
+				Value: cg.names.GetEnumFieldJSValue(ef),
+			},
+		)
+	}
+	imports := tsImports{}
+	imports.add(cg.importPath(outDir, enum.Features.String(js.Features, js.FFilePath)), sd.TypeName)
+	sd.Imports = imports.list()
+
+	templ, err := template.New("ENUM_SELECT").Delims("[[", "]]").Parse(enumSelectTemplate)
+	if err != nil {
+		return err
+	}
+	return cg.writeFile(filepath.Join(outDir, sd.Name+".tsx"), templ, sd)
+}
@@ -0,0 +1,324 @@
+package react
+
+import (
+	"path/filepath"
+	"sort"
+	"text/template"
+
+	"github.com/vc2402/vivard/gen"
+	"github.com/vc2402/vivard/gen/js"
+)
+
+type hookArg struct {
+	Name     string
+	JSType   string
+	Optional bool
+	// Required - query is skipped while the arg is null or undefined
+	Required bool
+}
+
+type hookDef struct {
+	Name     string
+	FuncName string
+	Query    bool
+	Args     []hookArg
+	RetType  string
+}
+
+type hooksFile struct {
+	Imports []tsImport
+	Runtime string
+	Hooks   []hookDef
+}
+
+type tsImport struct {
+	Path  string
+	Names []string
+}
+
+// tsImports collects names to import from files
+type tsImports map[string][]string
+
+func (imp tsImports) add(path string, name string) {
+	for _, n := range imp[path] {
+		if n == name {
+			return
+		}
+	}
+	imp[path] = append(imp[path], name)
+}
+
+func (imp tsImports) list() []tsImport {
+	ret := make([]tsImport, 0, len(imp))
+	for p, names := range imp {
+		sort.Strings(names)
+		ret = append(ret, tsImport{Path: p, Names: names})
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Path < ret[j].Path })
+	return ret
+}
+
+func (cg *ClientGenerator) generateHooks(outDir string) error {
+	imports := tsImports{}
+	hf := hooksFile{Runtime: cg.importPath(outDir, filepath.Join(cg.getOutputDir(), runtimeFileName))}
+	for _, e := range cg.b.File.Entries {
+		hooks, err := cg.getEntityHooks(outDir, e, imports)
+		if err != nil {
+			return err
+		}
+		hf.Hooks = append(hf.Hooks, hooks...)
+	}
+	hf.Imports = imports.list()
+	templ, err := template.New("HOOKS").Parse(hooksTemplate)
+	if err != nil {
+		return err
+	}
+	return cg.writeFile(filepath.Join(outDir, hooksFileName), templ, hf)
+}
+
+func (cg *ClientGenerator) getEntityHooks(outDir string, e *gen.Entity, imports tsImports) (hooks []hookDef, err error) {
+	typesPath := cg.importPath(outDir, e.FS(js.Features, js.FFilePath))
+	typeName := e.Annotations.GetStringAnnotationDef(js.Annotation, js.AnnotationName, "")
+	isCfg := e.HasModifier(gen.TypeModifierConfig)
+	for op := gen.GQLOperationGet; op < gen.GQLOperationLast; op++ {
+		if !cg.hasOperation(e, op) {
+			continue
+		}
+		fn, err := cg.desc.Project.CallFeatureFunc(e, js.Features, js.FFunctionName, op)
+		if err != nil {
+			return nil, err
+		}
+		hd := hookDef{FuncName: fn.(string), RetType: typeName}
+		switch op {
+		case gen.GQLOperationGet:
+			hd.Query = true
+			hd.Name = "use" + capitalize(hd.FuncName)
+			if !isCfg {
+				hd.Args = []hookArg{cg.idArg(e)}
+			}
+		case gen.GQLOperationSet:
+			hd.Name = "use" + capitalize(hd.FuncName)
+			inputName := e.Annotations.GetStringAnnotationDef(js.Annotation, js.AnnotationInputName, cg.names.GetJSEntityInputTypeName(e.Name))
+			hd.Args = []hookArg{{Name: "val", JSType: inputName}}
+			imports.add(typesPath, inputName)
+		case gen.GQLOperationCreate:
+			hd.Name = "use" + capitalize(hd.FuncName)
+			hd.Args = []hookArg{{Name: "val", JSType: typeName}}
+		case gen.GQLOperationList:
+			hd.Query = true
+			hd.Name = "useList" + plural(e.Name)
+			hd.RetType += "[]"
+			if e.FB(gen.FeatureDictKind, gen.FDQualified) {
+				qt, _ := e.Features.GetEntity(gen.FeatureDictKind, gen.FDQualifierType)
+				jsType := "string[]"
+				if qt != nil && qt.GetIdField() != nil && qt.GetIdField().Type.Type == gen.TipInt {
+					jsType = "number[]"
+				}
+				hd.Args = []hookArg{{Name: "quals", JSType: jsType, Optional: true}}
+			}
+		case gen.GQLOperationLookup:
+			hd.Query = true
+			hd.Name = "useLookup" + plural(e.Name)
+			hd.RetType += "[]"
+			hd.Args = []hookArg{{Name: "query", JSType: "string", Required: true}}
+		case gen.GQLOperationDelete:
+			hd.Name = "use" + capitalize(hd.FuncName)
+			hd.RetType = "boolean"
+			hd.Args = []hookArg{cg.idArg(e)}
+		case gen.GQLOperationFind:
+			it, _ := e.Features.GetEntity(gen.FeaturesAPIKind, gen.FAPIFindParamType)
+			hd.Query = true
+			hd.Name = "useFind" + plural(e.Name)
+			hd.RetType += "[]"
+			paramType := cg.names.GetJSEntityTypeName(it.Name)
+			hd.Args = []hookArg{{Name: "query", JSType: paramType, Required: true}}
+			imports.add(typesPath, paramType)
+		case gen.GQLOperationBulkCreate, gen.GQLOperationBulkSet:
+			hd.Name = "use" + capitalize(hd.FuncName)
+			hd.RetType += "[]"
+			hd.Args = []hookArg{{Name: "val", JSType: hd.RetType}}
+		}
+		if hd.RetType != "boolean" {
+			imports.add(typesPath, typeName)
+		}
+		imports.add(typesPath, hd.FuncName)
+		hooks = append(hooks, hd)
+	}
+
+	for _, m := range e.Methods {
+		qn := m.FS(gen.GQLFeatures, gen.GQLFMethodName)
+		if qn == "" {
+			continue
+		}
+		hd := hookDef{
+			Name:     "use" + capitalize(qn),
+			FuncName: qn,
+			Query:    m.FS(gen.GQLFeatures, gen.GQLFMethodType) == "query",
+			RetType:  "void",
+		}
+		if m.RetValue != nil {
+			hd.RetType = cg.names.GetJSTypeName(m.RetValue, false)
+			cg.addTypeImport(outDir, m.RetValue, false, imports)
+		}
+		if idfld := e.GetIdField(); idfld != nil {
+			if _, ok := idfld.Features.GetString(gen.GQLFeatures, gen.GQLFTypeTag); ok {
+				hd.Args = append(hd.Args, hookArg{Name: "id", JSType: e.FS(js.Features, js.FIDType), Required: true})
+			}
+		}
+		for _, p := range m.Params {
+			hd.Args = append(
+				hd.Args,
+				hookArg{Name: p.Name, JSType: cg.names.GetJSInputTypeName(p.Type, false), Required: p.Type.NonNullable},
+			)
+			cg.addTypeImport(outDir, p.Type, true, imports)
+		}
+		imports.add(typesPath, qn)
+		hooks = append(hooks, hd)
+	}
+	return
+}
+
+func (cg *ClientGenerator) idArg(e *gen.Entity) hookArg {
+	idfld := e.GetIdField()
+	return hookArg{
+		Name:     idfld.Annotations.GetStringAnnotationDef(js.Annotation, js.AnnotationName, ""),
+		JSType:   e.FS(js.Features, js.FIDType),
+		Required: true,
+	}
+}
+
+// addTypeImport adds import of type (or input type) for complex type ref
+func (cg *ClientGenerator) addTypeImport(outDir string, ref *gen.TypeRef, input bool, imports tsImports) {
+	for ref.Array != nil {
+		ref = ref.Array
+	}
+	if ref.Map != nil {
+		return
+	}
+	t, ok := cg.desc.FindType(ref.Type)
+	if !ok {
+		return
+	}
+	var path, name string
+	switch {
+	case t.Entity() != nil:
+		path = t.Entity().FS(js.Features, js.FFilePath)
+		if input {
+			name = cg.names.GetJSEntityInputTypeName(ref.Type)
+		} else {
+			name = cg.names.GetJSEntityTypeName(ref.Type)
+		}
+	case t.Enum() != nil:
+		path = t.Enum().Features.String(js.Features, js.FFilePath)
+		if input {
+			name = cg.names.GetJSEntityInputTypeName(ref.Type)
+		} else {
+			name = t.Enum().Features.String(js.Features, js.FName)
+		}
+	}
+	if path != "" && name != "" {
+		imports.add(cg.importPath(outDir, path), name)
+	}
+}
+
+const hooksTemplate = `/*Code generated by vivgen. DO NOT EDIT.*/
+import { useMutationOperation, useQueryOperation, QueryOptions, QueryState } from '{{.Runtime}}';
+{{range .Imports}}import { {{range $idx, $n := .Names}}{{if gt $idx 0}}, {{end}}{{$n}}{{end}} } from '{{.Path}}';
+{{end}}{{range .Hooks}}{{if .Query}}
+export function {{.Name}}({{range .Args}}{{.Name}}{{if .Optional}}?{{end}}: {{.JSType}}{{if .Required}} | null | undefined{{end}}, {{end}}options?: QueryOptions): QueryState<{{.RetType}}> {
+  return useQueryOperation({{.FuncName}}, [{{range $idx, $arg := .Args}}{{if gt $idx 0}}, {{end}}{{$arg.Name}}{{if $arg.Required}}!{{end}}{{end}}], !!options?.skip{{range .Args}}{{if .Required}} || {{.Name}} == null{{end}}{{end}});
+}
+{{else}}
+export function {{.Name}}() {
+  return useMutationOperation({{.FuncName}});
+}
+{{end}}{{end}}`
+
+const runtimeFileContent = `/*Code generated by vivgen. DO NOT EDIT.*/
+import { createContext, useCallback, useContext, useEffect, useState } from 'react';
+
+// GQLClientContext provides the client passed to generated GQL functions (ApolloClient or GQLClient, depending on gql-ts target)
+export const GQLClientContext = createContext<any>(null);
+
+export function useGQLClient(): any {
+  const client = useContext(GQLClientContext);
+  if(!client)
+    throw new Error("GQL client is not provided: wrap the application into GQLClientContext.Provider");
+  return client;
+}
+
+export type QueryOptions = {
+  // skip - do not run the query (e.g. while its arguments are not known yet)
+  skip?: boolean,
+};
+
+export type QueryState<T> = {
+  data: T | undefined,
+  loading: boolean,
+  error: any,
+  refetch: () => Promise<T | undefined>,
+};
+
+export type MutationState<T> = {
+  data: T | undefined,
+  loading: boolean,
+  error: any,
+};
+
+export function useQueryOperation<T, A extends any[]>(
+  op: (client: any, ...args: A) => Promise<T>,
+  args: A,
+  skip = false,
+): QueryState<T> {
+  const client = useGQLClient();
+  const [data, setData] = useState<T | undefined>(undefined);
+  const [loading, setLoading] = useState(!skip);
+  const [error, setError] = useState<any>(null);
+  const key = JSON.stringify(args);
+  const refetch = useCallback(async () => {
+    setLoading(true);
+    setError(null);
+    try {
+      const res = await op(client, ...args);
+      setData(res);
+      return res;
+    } catch(exc) {
+      setError(exc);
+      return undefined;
+    } finally {
+      setLoading(false);
+    }
+  // args are compared by their JSON representation
+  // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [client, op, key]);
+  useEffect(() => {
+    if(!skip)
+      refetch();
+  }, [refetch, skip]);
+  return { data, loading, error, refetch };
+}
+
+export function useMutationOperation<T, A extends any[]>(
+  op: (client: any, ...args: A) => Promise<T>,
+): [(...args: A) => Promise<T>, MutationState<T>] {
+  const client = useGQLClient();
+  const [data, setData] = useState<T | undefined>(undefined);
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<any>(null);
+  const mutate = useCallback(async (...args: A) => {
+    setLoading(true);
+    setError(null);
+    try {
+      const res = await op(client, ...args);
+      setData(res);
+      return res;
+    } catch(exc) {
+      setError(exc);
+      throw exc;
+    } finally {
+      setLoading(false);
+    }
+  }, [client, op]);
+  return [mutate, { data, loading, error }];
+}
+`
@@ -0,0 +1,305 @@
+package react
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/vc2402/vivard/gen"
+	"github.com/vc2402/vivard/gen/js"
+)
+
+const (
+	reactGeneratorName = "react"
+
+	// uiAnnotation - framework neutral field/type annotation; the same tags as for vue annotation are used
+	//  (label, tab, row, width, order, readonly, ignore, textArea); if it is absent vue annotation is used
+	uiAnnotation = "ui"
+	// uiFormAnnotation - form parameters for type (ignore)
+	uiFormAnnotation = "ui-form"
+	// uiTabsAnnotation - set of tabs for the form in form tabid="tab-label"
+	uiTabsAnnotation = "ui-tabs"
+	// uiLookupAnnotation - lookup (select) parameters for type (ignore)
+	uiLookupAnnotation = "ui-lookup"
+
+	vueAnnotation       = "vue"
+	vueFormAnnotation   = "vue-form"
+	vueTabsAnnotation   = "vue-tabs"
+	vueTabAnnotation    = "vue-tab"
+	vueLookupAnnotation = "vue-lookup"
+
+	uiaLabel    = "label"
+	uiaTab      = "tab"
+	uiaRow      = "row"
+	uiaWidth    = "width"
+	uiaOrder    = "order"
+	uiaReadonly = "readonly"
+	uiaIgnore   = "ignore"
+	uiaTextArea = "textArea"
+)
+
+const (
+	ROptions = "react"
+
+	runtimeFileName = "vivard-react.ts"
+	hooksFileName   = "hooks.ts"
+)
+
+const (
+	// featureReactKind kind for react features
+	featureReactKind gen.FeatureKind = "react-client"
+
+	//fRKOutDir - string with path for components of the file
+	fRKOutDir = "out-dir"
+	//fRKFormComponent - string, name of form component (if it should be generated)
+	fRKFormComponent = "form-component"
+	//fRKSelectComponent - string, name of select component (if it should be generated)
+	fRKSelectComponent = "select-component"
+)
+
+type ClientOptions struct {
+	OutputDir string `json:"output_dir"`
+}
+
+// ClientGenerator generates React + TypeScript client: typed hooks for GQL operations generated by gen/js,
+// form components driven by ui (or vue) layout annotations and select components for dictionaries and enums
+type ClientGenerator struct {
+	desc             *gen.Package
+	options          ClientOptions
+	b                *gen.Builder
+	names            js.GQLCLientGenerator
+	runtimeGenerated bool
+}
+
+func init() {
+	gen.RegisterPluginFactory(func() gen.Generator { return &ClientGenerator{} })
+}
+
+func (cg *ClientGenerator) Name() string {
+	return reactGeneratorName
+}
+
+// Requires from gen.PluginsRequirer
+func (cg *ClientGenerator) Requires() []string {
+	return []string{js.GQLClientGeneratorName}
+}
+
+func (cg *ClientGenerator) SetOptions(options any) error {
+	return gen.OptionsAnyToStruct(options, &cg.options)
+}
+
+func (cg *ClientGenerator) CheckAnnotation(desc *gen.Package, ann *gen.Annotation, item interface{}) (bool, error) {
+	annname := strings.Split(ann.Name, ":")
+	switch annname[0] {
+	case uiAnnotation, uiFormAnnotation, uiTabsAnnotation, uiLookupAnnotation:
+		return true, nil
+	case vueAnnotation, vueFormAnnotation, vueTabsAnnotation, vueTabAnnotation, vueLookupAnnotation:
+		// layout annotations of vue generator are used when there are no ui ones
+		return true, nil
+	}
+	return false, nil
+}
+
+// AnnotationSchemas from gen.AnnotationsDescriber
+func (cg *ClientGenerator) AnnotationSchemas() []gen.AnnotationSchema {
+	field := []gen.AnnotationTagSchema{
+		{Name: uiaLabel, Type: gen.AnnotationTagString, Doc: "label of field"},
+		{Name: uiaTab, Type: gen.AnnotationTagString, Doc: "id of tab with field"},
+		{Name: uiaRow, Type: gen.AnnotationTagNumber, Doc: "row of field in form"},
+		{Name: uiaWidth, Type: gen.AnnotationTagAny, Doc: "width of field"},
+		{Name: uiaOrder, Type: gen.AnnotationTagNumber, Doc: "order of field in form"},
+		{Name: uiaReadonly, Type: gen.AnnotationTagBool, Default: false, Doc: "field can not be changed in form"},
+		{Name: uiaIgnore, Type: gen.AnnotationTagBool, Default: false, Doc: "field is not shown in form"},
+		{Name: uiaTextArea, Type: gen.AnnotationTagAny, Doc: "use text area for field"},
+	}
+	component := []gen.AnnotationTagSchema{
+		{Name: uiaIgnore, Type: gen.AnnotationTagBool, Default: false, Doc: "do not generate component"},
+	}
+	schema := func(name string, tags []gen.AnnotationTagSchema, doc string) gen.AnnotationSchema {
+		return gen.AnnotationSchema{Name: name, Qualified: true, Targets: gen.AnnotationTargetAny, Tags: tags, AnyTags: true, Doc: doc}
+	}
+	return []gen.AnnotationSchema{
+		schema(uiAnnotation, field, "layout of field in React form"),
+		schema(uiFormAnnotation, component, "form component of type"),
+		schema(uiTabsAnnotation, nil, "tabs of form: tabid=\"tab label\""),
+		schema(uiLookupAnnotation, component, "select component of type"),
+		schema(vueAnnotation, field, "layout of field in React form if there is no ui annotation"),
+		schema(vueFormAnnotation, component, "form component of type if there is no ui-form annotation"),
+		schema(vueTabsAnnotation, nil, "tabs of form if there is no ui-tabs annotation"),
+		schema(vueTabAnnotation, nil, "tab description (vue-tab:tabid)"),
+		schema(vueLookupAnnotation, component, "select component of type if there is no ui-lookup annotation"),
+	}
+}
+
+func (cg *ClientGenerator) Prepare(desc *gen.Package) error {
+	cg.desc = desc
+	if _, err := desc.Options().CustomToStruct(ROptions, &cg.options); err != nil {
+		desc.AddWarning(fmt.Sprintf("problem while setting custom options for %s: %v", ROptions, err))
+	}
+	for _, file := range desc.Files {
+		outDir := filepath.Join(cg.getOutputDir(), file.Package, file.Name)
+		for _, t := range file.Entries {
+			t.Features.Set(featureReactKind, fRKOutDir, outDir)
+			if t.HasModifier(gen.TypeModifierSingleton) || t.HasModifier(gen.TypeModifierExternal) ||
+				t.HasModifier(gen.TypeModifierConfig) || t.HasModifier(gen.TypeModifierTransient) {
+				continue
+			}
+			if !cg.typeIgnored(t, uiFormAnnotation, vueFormAnnotation) && !t.FB(gen.FeaturesCommonKind, gen.FCReadonly) {
+				t.Features.Set(featureReactKind, fRKFormComponent, t.Name+"Form")
+			}
+			if t.HasModifier(gen.TypeModifierDictionary) && cg.hasOperation(t, gen.GQLOperationList) &&
+				!cg.typeIgnored(t, uiLookupAnnotation, vueLookupAnnotation) {
+				t.Features.Set(featureReactKind, fRKSelectComponent, t.Name+"Select")
+			}
+		}
+		for _, enum := range file.Enums {
+			enum.Features.Set(featureReactKind, fRKOutDir, outDir)
+			enum.Features.Set(featureReactKind, fRKSelectComponent, enum.Name+"Select")
+		}
+	}
+	return nil
+}
+
+func (cg *ClientGenerator) Generate(b *gen.Builder) (err error) {
+	cg.desc = b.Descriptor
+	cg.b = b
+	if len(b.File.Entries) == 0 && len(b.File.Enums) == 0 {
+		return nil
+	}
+	for _, e := range b.File.Entries {
+		if _, ok := e.Features.GetString(js.Features, js.FFilePath); !ok {
+			return fmt.Errorf("%s: %s generator is required", reactGeneratorName, js.GQLClientGeneratorName)
+		}
+	}
+	if err = cg.generateRuntime(); err != nil {
+		return
+	}
+	outDir := filepath.Join(cg.getOutputDir(), b.File.Package, b.File.Name)
+	if err = cg.generateHooks(outDir); err != nil {
+		return fmt.Errorf("while generating hooks for %s: %w", b.File.Name, err)
+	}
+	for _, enum := range b.File.Enums {
+		if err = cg.generateEnumSelect(outDir, enum); err != nil {
+			return fmt.Errorf("while generating select for %s: %w", enum.Name, err)
+		}
+	}
+	for _, e := range b.File.Entries {
+		if _, ok := e.Features.GetString(featureReactKind, fRKSelectComponent); ok {
+			if err = cg.generateSelect(outDir, e); err != nil {
+				return fmt.Errorf("while generating select for %s: %w", e.Name, err)
+			}
+		}
+		if _, ok := e.Features.GetString(featureReactKind, fRKFormComponent); ok {
+			if err = cg.generateForm(outDir, e); err != nil {
+				return fmt.Errorf("while generating form for %s: %w", e.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (cg *ClientGenerator) generateRuntime() error {
+	if cg.runtimeGenerated {
+		return nil
+	}
+	err := cg.desc.Project.WriteFile(filepath.Join(cg.getOutputDir(), runtimeFileName), []byte(runtimeFileContent))
+	if err != nil {
+		return err
+	}
+	cg.runtimeGenerated = true
+	return nil
+}
+
+// typeIgnored returns true if ignore tag is set for the type in ui annotation or in one of the given ones
+func (cg *ClientGenerator) typeIgnored(t *gen.Entity, anns ...string) bool {
+	for _, ann := range append([]string{uiAnnotation, vueAnnotation}, anns...) {
+		if ignore, ok := t.Annotations.GetBoolAnnotation(ann, uiaIgnore); ok && ignore {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOperation returns true if js functions is generated for the operation (see js.GQLCLientGenerator.generateQueriesFile)
+func (cg *ClientGenerator) hasOperation(e *gen.Entity, op gen.GQLOperationKind) bool {
+	if e.HasModifier(gen.TypeModifierTransient) || e.HasModifier(gen.TypeModifierEmbeddable) ||
+		e.HasModifier(gen.TypeModifierSingleton) || e.HasModifier(gen.TypeModifierExternal) {
+		return false
+	}
+	if _, ok := e.Features.GetString(gen.GQLFeatures, gen.GQLOperationsAnnotationsTags[op]); !ok {
+		return false
+	}
+	isCfg := e.HasModifier(gen.TypeModifierConfig)
+	switch op {
+	case gen.GQLOperationSet:
+		return !e.FB(gen.FeaturesCommonKind, gen.FCReadonly)
+	case gen.GQLOperationCreate:
+		return !e.FB(gen.FeaturesCommonKind, gen.FCReadonly) && !isCfg
+	case gen.GQLOperationList, gen.GQLOperationLookup, gen.GQLOperationDelete:
+		return !isCfg
+	case gen.GQLOperationFind:
+		_, ok := e.Features.GetEntity(gen.FeaturesAPIKind, gen.FAPIFindParamType)
+		return ok
+	case gen.GQLOperationBulkCreate:
+		return e.FB(gen.FeatGoKind, gen.FCGBulkNew)
+	case gen.GQLOperationBulkSet:
+		return e.FB(gen.FeatGoKind, gen.FCGBulkSet)
+	}
+	return true
+}
+
+// createFile returns file that is written on Close only if its content is changed
+func (cg *ClientGenerator) createFile(path string) *gen.OutputFile {
+	return cg.desc.Project.CreateFile(path)
+}
+
+func (cg *ClientGenerator) getOutputDir() (ret string) {
+	ret = "./gql-ts"
+	if opt := cg.desc.Options().ClientOutputDir; opt != "" {
+		ret = opt
+	}
+	if opt := cg.options.OutputDir; opt != "" {
+		return filepath.FromSlash(opt)
+	}
+	return filepath.FromSlash(filepath.Join(ret, "react"))
+}
+
+// importPath returns path of the file (without extension) relative to dir in form suitable for TS import
+func (cg *ClientGenerator) importPath(dir string, file string) string {
+	file = strings.TrimSuffix(file, filepath.Ext(file))
+	ret, err := filepath.Rel(dir, file)
+	if err != nil {
+		cg.b.AddWarning(fmt.Sprintf("problem while getting relative path for '%s': %v", file, err))
+		ret = file
+	} else if !strings.HasPrefix(ret, ".") {
+		ret = "./" + ret
+	}
+	return filepath.ToSlash(ret)
+}
+
+func (cg *ClientGenerator) writeFile(path string, templ *template.Template, data any) error {
+	outFile := cg.createFile(path)
+	if err := templ.Execute(outFile, data); err != nil {
+		return err
+	}
+	return outFile.Close()
+}
+
+// plural returns simple English plural for type name (Order -> Orders, Category -> Categories)
+func plural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,144 @@
+package react
+
+// templates below use [[ ]] as delimiters as JSX uses {{ }} for object literals
+
+const formTemplate = `/*Code generated by vivgen. DO NOT EDIT.*/
+import React[[if gt (len .Tabs) 1]], { useState }[[end]] from 'react';
+[[range .Imports]]import { [[range $idx, $n := .Names]][[if gt $idx 0]], [[end]][[$n]][[end]] } from '[[.Path]]';
+[[end]]
+export type [[.Name]]Props = {
+  value: [[.TypeName]] | null,
+  onChange: (value: [[.TypeName]]) => void,
+  disabled?: boolean,
+  isNew?: boolean,
+};
+
+export function [[.Name]]({ value, onChange, disabled = false, isNew = false }: [[.Name]]Props) {
+[[- if gt (len .Tabs) 1]]
+  const [tab, setTab] = useState("[[.DefaultTab]]");
+[[- end]]
+  if(!value)
+    return null;
+  const set = (field: keyof [[.TypeName]], val: any) => onChange({ ...value, [field]: val });
+  return (
+    <div className="vivard-form">
+[[- if gt (len .Tabs) 1]]
+      <div className="vivard-form-tabs">
+[[- range .Tabs]]
+        <button type="button" className={tab == "[[.ID]]" ? "active" : ""} onClick={() => setTab("[[.ID]]")}>[[.Label]]</button>
+[[- end]]
+      </div>
+[[- range .Tabs]]
+      {tab == "[[.ID]]" && (
+        <div className="vivard-form-tab">[[template "ROWS" .Rows]]
+        </div>
+      )}
+[[- end]]
+[[- else]][[template "ROWS" (index .Tabs 0).Rows]]
+[[- end]]
+    </div>
+  );
+}
+[[define "ROWS"]][[range .]]
+          <div className="vivard-form-row" style={{ display: "flex", flexWrap: "wrap", gap: 8 }}>
+[[- range .]]
+            <label className="vivard-field" style={[[.Style]]}[[if .Hint]] title={[[.Hint]]}[[end]]>
+              <span>[[.Label]]</span>
+              [[template "INPUT" .]]
+            </label>
+[[- end]]
+          </div>
+[[- end]][[end]]
+[[define "INPUT"]]
+[[- if eq .Input "text" -]]
+<input type="text" value={value.[[.Name]] ?? ""} disabled={[[.Disabled]]} onChange={e => set("[[.Name]]", e.target.value)}/>
+[[- else if eq .Input "number" -]]
+<input type="number" value={value.[[.Name]] ?? ""} disabled={[[.Disabled]]} onChange={e => set("[[.Name]]", e.target.value === "" ? null : Number(e.target.value))}/>
+[[- else if eq .Input "checkbox" -]]
+<input type="checkbox" checked={!!value.[[.Name]]} disabled={[[.Disabled]]} onChange={e => set("[[.Name]]", e.target.checked)}/>
+[[- else if eq .Input "datetime" -]]
+<input type="datetime-local" value={value.[[.Name]] ? new Date(value.[[.Name]]).toISOString().substring(0, 16) : ""} disabled={[[.Disabled]]} onChange={e => set("[[.Name]]", e.target.value ? new Date(e.target.value + "Z").toISOString() : null)}/>
+[[- else if eq .Input "time" -]]
+<input type="time" value={value.[[.Name]] ?? ""} disabled={[[.Disabled]]} onChange={e => set("[[.Name]]", e.target.value)}/>
+[[- else if eq .Input "textarea" -]]
+<textarea rows={[[.Lines]]} value={value.[[.Name]] ?? ""} disabled={[[.Disabled]]} onChange={e => set("[[.Name]]", e.target.value)}/>
+[[- else if eq .Input "strings" -]]
+<input type="text" value={(value.[[.Name]] ?? []).join(", ")} disabled={[[.Disabled]]} onChange={e => set("[[.Name]]", e.target.value.split(",").map(s => s.trim()).filter(s => s))}/>
+[[- else if eq .Input "select" -]]
+<[[.Component]] value={value.[[.Name]]} disabled={[[.Disabled]]}[[.Attrs]] onChange={v => set("[[.Name]]", v)}/>
+[[- else if eq .Input "form" -]]
+<[[.Component]] value={value.[[.Name]] ?? null} disabled={[[.Disabled]]} onChange={v => set("[[.Name]]", v)}/>
+[[- end]]
+[[- end]]
+`
+
+const selectTemplate = `/*Code generated by vivgen. DO NOT EDIT.*/
+import React from 'react';
+[[range .Imports]]import { [[range $idx, $n := .Names]][[if gt $idx 0]], [[end]][[$n]][[end]] } from '[[.Path]]';
+[[end]]
+export type [[.Name]]Props = {
+  value?: [[.TypeName]] | [[.IDType]] | null,
+  // onChange is called with selected item (or its id if returnObject is false) or null
+  onChange: (value: any) => void,
+  returnObject?: boolean,
+  disabled?: boolean,
+  className?: string,
+  filter?: (item: [[.TypeName]]) => boolean,
+};
+
+export function [[.Name]]({ value, onChange, returnObject = true, disabled = false, className, filter }: [[.Name]]Props) {
+  const { data, loading, error } = [[.Hook]]();
+  const items = (data ?? []).filter(item => !filter || filter(item));
+  const selected = value == null ? "" : String(typeof value == "object" ? value.[[.IDField]] : value);
+  return (
+    <select
+      className={className}
+      value={selected}
+      disabled={disabled || loading}
+      title={error ? String(error) : undefined}
+      onChange={e => {
+        const item = items.find(it => String(it.[[.IDField]]) === e.target.value);
+        onChange(item ? (returnObject ? item : item.[[.IDField]]) : null);
+      }}
+    >
+      <option value=""></option>
+      {items.map(item => <option key={String(item.[[.IDField]])} value={String(item.[[.IDField]])}>{String(item.[[.Title]] ?? item.[[.IDField]])}</option>)}
+    </select>
+  );
+}
+`
+
+const enumSelectTemplate = `/*Code generated by vivgen. DO NOT EDIT.*/
+import React from 'react';
+[[range .Imports]]import { [[range $idx, $n := .Names]][[if gt $idx 0]], [[end]][[$n]][[end]] } from '[[.Path]]';
+[[end]]
+export const [[.Name]]Options: { label: string, value: [[.TypeName]] }[] = [
+[[- range .Options]]
+  { label: [[.Label]], value: [[.Value]] },
+[[- end]]
+];
+
+export type [[.Name]]Props = {
+  value?: [[.TypeName]] | null,
+  onChange: (value: [[.TypeName]] | null) => void,
+  disabled?: boolean,
+  className?: string,
+};
+
+export function [[.Name]]({ value, onChange, disabled = false, className }: [[.Name]]Props) {
+  return (
+    <select
+      className={className}
+      value={value == null ? "" : String(value)}
+      disabled={disabled}
+      onChange={e => {
+        const opt = [[.Name]]Options.find(o => String(o.value) === e.target.value);
+        onChange(opt ? opt.value : null);
+      }}
+    >
+      <option value=""></option>
+      {[[.Name]]Options.map(o => <option key={String(o.value)} value={String(o.value)}>{o.label}</option>)}
+    </select>
+  );
+}
+`
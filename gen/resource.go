@@ -60,13 +60,18 @@ const (
 )
 
 func init() {
-	RegisterPlugin(&ResourceGenerator{})
+	RegisterPluginFactory(func() Generator { return &ResourceGenerator{} })
 }
 
 func (cg *ResourceGenerator) Name() string {
 	return resourceGeneratorName
 }
 
+// Requires from PluginsRequirer
+func (cg *ResourceGenerator) Requires() []string {
+	return []string{serviceGeneratorName}
+}
+
 func (cg *ResourceGenerator) SetOptions(options any) error {
 	if opts, ok := options.(map[string]any); ok {
 		if root, ok := opts[orRootResource].(string); ok {
@@ -95,6 +100,24 @@ func (cg *ResourceGenerator) CheckAnnotation(desc *Package, ann *Annotation, ite
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (cg *ResourceGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    resourceAnnotation,
+			Targets: AnnotationTargetEntity,
+			Tags: []AnnotationTagSchema{
+				{Name: raKey, Type: AnnotationTagString, Default: "<package>:<Type>", Doc: "key of resource"},
+				{Name: raDescription, Type: AnnotationTagString, Default: "<Type>", Doc: "description of resource"},
+				{Name: raParent, Type: AnnotationTagString, Default: "<package>", Doc: "key of parent resource"},
+				{Name: raCheckAccess, Type: AnnotationTagBool, Doc: "check access to objects of type"},
+			},
+			AnyTags: true,
+			Doc:     "type is access controlled resource",
+		},
+	}
+}
+
 func (cg *ResourceGenerator) Prepare(desc *Package) error {
 	if cg.delimiter == "" {
 		cg.delimiter = ":"
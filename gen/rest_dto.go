@@ -0,0 +1,323 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// restFieldName returns name of f in REST bodies (the same as in GraphQL type); false if f is not in REST bodies:
+// fields without GraphQL name, fields of polymorphic types, auxiliary and ignored fields (e.g. one-to-many lists
+// that are available with their own resources) are skipped
+func restFieldName(desc *Package, f *Field) (string, bool) {
+	name, ok := f.Annotations.GetStringAnnotation(GQLAnnotation, GQLAnnotationNameTag)
+	if !ok || f.IsPolymorphic() || f.HasModifier(AttrModifierAuxiliary) || f.FB(FeaturesCommonKind, FCIgnore) {
+		return "", false
+	}
+	if f.HasModifier(AttrModifierCalculated) && restStoredRef(desc, f.Type) {
+		// getter of calculated field returns the object, not its id
+		return "", false
+	}
+	return name, true
+}
+
+// restReadonlyField returns true if f is returned in REST bodies but is not accepted in requests
+func restReadonlyField(f *Field) bool {
+	return f.HasModifier(AttrModifierCalculated) || f.FB(FeatGoKind, FCGCalculated) || f.FB(FeaturesCommonKind, FCReadonly)
+}
+
+// restStoredRef returns true if ref (or array's item) refers to stored type; such values are kept as ids (see Builder.GoType)
+func restStoredRef(desc *Package, ref *TypeRef) bool {
+	embedded := false
+	for ref.Array != nil {
+		embedded = ref.Embedded
+		ref = ref.Array
+	}
+	if ref.Map != nil || IsPrimitiveType(ref.Type) {
+		return false
+	}
+	dt, ok := desc.FindType(ref.Type)
+	if !ok || dt.entry == nil || dt.IsPolymorphic() {
+		return false
+	}
+	e := dt.entry
+	return !ref.Embedded && !embedded &&
+		!e.HasModifier(TypeModifierEmbeddable) &&
+		!e.HasModifier(TypeModifierTransient) &&
+		!e.HasModifier(TypeModifierExternal) &&
+		!e.HasModifier(TypeModifierConfig)
+}
+
+// restHasDTO returns true if DTO is generated for t
+func restHasDTO(t *Entity) bool {
+	return !t.HasModifier(TypeModifierExternal) && !t.HasModifier(TypeModifierSingleton)
+}
+
+// dtoName returns name of type that represents entity in REST bodies
+func (cg *RESTGenerator) dtoName(e *Entity) jen.Code {
+	if e.Pckg != cg.desc {
+		return jen.Qual(e.Pckg.fullPackage, e.Name+"REST")
+	}
+	return jen.Id(e.Name + "REST")
+}
+
+// dtoFieldName returns exported name of DTO's field for f: Go name of the field with the first letter in upper case
+func (cg *RESTGenerator) dtoFieldName(f *Field) string {
+	name := f.FS(FeatGoKind, FCGName)
+	if name == "" {
+		name = f.Name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// nestedEntity returns entity that is kept inside the object (embedded, embeddable, transient or config type)
+// and so is represented with its DTO; nil for other types (stored references are represented by id)
+func (cg *RESTGenerator) nestedEntity(ref *TypeRef, embedded bool) *Entity {
+	if ref.Array != nil || ref.Map != nil || IsPrimitiveType(ref.Type) {
+		return nil
+	}
+	dt, ok := cg.desc.FindType(ref.Type)
+	if !ok || dt.entry == nil || dt.IsPolymorphic() || dt.entry.HasModifier(TypeModifierExternal) {
+		return nil
+	}
+	e := dt.entry
+	if ref.Embedded || embedded ||
+		e.HasModifier(TypeModifierEmbeddable) ||
+		e.HasModifier(TypeModifierTransient) ||
+		e.HasModifier(TypeModifierConfig) {
+		return e
+	}
+	return nil
+}
+
+// dtoType returns type of value of ref in REST bodies
+func (cg *RESTGenerator) dtoType(ref *TypeRef, embedded bool) jen.Code {
+	if ref.Array != nil {
+		return jen.Index().Add(cg.dtoType(ref.Array, ref.Embedded))
+	}
+	if e := cg.nestedEntity(ref, embedded); e != nil {
+		return jen.Op("*").Add(cg.dtoName(e))
+	}
+	return cg.b.GoType(ref, embedded)
+}
+
+// isDTOValueType returns true if value of ref is not nillable and so DTO's field is a pointer to it
+func (cg *RESTGenerator) isDTOValueType(ref *TypeRef) bool {
+	if ref.Array != nil || ref.Map != nil || ref.Type == TipBytes || ref.Type == TipAny {
+		return false
+	}
+	return cg.nestedEntity(ref, false) == nil
+}
+
+// generateDTO generates <T>REST type that is used for t in REST bodies instead of t itself (which has unexported fields)
+// and Engine's methods that convert t to and from it; values are set with setters (as GraphQL input parser does)
+func (cg *RESTGenerator) generateDTO(t *Entity) error {
+	name := t.Name
+	var fields []jen.Code
+	var toREST, fromREST []jen.Code
+	for _, f := range t.GetFields(true, true) {
+		jsonName, ok := restFieldName(cg.desc, f)
+		if !ok {
+			continue
+		}
+		if f.Type.Map != nil && cg.nestedEntity(f.Type.Map.ValueType, f.Type.Map.ValueType.Embedded) != nil {
+			cg.desc.AddWarning(fmt.Sprintf("at %v: REST: maps of objects are not supported; field %s is skipped", f.Pos, f.Name))
+			continue
+		}
+		fieldName := cg.dtoFieldName(f)
+		valueType := cg.isDTOValueType(f.Type)
+		typ := cg.dtoType(f.Type, f.Type.Embedded)
+		if valueType {
+			typ = jen.Op("*").Add(typ)
+		}
+		fields = append(
+			fields,
+			goDoc(withDeprecation(f.Doc, f.Annotations)).Id(fieldName).Add(typ).Tag(map[string]string{"json": jsonName + ",omitempty"}),
+		)
+		dst := jen.Id("ret").Dot(fieldName)
+		if f.HasModifier(AttrModifierCalculated) || f.FB(FeatGoKind, FCGCalculated) {
+			block := []jen.Code{
+				jen.List(jen.Id("v"), jen.Err()).Op(":=").Add(
+					cg.desc.CallCodeFeatureFunc(f, FeaturesCommonKind, FCGetterCode, "obj", "ctx", true),
+				),
+				returnIfErrValue(jen.Nil()),
+			}
+			if valueType {
+				block = append(block, dst.Clone().Op("=").Op("&").Id("v"))
+			} else {
+				block = append(block, cg.convertToDTO(f.Type, f.Type.Embedded, dst, jen.Id("v"), 0)...)
+			}
+			toREST = append(toREST, jen.Block(block...))
+			continue
+		}
+		attr := jen.Id("obj").Dot(f.Name)
+		switch {
+		case valueType && f.FB(FeatGoKind, FCGPointer):
+			toREST = append(toREST, dst.Clone().Op("=").Add(attr))
+		case valueType:
+			toREST = append(toREST, dst.Clone().Op("=").Op("&").Add(attr))
+		case f.FB(FeatGoKind, FCGPointer):
+			// nillable value (e.g. bytes) kept by pointer
+			toREST = append(
+				toREST,
+				jen.If(attr.Clone().Op("!=").Nil()).Block(
+					cg.convertToDTO(f.Type, f.Type.Embedded, dst, jen.Op("*").Add(attr), 0)...,
+				),
+			)
+		default:
+			toREST = append(toREST, cg.convertToDTO(f.Type, f.Type.Embedded, dst, attr, 0)...)
+		}
+		if restReadonlyField(f) {
+			continue
+		}
+		src := jen.Id("in").Dot(fieldName)
+		setter := cg.b.GetMethodName(f, CGSetterMethod)
+		var set []jen.Code
+		switch {
+		case valueType:
+			set = []jen.Code{jen.Id("obj").Dot(setter).Call(jen.Op("*").Add(src))}
+		case cg.needsConversion(f.Type, f.Type.Embedded):
+			set = []jen.Code{jen.Var().Id("v").Add(cg.b.GoType(f.Type, f.Type.Embedded))}
+			set = append(set, cg.convertFromDTO(f.Type, f.Type.Embedded, jen.Id("v"), src, 0)...)
+			set = append(set, jen.Id("obj").Dot(setter).Call(jen.Id("v")))
+		default:
+			set = []jen.Code{jen.Id("obj").Dot(setter).Call(src)}
+		}
+		fromREST = append(fromREST, jen.If(src.Clone().Op("!=").Nil()).Block(set...))
+	}
+
+	cg.b.Types.Add(
+		jen.Commentf("%sREST is representation of %s in REST requests and responses", name, name).Line().
+			Type().Id(name + "REST").Struct(fields...).Line(),
+	)
+
+	cg.b.Functions.Add(
+		jen.Commentf("%sToREST returns representation of obj for REST response", name).Line().
+			Func().Parens(jen.Id(EngineVar).Op("*").Id("Engine")).Id(name+"ToREST").Params(
+			jen.Id("ctx").Qual("context", "Context"),
+			jen.Id("obj").Op("*").Id(name),
+		).Parens(jen.List(jen.Id("ret").Op("*").Id(name+"REST"), jen.Err().Error())).BlockFunc(
+			func(g *jen.Group) {
+				g.If(jen.Id("obj").Op("==").Nil()).Block(jen.Return(jen.Nil(), jen.Nil()))
+				g.Id("ret").Op("=").Op("&").Id(name + "REST").Values()
+				for _, c := range toREST {
+					g.Add(c)
+				}
+				g.Return(jen.Id("ret"), jen.Nil())
+			},
+		).Line(),
+	)
+
+	cg.b.Functions.Add(
+		jen.Commentf("%sListToREST returns representation of list for REST response", name).Line().
+			Func().Parens(jen.Id(EngineVar).Op("*").Id("Engine")).Id(name+"ListToREST").Params(
+			jen.Id("ctx").Qual("context", "Context"),
+			jen.Id("list").Index().Op("*").Id(name),
+		).Parens(jen.List(jen.Index().Op("*").Id(name+"REST"), jen.Error())).Block(
+			jen.Id("ret").Op(":=").Make(jen.Index().Op("*").Id(name+"REST"), jen.Len(jen.Id("list"))),
+			jen.For(jen.List(jen.Id("i"), jen.Id("obj")).Op(":=").Range().Id("list")).Block(
+				jen.Var().Err().Error(),
+				jen.If(
+					jen.List(jen.Id("ret").Index(jen.Id("i")), jen.Err()).Op("=").Id(EngineVar).Dot(name+"ToREST").Call(jen.Id("ctx"), jen.Id("obj")),
+					jen.Err().Op("!=").Nil(),
+				).Block(jen.Return(jen.Nil(), jen.Err())),
+			),
+			jen.Return(jen.Id("ret"), jen.Nil()),
+		).Line(),
+	)
+
+	var initObj jen.Code
+	if t.HasModifier(TypeModifierAbstract) {
+		initObj = jen.Return(
+			jen.Nil(),
+			jen.Qual("errors", "New").Call(jen.Lit(fmt.Sprintf("%sFromREST: object of abstract type can not be created", name))),
+		)
+	} else {
+		initObj = jen.If(
+			jen.List(jen.Id("obj"), jen.Err()).Op("=").Id(EngineVar).Dot(cg.desc.GetMethodName(MethodInit, name)).Call(jen.Id("ctx")),
+			jen.Err().Op("!=").Nil(),
+		).Block(jen.Return(jen.Nil(), jen.Err()))
+	}
+	cg.b.Functions.Add(
+		jen.Commentf(
+			"%sFromREST sets obj (created if nil) from REST request in; absent values are kept, read-only fields are ignored",
+			name,
+		).Line().
+			Func().Parens(jen.Id(EngineVar).Op("*").Id("Engine")).Id(name+"FromREST").Params(
+			jen.Id("ctx").Qual("context", "Context"),
+			jen.Id("in").Op("*").Id(name+"REST"),
+			jen.Id("obj").Op("*").Id(name),
+		).Parens(jen.List(jen.Id("ret").Op("*").Id(name), jen.Err().Error())).BlockFunc(
+			func(g *jen.Group) {
+				g.If(jen.Id("obj").Op("==").Nil()).Block(initObj)
+				g.If(jen.Id("in").Op("==").Nil()).Block(jen.Return(jen.Id("obj"), jen.Nil()))
+				for _, c := range fromREST {
+					g.Add(c)
+				}
+				g.Return(jen.Id("obj"), jen.Nil())
+			},
+		).Line(),
+	)
+	return nil
+}
+
+// needsConversion returns true if value of ref contains nested objects and so should be converted to (from) DTO
+func (cg *RESTGenerator) needsConversion(ref *TypeRef, embedded bool) bool {
+	if ref.Array != nil {
+		return cg.needsConversion(ref.Array, ref.Embedded)
+	}
+	return cg.nestedEntity(ref, embedded) != nil
+}
+
+// convertToDTO returns statements that set dst to REST representation of src of type ref
+func (cg *RESTGenerator) convertToDTO(ref *TypeRef, embedded bool, dst, src *jen.Statement, depth int) []jen.Code {
+	if !cg.needsConversion(ref, embedded) {
+		return []jen.Code{dst.Clone().Op("=").Add(src)}
+	}
+	if e := cg.nestedEntity(ref, embedded); e != nil {
+		return []jen.Code{
+			jen.If(
+				jen.List(dst.Clone(), jen.Err()).Op("=").Add(cg.desc.GetTypeEngineAccessor(e)).Dot(e.Name+"ToREST").Call(jen.Id("ctx"), src),
+				jen.Err().Op("!=").Nil(),
+			).Block(jen.Return(jen.Nil(), jen.Err())),
+		}
+	}
+	idx := fmt.Sprintf("i%d", depth)
+	item := fmt.Sprintf("v%d", depth)
+	return []jen.Code{
+		jen.If(src.Clone().Op("!=").Nil()).Block(
+			dst.Clone().Op("=").Make(cg.dtoType(ref, embedded), jen.Len(src)),
+			jen.For(jen.List(jen.Id(idx), jen.Id(item)).Op(":=").Range().Add(src)).Block(
+				cg.convertToDTO(ref.Array, ref.Embedded, dst.Clone().Index(jen.Id(idx)), jen.Id(item), depth+1)...,
+			),
+		),
+	}
+}
+
+// convertFromDTO returns statements that set dst to value of type ref from its REST representation src
+func (cg *RESTGenerator) convertFromDTO(ref *TypeRef, embedded bool, dst, src *jen.Statement, depth int) []jen.Code {
+	if !cg.needsConversion(ref, embedded) {
+		return []jen.Code{dst.Clone().Op("=").Add(src)}
+	}
+	if e := cg.nestedEntity(ref, embedded); e != nil {
+		return []jen.Code{
+			jen.If(
+				jen.List(dst.Clone(), jen.Err()).Op("=").Add(cg.desc.GetTypeEngineAccessor(e)).Dot(e.Name+"FromREST").Call(
+					jen.Id("ctx"),
+					src,
+					jen.Nil(),
+				),
+				jen.Err().Op("!=").Nil(),
+			).Block(jen.Return(jen.Nil(), jen.Err())),
+		}
+	}
+	idx := fmt.Sprintf("i%d", depth)
+	item := fmt.Sprintf("v%d", depth)
+	return []jen.Code{
+		dst.Clone().Op("=").Make(cg.b.GoType(ref, embedded), jen.Len(src)),
+		jen.For(jen.List(jen.Id(idx), jen.Id(item)).Op(":=").Range().Add(src)).Block(
+			cg.convertFromDTO(ref.Array, ref.Embedded, dst.Clone().Index(jen.Id(idx)), jen.Id(item), depth+1)...,
+		),
+	}
+}
@@ -0,0 +1,630 @@
+package gen
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const (
+	restGeneratorName = "REST"
+	restOptionsName   = "rest"
+
+	// RESTAnnotation may be used for types: $rest(path="orders") sets the resource path segment;
+	// $rest(skip) excludes type from REST API
+	RESTAnnotation        = "rest"
+	RESTAnnotationPathTag = "path"
+	RESTAnnotationSkipTag = "skip"
+
+	RESTFeatures = "rest"
+	// RESTFPath - string; for *Entity; path of the resource (e.g. /shop/orders)
+	RESTFPath = "path"
+
+	restEngineVarName   = "restEngine"
+	restDefaultSpecName = "openapi.yaml"
+)
+
+type RESTOptions struct {
+	// OpenAPIFile - path of generated OpenAPI spec; <output>/<package>/openapi.yaml by default
+	OpenAPIFile string `json:"openapi_file"`
+	// Title and Version for info section of the spec
+	Title   string `json:"title"`
+	Version string `json:"version"`
+	// ServerURL - URL for servers section of the spec (e.g. "/api", the prefix given to RESTEngine.HTTPHandler)
+	ServerURL string `json:"server_url"`
+}
+
+// RESTGenerator generates net/http handlers for the operations generated by GQLGenerator and registers them
+// in vivard.RESTEngine; bodies are generated <Type>REST DTOs with the same fields as GraphQL types have
+// and OpenAPI 3 spec is written for each package
+type RESTGenerator struct {
+	proj    *Project
+	desc    *Package
+	b       *Builder
+	options RESTOptions
+	// specGenerated - packages for which spec is written
+	specGenerated map[string]bool
+}
+
+// restOperation describes route for GQL operation
+type restOperation struct {
+	kind    GQLOperationKind
+	name    string
+	method  string
+	pattern string
+}
+
+func init() {
+	RegisterPluginFactory(func() Generator { return &RESTGenerator{} })
+}
+
+func (cg *RESTGenerator) Name() string {
+	return restGeneratorName
+}
+
+// Requires from PluginsRequirer
+func (cg *RESTGenerator) Requires() []string {
+	return []string{GQLGeneratorName}
+}
+
+func (cg *RESTGenerator) SetOptions(opts any) error {
+	return OptionsAnyToStruct(opts, &cg.options)
+}
+
+func (cg *RESTGenerator) SetDescriptor(proj *Project) {
+	cg.proj = proj
+	cg.specGenerated = map[string]bool{}
+	if _, err := proj.Options.CustomToStruct(restOptionsName, &cg.options); err != nil {
+		proj.AddWarning(fmt.Sprintf("problem while setting custom options for %s: %v", restOptionsName, err))
+	}
+}
+
+func (cg *RESTGenerator) CheckAnnotation(desc *Package, ann *Annotation, item interface{}) (bool, error) {
+	if ann.Name == RESTAnnotation {
+		if _, ok := item.(*Entity); !ok {
+			return true, fmt.Errorf("at %v: annotation %s may be used for types only", ann.Pos, RESTAnnotation)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// AnnotationSchemas from AnnotationsDescriber
+func (cg *RESTGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    RESTAnnotation,
+			Targets: AnnotationTargetEntity,
+			Tags: []AnnotationTagSchema{
+				{Name: RESTAnnotationPathTag, Type: AnnotationTagString, Doc: "path segment of the resource"},
+				{Name: RESTAnnotationSkipTag, Type: AnnotationTagBool, Default: false, Doc: "do not generate REST resource"},
+			},
+			AnyTags: true,
+			Doc:     "REST resource settings of type",
+		},
+	}
+}
+
+func (cg *RESTGenerator) Prepare(desc *Package) error {
+	cg.desc = desc
+	for _, file := range desc.Files {
+		for _, t := range file.Entries {
+			if t.Annotations.GetBoolAnnotationDef(RESTAnnotation, RESTAnnotationSkipTag, false) {
+				continue
+			}
+			name := strings.ToLower(t.Name[:1]) + t.Name[1:]
+			if !t.HasModifier(TypeModifierConfig) {
+				name = restPlural(name)
+			}
+			name = t.Annotations.GetStringAnnotationDef(RESTAnnotation, RESTAnnotationPathTag, name)
+			t.Features.Set(RESTFeatures, RESTFPath, fmt.Sprintf("/%s/%s", desc.Name, strings.Trim(name, "/")))
+		}
+	}
+	return nil
+}
+
+func (cg *RESTGenerator) Generate(b *Builder) (err error) {
+	cg.desc = b.Descriptor
+	cg.b = b
+	first := true
+	for _, t := range b.File.Entries {
+		if restHasDTO(t) {
+			if err = cg.generateDTO(t); err != nil {
+				return err
+			}
+		}
+		ops := cg.getOperations(t)
+		if len(ops) == 0 {
+			continue
+		}
+		if first {
+			b.Generator.Id(restEngineVarName).Op(":=").Id(EngineVar).Dot(EngineVivard).Dot("GetService").Params(
+				jen.Qual(VivardPackage, "ServiceREST"),
+			).Assert(jen.Op("*").Qual(VivardPackage, "RESTEngine")).Line()
+			first = false
+		}
+		for _, op := range ops {
+			fname, err := cg.generateHandler(t, op)
+			if err != nil {
+				return err
+			}
+			b.Generator.Id(restEngineVarName).Dot("AddRoute").Params(
+				jen.Qual("net/http", "Method"+op.method[:1]+strings.ToLower(op.method[1:])),
+				jen.Lit(op.pattern),
+				jen.Lit(op.name),
+				jen.Id(EngineVar).Dot(fname).Call(jen.Id(restEngineVarName)),
+			).Line()
+		}
+	}
+	if !cg.specGenerated[b.Descriptor.Name] {
+		cg.specGenerated[b.Descriptor.Name] = true
+		return cg.writeSpec(b.Descriptor)
+	}
+	return nil
+}
+
+// getOperations returns routes for the operations generated by GQLGenerator for the type
+func (cg *RESTGenerator) getOperations(t *Entity) (ops []restOperation) {
+	path := t.FS(RESTFeatures, RESTFPath)
+	if path == "" {
+		return nil
+	}
+	if level, ok := t.Features.GetString(FeaturesAPIKind, FAPILevel); ok && level != FAPILAll {
+		return nil
+	}
+	add := func(kind GQLOperationKind, method string, pattern string) {
+		if name, ok := t.Features.GetString(GQLFeatures, GQLOperationsAnnotationsTags[kind]); ok {
+			ops = append(ops, restOperation{kind: kind, name: name, method: method, pattern: pattern})
+		}
+	}
+	readonly := t.FB(FeaturesCommonKind, FCReadonly)
+	if t.HasModifier(TypeModifierConfig) {
+		add(GQLOperationGet, http.MethodGet, path)
+		add(GQLOperationSet, http.MethodPut, path)
+		return
+	}
+	idField := t.GetIdField()
+	if idField == nil {
+		return nil
+	}
+	if idField.Type.Type != TipInt && idField.Type.Type != TipString {
+		cg.desc.AddWarning(
+			fmt.Sprintf("at %v: REST: id of type %s is not supported; type %s is skipped", t.Pos, idField.Type.Type, t.Name),
+		)
+		return nil
+	}
+	itemPath := path + "/{id}"
+	add(GQLOperationLookup, http.MethodGet, path+"/lookup")
+	if _, ok := t.Features.GetEntity(FeaturesAPIKind, FAPIFindParamType); ok {
+		add(GQLOperationFind, http.MethodPost, path+"/find")
+	}
+	if t.IsDictionary() {
+		add(GQLOperationList, http.MethodGet, path)
+	}
+	add(GQLOperationGet, http.MethodGet, itemPath)
+	if !readonly {
+		add(GQLOperationSet, http.MethodPut, itemPath)
+		if !t.HasModifier(TypeModifierAbstract) {
+			add(GQLOperationCreate, http.MethodPost, path)
+		}
+		add(GQLOperationDelete, http.MethodDelete, itemPath)
+		if t.FB(FeatGoKind, FCGBulkNew) && !t.HasModifier(TypeModifierAbstract) {
+			add(GQLOperationBulkCreate, http.MethodPost, path+"/bulk")
+		}
+		if t.FB(FeatGoKind, FCGBulkSet) {
+			add(GQLOperationBulkSet, http.MethodPut, path+"/bulk")
+		}
+	}
+	return
+}
+
+// generateHandler generates Engine's method that returns http.HandlerFunc for operation
+func (cg *RESTGenerator) generateHandler(t *Entity, op restOperation) (fname string, err error) {
+	name := t.Name
+	isCfg := t.HasModifier(TypeModifierConfig)
+	var body []jen.Code
+	switch op.kind {
+	case GQLOperationGet:
+		fname = fmt.Sprintf("%sRESTGetHandler", name)
+		if isCfg {
+			body = append(
+				body,
+				jen.List(jen.Id("obj"), jen.Err()).Op(":=").Id(EngineVar).Dot(cg.desc.GetMethodName(MethodGet, name)).Call(
+					jen.Id("r").Dot("Context").Call(),
+				),
+			)
+		} else {
+			body = append(body, cg.parseID(t)...)
+			body = append(
+				body,
+				jen.List(jen.Id("obj"), jen.Err()).Op(":=").Id(EngineVar).Dot(cg.desc.GetMethodName(MethodGet, name)).Call(
+					jen.Id("r").Dot("Context").Call(),
+					jen.Id("id"),
+				),
+			)
+		}
+		body = append(body, restReturnIfErr(), restReturnIfNotFound())
+		body = append(body, cg.writeObject(t)...)
+	case GQLOperationSet:
+		fname = fmt.Sprintf("%sRESTSetHandler", name)
+		if isCfg {
+			body = append(
+				body,
+				jen.List(jen.Id("obj"), jen.Err()).Op(":=").Id(EngineVar).Dot(cg.desc.GetMethodName(MethodGet, name)).Call(
+					jen.Id("r").Dot("Context").Call(),
+				),
+				restReturnIfErr(),
+			)
+			body = append(body, cg.decodeObject(t, jen.Id("obj"))...)
+		} else {
+			idField := t.GetIdField()
+			id := jen.Id("id")
+			if idField.FB(FeatGoKind, FCGPointer) {
+				id = jen.Op("&").Id("id")
+			}
+			body = append(body, cg.parseID(t)...)
+			body = append(
+				body,
+				jen.List(jen.Id("obj"), jen.Err()).Op(":=").Id(EngineVar).Dot(cg.desc.GetMethodName(MethodGet, name)).Call(
+					jen.Id("r").Dot("Context").Call(),
+					jen.Id("id"),
+				),
+				restReturnIfErr(),
+				restReturnIfNotFound(),
+			)
+			body = append(body, cg.decodeObject(t, jen.Id("obj"))...)
+			body = append(
+				body,
+				jen.Comment("id from path takes precedence over the one from body"),
+				jen.Id("obj").Dot(idField.FS(FeatGoKind, FCGName)).Op("=").Add(id),
+			)
+		}
+		body = append(
+			body,
+			jen.List(jen.Id("obj"), jen.Err()).Op("=").Id(EngineVar).Dot(cg.desc.GetMethodName(MethodSet, name)).Call(
+				jen.Id("r").Dot("Context").Call(),
+				jen.Id("obj"),
+			),
+			restReturnIfErr(),
+		)
+		body = append(body, cg.writeObject(t)...)
+	case GQLOperationCreate:
+		fname = fmt.Sprintf("%sRESTCreateHandler", name)
+		body = append(body, jen.Var().Id("obj").Op("*").Id(name), jen.Var().Err().Error())
+		body = append(body, cg.decodeObject(t, jen.Id("obj"))...)
+		body = append(
+			body,
+			jen.List(jen.Id("obj"), jen.Err()).Op("=").Id(EngineVar).Dot(cg.desc.GetMethodName(MethodNew, name)).Call(
+				jen.Id("r").Dot("Context").Call(),
+				jen.Id("obj"),
+			),
+			restReturnIfErr(),
+		)
+		body = append(body, cg.writeObject(t)...)
+	case GQLOperationDelete:
+		fname = fmt.Sprintf("%sRESTDeleteHandler", name)
+		body = append(body, cg.parseID(t)...)
+		body = append(
+			body,
+			jen.Err().Op("=").Id(EngineVar).Dot(cg.desc.GetMethodName(MethodDelete, name)).Call(
+				jen.Id("r").Dot("Context").Call(),
+				jen.Id("id"),
+			),
+			restReturnIfErr(),
+			restWriteResult(jen.True()),
+		)
+	case GQLOperationList:
+		fname = fmt.Sprintf("%sRESTListHandler", name)
+		body, err = cg.listHandlerBody(t)
+		if err != nil {
+			return
+		}
+	case GQLOperationLookup:
+		fname = fmt.Sprintf("%sRESTLookupHandler", name)
+		body = append(
+			body,
+			jen.List(jen.Id("list"), jen.Err()).Op(":=").Id(EngineVar).Dot(cg.desc.GetMethodName(MethodLookup, name)).Call(
+				jen.Id("r").Dot("Context").Call(),
+				jen.Id("r").Dot("URL").Dot("Query").Call().Dot("Get").Call(jen.Lit("query")),
+			),
+			restReturnIfErr(),
+		)
+		body = append(body, cg.writeList(t)...)
+	case GQLOperationFind:
+		fname = fmt.Sprintf("%sRESTFindHandler", name)
+		it, _ := t.Features.GetEntity(FeaturesAPIKind, FAPIFindParamType)
+		body = append(body, jen.Var().Id("query").Op("*").Add(cg.qualifiedName(it)), jen.Var().Err().Error())
+		body = append(body, cg.decodeObject(it, jen.Id("query"))...)
+		body = append(
+			body,
+			jen.List(jen.Id("list"), jen.Err()).Op(":=").Id(EngineVar).Dot(cg.desc.GetMethodName(MethodFind, name)).Call(
+				jen.Id("r").Dot("Context").Call(),
+				jen.Id("query"),
+			),
+			restReturnIfErr(),
+		)
+		body = append(body, cg.writeList(t)...)
+	case GQLOperationBulkCreate:
+		fname = fmt.Sprintf("%sRESTBulkCreateHandler", name)
+		body = cg.bulkHandlerBody(t, MethodNewBulk)
+	case GQLOperationBulkSet:
+		fname = fmt.Sprintf("%sRESTBulkSetHandler", name)
+		body = cg.bulkHandlerBody(t, MethodSetBulk)
+	}
+	cg.b.Functions.Add(
+		jen.Func().Parens(jen.Id(EngineVar).Op("*").Id("Engine")).Id(fname).Params(
+			jen.Id("re").Op("*").Qual(VivardPackage, "RESTEngine"),
+		).Qual("net/http", "HandlerFunc").Block(
+			jen.Return(
+				jen.Func().Params(
+					jen.Id("w").Qual("net/http", "ResponseWriter"),
+					jen.Id("r").Op("*").Qual("net/http", "Request"),
+				).Block(body...),
+			),
+		).Line(),
+	)
+	return
+}
+
+// parseID returns statements that define id var from path param
+func (cg *RESTGenerator) parseID(t *Entity) []jen.Code {
+	param := jen.Qual(VivardPackage, "RESTParam").Call(jen.Id("r"), jen.Lit("id"))
+	if t.GetIdField().Type.Type == TipString {
+		return []jen.Code{jen.Id("id").Op(":=").Add(param), jen.Var().Err().Error()}
+	}
+	return []jen.Code{
+		jen.List(jen.Id("id"), jen.Err()).Op(":=").Qual("strconv", "Atoi").Call(param),
+		jen.If(jen.Err().Op("!=").Nil()).Block(
+			jen.Id("re").Dot("WriteError").Call(jen.Id("w"), jen.Qual(VivardPackage, "RESTBadRequest").Call(jen.Err())),
+			jen.Return(),
+		),
+	}
+}
+
+// listHandlerBody returns body of list handler; qualified dictionaries accept qualifiers as repeated query param 'qual'
+func (cg *RESTGenerator) listHandlerBody(t *Entity) (body []jen.Code, err error) {
+	name := t.Name
+	listMethod := MethodList
+	if t.HasModifier(TypeModifierDictionary) {
+		listMethod = MethodGetAll
+	}
+	listCall := func(args ...jen.Code) jen.Code {
+		return jen.List(jen.Id("list"), jen.Err()).Op(":=").Id(EngineVar).Dot(cg.desc.GetMethodName(listMethod, name)).Call(
+			append([]jen.Code{jen.Id("r").Dot("Context").Call()}, args...)...,
+		)
+	}
+	if listMethod == MethodGetAll && t.FB(FeatureDictKind, FDQualified) {
+		qt, _ := t.Features.GetEntity(FeatureDictKind, FDQualifierType)
+		var parse []jen.Code
+		switch qt.GetIdField().Type.Type {
+		case TipInt:
+			parse = []jen.Code{
+				jen.List(jen.Id("q"), jen.Err()).Op(":=").Qual("strconv", "Atoi").Call(jen.Id("s")),
+				jen.If(jen.Err().Op("!=").Nil()).Block(
+					jen.Id("re").Dot("WriteError").Call(jen.Id("w"), jen.Qual(VivardPackage, "RESTBadRequest").Call(jen.Err())),
+					jen.Return(),
+				),
+				jen.Id("quals").Index(jen.Id("i")).Op("=").Id("q"),
+			}
+		case TipString:
+			parse = []jen.Code{jen.Id("quals").Index(jen.Id("i")).Op("=").Id("s")}
+		default:
+			return nil, fmt.Errorf("at %s: only dicts with id field of type int and string may be used as qualifier", t.Pos)
+		}
+		body = append(
+			body,
+			jen.Id("params").Op(":=").Id("r").Dot("URL").Dot("Query").Call().Index(jen.Lit("qual")),
+			jen.Id("quals").Op(":=").Make(jen.Index().Add(cg.b.GoType(qt.GetIdField().Type)), jen.Len(jen.Id("params"))),
+			jen.For(jen.List(jen.Id("i"), jen.Id("s")).Op(":=").Range().Id("params")).Block(parse...),
+			listCall(jen.Id("quals").Op("...")),
+		)
+	} else {
+		body = append(body, listCall())
+	}
+	body = append(body, restReturnIfErr())
+	body = append(body, cg.writeList(t)...)
+	return
+}
+
+// bulkHandlerBody returns body of bulk handler with the same filters as GraphQL mutation has
+func (cg *RESTGenerator) bulkHandlerBody(t *Entity, method MethodKind) (body []jen.Code) {
+	name := t.Name
+	body = append(body, jen.Var().Id("vals").Index().Qual("encoding/json", "RawMessage"))
+	body = append(body, restDecodeBody(jen.Op("&").Id("vals")))
+	body = append(body, jen.Var().Err().Error())
+	if t.FB(FeatGoKind, FCGBulkFilterRaw) {
+		// raw filter gets the values in the same form as GraphQL resolver does
+		body = append(
+			body,
+			jen.Id("raw").Op(":=").Make(jen.Index().Any(), jen.Len(jen.Id("vals"))),
+			jen.For(jen.List(jen.Id("i"), jen.Id("val")).Op(":=").Range().Id("vals")).Block(
+				jen.If(
+					jen.Err().Op("=").Qual("encoding/json", "Unmarshal").Call(jen.Id("val"), jen.Op("&").Id("raw").Index(jen.Id("i"))),
+					jen.Err().Op("!=").Nil(),
+				).Block(
+					jen.Id("re").Dot("WriteError").Call(jen.Id("w"), jen.Qual(VivardPackage, "RESTBadRequest").Call(jen.Err())),
+					jen.Return(),
+				),
+			),
+			jen.List(jen.Id("raw"), jen.Err()).Op("=").Id(EngineVar).Dot(fmt.Sprintf("%sBulkFilterRaw", name)).Call(
+				jen.Id("r").Dot("Context").Call(),
+				jen.Id("raw"),
+			),
+			restReturnIfErr(),
+			jen.Id("vals").Op("=").Id("vals").Index(jen.Op(":").Lit(0)),
+			jen.For(jen.List(jen.Id("_"), jen.Id("val")).Op(":=").Range().Id("raw")).Block(
+				jen.List(jen.Id("data"), jen.Err()).Op(":=").Qual("encoding/json", "Marshal").Call(jen.Id("val")),
+				restReturnIfErr(),
+				jen.Id("vals").Op("=").Append(jen.Id("vals"), jen.Id("data")),
+			),
+		)
+	}
+	idxVar := "_"
+	if t.FB(FeatGoKind, FCGBulkFilterEach) {
+		idxVar = "idx"
+	}
+	body = append(
+		body,
+		jen.Id("objs").Op(":=").Make(jen.Index().Op("*").Id(name), jen.Lit(0), jen.Len(jen.Id("vals"))),
+		jen.For(jen.List(jen.Id(idxVar), jen.Id("val")).Op(":=").Range().Id("vals")).BlockFunc(
+			func(g *jen.Group) {
+				g.Id("in").Op(":=").Op("&").Id(name + "REST").Values()
+				g.If(
+					jen.Err().Op("=").Qual("encoding/json", "Unmarshal").Call(jen.Id("val"), jen.Id("in")),
+					jen.Err().Op("!=").Nil(),
+				).Block(
+					jen.Id("re").Dot("WriteError").Call(jen.Id("w"), jen.Qual(VivardPackage, "RESTBadRequest").Call(jen.Err())),
+					jen.Return(),
+				)
+				g.List(jen.Id("obj"), jen.Err()).Op(":=").Id(EngineVar).Dot(name+"FromREST").Call(
+					jen.Id("r").Dot("Context").Call(),
+					jen.Id("in"),
+					jen.Nil(),
+				)
+				g.Add(restReturnIfErr())
+				if t.FB(FeatGoKind, FCGBulkFilterEach) {
+					g.List(jen.Id("obj"), jen.Err()).Op("=").Id(EngineVar).Dot(fmt.Sprintf("%sBulkFilterEach", name)).Call(
+						jen.Id("r").Dot("Context").Call(),
+						jen.Id("obj"),
+						jen.Id("idx"),
+						jen.Len(jen.Id("vals")),
+					)
+					g.Add(restReturnIfErr())
+					g.If(jen.Id("obj").Op("==").Nil()).Block(jen.Continue())
+				}
+				g.Id("objs").Op("=").Append(jen.Id("objs"), jen.Id("obj"))
+			},
+		),
+	)
+	if t.FB(FeatGoKind, FCGBulkFilterAll) {
+		body = append(
+			body,
+			jen.List(jen.Id("objs"), jen.Err()).Op("=").Id(EngineVar).Dot(fmt.Sprintf("%sBulkFilterAll", name)).Call(
+				jen.Id("r").Dot("Context").Call(),
+				jen.Id("objs"),
+			),
+			restReturnIfErr(),
+		)
+	}
+	body = append(
+		body,
+		jen.If(jen.Len(jen.Id("objs")).Op("==").Lit(0)).Block(
+			restWriteResult(jen.Index().Op("*").Id(name+"REST").Values()),
+			jen.Return(),
+		),
+		jen.List(jen.Id("list"), jen.Err()).Op(":=").Id(EngineVar).Dot(cg.desc.GetMethodName(method, name)).Call(
+			jen.Id("r").Dot("Context").Call(),
+			jen.Id("objs"),
+		),
+		restReturnIfErr(),
+	)
+	body = append(body, cg.writeList(t)...)
+	return
+}
+
+// qualifiedName returns name of type t (qualified if t is from another package)
+func (cg *RESTGenerator) qualifiedName(t *Entity) jen.Code {
+	if t.Pckg != cg.desc {
+		return jen.Qual(t.Pckg.fullPackage, t.Name)
+	}
+	return jen.Id(t.Name)
+}
+
+// decodeObject returns statements that decode request body to DTO of t and set obj from it
+// (obj is created if it is nil)
+func (cg *RESTGenerator) decodeObject(t *Entity, obj *jen.Statement) []jen.Code {
+	return []jen.Code{
+		jen.Id("in").Op(":=").Op("&").Add(cg.dtoName(t)).Values(),
+		restDecodeBody(jen.Id("in")),
+		jen.List(obj.Clone(), jen.Err()).Op("=").Add(cg.desc.GetTypeEngineAccessor(t)).Dot(t.Name+"FromREST").Call(
+			jen.Id("r").Dot("Context").Call(),
+			jen.Id("in"),
+			obj,
+		),
+		restReturnIfErr(),
+	}
+}
+
+// writeObject returns statements that write obj as DTO of t
+func (cg *RESTGenerator) writeObject(t *Entity) []jen.Code {
+	return []jen.Code{
+		jen.List(jen.Id("res"), jen.Err()).Op(":=").Id(EngineVar).Dot(t.Name+"ToREST").Call(
+			jen.Id("r").Dot("Context").Call(),
+			jen.Id("obj"),
+		),
+		restReturnIfErr(),
+		restWriteResult(jen.Id("res")),
+	}
+}
+
+// writeList returns statements that write list as DTOs of t
+func (cg *RESTGenerator) writeList(t *Entity) []jen.Code {
+	return []jen.Code{
+		jen.List(jen.Id("res"), jen.Err()).Op(":=").Id(EngineVar).Dot(t.Name+"ListToREST").Call(
+			jen.Id("r").Dot("Context").Call(),
+			jen.Id("list"),
+		),
+		restReturnIfErr(),
+		restWriteResult(jen.Id("res")),
+	}
+}
+
+func (cg *RESTGenerator) writeSpec(desc *Package) error {
+	spec, err := cg.openAPISpec(desc)
+	if err != nil {
+		return err
+	}
+	if spec == nil {
+		return nil
+	}
+	fileName := cg.options.OpenAPIFile
+	if fileName == "" {
+		fileName = filepath.Join(cg.proj.Options.OutputDir, desc.Name, restDefaultSpecName)
+	} else if strings.Contains(fileName, "%s") {
+		fileName = fmt.Sprintf(fileName, desc.Name)
+	}
+	return cg.proj.WriteFile(fileName, spec.yaml())
+}
+
+func restReturnIfErr() jen.Code {
+	return jen.If(jen.Err().Op("!=").Nil()).Block(
+		jen.Id("re").Dot("WriteError").Call(jen.Id("w"), jen.Err()),
+		jen.Return(),
+	)
+}
+
+func restReturnIfNotFound() jen.Code {
+	return jen.If(jen.Id("obj").Op("==").Nil()).Block(
+		jen.Id("re").Dot("WriteError").Call(jen.Id("w"), jen.Qual(VivardPackage, "ErrItemNotFound")),
+		jen.Return(),
+	)
+}
+
+func restDecodeBody(val jen.Code) jen.Code {
+	return jen.If(
+		jen.Err().Op(":=").Qual(VivardPackage, "RESTDecodeBody").Call(jen.Id("r"), val),
+		jen.Err().Op("!=").Nil(),
+	).Block(
+		jen.Id("re").Dot("WriteError").Call(jen.Id("w"), jen.Err()),
+		jen.Return(),
+	)
+}
+
+func restWriteResult(val jen.Code) jen.Code {
+	return jen.Id("re").Dot("WriteResult").Call(jen.Id("w"), val)
+}
+
+// restPlural returns plural form of (English) noun for resource path
+func restPlural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsAny(name[len(name)-2:len(name)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"),
+		strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
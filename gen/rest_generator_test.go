@@ -0,0 +1,126 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+const restTestSource = `package shop;
+
+embeddable type Address {
+  city: string;
+  zip: string!;
+}
+
+type Category {
+  categoryID: int <id>;
+  title: string!;
+}
+
+type Order {
+  orderID: int <id>;
+  name: string!;
+  note: string;
+  amount: decimal;
+  placed: date;
+  tags: [string];
+  category: Category;
+  addr: Address <embedded>;
+  addrs: [Address] <embedded>;
+  avatar: bytes;
+  total: int <calculated @resolve>;
+}
+`
+
+var restTestPlugins = []string{GQLGeneratorName, nocacheGeneratorName, mongoGeneratorName, restGeneratorName}
+
+func TestRESTDTO(t *testing.T) {
+	files := generateTest(t, restTestSource, restTestPlugins...)
+	src := files["shop/test.go"]
+	assertContains(
+		t, src,
+		"OrderID *int `json:\"orderID,omitempty\"`",
+		"Amount *vivard.Decimal `json:\"amount,omitempty\"`",
+		"Category *int `json:\"category,omitempty\"`",
+		"Addr *AddressREST `json:\"addr,omitempty\"`",
+		"Addrs []*AddressREST `json:\"addrs,omitempty\"`",
+		"Avatar []byte `json:\"avatar,omitempty\"`",
+		"if obj.avatar != nil { ret.Avatar = *obj.avatar }",
+		"Total *int `json:\"total,omitempty\"`",
+		"in := &OrderREST{}",
+		"obj, err = eng.OrderFromREST(r.Context(), in, obj)",
+		"res, err := eng.OrderToREST(r.Context(), obj)",
+	)
+	if strings.Contains(src, "in.Total") {
+		t.Error("calculated field is accepted in REST request")
+	}
+	assertContains(
+		t, files["shop/openapi.yaml"],
+		"total: type: integer nullable: true readOnly: true",
+	)
+}
+
+// TestRESTDTORoundTrip compiles generated package and checks that objects are converted from and to JSON bodies
+// with GraphQL names of fields through the setters
+func TestRESTDTORoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles generated code")
+	}
+	src := strings.Replace(restTestSource, "  total: int <calculated @resolve>;\n", "", 1)
+	runGenerated(t, src, restTestPlugins, "shop", restRoundTripMain)
+}
+
+const restRoundTripMain = `package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"PREFIX/shop"
+)
+
+func main() {
+	ctx := context.Background()
+	eng := &shop.Engine{}
+	body := "{\"orderID\":7,\"name\":\"tea\",\"amount\":\"1.50\",\"tags\":[\"green\"],\"category\":3," +
+		"\"addr\":{\"city\":\"Paris\",\"zip\":\"75001\"},\"addrs\":[{\"zip\":\"1\"},{\"zip\":\"2\"}],\"avatar\":\"aGk=\"}"
+	in := &shop.OrderREST{}
+	check(json.Unmarshal([]byte(body), in))
+	obj, err := eng.OrderFromREST(ctx, in, nil)
+	check(err)
+	if obj.GetOrderID() != 7 || obj.GetName() != "tea" || obj.GetAmount().String() != "1.50" ||
+		obj.GetCategory() != 3 || obj.GetAddr().GetCity() != "Paris" || len(obj.GetAddrs()) != 2 ||
+		obj.GetAddrs()[1].GetZip() != "2" || string(obj.GetAvatar()) != "hi" || !obj.IsNoteNull() {
+		fail("object is not set from body: %+v", obj)
+	}
+	// absent values are kept
+	note := "fresh"
+	obj, err = eng.OrderFromREST(ctx, &shop.OrderREST{Note: &note}, obj)
+	check(err)
+	if obj.GetNote() != note || obj.GetName() != "tea" {
+		fail("partial update: %+v", obj)
+	}
+	res, err := eng.OrderToREST(ctx, obj)
+	check(err)
+	data, err := json.Marshal(res)
+	check(err)
+	want := "{\"orderID\":7,\"name\":\"tea\",\"note\":\"fresh\",\"amount\":\"1.50\",\"tags\":[\"green\"],\"category\":3," +
+		"\"addr\":{\"city\":\"Paris\",\"zip\":\"75001\"},\"addrs\":[{\"zip\":\"1\"},{\"zip\":\"2\"}],\"avatar\":\"aGk=\"}"
+	if string(data) != want {
+		fail("result:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func check(err error) {
+	if err != nil {
+		fail("%v", err)
+	}
+}
+
+func fail(format string, args ...any) {
+	fmt.Printf(format+"\n", args...)
+	os.Exit(1)
+}
+`
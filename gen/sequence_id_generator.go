@@ -27,7 +27,7 @@ type SequnceIDGenerator struct {
 }
 
 func init() {
-	RegisterPlugin(&SequnceIDGenerator{})
+	RegisterPluginFactory(func() Generator { return &SequnceIDGenerator{} })
 }
 
 func (cg *SequnceIDGenerator) Name() string {
@@ -65,7 +65,7 @@ type serviceDescriptor struct {
 }
 
 func init() {
-	RegisterPlugin(&ServiceGenerator{})
+	RegisterPluginFactory(func() Generator { return &ServiceGenerator{} })
 }
 
 func (cg *ServiceGenerator) Name() string {
@@ -111,6 +111,36 @@ func (cg *ServiceGenerator) CheckAnnotation(desc *Package, ann *Annotation, item
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (cg *ServiceGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    serviceAnnotation,
+			Targets: AnnotationTargetEntity,
+			Tags: []AnnotationTagSchema{
+				{Name: saName, Type: AnnotationTagString, Doc: "name of service"},
+			},
+			NameTag: saName,
+			AnyTags: true,
+			Doc:     "registers singleton as Engine's service",
+		},
+		{
+			Name:    serviceInjectAnnotation,
+			Targets: AnnotationTargetField,
+			Tags: []AnnotationTagSchema{
+				{Name: saName, Type: AnnotationTagString, Doc: "name of service"},
+				{Name: saAlias, Type: AnnotationTagString, Doc: "alias of service (name by default)"},
+				{Name: saPackage, Type: AnnotationTagString, Doc: "package of service type"},
+				{Name: saType, Type: AnnotationTagString, Doc: "type of service"},
+				{Name: saPointer, Type: AnnotationTagBool, Default: false, Doc: "service type is pointer"},
+			},
+			NameTag: saName,
+			AnyTags: true,
+			Doc:     "injects Engine's service into singleton's field",
+		},
+	}
+}
+
 func (cg *ServiceGenerator) Prepare(desc *Package) error {
 	cg.desc = desc
 	if cg.services == nil {
@@ -0,0 +1,102 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/participle/lexer"
+	"github.com/dave/jennifer/jen"
+)
+
+// SourcePositionsMode defines whether generated Go code refers to positions of its origin in DSL files
+type SourcePositionsMode int
+
+const (
+	// NoSourcePositions - generated code does not refer to DSL (default)
+	NoSourcePositions SourcePositionsMode = iota
+	// SourcePositionsComments - '// from file.vvf:NN' comment is added before declarations
+	SourcePositionsComments
+	// SourcePositionsLineDirectives - '/*line file.vvf:NN*/' directive is added before declarations,
+	// so compiler errors and go vet output for them point to DSL files
+	SourcePositionsLineDirectives
+)
+
+const (
+	lineDirectivePrefix       = "//line "
+	inlineLineDirectivePrefix = "/*line "
+)
+
+// SourcePos returns comment or line directive (see Opts.SourcePositions) that refers to pos in DSL file;
+// it should be added right before declaration (struct, field, func) generated from item at pos, after its doc comment.
+// Empty statement is returned if source positions are not required
+func (b *Builder) SourcePos(pos lexer.Position) *jen.Statement {
+	ret := &jen.Statement{}
+	if pos.Line == 0 {
+		return ret
+	}
+	switch b.Project.Options.SourcePositions {
+	case SourcePositionsComments:
+		ret.Commentf("from %s:%d", b.sourceFileRef(pos), pos.Line).Line()
+	case SourcePositionsLineDirectives:
+		// inline form is used as it is allowed anywhere in line (jen adds space after '//' comment that breaks directive);
+		// comment starting with '/*' is rendered by jen as is
+		ret.Comment(fmt.Sprintf("%s%s:%d*/", inlineLineDirectivePrefix, b.sourceFileRef(pos), pos.Line))
+	}
+	return ret
+}
+
+// sourceFileRef returns path of DSL file relative to directory of generated Go file (as line directive requires)
+func (b *Builder) sourceFileRef(pos lexer.Position) string {
+	src, err := filepath.Abs(pos.Filename)
+	if err != nil {
+		return filepath.ToSlash(pos.Filename)
+	}
+	dir, err := filepath.Abs(filepath.Join(b.Project.Options.OutputDir, b.Descriptor.Name))
+	if err != nil {
+		return filepath.ToSlash(src)
+	}
+	if rel, err := filepath.Rel(dir, src); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(src)
+}
+
+// restoreLinePositions adds line directives that return positions to generated file (named name)
+// after lines that refer to DSL with line directives: only the first line of declaration refers to DSL,
+// so errors in functions' bodies are reported at their positions in generated file.
+// Directives that were left by formatter on their own lines are replaced with '//line' ones
+func restoreLinePositions(src []byte, name string) []byte {
+	if !bytes.Contains(src, []byte(inlineLineDirectivePrefix)) {
+		return src
+	}
+	name = filepath.Base(name)
+	lines := strings.SplitAfter(string(src), "\n")
+	out := &strings.Builder{}
+	outLines := 0
+	write := func(line string) {
+		out.WriteString(line)
+		outLines++
+	}
+	isDirective := func(line string) bool {
+		return strings.HasPrefix(strings.TrimLeft(line, "\t"), inlineLineDirectivePrefix)
+	}
+	// refers is true if the next line refers to DSL
+	refers := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if isDirective(line) && strings.HasSuffix(trimmed, "*/") {
+			// '//line' directive sets position of the next line (it should be at the beginning of line)
+			write(lineDirectivePrefix + strings.TrimSuffix(trimmed[len(inlineLineDirectivePrefix):], "*/") + "\n")
+			refers = true
+			continue
+		}
+		write(line)
+		if (refers || isDirective(line)) && i+1 < len(lines) && lines[i+1] != "" && !isDirective(lines[i+1]) {
+			write(fmt.Sprintf("%s%s:%d\n", lineDirectivePrefix, name, outLines+2))
+		}
+		refers = false
+	}
+	return []byte(out.String())
+}
@@ -30,7 +30,7 @@ const ValidatorFuncNameTemplate = "Validate%s"
 const ValidatorOptionsName = "validatorGO"
 
 func init() {
-	RegisterPlugin(&Validator{options: ValidatorOptions{ValidateDictionaries: true}})
+	RegisterPluginFactory(func() Generator { return &Validator{options: ValidatorOptions{ValidateDictionaries: true}} })
 }
 
 func (cg *Validator) Name() string {
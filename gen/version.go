@@ -51,13 +51,15 @@ const (
 )
 
 func init() {
-	RegisterPlugin(
-		&VersionGenerator{
-			o: VersionOptions{
-				DefaultBehaviour: vaBehaviourWarning,
-				DefaultFieldName: VersionDefaultFieldName,
-				Scope:            vaTypeWise,
-			},
+	RegisterPluginFactory(
+		func() Generator {
+			return &VersionGenerator{
+				o: VersionOptions{
+					DefaultBehaviour: vaBehaviourWarning,
+					DefaultFieldName: VersionDefaultFieldName,
+					Scope:            vaTypeWise,
+				},
+			}
 		},
 	)
 }
@@ -66,6 +68,11 @@ func (vg *VersionGenerator) Name() string {
 	return versionGeneratorName
 }
 
+// Requires from PluginsRequirer
+func (vg *VersionGenerator) Requires() []string {
+	return []string{loggerGeneratorName, sequenceGeneratorName}
+}
+
 func (vg *VersionGenerator) SetOptions(options any) error {
 	if options != nil {
 		return OptionsAnyToStruct(options, &vg.o)
@@ -156,6 +163,28 @@ func (vg *VersionGenerator) CheckAnnotation(desc *Package, ann *Annotation, item
 	return false, nil
 }
 
+// AnnotationSchemas from AnnotationsDescriber
+func (vg *VersionGenerator) AnnotationSchemas() []AnnotationSchema {
+	return []AnnotationSchema{
+		{
+			Name:    versionAnnotation,
+			Targets: AnnotationTargetEntity,
+			Tags: []AnnotationTagSchema{
+				{Name: vaField, Type: AnnotationTagString, Default: vg.o.DefaultFieldName, Doc: "name of version field"},
+				{Name: vaBehaviourWarning, Type: AnnotationTagBool, Doc: "log saving of outdated version"},
+				{Name: vaBehaviourError, Type: AnnotationTagBool, Doc: "return error on saving of outdated version"},
+				{Name: vaBehaviourNothing, Type: AnnotationTagBool, Doc: "ignore saving of outdated version"},
+				{Name: vaTryMerge, Type: AnnotationTagBool, Default: vg.o.TryMerge, Doc: "check whether changes intersect (requires changes recording)"},
+				{Name: vaObjectWise, Type: AnnotationTagBool, Doc: "version is kept for every object"},
+				{Name: vaTypeWise, Type: AnnotationTagBool, Doc: "version is kept for the whole type"},
+				{Name: vaSequenceName, Type: AnnotationTagString, Default: "<Type>Version", Doc: "name of sequence for versions"},
+			},
+			AnyTags: true,
+			Doc:     "adds version field checked on save",
+		},
+	}
+}
+
 func (vg *VersionGenerator) Prepare(desc *Package) error {
 	//for _, file := range desc.Files {
 	//	for _, e := range file.Entries {
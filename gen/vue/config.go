@@ -16,36 +16,28 @@ const TabAsSpace = "  "
 func (ch *configHelper) generate() error {
 	tabs := ch.e.Annotations.GetBoolAnnotationDef(gen.AnnotationConfig, vueTabsAnnotation, false)
 	if tabs {
-		ch.parse(configTabsHTMLTemplate).
-			parse(vueConfigTabsTSTemplate)
+		ch.parse(ch.cg.flavor.configTabsHTML).
+			parse(ch.cg.flavor.configTabsTS)
 
 	} else {
-		ch.parse(configTreeHTMLTemplate).
-			parse(vueConfigTreeTSTemplate)
+		ch.parse(ch.cg.flavor.configTreeHTML).
+			parse(ch.cg.flavor.configTreeTS)
 	}
-	ch.parse(cssConfigTemplate).
-		parse(configListItemHTMLTemplate).
-		parse(configValueItemHTMLTemplate).
-		parse(configDictEditHTMLTemplate).
-		parse(configValueHTMLTemplate).
-		parse(vueConfigTSCommonTemplate)
+	ch.parse(ch.cg.flavor.configCommon...)
 	if ch.err != nil {
 		return fmt.Errorf("error while parsing config template: %v", ch.err)
 	}
 	p := ch.e.FS(featureVueKind, fVKConfComponentPath)
 	p = filepath.Join(ch.outDir, p)
 
-	f, err := os.Create(p)
-	if err != nil {
-		return fmt.Errorf("error opening file '%s': %v", p, err)
-	}
+	f := ch.cg.createFile(p)
 	defer f.Close()
 
 	ch.parse("<template>{{template \"TEMPL_CONFIG\" .}}</template>\n{{template \"TS_CONFIG\" .}}\n{{template \"CSS\" .}}\n")
 	if ch.err != nil {
 		return fmt.Errorf("error while parsing config file template: %v", ch.err)
 	}
-	err = ch.templ.Execute(f, ch.e)
+	err := ch.templ.Execute(f, ch.e)
 	if err != nil {
 		return fmt.Errorf("error while executing form template: %v", err)
 	}
@@ -499,11 +491,13 @@ import {RoundNumber} from '@/filters/numberFilter';
 	return th, nil
 }
 
-func (ch *configHelper) parse(str string) *configHelper {
-	if ch.err != nil {
-		return ch
+func (ch *configHelper) parse(str ...string) *configHelper {
+	for _, s := range str {
+		if ch.err != nil {
+			return ch
+		}
+		ch.templ, ch.err = ch.templ.Parse(s)
 	}
-	ch.templ, ch.err = ch.templ.Parse(str)
 	return ch
 }
 
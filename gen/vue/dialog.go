@@ -3,7 +3,6 @@ package vue
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
 )
 
@@ -15,23 +14,20 @@ func (h *helper) createDialog(compPath ...string) error {
 		p = compPath[0]
 	}
 	p = filepath.Join(h.outDir, p)
-	f, err := os.Create(p)
+	f := h.cg.createFile(p)
 	defer f.Close()
-	if err != nil {
-		return fmt.Errorf("while opening file for DialogComponent for %s: %v", h.e.Name, err)
-	}
-	h.parse(htmlGridDialogTemplate).
-		parse(vueGridDialogTSTemplateBody).
+	h.parse(h.cg.flavor.dialogHTML).
+		parse(h.cg.flavor.dialogTSBody).
 		parse("{{template \"TS\" .}}\n{{template \"HTML\" .}}\n")
 	if h.err != nil {
 		return fmt.Errorf("error while parsing template: %v", h.err)
 	}
 	buffer := bytes.Buffer{}
-	err = h.templ.Execute(&buffer, h.e)
+	err := h.templ.Execute(&buffer, h.e)
 	if err != nil {
 		return fmt.Errorf("while executing template for DialogComponent for %s: %v", h.e.Name, err)
 	}
-	h.parse(vueGridDialogTSTemplateHeader).
+	h.parse(h.cg.flavor.dialogTSHeader).
 		parse("{{template \"TS-HEADER\" .}}\n")
 	if h.err != nil {
 		return fmt.Errorf("error while parsing template: %v", h.err)
@@ -2,7 +2,6 @@ package vue
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 )
 
@@ -13,18 +12,15 @@ func (h *helper) createDictEditor() error {
 	}
 
 	p = filepath.Join(h.outDir, p)
-	f, err := os.Create(p)
+	f := h.cg.createFile(p)
 	defer f.Close()
-	if err != nil {
-		return fmt.Errorf("while opening file '%s' for DictEditComponent for %s: %v", p, h.e.Name, err)
-	}
-	h.parse(vueDictHTMLTemplate).
-		parse(vueDictTSTemplate).
+	h.parse(h.cg.flavor.dictEditHTML).
+		parse(h.cg.flavor.dictEditTS).
 		parse("{{template \"HTML\" .}}\n{{template \"TS\" .}}\n")
 	if h.err != nil {
 		return fmt.Errorf("error while parsing template: %v", h.err)
 	}
-	err = h.templ.Execute(f, h)
+	err := h.templ.Execute(f, h)
 	if err != nil {
 		return fmt.Errorf("while executing template for DictEditComponent for %s: %v", h.e.Name, err)
 	}
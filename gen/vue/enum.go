@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/vc2402/vivard/gen"
 	"github.com/vc2402/vivard/gen/js"
-	"os"
 	"path/filepath"
 	"text/template"
 )
@@ -12,19 +11,16 @@ import (
 func (cg *ClientGenerator) generateEnum(outDir string, e *gen.Enum) error {
 	fileName := e.Features.String(featureVueKind, fVKLookupComponentPath)
 	p := filepath.Join(outDir, fileName)
-	f, err := os.Create(p)
-	if err != nil {
-		return fmt.Errorf("while opening file for ViewComponent for %s: %v", e.Name, err)
-	}
+	f := cg.createFile(p)
 	defer f.Close()
 	templ := template.New("ENUM").
 		Funcs(cg.getEnumFuncMap(e))
 
-	templ, err = templ.Parse(htmlEnumLookupTemplate)
+	templ, err := templ.Parse(cg.flavor.enumLookupHTML)
 	if err != nil {
 		return err
 	}
-	templ, err = templ.Parse(vueEnumLookupTSTemplate)
+	templ, err = templ.Parse(cg.flavor.enumLookupTS)
 	if err != nil {
 		return err
 	}
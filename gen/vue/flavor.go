@@ -0,0 +1,135 @@
+package vue
+
+// flavor describes the differences between generated clients for different versions of Vue: templates and
+// expressions that are used by helpers
+type flavor struct {
+	// optionsName - name of custom options
+	optionsName string
+	// apolloClient - default expression for apollo client
+	apolloClient string
+	// self - prefix for component's properties in generated TS code
+	self string
+	// display - prefix for Vuetify display breakpoints in HTML templates
+	display string
+
+	lookupHTML               string
+	dictionaryLookupTSHeader string
+	dictionaryLookupTSBody   string
+	entityLookupTSHeader     string
+	entityLookupTSBody       string
+	typeDescriptorTS         string
+
+	// viewTemplates - html, fields and css templates for view component
+	viewTemplates []string
+	viewTSHeader  string
+	viewTSBody    string
+
+	dialogHTML     string
+	dialogTSHeader string
+	dialogTSBody   string
+
+	tablessForm     string
+	tabbedForm      string
+	gridFormContent string
+	flexFormContent string
+	formList        string
+	formDisabled    string
+	// formInputs - templates for inputs of all the kinds
+	formInputs []string
+	formTS     string
+	formListTS string
+	formCSS    string
+
+	dictEditHTML string
+	dictEditTS   string
+
+	historyHTML string
+	historyTS   string
+
+	configTabsHTML string
+	configTabsTS   string
+	configTreeHTML string
+	configTreeTS   string
+	// configCommon - css, items templates and common TS for config component
+	configCommon []string
+
+	enumLookupHTML string
+	enumLookupTS   string
+}
+
+var vue2Flavor = &flavor{
+	optionsName:  VCOptions,
+	apolloClient: vcoApolloClientDef,
+	self:         "this.",
+	display:      "$vuetify.breakpoint.",
+
+	lookupHTML:               htmlLookupTemplate,
+	dictionaryLookupTSHeader: vueDictionaryLookupTSTemplateHeader,
+	dictionaryLookupTSBody:   vueDictionaryLookupTSTemplateBody,
+	entityLookupTSHeader:     vueEntityLookupTSTemplateHeader,
+	entityLookupTSBody:       vueEntityLookupTSTemplateBody,
+	typeDescriptorTS:         typeDescriptorTSTemplate,
+
+	viewTemplates: []string{
+		htmlCardViewTemplate,
+		htmlFieldViewTemplate,
+		htmlFieldViewBoolTemplate,
+		htmlFieldViewComplexTemplate,
+		htmlFieldViewDateTemplate,
+		htmlFieldViewFloatTemplate,
+		htmlFieldViewIntTemplate,
+		htmlFieldViewTextTemplate,
+		htmlViewCSS,
+	},
+	viewTSHeader: vueViewTSTemplateHeader,
+	viewTSBody:   vueViewTSTemplateBody,
+
+	dialogHTML:     htmlGridDialogTemplate,
+	dialogTSHeader: vueGridDialogTSTemplateHeader,
+	dialogTSBody:   vueGridDialogTSTemplateBody,
+
+	tablessForm:     htmlTablessFormTemplate,
+	tabbedForm:      htmlTabbedFormTemplate,
+	gridFormContent: htmlGridFormTemplate,
+	flexFormContent: htmlFlexFormTemplate,
+	formList:        newHtmlFormListTemplate,
+	formDisabled:    newHtmlFormDisabledTemplate,
+	formInputs: []string{
+		htmlFormInputTemplate,
+		htmlFormTextInputTemplate,
+		htmlFormTextAreaTemplate,
+		htmlFormDateInputTemplate,
+		htmlFormColorInputTemplate,
+		htmlFormMapInputTemplate,
+		htmlFormArrayInputTemplate,
+		htmlFormArrayAsListTemplate,
+		htmlFormArrayAsChipsTemplate,
+		htmlFormLookupInputTemplate,
+		htmlFormBoolInputTemplate,
+	},
+	formTS:     vueTabbedFormTSTemplate,
+	formListTS: newFormListTSTemplate,
+	formCSS:    cssFormTemplate,
+
+	dictEditHTML: vueDictHTMLTemplate,
+	dictEditTS:   vueDictTSTemplate,
+
+	historyHTML: htmlHistoryToolTipOnHoverTemplate, // htmlHistoryTooltipOnClickTemplate
+	historyTS:   vueHistoryTSTemplate,
+
+	configTabsHTML: configTabsHTMLTemplate,
+	configTabsTS:   vueConfigTabsTSTemplate,
+	configTreeHTML: configTreeHTMLTemplate,
+	configTreeTS:   vueConfigTreeTSTemplate,
+	configCommon: []string{
+		cssConfigTemplate,
+		configListItemHTMLTemplate,
+		configValueItemHTMLTemplate,
+		configDictEditHTMLTemplate,
+		configValueHTMLTemplate,
+		vueConfigTSCommonTemplate,
+	},
+
+	enumLookupHTML: htmlEnumLookupTemplate,
+	enumLookupTS:   vueEnumLookupTSTemplate,
+}
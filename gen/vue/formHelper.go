@@ -2,6 +2,7 @@ package vue
 
 import (
 	"fmt"
+	"html"
 	"os"
 	"path/filepath"
 	"sort"
@@ -215,8 +216,22 @@ func (cg *ClientGenerator) newFormHelper(
 				f.fld.HasModifier(gen.AttrModifierEmbeddedRef) ||
 				f.fld.FB(gen.GQLFeatures, gen.GQLFIDOnly)
 		},
-		"GetFields": func(h *helper) []fieldDescriptor {
-			return h.ctx.fields
+		"GetFields": func(it interface{}) []fieldDescriptor {
+			switch v := it.(type) {
+			case *helper:
+				return v.ctx.fields
+			case [][]fieldDescriptor:
+				// fields of the tab
+				var ret []fieldDescriptor
+				for _, row := range v {
+					ret = append(ret, row...)
+				}
+				return ret
+			}
+			return nil
+		},
+		"WithTabs": func() bool {
+			return ctx.withTabs
 		},
 		"Tabs": func() [][][]fieldDescriptor {
 			tabs, _ := ctx.getTabs(0)
@@ -323,7 +338,8 @@ import {RoundNumber} from '@/filters/numberFilter';
 				qt, _ := e.Features.GetEntity(gen.FeatureDictKind, gen.FDQualifierType)
 				idfld := qt.GetIdField()
 				return fmt.Sprintf(
-					"this.qualifier && (this.qualifiedByObject ? [this.qualifier.%s] : [this.qualifier])",
+					"%[1]squalifier && (%[1]squalifiedByObject ? [%[1]squalifier.%[2]s] : [%[1]squalifier])",
+					cg.flavor.self,
 					idfld.Annotations.GetStringAnnotationDef(js.Annotation, js.AnnotationName, ""),
 				)
 			}
@@ -334,7 +350,8 @@ import {RoundNumber} from '@/filters/numberFilter';
 				qt, _ := e.Features.GetEntity(gen.FeatureDictKind, gen.FDQualifierType)
 				idfld := qt.GetIdField()
 				return fmt.Sprintf(
-					"this.qualifier && (!this.qualifiedByObject || this.qualifier.%s)",
+					"%[1]squalifier && (!%[1]squalifiedByObject || %[1]squalifier.%[2]s)",
+					cg.flavor.self,
 					idfld.Annotations.GetStringAnnotationDef(js.Annotation, js.AnnotationName, ""),
 				)
 			}
@@ -752,6 +769,13 @@ import {RoundNumber} from '@/filters/numberFilter';
 			}
 			return ""
 		},
+		"HintAttrs": func(f fieldDescriptor) string {
+			if f.fld.Doc == "" {
+				return ""
+			}
+			// doc comment of the field is shown as hint
+			return fmt.Sprintf("hint=\"%s\" ", html.EscapeString(strings.Join(gen.DocLines(f.fld.Doc), " ")))
+		},
 		"InputAttrs": func(f fieldDescriptor) string {
 			ret := ""
 			if f.mask != "" {
@@ -801,6 +825,12 @@ import {RoundNumber} from '@/filters/numberFilter';
 			case gen.TipDate:
 				t := f.fld.Annotations.GetStringAnnotationDef(vueAnnotation, vueAnnotationDisplayType, vueATISODate)
 				return fmt.Sprintf(":type=\"'%s'\"", t)
+			case gen.TipDateTime:
+				t := f.fld.Annotations.GetStringAnnotationDef(vueAnnotation, vueAnnotationDisplayType, vueATDateTime)
+				return fmt.Sprintf(":type=\"'%s'\"", t)
+			case gen.TipTime:
+				t := f.fld.Annotations.GetStringAnnotationDef(vueAnnotation, vueAnnotationDisplayType, vueATTime)
+				return fmt.Sprintf(":type=\"'%s'\"", t)
 			}
 			return ""
 		},
@@ -982,7 +1012,7 @@ import {RoundNumber} from '@/filters/numberFilter';
 			} else if len(th.ctx.fields) < 6 {
 				wideWidth = "50vw"
 			}
-			return fmt.Sprintf("$vuetify.breakpoint.lgAndUp ? '%s' : '80vw'", wideWidth)
+			return fmt.Sprintf("%slgAndUp ? '%s' : '80vw'", cg.flavor.display, wideWidth)
 		},
 		"NeedSecurity": func() bool {
 			return ctx.needSecurity || ctx.needResourceSecurity
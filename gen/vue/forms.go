@@ -2,7 +2,6 @@ package vue
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 )
 
@@ -34,17 +33,14 @@ func (h *helper) generateForm(formName string) error {
 	if h.e.FB(featureVueKind, fVKFormRequired) {
 		p := h.e.FS(featureVueKind, fVKFormComponentPath)
 		p = filepath.Join(h.outDir, p)
-		f, err := os.Create(p)
-		if err != nil {
-			return fmt.Errorf("error opening file '%s': %v", p, err)
-		}
+		f := h.cg.createFile(p)
 		defer f.Close()
 
 		h.parse("<template>{{template \"FORM\" .}}</template>\n{{template \"FORM.TS\" .}}\n{{template \"CSS\" .}}\n")
 		if h.err != nil {
 			return fmt.Errorf("error while parsing form file template: %v", h.err)
 		}
-		err = h.templ.Execute(f, h.e)
+		err := h.templ.Execute(f, h.e)
 		if err != nil {
 			return fmt.Errorf("error while executing form template: %v", err)
 		}
@@ -52,17 +48,14 @@ func (h *helper) generateForm(formName string) error {
 	if h.e.FB(featureVueKind, fVKFormListRequired) {
 		p := h.e.FS(featureVueKind, fVKFormListComponentPath)
 		p = filepath.Join(h.outDir, p)
-		f, err := os.Create(p)
-		if err != nil {
-			return fmt.Errorf("error opening file '%s': %v", p, err)
-		}
+		f := h.cg.createFile(p)
 		defer f.Close()
 
 		h.parse("<template>{{template \"FORM-LIST\" .}}</template>\n{{template \"FORM-LIST.TS\" .}}\n")
 		if h.err != nil {
 			return fmt.Errorf("error while parsing form list template: %v", h.err)
 		}
-		err = h.templ.Execute(f, h.e)
+		err := h.templ.Execute(f, h.e)
 		if err != nil {
 			return fmt.Errorf("error while executing form template: %v", err)
 		}
@@ -71,17 +64,14 @@ func (h *helper) generateForm(formName string) error {
 	if h.e.FB(featureVueKind, fVKCardRequired) {
 		p := h.e.FS(featureVueKind, fVKCardComponentPath)
 		p = filepath.Join(h.outDir, p)
-		f, err := os.Create(p)
-		if err != nil {
-			return fmt.Errorf("error opening file '%s': %v", p, err)
-		}
+		f := h.cg.createFile(p)
 		defer f.Close()
 
 		h.parse("<template>{{template \"FORM-CARD\" .}}</template>\n{{template \"FORM-CARD.TS\" .}}\n{{template \"CSS\" .}}\n")
 		if h.err != nil {
 			return fmt.Errorf("error while parsing card template: %v", h.err)
 		}
-		err = h.templ.Execute(f, h.e)
+		err := h.templ.Execute(f, h.e)
 		if err != nil {
 			return fmt.Errorf("error while executing card template: %v", err)
 		}
@@ -154,11 +144,17 @@ var htmlFormCardTemplate = `
 {{end}}
 `
 
-const htmlFormInputTemplate = `{{define "FORM_INPUT_FIELD"}}{{if ne (CustomComponent .) ""}}<{{CustomComponent .}} v-model="value.{{FieldName .}}" label="{{Label .}}" @change="changed('{{FieldName .}}')" :disabled="{{if Readonly .}}true{{else}}disabled{{end}}"/>
+const htmlFormInputTemplate = `{{define "FORM_INPUT_FIELD"}}{{if ne (CustomComponent .) ""}}<{{CustomComponent .}} v-model="value.{{FieldName .}}" label="{{Label .}}" {{HintAttrs .}}@change="changed('{{FieldName .}}')" :disabled="{{if Readonly .}}true{{else}}disabled{{end}}"/>
   {{else if eq (FormComponentType .) "string"}}{{template "TEXT_INPUT" .}}
   {{else if eq (FormComponentType .) "int"}}{{template "TEXT_INPUT" .}}
   {{else if eq (FormComponentType .) "float"}}{{template "TEXT_INPUT" .}}
+  {{else if eq (FormComponentType .) "decimal"}}{{template "TEXT_INPUT" .}}
+  {{else if eq (FormComponentType .) "duration"}}{{template "TEXT_INPUT" .}}
+  {{else if eq (FormComponentType .) "uuid"}}{{template "TEXT_INPUT" .}}
   {{else if eq (FormComponentType .) "date"}}{{template "DATE_INPUT" .}}
+  {{else if eq (FormComponentType .) "datetime"}}{{template "DATE_INPUT" .}}
+  {{else if eq (FormComponentType .) "time"}}{{template "DATE_INPUT" .}}
+  {{else if eq (FormComponentType .) "bytes"}}{{template "TEXT_AREA_INPUT" .}}
   {{else if eq (FormComponentType .) "bool"}}{{template "BOOL_INPUT" .}}
   {{else if eq (FormComponentType .) "color"}}{{template "COLOR_INPUT" .}}
   {{else if eq (FormComponentType .) "map"}}{{template "MAP_INPUT" .}}
@@ -168,7 +164,7 @@ const htmlFormInputTemplate = `{{define "FORM_INPUT_FIELD"}}{{if ne (CustomCompo
 
 const htmlFormTextInputTemplate = `{{define "TEXT_INPUT"}}<v-text-field v-if="value"
     v-model="value.{{FieldName .}}"
-    label="{{Label .}}" {{InputAttrs .}}
+    label="{{Label .}}" {{HintAttrs .}}{{InputAttrs .}}
     @change="changed('{{FieldName .}}')"
     :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"{{if IsIcon .}}
     :append-icon="value.{{FieldName .}}"{{end}}
@@ -181,7 +177,7 @@ const htmlFormTextInputTemplate = `{{define "TEXT_INPUT"}}<v-text-field v-if="va
 
 const htmlFormTextAreaTemplate = `{{define "TEXT_AREA_INPUT"}}<v-textarea v-if="value"
     v-model="value.{{FieldName .}}"
-    label="{{Label .}}" {{InputAttrs .}}
+    label="{{Label .}}" {{HintAttrs .}}{{InputAttrs .}}
     auto-grow
     outlined
     rows="{{TextAreaRows .}}"
@@ -198,20 +194,20 @@ const htmlFormTextAreaTemplate = `{{define "TEXT_AREA_INPUT"}}<v-textarea v-if="
 const htmlFormDateInputTemplate = `{{define "DATE_INPUT"}}<{{CustomComponent "date"}}  v-if="value"
     v-model="value.{{FieldName .}}"
     label="{{Label .}}"
-    @change="changed('{{FieldName .}}')"
+    {{HintAttrs .}}@change="changed('{{FieldName .}}')"
     :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"
     {{ConponentAddAttrs .}}
   ></{{CustomComponent "date"}}>{{end}}`
 const htmlFormColorInputTemplate = `{{define "COLOR_INPUT"}}<{{CustomComponent "color"}}  v-if="value"
     v-model="value.{{FieldName .}}"
     label="{{Label .}}"
-    @change="changed('{{FieldName .}}')"
+    {{HintAttrs .}}@change="changed('{{FieldName .}}')"
     :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"
   ></{{CustomComponent "color"}}>{{end}}`
 const htmlFormMapInputTemplate = `{{define "MAP_INPUT"}}<{{CustomComponent "map"}}  v-if="value"
     v-model="value.{{FieldName .}}"
     label="{{Label .}}"
-    @change="changed('{{FieldName .}}')"
+    {{HintAttrs .}}@change="changed('{{FieldName .}}')"
     :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"
     {{ConponentAddAttrs .}}
   ></{{CustomComponent "map"}}>{{end}}`
@@ -222,7 +218,7 @@ const htmlFormLookupInputTemplate = `{{define "LOOKUP_INPUT"}}
   v-if="value" 
   v-model="value.{{FieldName .}}" 
   label="{{Label .}}" 
-  @change="changed('{{FieldName .}}')" 
+  {{HintAttrs .}}@change="changed('{{FieldName .}}')" 
   :error-messages="validator && validator.errors.{{FieldName .}} || []"
   {{LookupAttrs .}} 
   :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"{{if ByRefField .}}
@@ -233,7 +229,7 @@ const htmlFormLookupInputTemplate = `{{define "LOOKUP_INPUT"}}
     </template>{{end}}
 </{{LookupComponent . true}}>{{end}}`
 
-const htmlFormBoolInputTemplate = `{{define "BOOL_INPUT"}}<v-checkbox  v-if="value" v-model="value.{{FieldName .}}" label="{{Label .}}" @change="changed('{{FieldName .}}')" :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"/>{{end}}`
+const htmlFormBoolInputTemplate = `{{define "BOOL_INPUT"}}<v-checkbox  v-if="value" v-model="value.{{FieldName .}}" label="{{Label .}}" {{HintAttrs .}}@change="changed('{{FieldName .}}')" :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"/>{{end}}`
 
 const htmlFormArrayAsListTemplate = `{{define "ARRAY_AS_LIST"}}<div class="d-flex flex-column mt-2">
   <div class="d-flex flex-row justify-space-around"><h3>{{Label .}}</h3>{{if not (Readonly .)}}<v-btn if="!disabled" text color="primary" @click="add{{FieldName . "U"}}"><v-icon>add</v-icon> {{Label .}}</v-btn>{{end}}</div>
@@ -98,11 +98,13 @@ func (cg *ClientGenerator) getTypesPath(e any) (string, error) {
 	return filepath.Join("..", "..", "..", "types", tn), nil
 }
 
-func (h *helper) parse(str string) *helper {
-	if h.err != nil {
-		return h
+func (h *helper) parse(str ...string) *helper {
+	for _, s := range str {
+		if h.err != nil {
+			return h
+		}
+		h.templ, h.err = h.templ.Parse(s)
 	}
-	h.templ, h.err = h.templ.Parse(str)
 	return h
 }
 
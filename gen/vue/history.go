@@ -2,31 +2,26 @@ package vue
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 )
 
 func (h *helper) generateHistoryComponent() error {
-	htmlHistoryTemplate := htmlHistoryToolTipOnHoverTemplate // htmlHistoryTooltipOnClickTemplate
-	h.parse(htmlHistoryTemplate).
-		parse(vueHistoryTSTemplate).
-		parse(cssFormTemplate)
+	h.parse(h.cg.flavor.historyHTML).
+		parse(h.cg.flavor.historyTS).
+		parse(h.cg.flavor.formCSS)
 	if h.err != nil {
 		return fmt.Errorf("error while parsing form template: %v", h.err)
 	}
 	p := h.e.FS(featureVueKind, fVKHistComponentPath)
 	p = filepath.Join(h.outDir, p)
-	f, err := os.Create(p)
-	if err != nil {
-		return fmt.Errorf("error opening file '%s': %v", p, err)
-	}
+	f := h.cg.createFile(p)
 	defer f.Close()
 
 	h.parse("<template>{{template \"COMPONENT\" .}}</template>\n{{template \"TS\" .}}\n{{template \"CSS\" .}}\n")
 	if h.err != nil {
 		return fmt.Errorf("error while parsing form file template: %v", h.err)
 	}
-	err = h.templ.Execute(f, h)
+	err := h.templ.Execute(f, h)
 	if err != nil {
 		return fmt.Errorf("error while executing form template: %v", err)
 	}
@@ -2,40 +2,28 @@ package vue
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 )
 
 func (h *helper) generateGridForm(formName string, compPath ...string) error {
 
-	baseTempl := htmlTablessFormTemplate
+	fl := h.cg.flavor
+	baseTempl := fl.tablessForm
 	if h.ctx.withTabs {
-		baseTempl = htmlTabbedFormTemplate
+		baseTempl = fl.tabbedForm
 	}
-	formTempl := htmlFlexFormTemplate
+	formTempl := fl.flexFormContent
 	if h.ctx.useGrid {
-		formTempl = htmlGridFormTemplate
+		formTempl = fl.gridFormContent
 	}
 	h.parse(baseTempl).
 		parse(formTempl).
-		parse(newHtmlFormListTemplate).
-		parse(newHtmlFormDisabledTemplate).
-		// parse(htmlFormCardTemplate).
-		parse(htmlFormInputTemplate).
-		parse(htmlFormTextInputTemplate).
-		parse(htmlFormTextAreaTemplate).
-		parse(htmlFormDateInputTemplate).
-		parse(htmlFormColorInputTemplate).
-		parse(htmlFormMapInputTemplate).
-		parse(htmlFormArrayInputTemplate).
-		parse(htmlFormArrayAsListTemplate).
-		parse(htmlFormArrayAsChipsTemplate).
-		parse(htmlFormLookupInputTemplate).
-		parse(htmlFormBoolInputTemplate).
-		parse(vueTabbedFormTSTemplate).
-		parse(newFormListTSTemplate).
-		// parse(vueFormCardTSTemplate).
-		parse(cssFormTemplate)
+		parse(fl.formList).
+		parse(fl.formDisabled).
+		parse(fl.formInputs...).
+		parse(fl.formTS).
+		parse(fl.formListTS).
+		parse(fl.formCSS)
 	if h.err != nil {
 		return fmt.Errorf("error while parsing form template: %v", h.err)
 	}
@@ -46,17 +34,14 @@ func (h *helper) generateGridForm(formName string, compPath ...string) error {
 			p = compPath[0]
 		}
 		p = filepath.Join(h.outDir, p)
-		f, err := os.Create(p)
-		if err != nil {
-			return fmt.Errorf("error opening file '%s': %v", p, err)
-		}
+		f := h.cg.createFile(p)
 		defer f.Close()
 
 		h.parse("<template>{{template \"FORM\" .}}</template>\n{{template \"FORM.TS\" .}}\n{{template \"CSS\" .}}\n")
 		if h.err != nil {
 			return fmt.Errorf("error while parsing form file template: %v", h.err)
 		}
-		err = h.templ.Execute(f, h)
+		err := h.templ.Execute(f, h)
 		if err != nil {
 			return fmt.Errorf("error while executing form template: %v", err)
 		}
@@ -71,17 +56,14 @@ func (h *helper) generateGridForm(formName string, compPath ...string) error {
 			return fmt.Errorf("FormList: form list requested but path not generated for %s", h.e.Name)
 		}
 		p = filepath.Join(h.outDir, p)
-		f, err := os.Create(p)
-		if err != nil {
-			return fmt.Errorf("FormList: Error opening file '%s': %v", p, err)
-		}
+		f := h.cg.createFile(p)
 		defer f.Close()
 
 		h.parse("<template>{{template \"FORM-LIST\" .}}</template>\n{{template \"FORM-LIST.TS\" .}}\n")
 		if h.err != nil {
 			return fmt.Errorf("error while parsing form list template: %v", h.err)
 		}
-		err = h.templ.Execute(f, h.e)
+		err := h.templ.Execute(f, h.e)
 		if err != nil {
 			return fmt.Errorf("error while executing form template: %v", err)
 		}
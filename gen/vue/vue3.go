@@ -0,0 +1,114 @@
+package vue
+
+import (
+	"github.com/vc2402/vivard/gen"
+)
+
+const (
+	vue3GeneratorName = "vue3"
+
+	VC3Options = "vue3"
+
+	vco3ApolloClientDef = "resolveClient()"
+)
+
+// Vue3ClientGenerator generates Vue 3 components (<script setup lang="ts">) for Vuetify 3 and @vue/apollo-composable;
+// it produces the same set of components as ClientGenerator and uses the same annotations
+type Vue3ClientGenerator struct {
+	ClientGenerator
+}
+
+func init() {
+	gen.RegisterPluginFactory(func() gen.Generator { return &Vue3ClientGenerator{} })
+}
+
+func (cg *Vue3ClientGenerator) Name() string {
+	return vue3GeneratorName
+}
+
+func (cg *Vue3ClientGenerator) Prepare(desc *gen.Package) error {
+	cg.flavor = vue3Flavor
+	return cg.ClientGenerator.Prepare(desc)
+}
+
+var vue3Flavor = &flavor{
+	optionsName:  VC3Options,
+	apolloClient: vco3ApolloClientDef,
+	self:         "props.",
+	display:      "",
+
+	lookupHTML:               vue3LookupHTMLTemplate,
+	dictionaryLookupTSHeader: vue3DictionaryLookupTSTemplateHeader,
+	dictionaryLookupTSBody:   vue3DictionaryLookupTSTemplateBody,
+	entityLookupTSHeader:     vue3EntityLookupTSTemplateHeader,
+	entityLookupTSBody:       vue3EntityLookupTSTemplateBody,
+	typeDescriptorTS:         vue3TypeDescriptorTSTemplate,
+
+	viewTemplates: []string{
+		vue3CardViewHTMLTemplate,
+		htmlFieldViewTemplate,
+		htmlFieldViewBoolTemplate,
+		htmlFieldViewComplexTemplate,
+		vue3FieldViewDateTemplate,
+		vue3FieldViewFloatTemplate,
+		vue3FieldViewIntTemplate,
+		htmlFieldViewTextTemplate,
+		htmlViewCSS,
+	},
+	viewTSHeader: vue3ViewTSTemplateHeader,
+	viewTSBody:   vue3ViewTSTemplateBody,
+
+	dialogHTML:     vue3DialogHTMLTemplate,
+	dialogTSHeader: vue3DialogTSTemplateHeader,
+	dialogTSBody:   vue3DialogTSTemplateBody,
+
+	tablessForm:     htmlTablessFormTemplate,
+	tabbedForm:      vue3TabbedFormTemplate,
+	gridFormContent: vue3GridFormTemplate,
+	flexFormContent: vue3FlexFormTemplate,
+	formList:        vue3FormListTemplate,
+	formDisabled:    newHtmlFormDisabledTemplate,
+	formInputs: []string{
+		vue3FormInputTemplate,
+		vue3FormTextInputTemplate,
+		vue3FormTextAreaTemplate,
+		vue3FormDateInputTemplate,
+		vue3FormColorInputTemplate,
+		vue3FormMapInputTemplate,
+		vue3FormArrayInputTemplate,
+		vue3FormArrayAsListTemplate,
+		vue3FormArrayAsChipsTemplate,
+		vue3FormLookupInputTemplate,
+		vue3FormBoolInputTemplate,
+	},
+	formTS:     vue3FormTSTemplate,
+	formListTS: vue3FormListTSTemplate,
+	formCSS:    vue3CSSTemplate,
+
+	dictEditHTML: vue3DictHTMLTemplate,
+	dictEditTS:   vue3DictTSTemplate,
+
+	historyHTML: vue3HistoryHTMLTemplate,
+	historyTS:   vue3HistoryTSTemplate,
+
+	configTabsHTML: vue3ConfigTabsHTMLTemplate,
+	configTabsTS:   vue3ConfigTabsTSTemplate,
+	configTreeHTML: vue3ConfigTreeHTMLTemplate,
+	configTreeTS:   vue3ConfigTreeTSTemplate,
+	configCommon: []string{
+		vue3CSSTemplate,
+		configListItemHTMLTemplate,
+		vue3ConfigValueItemHTMLTemplate,
+		configDictEditHTMLTemplate,
+		configValueHTMLTemplate,
+		vue3ConfigTSCommonTemplate,
+	},
+
+	enumLookupHTML: vue3EnumLookupHTMLTemplate,
+	enumLookupTS:   vue3EnumLookupTSTemplate,
+}
+
+const vue3CSSTemplate = `
+{{define "CSS"}}
+{{end}}
+`
@@ -0,0 +1,127 @@
+package vue
+
+const vue3ConfigTabsHTMLTemplate = `
+{{define "TEMPL_CONFIG"}}
+  <v-tabs v-model="tab">
+    {{range .Fields}}
+    <v-tab value="{{.Name}}">{{Label .}}</v-tab>{{end}}
+  </v-tabs>
+  <v-window v-model="tab">
+  {{range .Fields}}
+    <v-window-item value="{{.Name}}">{{Label .}}</v-window-item>{{end}}
+  </v-window>
+{{end}}
+`
+const vue3ConfigTreeHTMLTemplate = `
+{{define "TEMPL_CONFIG"}}
+  <div class="d-flex flex-row" style="height:100%;">
+    <div class="d-flex flex-column px-8 pt-2" style="height:100%; overflow-y: auto">
+      <v-treeview
+        :items="items"
+        item-title="name"
+        item-value="id"
+        rounded
+        density="compact"
+        activatable
+        return-object
+        @update:activated="activeChanged"
+      ></v-treeview>
+    </div>
+    <v-divider vertical></v-divider>
+    <div class="d-flex flex-column px-8 py-4">
+      <div class="d-flex flex-row py-4">
+        <v-btn variant="text" @click="save" color="primary" prepend-icon="mdi-content-save-all">Save</v-btn>
+        <v-btn variant="text" @click="load" color="primary" prepend-icon="mdi-refresh">Reload</v-btn>
+      </div>
+      <v-divider></v-divider>
+      <v-progress-linear v-if="loading" indeterminate></v-progress-linear>
+      <template v-if="!active">
+        Select the item
+      </template>
+      <template v-else-if="value">
+        {{range Leafs}}<div v-if="active.id == '{{.}}'">
+          {{template "CONF_VALUE" (Leaf .)}}
+        </div>{{end}}
+      </template>
+    </div>
+  </div>
+{{end}}
+`
+
+const vue3ConfigTreeTSTemplate = `
+{{define "TS_CONFIG"}}
+{{template "TS_CONFIG_COMMON" .}}import { VTreeview } from 'vuetify/labs/VTreeview';
+{{template "TS_CONFIG_LOAD" .}}
+const items = ref<TreeItem[]>({{GetTreeItems .}});
+const active = ref<TreeItem|null>(null);
+
+function activeChanged(act: unknown) {
+  const activated = act as TreeItem[];
+  if(!activated || !activated.length || !activated[0].leaf) {
+    active.value = null;
+  } else {
+    active.value = activated[0];
+  }
+}
+</script>
+{{end}}
+`
+
+const vue3ConfigValueItemHTMLTemplate = `
+{{define "CONFIG_VALUE_ITEM"}}
+  <{{FormComponent .}} v-model="value{{.Path}}"/>
+{{end}}
+`
+
+const vue3ConfigTSCommonTemplate = `
+{{define "TS_CONFIG_COMMON"}}
+<script setup lang="ts">
+import { ref, onMounted } from 'vue';
+import { resolveClient } from '@vue/apollo-composable';
+import { {{TypeName .}}, {{GetQuery .}}, {{SaveQuery .}} } from '{{TypesFilePath .}}';
+{{range RequiredComponents}}
+import {{.Comp}} from '{{.Imp}}';{{end}}
+{{range AdditionalComponents}}
+import {{.Comp}} from '{{.Imp}}';{{end}}
+
+{{end}}
+
+{{define "TS_CONFIG_LOAD"}}
+type TreeItem = {id:string, name:string, leaf: boolean, children?: TreeItem[]};
+
+const apolloClient = {{ApolloClient}};
+const value = ref<{{TypeName .}}|null>(null);
+const loading = ref(false);
+
+onMounted(load);
+
+async function load() {
+  loading.value = true;
+  try {
+    value.value = await {{GetQuery .}}(apolloClient);
+  } catch(exc) {
+    console.log("problem: ", exc);
+  }
+  loading.value = false;
+}
+async function save() {
+  if(value.value) {
+    loading.value = true;
+    try {
+      await {{SaveQuery .}}(apolloClient, value.value);
+    } catch(exc) {
+      console.log("problem: ", exc);
+    }
+    loading.value = false;
+  }
+}
+{{end}}
+`
+
+const vue3ConfigTabsTSTemplate = `
+{{define "TS_CONFIG"}}
+{{template "TS_CONFIG_COMMON" .}}{{template "TS_CONFIG_LOAD" .}}
+const tab = ref<string|null>(null);
+</script>
+{{end}}
+`
@@ -0,0 +1,353 @@
+package vue
+
+// Dialog
+var vue3DialogHTMLTemplate = `
+{{define "HTML"}}
+<template>
+  <v-dialog
+    v-model="showDialog"
+    persistent
+    scrollable
+    :fullscreen="smAndDown"
+    :max-width="{{DialogWidth}}"
+  >
+    <v-card>
+      <v-card-title class="d-flex flex-row align-center">
+        <span>{{"{{title()}}"}}</span>
+        <v-spacer></v-spacer>
+        <v-btn
+          variant="text"
+          icon="mdi-close"
+          color="primary"
+          @click="close()"
+        ></v-btn>
+      </v-card-title>
+
+      <v-card-text>
+        <v-progress-linear v-if="loading" indeterminate></v-progress-linear>
+        <div v-if="problem">{{"{{problem}}"}}</div>
+        <{{SelfFormComponent}} v-model="value" :isNew="isNew" :disabled="readonly || forDelete || disabled"{{if WithValidator}} :validator="validator"{{end}}/>
+      </v-card-text>
+      <v-divider></v-divider>
+      <v-card-actions>
+        <div class="d-flex flex-row justify-center flex-grow-1">
+          <v-btn
+            :color="forDelete? 'error' : 'primary'"
+            variant="flat"
+            @click="close(true)"
+            :disabled="readonly"
+          >
+            {{"{{"}}forDelete? deleteText : okText{{"}}"}}
+          </v-btn>
+        </div>
+        <div class="d-flex flex-row justify-end">
+          <v-btn
+            color="primary"
+            variant="text"
+            @click="close()"
+          >
+            Close
+          </v-btn>
+        </div>
+      </v-card-actions>
+    </v-card>
+  </v-dialog>
+</template>
+{{end}}
+`
+
+const vue3DialogTSTemplateHeader = `
+{{define "TS-HEADER"}}
+<script setup lang="ts">
+import { ref{{if WithValidator}}, reactive{{end}} } from 'vue';
+import { useDisplay } from 'vuetify';
+import { resolveClient } from '@vue/apollo-composable';
+import { {{TypeName .}}, New{{TypeName .}}Instance, {{GetQuery .}}, {{if not Readonly}}{{SaveQuery .}}, {{CreateQuery .}}, {{DeleteQuery .}}{{end}}{{if WithValidator}}, {{ValidatorClass}}{{end}} } from '{{TypesFilePath .}}';
+import {{SelfFormComponent}} from '{{SelfFormComponentPath}}';
+{{TypesFromTS}}
+{{end}}
+`
+const vue3DialogTSTemplateBody = `
+{{define "TS"}}
+withDefaults(defineProps<{
+  readonly?: boolean,
+  okText?: string,
+  deleteText?: string,
+  disabled?: boolean|{[key:string]:boolean},
+}>(), {
+  readonly: false,
+  okText: "{{Literal "okButton"}}",
+  deleteText: "{{Literal "deleteButton"}}",
+  disabled: false,
+});
+
+const apolloClient = {{ApolloClient}};
+const { lgAndUp, smAndDown } = useDisplay();
+const value = ref<{{TypeName .}}|null>(null);
+const isNew = ref(false);
+const showDialog = ref(false);
+const loading = ref(false);
+const problem = ref("");
+const forDelete = ref(false);
+const doNotGQL = {{NotExported .}};
+{{if WithValidator}}const validator = reactive(new {{ValidatorClass}}());{{end}}
+let resolve: (res: {{TypeName .}}|null)=>void = () => {};
+let reject: (err: any)=>void = () => {};
+
+function title() {
+  return forDelete.value? ("{{Literal "deleteVerb"}} " + {{Title .}} + "?") : {{Title .}};
+}
+function show(v: {{TypeName .}}|{{IDType .}}|null|undefined, isNewValue?: boolean): Promise<{{TypeName .}}|null> {
+  showDialog.value = true;
+  forDelete.value = false;
+  problem.value = "";
+  if(v === null || v === undefined) {
+    value.value = New{{TypeName .}}Instance();
+    isNew.value = true;
+  } else if(typeof v === "object") {
+    value.value = v;
+    isNew.value = isNewValue === undefined? !v.{{IDField}} : isNewValue;
+  } else {
+    loadAndShow(v);
+    isNew.value = false;
+  }
+  return new Promise((res, rej) => {
+    resolve = res;
+    reject = rej;
+  });
+}
+function showForDelete(v: {{TypeName .}}|{{IDType .}}): Promise<{{TypeName .}}|null> {
+  const ret = show(v);
+  forDelete.value = true;
+  return ret;
+}
+async function loadAndShow(id: {{IDType .}}) {
+  loading.value = true;
+  problem.value = "";
+  try {
+    value.value = await {{GetQuery .}}(apolloClient, id);
+  } catch(exc: any) {
+    problem.value = exc.toString();
+  }
+  loading.value = false;
+}
+async function saveAndClose() {
+  {{if Readonly}}resolve(value.value);
+  showDialog.value = false;{{else}}
+  if(doNotGQL) {
+    resolve(value.value);
+    showDialog.value = false;
+    return;
+  }
+  try {
+    let res: {{TypeName .}}|undefined;
+    if(forDelete.value) {
+      if(value.value) {
+        const deleted = await {{DeleteQuery .}}(apolloClient, value.value.{{IDField}});
+        if(deleted)
+          res = value.value;
+      }
+    } else {
+      if(isNew.value) {
+        res = await {{CreateQuery .}}(apolloClient, value.value!);
+      } else {
+        res = await {{SaveQuery .}}(apolloClient, value.value!);
+      }
+    }
+    if(res) {
+      resolve(res);
+      showDialog.value = false;
+    }
+  } catch(exc) {
+    {{if WithValidator}}if(validator.setFromServerResponse(exc)) {
+      return;
+    }
+    {{end}}reject(exc);
+  }{{end}}
+}
+function close(save?: boolean) {
+  if(!save) {
+    resolve(null);
+    showDialog.value = false;
+  } else {
+    saveAndClose();
+  }
+}
+
+defineExpose({ show, showForDelete });
+</script>
+{{end}}
+`
+
+const vue3DictHTMLTemplate = `{{define "HTML"}}
+<template>
+  <div>
+    <div v-if="problem">{{"{{"}}problem{{"}}"}}</div>
+    <div class="d-flex flex-row align-baseline">
+      <v-text-field
+        v-model="search"
+        prepend-icon="mdi-magnify"
+        label="Search"
+        single-line
+        hide-details
+      ></v-text-field>
+      {{if DictWithQualifier .}}<{{LookupForQualifier .}} class="mx-2" v-model="qualifier" :returnObject="false" :multiple="true" :hideAdd="true" label="{{DictQualifierTitle}}" @change="load()"/>
+      {{end}}<v-btn v-if="!hideReload" color="success" variant="text" icon="mdi-reload" @click="load()"></v-btn>
+      <v-btn v-if="showAddButton" color="success" variant="text" icon="mdi-plus-box" @click="add()"></v-btn>
+    </div>
+    <div class="table">
+      <v-data-table
+        :headers="headers"
+        :items="items"
+        :search="search"
+        density="compact"
+        fixed-header
+        item-value="{{IDField}}"
+        :loading="loading"
+        height="calc( 100% - 48px )"
+        class="ddb-table"
+        :items-per-page="20"
+        :items-per-page-options="[10, 20, 30, 40, 50, -1]"
+      >
+        <template v-slot:item="{ item }">
+          <tr>
+            {{range GetFields .}}{{if ShowInTable .}}<td class="table-cell">
+              {{if NeedIconForTable .}}<v-icon>{{"{{"}}item.{{TableIconName .}}{{"}}"}}</v-icon>{{else if eq (GUITableType .) "color"}}<span style="display: block; border-radius: 8px; min-width: 16px; min-height: 16px; max-width: 16px; max-height: 16px" :style="{'background-color': item.{{TableAttrName .}} }"></span>{{else if eq (GUITableType .) "custom"}}<{{GUITableComponent .}} :item="item" :header="{key: '{{TableAttrName .}}'}" :value="item.{{TableAttrName .}}" />{{else if eq (GUITableType .) "bool"}}<v-icon>{{"{{"}}item.{{TableAttrName .}} ? 'mdi-checkbox-outline' : 'mdi-checkbox-blank-outline'{{"}}"}}</v-icon>{{else if ne (TableAttrName .) ""}}{{"{{"}}{{if IsNullable .}}item.{{FieldName .}} && {{end}}item.{{TableAttrName .}}{{"}}"}}{{end}}
+            </td>{{end}}{{end}}
+            <td>
+              <div class="d-flex flex-row">
+                <v-btn color="success" variant="text" :icon="readonly || !canEdit ? 'mdi-eye' : 'mdi-pencil'" @click="edit(item)"></v-btn>
+                <v-btn v-if="!readonly && canDelete" color="warning" variant="text" icon="mdi-delete" @click="del(item)"></v-btn>
+              </div>
+            </td>
+          </tr>
+        </template>
+      </v-data-table>
+    </div>
+    <{{DialogComponent .}} :readonly="readonly || !canEdit" ref="dialog"/>
+  </div>
+</template>
+{{end}}`
+
+const vue3DictTSTemplate = `{{define "TS"}}
+<script setup lang="ts">
+import { ref, onMounted } from 'vue';
+import { resolveClient } from '@vue/apollo-composable';
+import { {{TypeName}}, {{ListQuery}} } from '{{TypesFilePath}}';
+{{range RequiredComponents}}import {{.Comp}} from '{{.Imp}}';
+{{end}}
+withDefaults(defineProps<{
+  readonly?: boolean,
+  showAddButton?: boolean,
+  hideReload?: boolean,
+  canEdit?: boolean,
+  canDelete?: boolean,
+}>(), {
+  readonly: false,
+  showAddButton: true,
+  hideReload: false,
+  canEdit: true,
+  canDelete: true,
+});
+
+const apolloClient = {{ApolloClient}};
+const dialog = ref<InstanceType<typeof {{DialogComponent .}}>|null>(null);
+const problem = ref("");
+const search = ref("");
+const items = ref<{{TypeName}}[]>([]);
+const loading = ref(false);{{if DictWithQualifier .}}
+const qualifier = ref<any>(null);{{end}}
+const headers = [{{range (GetFields .)}}{{if ShowInTable .}}
+  {title: "{{Label .}}", key: "{{TableAttrName .}}", {{if NeedIconForTable .}}icon: "{{TableIconName .}}", {{end}}type: "{{GUITableType .}}", color: "{{GUITableColor .}}"{{if ne (GUITableComponent .) ""}}, component: "{{GUITableComponent .}}"{{end}} },{{end}}{{end}}
+  {title: "", key: "actions", sortable: false},
+];
+
+onMounted(load);
+
+async function load() {
+  loading.value = true;
+  problem.value = "";
+  try {
+    items.value = await {{ListQuery}}(apolloClient{{if DictWithQualifier .}}, qualifier.value{{end}});
+  } catch(exc: any) {
+    console.log("exception: ", exc);
+    problem.value = "Problema: " + exc.toString();
+  }
+  loading.value = false;
+}
+async function edit(item: any) {
+  try {
+    const res = await dialog.value!.show(item.{{IDField}});
+    if(res) {
+      load();
+    }
+  } catch(exc: any) {
+    problem.value = exc.toString();
+  }
+}
+async function del(item: any) {
+  try {
+    const res = await dialog.value!.showForDelete(item.{{IDField}});
+    if(res) {
+      load();
+    }
+  } catch(exc: any) {
+    problem.value = exc.toString();
+  }
+}
+async function add() {
+  try {
+    const res = await dialog.value!.show(null);
+    if(res) {
+      load();
+    }
+  } catch(exc: any) {
+    problem.value = exc.toString();
+  }
+}
+
+defineExpose({ load });
+</script>{{end}}
+`
+
+var vue3HistoryHTMLTemplate = `
+{{define "COMPONENT"}}
+  <div>
+    <v-tooltip
+      :open-on-hover="true"
+      location="left"
+    >
+      <template v-slot:activator="{ props: activatorProps }">
+        <v-icon
+          :color="color"
+          v-bind="activatorProps"
+        >mdi-history</v-icon>
+      </template>
+      <div class="d-flex flex-column">
+        <div v-if="items">
+          <div class="d-flex flex-column" v-for="(it, idx) in items" :key="idx">
+            <{{ViewComponent}} :item="it"/>
+          </div>
+        </div>
+      </div>
+    </v-tooltip>
+  </div>
+{{end}}
+`
+
+const vue3HistoryTSTemplate = `
+{{define "TS"}}
+<script setup lang="ts">
+import { {{TypeName}} } from '{{TypesFilePath}}';
+{{range RequiredComponents}}
+import {{.Comp}} from '{{.Imp}}';{{end}}
+
+withDefaults(defineProps<{
+  items?: {{TypeName}}[],
+  color?: string,
+}>(), {
+  color: "primary",
+});
+</script>
+{{end}}
+`
@@ -0,0 +1,334 @@
+package vue
+
+// Forms templates
+var vue3TabbedFormTemplate = `
+{{define "FORM"}}
+  <v-tabs v-model="tab">
+    {{range Tabs}}<v-tab value="{{TabID .}}" {{if NeedRolesSecurity}}v-if="hasAccess('{{TabID .}}')"{{else if NeedResourceSecurity}}{{if ne (ResourceForTab (TabID .)) ""}}v-if="{{TabID .}}Accessible"{{end}}{{end}}>{{TabLable .}}</v-tab>
+    {{end}}
+  </v-tabs>
+  <v-window v-model="tab">
+    {{range Tabs}}<v-window-item value="{{TabID .}}" {{if NeedRolesSecurity}}v-if="hasAccess('{{TabID .}}')"{{else if NeedResourceSecurity}}{{if ne (ResourceForTab (TabID .)) ""}}v-if="{{TabID .}}Accessible"{{end}}{{end}}>{{if ne (ComponentForTab (TabID .)) ""}}<{{(ComponentForTab (TabID .))}} :modelValue="value"/>{{else}}{{template "FORM_CONTENT" .}}{{end}}</v-window-item>
+    {{end}}
+  </v-window>
+{{end}}
+`
+
+var vue3GridFormTemplate = `
+{{define "FORM_CONTENT"}}
+  <div class="d-flex flex-column" {{FormStyles}}>
+    <slot name="pre-fields"></slot>
+    {{range Rows .}}
+      <v-row justify="space-between" align="baseline"{{if Compact}} no-gutters{{end}}>
+        {{range .}}
+        <v-col {{GridColAttrs .}}>
+          {{if IsID .}}<div v-if="!isNew">{{"{{"}}value && value.{{FieldName .}}{{"}}"}}</div>
+          {{if NotAuto .}}<div class="mx-2" v-if="isNew"> {{template "FORM_INPUT_FIELD" .}}</div>{{end}}
+          {{else}}<div class="mx-2" {{FieldAttrs .}} >
+            {{template "FORM_INPUT_FIELD" .}}
+          </div>{{end}}
+        </v-col>
+        {{end}}
+      </v-row>
+    {{end}}
+    <v-btn v-if="!value && !disabled" variant="text" color="primary" prepend-icon="mdi-plus" @click="addValue">
+      {{Title}}
+    </v-btn>
+    <slot name="post-fields"></slot>
+  </div>
+{{end}}
+`
+
+var vue3FlexFormTemplate = `
+{{define "FORM_CONTENT"}}
+  <div class="d-flex flex-row {{FlexWrap}} {{FlexJustify}} align-baseline" {{FormStyles}}>
+    <slot name="pre-fields"></slot>
+    {{range (GetFields .)}}{{if IsID .}}<div v-if="!isNew" {{FlexFieldStyles .}}>{{"{{"}}value && value.{{FieldName .}}{{"}}"}}</div>{{if NotAuto .}}<div v-else class="mx-2" {{FieldAttrs .}} {{FlexFieldStyles .}}>{{template "FORM_INPUT_FIELD" .}}</div>{{end}}
+    {{else}}<div class="mx-2" {{FieldAttrs .}} {{FlexFieldStyles .}}>
+      {{template "FORM_INPUT_FIELD" .}}
+    </div>{{end}}
+    {{end}}
+    <v-btn v-if="!value && !disabled" variant="text" color="primary" prepend-icon="mdi-plus" @click="addValue">
+      {{Title}}
+    </v-btn>
+    <slot name="post-fields"></slot>
+  </div>
+{{end}}
+`
+
+const vue3FormInputTemplate = `{{define "FORM_INPUT_FIELD"}}{{if ne (CustomComponent .) ""}}<{{CustomComponent .}} v-model="value.{{FieldName .}}" label="{{Label .}}" {{HintAttrs .}}@update:model-value="changed('{{FieldName .}}')" :disabled="{{if Readonly .}}true{{else}}disabled{{end}}"/>
+  {{else if eq (FormComponentType .) "string"}}{{template "TEXT_INPUT" .}}
+  {{else if eq (FormComponentType .) "int"}}{{template "TEXT_INPUT" .}}
+  {{else if eq (FormComponentType .) "float"}}{{template "TEXT_INPUT" .}}
+  {{else if eq (FormComponentType .) "decimal"}}{{template "TEXT_INPUT" .}}
+  {{else if eq (FormComponentType .) "duration"}}{{template "TEXT_INPUT" .}}
+  {{else if eq (FormComponentType .) "uuid"}}{{template "TEXT_INPUT" .}}
+  {{else if eq (FormComponentType .) "date"}}{{template "DATE_INPUT" .}}
+  {{else if eq (FormComponentType .) "datetime"}}{{template "DATE_INPUT" .}}
+  {{else if eq (FormComponentType .) "time"}}{{template "DATE_INPUT" .}}
+  {{else if eq (FormComponentType .) "bytes"}}{{template "TEXT_AREA_INPUT" .}}
+  {{else if eq (FormComponentType .) "bool"}}{{template "BOOL_INPUT" .}}
+  {{else if eq (FormComponentType .) "color"}}{{template "COLOR_INPUT" .}}
+  {{else if eq (FormComponentType .) "map"}}{{template "MAP_INPUT" .}}
+  {{else if eq (FormComponentType .) "array"}}{{template "ARRAY_INPUT" .}}
+  {{else if eq (FormComponentType .) "text-area"}}{{template "TEXT_AREA_INPUT" .}}
+  {{else}}{{template "LOOKUP_INPUT" .}}{{end}}{{end}}`
+
+const vue3FormTextInputTemplate = `{{define "TEXT_INPUT"}}<v-text-field v-if="value"
+    v-model="value.{{FieldName .}}"
+    label="{{Label .}}" {{HintAttrs .}}{{InputAttrs .}}
+    @update:model-value="changed('{{FieldName .}}')"
+    :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"{{if IsIcon .}}
+    :append-inner-icon="value.{{FieldName .}}"{{end}}
+    :error-messages="validator && validator.errors.{{FieldName .}} || []"
+  >{{if FieldWithAppend .}}<template v-slot:append>
+     {{range AppendToField .}}{{.}}{{end}}
+   </template>{{end}}{{if WithPrependIcon .}}<template v-slot:prepend>
+     {{PrependIcon .}}</template>{{end}}{{if WithAppendIcon .}}<template v-slot:append-inner>
+     {{AppendIcon .}}</template>{{end}}</v-text-field>{{end}}`
+
+const vue3FormTextAreaTemplate = `{{define "TEXT_AREA_INPUT"}}<v-textarea v-if="value"
+    v-model="value.{{FieldName .}}"
+    label="{{Label .}}" {{HintAttrs .}}{{InputAttrs .}}
+    auto-grow
+    variant="outlined"
+    rows="{{TextAreaRows .}}"
+    @update:model-value="changed('{{FieldName .}}')"
+    :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"{{if IsIcon .}}
+    :append-inner-icon="value.{{FieldName .}}"{{end}}
+    :error-messages="validator && validator.errors.{{FieldName .}} || []"
+  >{{if FieldWithAppend .}}<template v-slot:append>
+     {{range AppendToField .}}{{.}}{{end}}
+   </template>{{end}}{{if WithPrependIcon .}}<template v-slot:prepend>
+     {{PrependIcon .}}</template>{{end}}{{if WithAppendIcon .}}<template v-slot:append-inner>
+     {{AppendIcon .}}</template>{{end}}</v-textarea>{{end}}`
+
+const vue3FormDateInputTemplate = `{{define "DATE_INPUT"}}<{{CustomComponent "date"}} v-if="value"
+    v-model="value.{{FieldName .}}"
+    label="{{Label .}}"
+    {{HintAttrs .}}@update:model-value="changed('{{FieldName .}}')"
+    :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"
+    {{ConponentAddAttrs .}}
+  ></{{CustomComponent "date"}}>{{end}}`
+const vue3FormColorInputTemplate = `{{define "COLOR_INPUT"}}<{{CustomComponent "color"}} v-if="value"
+    v-model="value.{{FieldName .}}"
+    label="{{Label .}}"
+    {{HintAttrs .}}@update:model-value="changed('{{FieldName .}}')"
+    :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"
+  ></{{CustomComponent "color"}}>{{end}}`
+const vue3FormMapInputTemplate = `{{define "MAP_INPUT"}}<{{CustomComponent "map"}} v-if="value"
+    v-model="value.{{FieldName .}}"
+    label="{{Label .}}"
+    {{HintAttrs .}}@update:model-value="changed('{{FieldName .}}')"
+    :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"
+    {{ConponentAddAttrs .}}
+  ></{{CustomComponent "map"}}>{{end}}`
+
+// generated lookups emit 'change' only when the user selects a value, so forms listen to it instead of update:modelValue
+const vue3FormArrayInputTemplate = `{{define "ARRAY_INPUT"}}{{if ArrayAsLookup .}}<{{LookupComponent . true}} v-if="value" v-model="value.{{FieldName .}}" label="{{Label .}}" @change="changed('{{FieldName .}}')" {{LookupAttrs .}}{{if ByRefField .}} :returnObject="false"{{end}} :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"{{if HideAddForLookup .}} :hideAdd="true"{{end}}/>{{else if ArrayAsList .}}{{template "ARRAY_AS_LIST" .}}{{else if ArrayAsChips .}}{{template "ARRAY_AS_CHIPS" .}}{{end}}{{end}}`
+
+const vue3FormLookupInputTemplate = `{{define "LOOKUP_INPUT"}}
+<{{LookupComponent . true}}
+  v-if="value"
+  v-model="value.{{FieldName .}}"
+  label="{{Label .}}"
+  {{HintAttrs .}}@change="changed('{{FieldName .}}')"
+  :error-messages="validator && validator.errors.{{FieldName .}} || []"
+  {{LookupAttrs .}}
+  :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"{{if ByRefField .}}
+  :returnObject="false"{{end}}{{if HideAddForLookup .}}
+  :hideAdd="true"{{end}}>{{if FieldWithAppend .}}
+    <template v-slot:append>{{range AppendToField .}}
+      {{.}}{{end}}
+    </template>{{end}}
+</{{LookupComponent . true}}>{{end}}`
+
+const vue3FormBoolInputTemplate = `{{define "BOOL_INPUT"}}<v-checkbox v-if="value" v-model="value.{{FieldName .}}" label="{{Label .}}" {{HintAttrs .}}@update:model-value="changed('{{FieldName .}}')" :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}"/>{{end}}`
+
+const vue3FormArrayAsListTemplate = `{{define "ARRAY_AS_LIST"}}<div class="d-flex flex-column mt-2">
+  <div class="d-flex flex-row justify-space-around"><h3>{{Label .}}</h3>{{if not (Readonly .)}}<v-btn v-if="!disabled" variant="text" color="primary" prepend-icon="mdi-plus" @click="add{{FieldName . "U"}}">{{Label .}}</v-btn>{{end}}</div>
+  <div v-if="value && value.{{FieldName .}}">
+    <div class="d-flex flex-column" v-for="(it, idx) in value.{{FieldName .}}" :key="idx">
+      <div class="d-flex flex-row align-center justify-space-between mt-3">
+        <{{LookupComponent . true}} v-model="value.{{FieldName .}}[idx]" :disabled="{{if Readonly .}}true{{else}}{{template "DISABLED_IN_FORM" .}}{{end}}" @update:model-value="changed('{{FieldName .}}')"/>
+        <div class="flex-grow-0 flex-shrink-1 ml-2">
+          <v-icon v-if="!disabled" color="error" @click="remove{{FieldName . "U"}}(idx)" title="delete">mdi-close-circle</v-icon>
+        </div>
+      </div>
+      <v-divider v-if="idx < value.{{FieldName .}}.length"></v-divider>
+    </div>
+  </div>
+  </div>
+ {{end}}`
+
+const vue3FormArrayAsChipsTemplate = `{{define "ARRAY_AS_CHIPS"}}<div class="d-flex flex-row align-center" v-if="value">
+  <span class="mr-3">{{Label .}}:</span>
+  <v-chip-group column>
+    <v-chip v-for="(key, idx) in value.{{FieldName .}}" :key="key" closable @click:close="value.{{FieldName .}}.splice(idx, 1); changed('{{FieldName .}}')" color="primary">
+    {{"{{"}}key{{"}}"}}
+    </v-chip>
+  </v-chip-group>
+  <v-text-field
+    class="mx-3"
+    v-model="new{{FieldName . "U"}}Chip"
+    label="Add"
+    @keydown.enter="add{{FieldName . "U"}}Chip()"
+    :disabled="{{template "DISABLED_IN_FORM" .}}"
+  >
+    <template v-slot:append>
+      <v-icon
+        :disabled="!new{{FieldName . "U"}}Chip"
+        color="success"
+        @click="add{{FieldName . "U"}}Chip()"
+      >mdi-plus-box</v-icon>
+    </template>
+  </v-text-field>
+</div>
+{{end}}`
+
+const vue3FormTSTemplate = `
+{{define "FORM.TS"}}
+<script setup lang="ts">
+import { computed, ref{{if LateInitRequired}}, defineAsyncComponent{{end}}{{if NeedSecurity}}, inject{{end}}{{if NeedResourceSecurity}}, onMounted{{end}} } from 'vue';
+import { {{TypeName}}, {{InstanceGeneratorName}} } from '{{TypesFilePath}}';
+{{TypesFromTS}}
+{{range RequiredComponents}}
+import {{.Comp}} from '{{.Imp}}';{{end}}
+{{range AdditionalComponents}}
+import {{.Comp}} from '{{.Imp}}';{{end}}
+{{if NeedSecurity}}{{SecurityImport}}{{end}}
+{{range LateInitRequiredComponents}}
+const {{.Comp}} = defineAsyncComponent(() => import('{{.Imp}}'));{{end}}
+
+const props = withDefaults(defineProps<{
+  modelValue?: {{TypeName}}|null,
+  isNew?: boolean,
+  disabled?: boolean|{[key:string]:boolean},
+  validator?: any,
+}>(), {
+  modelValue: null,
+  isNew: false,
+  disabled: false,
+});
+const emit = defineEmits<{
+  (e: 'update:modelValue', value: {{TypeName}}|null): void,
+  (e: 'change', field: string): void,
+}>();
+
+const value = computed(() => props.modelValue);{{if WithTabs}}
+const tab = ref<string|null>(null);{{end}}
+{{if NeedSecurity}}const loginManager = inject("loginManager") as LoginManager;{{end}}
+{{range Tabs}}{{if ne (ResourceForTab (TabID .)) ""}}const {{TabID .}}Accessible = ref(false);
+{{end}}{{end}}{{range (GetFields .)}}{{if and (eq (FormComponentType .) "array") (ArrayAsChips .)}}const new{{FieldName . "U"}}Chip = ref("");
+{{end}}{{end}}
+function changed(fld: keyof {{TypeName}}) {
+  emit('change', fld);
+  emit('update:modelValue', props.modelValue);
+}
+function addValue() {
+  emit('update:modelValue', {{InstanceGenerator}});
+}
+{{range (GetFields .)}}{{if eq (FormComponentType .) "array"}}{{if ArrayAsList .}}
+function add{{FieldName . "U"}}() {
+  if(!value.value)
+    return;
+  if(!value.value.{{FieldName .}})
+    value.value.{{FieldName .}} = [];
+  value.value.{{FieldName .}}!.push({{InstanceGeneratorForField .}});
+  changed('{{FieldName .}}');
+}
+function remove{{FieldName . "U"}}(idx: number) {
+  if(value.value && value.value.{{FieldName .}} && value.value.{{FieldName .}}[idx]) {
+    value.value.{{FieldName .}}.splice(idx, 1);
+    changed('{{FieldName .}}');
+  }
+}{{else if ArrayAsChips .}}
+function add{{FieldName . "U"}}Chip() {
+  if(!value.value || !new{{FieldName . "U"}}Chip.value)
+    return;
+  if(!value.value.{{FieldName .}})
+    value.value.{{FieldName .}} = [];
+  value.value.{{FieldName .}}!.push(new{{FieldName . "U"}}Chip.value);
+  new{{FieldName . "U"}}Chip.value = "";
+  changed('{{FieldName .}}');
+}{{end}}{{end}}{{end}}
+{{if NeedRolesSecurity}}
+function hasAccess(tab: string): boolean {
+  let roles: string[] = [];
+  switch(tab) {
+    {{range Tabs}}{{if ne (RolesForTab (TabID .)) ""}}case '{{TabID .}}': roles = [{{RolesForTab (TabID .)}}]; break;{{end}}{{end}}
+    default: return true;
+  }
+  for(let r of roles) {
+    if(loginManager.hasRole(r))
+      return true;
+  }
+  return false;
+}{{end}}
+{{if NeedResourceSecurity}}
+async function requestAccessRights() {
+  let resources: string[] = [{{range Tabs}}{{if ne (ResourceForTab (TabID .)) ""}}
+    "{{ResourceForTab (TabID .)}}", {{end}}
+  {{end}}];
+  const result = await loginManager.getResources(resources);
+
+  {{range Tabs}}{{if ne (ResourceForTab (TabID .)) ""}}{{TabID .}}Accessible.value = result.resource("{{ResourceForTab (TabID .)}}") && result.resource("{{ResourceForTab (TabID .)}}")!.checkAccessRight("r") || false;{{end}}
+  {{end}}
+}
+onMounted(requestAccessRights);{{end}}
+function isFieldDisabled(field: string): boolean {
+  return typeof props.disabled == "object" && props.disabled[field];
+}
+</script>
+{{end}}
+`
+
+const vue3FormListTemplate = `
+{{define "FORM-LIST"}}
+<div>
+  <div v-if="modelValue">
+    <div v-for="(d, idx) in modelValue" :key="idx" class="d-flex flex-row align-baseline justify-space-between">
+      <{{FormComponent}} :modelValue="d" @change="onItemChanged"/>
+      <v-btn variant="text" icon="mdi-delete" color="warning" @click="onDelItem(idx)"></v-btn>
+    </div>
+  </div>
+  <v-btn variant="text" icon="mdi-plus" color="primary" @click="onAddItem"></v-btn>
+</div>
+{{end}}
+`
+
+const vue3FormListTSTemplate = `
+{{define "FORM-LIST.TS"}}
+<script setup lang="ts">
+import { {{TypeName}}, {{InstanceGeneratorName}} } from '{{TypesFilePath}}';
+{{range RequiredComponents}}
+import {{.Comp}} from '{{.Imp}}';{{end}}
+
+const props = withDefaults(defineProps<{
+  modelValue?: {{TypeName}}[],
+}>(), {
+  modelValue: () => [],
+});
+const emit = defineEmits<{
+  (e: 'update:modelValue', value: {{TypeName}}[]): void,
+  (e: 'change', value: {{TypeName}}[]): void,
+}>();
+
+function emitValue(value: {{TypeName}}[]) {
+  emit('update:modelValue', value);
+  emit('change', value);
+}
+function onItemChanged() {
+  emitValue(props.modelValue);
+}
+function onDelItem(idx: number) {
+  const value = [...props.modelValue];
+  value.splice(idx, 1);
+  emitValue(value);
+}
+function onAddItem() {
+  emitValue([...(props.modelValue || []), {{InstanceGenerator}}]);
+}
+</script>
+{{end}}
+`
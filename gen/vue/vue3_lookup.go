@@ -0,0 +1,468 @@
+package vue
+
+const vue3TypeDescriptorTSTemplate = `
+export const {{TypeName .}}Descriptor = {
+  id: "{{IDField}}",
+  headers: [{{range (GetFields .)}} {{if ShowInTable .}}
+    {title: "{{Label .}}", key: "{{TableAttrName .}}", {{if NeedIconForTable .}}icon: "{{TableIconName .}}", {{end}}type: "{{GUITableType .}}"{{if ne (GUITableColor .) ""}}, color: "{{GUITableColor .}}"{{end}}{{if ne (GUITableComponent .) ""}}, component: "{{GUITableComponent .}}"{{end}}{{if ne (GUITableTooltip .) ""}}, tooltip: "{{GUITableTooltip .}}"{{end}}, editable: {{EditableInTable .}}{{if ne (FieldRoles .) ""}}, roles: [{{FieldRoles .}}]{{end}}},{{end}}{{end}}
+  ]
+};
+`
+
+// CardView
+const vue3CardViewHTMLTemplate = `
+{{define "HTML"}}
+<template>
+  <v-card>
+    <v-card-text>
+      <v-progress-linear v-if="loading" indeterminate></v-progress-linear>
+      <div class="d-flex flex-row flex-wrap justify-space-around align-baseline" v-if="value">
+        {{range (GetFields .)}}{{if ShowInDialog .}}<div class="mx-2">
+          {{template "VIEW_FIELD" .}}
+        </div>{{end}}
+        {{end}}
+      </div>
+    </v-card-text>
+  </v-card>
+</template>
+{{end}}
+`
+
+// there are no filters in Vue 3, so functions are called instead
+const vue3FieldViewIntTemplate = `{{define "INT_VIEW"}}{{"{{"}}{{Filter "int"}}(value.{{FieldName .}}){{"}}"}}{{end}}`
+const vue3FieldViewFloatTemplate = `{{define "FLOAT_VIEW"}}{{"{{"}}{{Filter "float"}}(value.{{FieldName .}}){{"}}"}}{{end}}`
+const vue3FieldViewDateTemplate = `{{define "DATE_VIEW"}}{{"{{"}}{{Filter "date"}}(value.{{FieldName .}}){{"}}"}}{{end}}`
+
+const vue3ViewTSTemplateHeader = `
+{{define "TS-HEADER"}}
+<script setup lang="ts">
+import { ref, watch } from 'vue';{{if ne (IDType .) "" }}
+import { resolveClient } from '@vue/apollo-composable';{{end}}
+import { {{TypeName .}}{{if ne (IDType .) "" }}, {{GetQuery .}}{{end}} } from '{{TypesFilePath .}}';
+{{FiltersImports}}
+{{TypesFromTS}}
+{{end}}
+`
+const vue3ViewTSTemplateBody = `
+{{define "TS"}}
+const props = withDefaults(defineProps<{
+  item?: {{TypeName .}}{{if ne (IDType .) "" }}|{{IDType .}}{{end}}|null,
+  showEmpty?: boolean,
+}>(), {
+  item: null,
+  showEmpty: false,
+});
+{{if ne (IDType .) "" }}const apolloClient = {{ApolloClient}};
+{{end}}const value = ref<{{TypeName .}}|null>(null);
+const loading = ref(false);
+
+watch(() => props.item, onItemChanged);
+onItemChanged();
+
+function onItemChanged() {
+  if(!props.item) {
+    value.value = null;
+  } else if(typeof props.item == "object") {
+    value.value = props.item;
+  }{{if ne (IDType .) "" }} else if(typeof props.item == "{{IDTypeJS .}}") {
+    load(props.item as {{IDType .}});
+  }{{end}}
+}
+{{if ne (IDType .) "" }}
+async function load(id: {{IDType .}}) {
+  loading.value = true;
+  try {
+    value.value = await {{GetQuery .}}(apolloClient, id);
+  } catch(exc) {
+    console.log("problem: ", exc);
+  }
+  loading.value = false;
+}{{end}}
+</script>
+{{end}}
+`
+
+// Lookups
+
+const vue3LookupHTMLTemplate = `
+{{define "HTML"}}
+<template>
+  <div class="d-flex flex-row">
+    <v-autocomplete
+      v-model="selected"
+      :hint="hint"
+      :items="items"
+      :readonly="readonly"
+      :disabled="disabled"
+      :label="label"
+      item-title="{{ItemText}}"
+      item-value="{{ItemValue}}"
+      :item-props="itemProps"
+      :return-object="returnObject"
+      :loading="loading"
+      :error-messages="problem || errorMessages"
+      hide-no-data
+      hide-details="auto"
+      {{if CanBeMultiple}}:multiple="multiple"
+      :chips="multiple"
+      :closable-chips="multiple"
+      :rules="rules"{{end}}
+      @update:model-value="emit('change')"
+      @update:search="onChange($event)"
+    >
+      <template v-slot:append v-if="!hideAdd && !disabled">
+        {{if LookupWithAdd}}<v-icon
+          color="success"
+          @click="onAdd()"
+        >mdi-plus-box</v-icon>{{end}}
+        <slot name="append"></slot>
+      </template>
+    </v-autocomplete>
+    {{if LookupWithAdd}}<{{DialogComponent .}} v-if="!hideAdd" ref="dialog"/>{{end}}
+  </div>
+</template>
+{{end}}
+`
+
+const vue3DictionaryLookupTSTemplateHeader = `
+{{define "TS-HEADER"}}
+<script setup lang="ts">
+import { ref, watch } from 'vue';
+import { resolveClient } from '@vue/apollo-composable';
+import { {{TypeName .}}, {{ListQuery}} } from '{{TypesFilePath .}}';
+{{if LookupWithAdd}}import {{DialogComponent .}} from './{{DialogComponent .}}.vue';{{end}}
+{{TypesFromTS}}
+{{end}}
+`
+
+const vue3DictionaryLookupTSTemplateBody = `
+{{define "TS"}}
+type Value = {{TypeName .}}|{{IDType .}}{{if CanBeMultiple}}|{{TypeName .}}[]|{{IDType .}}[]{{end}};
+
+const props = withDefaults(defineProps<{
+  modelValue?: Value|null,
+  hint?: string,
+  label?: string,
+  readonly?: boolean,{{if CanBeMultiple}}
+  multiple?: boolean,{{end}}
+  returnObject?: boolean,
+  hideAdd?: boolean,
+  disabled?: boolean,{{if DictWithQualifier .}}
+  qualifier?: any,
+  qualifiedByObject?: boolean,
+  allowEmptyQualifier?: boolean,{{end}}
+  rules?: string[] | ((v:any)=>string|boolean)[],
+  errorMessages?: string|string[],
+  filter?: (value: {{TypeName .}}) => boolean,
+  autoSelect?: ((value: {{TypeName .}}) => boolean)|string|number|boolean,
+  disabledProperty?: string,
+}>(), {
+  modelValue: null,{{if CanBeMultiple}}
+  multiple: false,{{end}}
+  returnObject: true,
+  hideAdd: false,
+  disabled: false,{{if DictWithQualifier .}}
+  qualifiedByObject: true,{{end}}
+  rules: () => [],
+  errorMessages: () => [],
+  disabledProperty: "disabled",
+});
+const emit = defineEmits<{
+  (e: 'update:modelValue', value: Value|null): void,
+  (e: 'change'): void,
+}>();
+
+const apolloClient = {{ApolloClient}};{{if LookupWithAdd}}
+const dialog = ref<InstanceType<typeof {{DialogComponent .}}>|null>(null);{{end}}
+const selected = ref<Value|null>(null);
+const items = ref<{{TypeName .}}[]>([]);
+const loading = ref(false);
+const problem = ref("");
+
+watch(() => props.modelValue, onValueChange);
+watch(selected, () => emit('update:modelValue', selected.value));{{if DictWithQualifier .}}
+watch(() => props.qualifier, load);{{end}}
+onCreated();
+
+function itemProps(item: any) {
+  return {disabled: !!item[props.disabledProperty]};
+}
+function onValueChange() {
+  selected.value = props.modelValue;
+}
+async function onCreated() {
+  await load();
+  if(props.modelValue != undefined)
+    onValueChange();
+}
+async function load() {
+  {{if DictWithQualifier .}}if(!({{IsQualifierFilled}}) && !props.allowEmptyQualifier)
+    return;
+  {{end}}loading.value = true;
+  items.value = [];
+  problem.value = "";
+  try {
+    const res = await {{ListQuery}}(apolloClient{{if ne ListQueryAttrs ""}}, {{ListQueryAttrs}}{{end}});
+    if(res) {
+      items.value = res;
+      if(props.filter) {
+        items.value = items.value.filter(props.filter);
+      }
+      const autoSelect = props.autoSelect;
+      if(autoSelect) {
+        if(typeof autoSelect == "function") {
+          selected.value = items.value.find(autoSelect) || null;
+        } else if(typeof autoSelect == "string") {
+          selected.value = items.value.find(item => item[autoSelect as keyof {{TypeName .}}]) || null;
+        } else if(typeof autoSelect == "number") {
+          if(items.value.length > autoSelect)
+            selected.value = items.value[autoSelect];
+        } else if(typeof autoSelect == "boolean") {
+          if(items.value.length)
+            selected.value = items.value[0];
+        }
+      }
+    }
+  } catch(exc: any) {
+    problem.value = exc.toString();
+  }
+  loading.value = false;
+}
+{{if LookupWithAdd}}
+async function onAdd() {
+  try {
+    const res = await dialog.value!.show(null);
+    if(res) {
+      await load();
+      selected.value = res;
+      emit('change');
+    }
+  } catch(exc: any) {
+    problem.value = exc.toString();
+  }
+}{{end}}
+function onChange(search: string) {
+}
+</script>
+{{end}}
+`
+const vue3EntityLookupTSTemplateHeader = `
+{{define "TS-HEADER"}}
+<script setup lang="ts">
+import { ref, watch{{if HasFindType}}, onMounted{{end}} } from 'vue';
+import { resolveClient } from '@vue/apollo-composable';
+import { {{TypeName .}}, {{LookupQuery}}, {{GetQuery .}}{{if HasFindType}}, {{FindQuery}}, {{FindTypeName}}{{end}} } from '{{TypesFilePath .}}';
+import {{DialogComponent .}} from './{{DialogComponent .}}.vue';
+{{end}}
+`
+
+const vue3EntityLookupTSTemplateBody = `
+{{define "TS"}}
+type Value = {{TypeName .}}|{{IDType .}}{{if CanBeMultiple}}|{{TypeName .}}[]|{{IDType .}}[]{{end}};
+
+const props = withDefaults(defineProps<{
+  modelValue?: Value|null,
+  hint?: string,
+  label?: string,
+  readonly?: boolean,
+  returnObject?: boolean,
+  hideAdd?: boolean,
+  disabled?: boolean,{{if CanBeMultiple}}
+  multiple?: boolean,{{end}}
+  rules?: string[] | ((v:any)=>string|boolean)[],
+  errorMessages?: string|string[],{{if HasFindType}}
+  query?: {{FindTypeName}}|null,{{end}}
+  filter?: (value: {{TypeName .}}) => boolean,
+  disabledProperty?: string,
+}>(), {
+  modelValue: null,
+  returnObject: true,
+  hideAdd: false,
+  disabled: false,{{if CanBeMultiple}}
+  multiple: false,{{end}}
+  rules: () => [],
+  errorMessages: () => [],{{if HasFindType}}
+  query: null,{{end}}
+  disabledProperty: "disabled",
+});
+const emit = defineEmits<{
+  (e: 'update:modelValue', value: Value|null): void,
+  (e: 'change'): void,
+}>();
+
+const apolloClient = {{ApolloClient}};
+const dialog = ref<InstanceType<typeof {{DialogComponent .}}>|null>(null);
+const selected = ref<Value|null>(null);
+const items = ref<{{TypeName .}}[]>([]);
+const loading = ref(false);
+const problem = ref("");
+let lastSearch: string|null = null;
+let searchString = "";
+let timer: any = null;
+
+watch(() => props.modelValue, onValueChange);
+watch(selected, () => emit('update:modelValue', selected.value));{{if HasFindType}}
+watch(() => props.query, onQueryChange);
+onMounted(onQueryChange);{{end}}
+if(props.modelValue)
+  onValueChange();
+
+function itemProps(item: any) {
+  return {disabled: !!item[props.disabledProperty]};
+}
+function onValueChange() {
+  selected.value = props.modelValue;
+  if(selected.value && !items.value.length) {
+    if(props.returnObject)
+      items.value = {{if CanBeMultiple}}props.multiple ? selected.value as {{TypeName .}}[] : {{end}}[selected.value as {{TypeName .}}];
+    else
+      fillSelectedFromId(props.modelValue as {{IDType .}});
+  }
+}{{if HasFindType}}
+function onQueryChange() {
+  if(props.query)
+    search();
+}{{end}}
+async function search() {
+  loading.value = true;
+  items.value = [];
+  problem.value = "";
+  try { {{if HasFindType}}
+    let useQuery = false;
+    if(props.query) {
+      let prop: keyof {{FindTypeName}};
+      for(prop in props.query) {
+        if(props.query[prop]) {
+          useQuery = true;
+        }
+      }
+    }
+    if(useQuery) {
+      items.value = await {{FindQuery}}(apolloClient, props.query!);
+    } else if(searchString) { {{end}}
+      lastSearch = searchString;
+      const res = await {{LookupQuery}}(apolloClient, lastSearch);
+      if(res) {
+        items.value = res;
+      }{{if HasFindType}}
+    }{{end}}
+    if(props.filter) {
+      items.value = items.value.filter(props.filter);
+    }
+  } catch(exc: any) {
+    problem.value = exc.toString();
+  }
+  loading.value = false;
+}
+
+async function onAdd() {
+  try {
+    const res = await dialog.value!.show(null);
+    if(res) {
+      items.value = [res];
+      selected.value = res;
+      emit('change');
+    }
+  } catch(exc: any) {
+    problem.value = exc.toString();
+  }
+}
+function doSearch() {
+  timer = null;
+  if(searchString && (!lastSearch || !searchString.startsWith(lastSearch))) {
+    if(loading.value)
+      onChange(searchString);
+    else
+      search();
+  }
+}
+function onChange(event: string) { {{if HasFindType}}
+  if(props.query)
+    return;{{end}}
+  if(searchString == event {{if ItemTypeIsString}}|| selected.value && (selected.value as {{TypeName .}}).{{ItemText}} && (selected.value as {{TypeName .}}).{{ItemText}} == event{{end}})
+    return;
+  if(timer)
+    clearTimeout(timer);
+  timer = setTimeout(() => doSearch(), 500);
+  searchString = event;
+}
+async function fillSelectedFromId(id: {{IDType .}}) {
+  try {
+    const res = await {{GetQuery .}}(apolloClient, id);
+    if(res) {
+      items.value = [res];
+      selected.value = props.returnObject ? res : res.{{ItemValue}};
+    }
+  } catch(exc: any) {
+    problem.value = exc.toString();
+  }
+}
+</script>
+{{end}}
+`
+
+// Enums
+
+const vue3EnumLookupHTMLTemplate = `
+{{define "HTML"}}
+<template>
+  <div class="d-flex flex-row">
+    <v-select
+      v-model="selected"
+      :hint="hint"
+      :items="items"
+      :readonly="readonly"
+      :disabled="disabled"
+      :label="label"
+      :multiple="multiple"
+      :chips="multiple"
+      hide-no-data
+      hide-details="auto"
+      @update:model-value="emit('change')"
+    >
+    </v-select>
+  </div>
+</template>
+{{end}}
+`
+
+const vue3EnumLookupTSTemplate = `
+{{define "TS"}}
+<script setup lang="ts">
+import { ref, watch } from 'vue';
+import { {{TypeName .}}, {{range .Fields}}{{.Name}}, {{end}}} from '{{TypesFilePath .}}';
+
+type Value = {{TypeName .}}|{{TypeName .}}[];
+
+const props = withDefaults(defineProps<{
+  modelValue?: Value|null,
+  hint?: string,
+  label?: string,
+  readonly?: boolean,
+  disabled?: boolean,
+  multiple?: boolean,
+}>(), {
+  modelValue: null,
+  disabled: false,
+  multiple: false,
+});
+const emit = defineEmits<{
+  (e: 'update:modelValue', value: Value|null): void,
+  (e: 'change'): void,
+}>();
+
+const selected = ref<Value|null>({{DefaultValue .}});
+const items = [
+  {{range .Fields}}{title: "{{FieldLabel .}}", value: {{.Name}}},{{end}}
+];
+
+watch(() => props.modelValue, onValueChange);
+watch(selected, () => emit('update:modelValue', selected.value));
+onValueChange();
+
+function onValueChange() {
+  selected.value = props.modelValue;
+}
+</script>
+{{end}}
+`
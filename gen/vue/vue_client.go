@@ -3,7 +3,6 @@ package vue
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -233,16 +232,27 @@ type ClientGenerator struct {
 	desc    *gen.Package
 	options ClientOptions
 	b       *gen.Builder
+	flavor  *flavor
 }
 
 func init() {
-	gen.RegisterPlugin(&ClientGenerator{})
+	gen.RegisterPluginFactory(func() gen.Generator { return &ClientGenerator{} })
 }
 
 func (cg *ClientGenerator) Name() string {
 	return vueGeneratorName
 }
 
+// Requires from gen.PluginsRequirer
+func (cg *ClientGenerator) Requires() []string {
+	return []string{js.GQLClientGeneratorName}
+}
+
+// After from gen.PluginsFollower
+func (cg *ClientGenerator) After() []string {
+	return []string{js.ValidatorGeneratorName}
+}
+
 func (cg *ClientGenerator) SetOptions(options any) error {
 	return gen.OptionsAnyToStruct(options, &cg.options)
 }
@@ -268,13 +278,63 @@ func (cg *ClientGenerator) CheckAnnotation(desc *gen.Package, ann *gen.Annotatio
 	return false, nil
 }
 
+// AnnotationSchemas from gen.AnnotationsDescriber; layout annotations accept many tags depending on
+// the context, so only the common ones are listed
+func (cg *ClientGenerator) AnnotationSchemas() []gen.AnnotationSchema {
+	common := []gen.AnnotationTagSchema{
+		{Name: vueAnnotationIgnore, Type: gen.AnnotationTagAny, Doc: "do not generate component"},
+		{
+			Name: vueAnnotationUse,
+			Type: gen.AnnotationTagAny,
+			Doc:  "use existing component ('ComponentName from fileName'); for field: use it in qualified form",
+		},
+		{Name: vcaLabel, Type: gen.AnnotationTagString, Doc: "label of field or title of component"},
+		{Name: vcaWidth, Type: gen.AnnotationTagAny, Doc: "width of component or field"},
+	}
+	field := append(
+		common,
+		gen.AnnotationTagSchema{Name: vueAnnotationDisplayType, Type: gen.AnnotationTagString, Doc: "how field is displayed"},
+		gen.AnnotationTagSchema{Name: vueAnnotationReadonly, Type: gen.AnnotationTagAny, Doc: "field can not be changed in form"},
+		gen.AnnotationTagSchema{Name: vcaTab, Type: gen.AnnotationTagString, Doc: "id of tab with field"},
+		gen.AnnotationTagSchema{Name: vcaRow, Type: gen.AnnotationTagNumber, Doc: "row of field in form"},
+		gen.AnnotationTagSchema{Name: vcaOrder, Type: gen.AnnotationTagNumber, Default: 1000, Doc: "order of field in form"},
+		gen.AnnotationTagSchema{Name: vcaIf, Type: gen.AnnotationTagString, Doc: "condition (js) when field is shown in form"},
+		gen.AnnotationTagSchema{Name: vcaTextArea, Type: gen.AnnotationTagAny, Doc: "use text area (true or number of lines)"},
+	)
+	schema := func(name string, tags []gen.AnnotationTagSchema, doc string) gen.AnnotationSchema {
+		return gen.AnnotationSchema{Name: name, Qualified: true, Targets: gen.AnnotationTargetAny, Tags: tags, AnyTags: true, Doc: doc}
+	}
+	return []gen.AnnotationSchema{
+		schema(vueAnnotation, field, "layout of type or field in Vue components (qualifier selects named form)"),
+		schema(vueFormAnnotation, common, "form component of type (qualifier sets name of form)"),
+		schema(vueViewAnnotation, common, "view component of type"),
+		schema(vueDialogAnnotation, common, "dialog component of type"),
+		schema(vueLookupAnnotation, common, "lookup component of type"),
+		schema(vueTableAnnotation, common, "table component of type"),
+		schema(vueTabSet, nil, "tabs of forms: tabid=\"tab label\""),
+		schema(vueTab, nil, "tab description (vue-tab:tabid)"),
+		{
+			Name:    gen.AnnotationConfig,
+			Targets: gen.AnnotationTargetEntity,
+			Tags: []gen.AnnotationTagSchema{
+				{Name: gen.AnnCfgValue, Type: gen.AnnotationTagBool, Default: false, Doc: "type describes config value"},
+			},
+			AnyTags: true,
+			Doc:     "config type settings",
+		},
+	}
+}
+
 func (cg *ClientGenerator) Prepare(desc *gen.Package) error {
 	cg.desc = desc
-	if _, err := desc.Options().CustomToStruct(VCOptions, &cg.options); err != nil {
-		desc.AddWarning(fmt.Sprintf("problem while setting custom options for vue: %v", err))
+	if cg.flavor == nil {
+		cg.flavor = vue2Flavor
+	}
+	if _, err := desc.Options().CustomToStruct(cg.flavor.optionsName, &cg.options); err != nil {
+		desc.AddWarning(fmt.Sprintf("problem while setting custom options for %s: %v", cg.flavor.optionsName, err))
 	}
 	if cg.options.ApolloClientVar == "" {
-		cg.options.ApolloClientVar = vcoApolloClientDef
+		cg.options.ApolloClientVar = cg.flavor.apolloClient
 	}
 	//outDir := cg.getOutputDir()
 	for _, file := range desc.Files {
@@ -380,6 +440,12 @@ func (cg *ClientGenerator) Prepare(desc *gen.Package) error {
 							f.Annotations.AddTag(vueFormAnnotation, vueAnnotationIgnore, true)
 							continue
 						}
+						// there are no forms for unions and interfaces yet
+						if f.IsPolymorphic() {
+							f.Annotations.AddTag(vueFormAnnotation, vueAnnotationIgnore, true)
+							f.Annotations.AddTag(vueTableAnnotation, vueAnnotationIgnore, true)
+							continue
+						}
 						if _, ok := f.Annotations.GetStringAnnotation(vueAnnotation, vcaIf); ok {
 							err := cg.checkFieldIfStatment(t, f)
 							if err != nil {
@@ -503,31 +569,27 @@ func (cg *ClientGenerator) generateFor(outDir string, e *gen.Entity) (err error)
 		if th.idField != nil {
 			p := e.FS(featureVueKind, fVKLookupComponentPath)
 			p = filepath.Join(outDir, p)
-			f, err := os.Create(p)
-			if err != nil {
-				return fmt.Errorf("while opening file for LookupComponent for %s: %v", e.Name, err)
-
-			}
+			f := cg.createFile(p)
 			defer f.Close()
 			if e.IsDictionary() {
-				th.parse(vueDictionaryLookupTSTemplateBody)
+				th.parse(cg.flavor.dictionaryLookupTSBody)
 			} else {
-				th.parse(vueEntityLookupTSTemplateBody)
+				th.parse(cg.flavor.entityLookupTSBody)
 			}
-			th.parse(htmlLookupTemplate).parse("{{template \"TS\" .}}\n{{template \"HTML\" .}}\n")
+			th.parse(cg.flavor.lookupHTML).parse("{{template \"TS\" .}}\n{{template \"HTML\" .}}\n")
 			if th.err != nil {
 				return fmt.Errorf("Error while parsing template for LookupComponent: %v\n", th.err)
 			}
 			buffer := bytes.Buffer{}
-			err = th.templ.Execute(&buffer, th)
+			err := th.templ.Execute(&buffer, th)
 			if err != nil {
 				return fmt.Errorf("while executing template for LookupComponent for %s: %v", th.e.Name, err)
 			}
 
 			if e.IsDictionary() {
-				th.parse(vueDictionaryLookupTSTemplateHeader)
+				th.parse(cg.flavor.dictionaryLookupTSHeader)
 			} else {
-				th.parse(vueEntityLookupTSTemplateHeader)
+				th.parse(cg.flavor.entityLookupTSHeader)
 			}
 			th.parse("{{template \"TS-HEADER\" .}}\n")
 			if th.err != nil {
@@ -552,12 +614,9 @@ func (cg *ClientGenerator) generateFor(outDir string, e *gen.Entity) (err error)
 				return err
 			}
 			p = filepath.Join(outDir, p)
-			f, err := os.Create(p)
-			if err != nil {
-				return fmt.Errorf("while opening file for TypeDescriptor for %s: %v", e.Name, err)
-			}
+			f := cg.createFile(p)
 			defer f.Close()
-			th.parse(typeDescriptorTSTemplate)
+			th.parse(cg.flavor.typeDescriptorTS)
 			if th.err != nil {
 				return fmt.Errorf("while parsing template: %v", th.err)
 			}
@@ -572,31 +631,20 @@ func (cg *ClientGenerator) generateFor(outDir string, e *gen.Entity) (err error)
 		if p != "" {
 			th, err = cg.newFormHelper("VIEW", e, vueViewAnnotation, "", outDir)
 			p = filepath.Join(outDir, p)
-			f, err := os.Create(p)
-			if err != nil {
-				return fmt.Errorf("while opening file for ViewComponent for %s: %v", e.Name, err)
-			}
+			f := cg.createFile(p)
 			defer f.Close()
-			th.parse(htmlCardViewTemplate).
-				parse(htmlFieldViewTemplate).
-				parse(htmlFieldViewBoolTemplate).
-				parse(htmlFieldViewComplexTemplate).
-				parse(htmlFieldViewDateTemplate).
-				parse(htmlFieldViewFloatTemplate).
-				parse(htmlFieldViewIntTemplate).
-				parse(htmlFieldViewTextTemplate).
-				parse(vueViewTSTemplateBody).
-				parse(htmlViewCSS).
+			th.parse(cg.flavor.viewTemplates...).
+				parse(cg.flavor.viewTSBody).
 				parse("{{template \"TS\" .}}\n{{template \"HTML\" .}}\n{{template \"CSS\" .}}\n")
 			if th.err != nil {
 				return fmt.Errorf("error while parsing view template: %v", th.err)
 			}
 			buffer := bytes.Buffer{}
-			err = th.templ.Execute(&buffer, th)
+			err := th.templ.Execute(&buffer, th)
 			if err != nil {
 				return fmt.Errorf("while executing template for ViewComponent for %s: %v", e.Name, err)
 			}
-			th.parse(vueViewTSTemplateHeader).
+			th.parse(cg.flavor.viewTSHeader).
 				parse("{{template \"TS-HEADER\" .}}\n")
 			if th.err != nil {
 				return fmt.Errorf("error while parsing template for View: %v", th.err)
@@ -679,9 +727,13 @@ func (cg *ClientGenerator) generateFor(outDir string, e *gen.Entity) (err error)
 	return nil
 }
 
+// createFile returns file that is written on Close only if its content is changed
+func (cg *ClientGenerator) createFile(path string) *gen.OutputFile {
+	return cg.desc.Project.CreateFile(path)
+}
+
 func (cg *ClientGenerator) getOutputDir() (ret string) {
 	ret = filepath.Join(cg.getClientOutputDir(), "components")
-	os.MkdirAll(ret, os.ModeDir|os.ModePerm)
 	return
 }
 
@@ -706,12 +758,7 @@ func (cg *ClientGenerator) getOutputDirForEnum(e *gen.Enum) (ret string) {
 }
 
 func (cg *ClientGenerator) getOutputDirForFile(packageName, fileName string) (ret string) {
-	dir := filepath.Join(cg.getOutputDir(), packageName, fileName)
-	err := os.MkdirAll(dir, os.ModeDir|os.ModePerm)
-	if err != nil {
-		cg.desc.AddError(err)
-	}
-	return dir
+	return filepath.Join(cg.getOutputDir(), packageName, fileName)
 }
 
 func (cg *ClientGenerator) pathToRelative(from, to string) (ret string) {
@@ -741,7 +788,7 @@ func (cg *ClientGenerator) getJSAttrNameForDisplay(f *gen.Field, forTable bool,
 		}
 	}
 	if f.Type.Complex && !custom {
-		if f.Type.Array != nil || f.Type.Map != nil {
+		if f.Type.Array != nil || f.Type.Map != nil || f.IsPolymorphic() {
 			return ""
 		} else if t, ok := cg.desc.FindType(f.Type.Type); ok && t.Entity() != nil {
 			found := false
@@ -916,7 +963,12 @@ var htmlCardViewTemplate = `
 const htmlFieldViewTemplate = `{{define "VIEW_FIELD"}}{{if ShowInView .}}<div v-if="value.{{AttrName .}} != undefined || showEmpty" class="d-flex flex-column"><div class="field-value">{{if eq (TypeForView .) "string"}}{{template "TEXT_VIEW" .}}
   {{else if eq (TypeForView .) "int"}}{{template "TEXT_VIEW" .}}
   {{else if eq (TypeForView .) "float"}}{{template "TEXT_VIEW" .}}
+  {{else if eq (TypeForView .) "decimal"}}{{template "TEXT_VIEW" .}}
+  {{else if eq (TypeForView .) "time"}}{{template "TEXT_VIEW" .}}
+  {{else if eq (TypeForView .) "duration"}}{{template "TEXT_VIEW" .}}
+  {{else if eq (TypeForView .) "uuid"}}{{template "TEXT_VIEW" .}}
   {{else if eq (TypeForView .) "date"}}{{template "DATE_VIEW" .}}
+  {{else if eq (TypeForView .) "datetime"}}{{template "DATE_VIEW" .}}
   {{else if eq (TypeForView .) "bool"}}{{template "BOOL_VIEW" .}}
   {{else}}{{template "COMPLEX_VIEW" .}}{{end}}</div>
   <div class="field-title">{{Label .}}</div></div>{{end}}{{end}}`
@@ -6,6 +6,7 @@ require (
 	github.com/alecthomas/participle v0.7.1
 	github.com/dave/jennifer v1.6.1
 	github.com/dop251/goja v0.0.0-20230621100801-7749907a8a20
+	github.com/fsnotify/fsnotify v1.6.0
 	github.com/graphql-go/graphql v0.8.0
 	github.com/graphql-go/handler v0.2.3
 	github.com/jmoiron/sqlx v1.3.5
@@ -23,7 +24,6 @@ require (
 
 require (
 	github.com/dlclark/regexp2 v1.7.0 // indirect
-	github.com/fsnotify/fsnotify v1.6.0 // indirect
 	github.com/go-sourcemap/sourcemap v2.1.3+incompatible // indirect
 	github.com/golang/snappy v0.0.1 // indirect
 	github.com/google/pprof v0.0.0-20230207041349-798e818bf904 // indirect
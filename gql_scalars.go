@@ -0,0 +1,239 @@
+package vivard
+
+import (
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// names of custom GQL scalars (may be used with GQLDescriptor.GetType and GetInputType)
+const (
+	GQLScalarDecimal  = "Decimal"
+	GQLScalarTime     = "Time"
+	GQLScalarDuration = "Duration"
+	GQLScalarUUID     = "UUID"
+	GQLScalarBytes    = "Bytes"
+)
+
+// GQLDecimal - scalar for Decimal; serialized as string
+var GQLDecimal = graphql.NewScalar(
+	graphql.ScalarConfig{
+		Name:        GQLScalarDecimal,
+		Description: "Decimal number in string representation",
+		Serialize: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case Decimal:
+				return v.String()
+			case *Decimal:
+				if v == nil {
+					return nil
+				}
+				return v.String()
+			}
+			return nil
+		},
+		ParseValue: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case string:
+				if d, err := ParseDecimal(v); err == nil {
+					return d
+				}
+			case float64:
+				return DecimalFromFloat(v)
+			case int:
+				return DecimalFromInt(int64(v))
+			}
+			return nil
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			switch v := valueAST.(type) {
+			case *ast.StringValue:
+				if d, err := ParseDecimal(v.Value); err == nil {
+					return d
+				}
+			case *ast.FloatValue:
+				if d, err := ParseDecimal(v.Value); err == nil {
+					return d
+				}
+			case *ast.IntValue:
+				if d, err := ParseDecimal(v.Value); err == nil {
+					return d
+				}
+			}
+			return nil
+		},
+	},
+)
+
+// GQLTime - scalar for TimeOfDay; serialized as string "15:04:05"
+var GQLTime = graphql.NewScalar(
+	graphql.ScalarConfig{
+		Name:        GQLScalarTime,
+		Description: "Time of day in form HH:MM:SS",
+		Serialize: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case TimeOfDay:
+				return v.String()
+			case *TimeOfDay:
+				if v == nil {
+					return nil
+				}
+				return v.String()
+			}
+			return nil
+		},
+		ParseValue: func(value interface{}) interface{} {
+			if s, ok := value.(string); ok {
+				if t, err := ParseTimeOfDay(s); err == nil {
+					return t
+				}
+			}
+			return nil
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			if v, ok := valueAST.(*ast.StringValue); ok {
+				if t, err := ParseTimeOfDay(v.Value); err == nil {
+					return t
+				}
+			}
+			return nil
+		},
+	},
+)
+
+// GQLDuration - scalar for time.Duration; serialized as string like "1h30m0s"; number of seconds is accepted as input
+var GQLDuration = graphql.NewScalar(
+	graphql.ScalarConfig{
+		Name:        GQLScalarDuration,
+		Description: "Duration in Go notation (e.g. 1h30m); number is treated as seconds",
+		Serialize: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case time.Duration:
+				return v.String()
+			case *time.Duration:
+				if v == nil {
+					return nil
+				}
+				return v.String()
+			}
+			return nil
+		},
+		ParseValue: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case string:
+				if d, err := time.ParseDuration(v); err == nil {
+					return d
+				}
+			case int:
+				return time.Duration(v) * time.Second
+			case float64:
+				return time.Duration(v * float64(time.Second))
+			}
+			return nil
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			switch v := valueAST.(type) {
+			case *ast.StringValue:
+				if d, err := time.ParseDuration(v.Value); err == nil {
+					return d
+				}
+			case *ast.IntValue:
+				if i, err := strconv.Atoi(v.Value); err == nil {
+					return time.Duration(i) * time.Second
+				}
+			}
+			return nil
+		},
+	},
+)
+
+// GQLUUID - scalar for UUID
+var GQLUUID = graphql.NewScalar(
+	graphql.ScalarConfig{
+		Name:        GQLScalarUUID,
+		Description: "UUID in canonical form",
+		Serialize: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case UUID:
+				return v.String()
+			case *UUID:
+				if v == nil {
+					return nil
+				}
+				return v.String()
+			}
+			return nil
+		},
+		ParseValue: func(value interface{}) interface{} {
+			if s, ok := value.(string); ok {
+				if u, err := ParseUUID(s); err == nil {
+					return u
+				}
+			}
+			return nil
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			if v, ok := valueAST.(*ast.StringValue); ok {
+				if u, err := ParseUUID(v.Value); err == nil {
+					return u
+				}
+			}
+			return nil
+		},
+	},
+)
+
+// GQLBytes - scalar for []byte; serialized as base64 string
+var GQLBytes = graphql.NewScalar(
+	graphql.ScalarConfig{
+		Name:        GQLScalarBytes,
+		Description: "Binary data in base64 encoding",
+		Serialize: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case []byte:
+				return base64.StdEncoding.EncodeToString(v)
+			case *[]byte:
+				if v == nil {
+					return nil
+				}
+				return base64.StdEncoding.EncodeToString(*v)
+			}
+			return nil
+		},
+		ParseValue: func(value interface{}) interface{} {
+			if s, ok := value.(string); ok {
+				if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+					return b
+				}
+			}
+			return nil
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			if v, ok := valueAST.(*ast.StringValue); ok {
+				if b, err := base64.StdEncoding.DecodeString(v.Value); err == nil {
+					return b
+				}
+			}
+			return nil
+		},
+	},
+)
+
+func getGQLScalar(name string) (*graphql.Scalar, bool) {
+	switch name {
+	case GQLScalarDecimal:
+		return GQLDecimal, true
+	case GQLScalarTime:
+		return GQLTime, true
+	case GQLScalarDuration:
+		return GQLDuration, true
+	case GQLScalarUUID:
+		return GQLUUID, true
+	case GQLScalarBytes:
+		return GQLBytes, true
+	}
+	return nil, false
+}
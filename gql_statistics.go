@@ -34,6 +34,19 @@ type queryStatistics struct {
 	errors       []string
 }
 
+// deprecatedUsage is sent to statistics processor by resolvers of deprecated fields
+type deprecatedUsage struct {
+	name string
+	at   time.Time
+}
+
+type deprecatedUsageStatistics struct {
+	name        string
+	count       int
+	firstUsedAt time.Time
+	lastUsedAt  time.Time
+}
+
 type statistic struct {
 	from        time.Time
 	to          time.Time
@@ -82,6 +95,17 @@ func (gqe *GQLEngine) statisticsProcessor() {
 	}
 }
 
+func (gqe *GQLEngine) deprecatedUsed(name string) {
+	if !gqe.collectStatistics {
+		return
+	}
+	if len(gqe.statisticsChannel) < cap(gqe.statisticsChannel) {
+		gqe.statisticsChannel <- deprecatedUsage{name: name, at: time.Now()}
+	} else if gqe.log != nil {
+		gqe.log.Warn("deprecatedUsed: statisticsChannel is overcrowded; skipping statistics")
+	}
+}
+
 func (gqe *GQLEngine) collectQueryStatistics(qs queryStatistics) {
 	if len(gqe.statisticsChannel) < cap(gqe.statisticsChannel) {
 		gqe.statisticsChannel <- qs
@@ -99,7 +123,29 @@ func (gqe *GQLEngine) doProcessStatistics(s interface{}) {
 	switch s.(type) {
 	case queryStatistics:
 		gqe.doProcessQueryStatistics(s.(queryStatistics))
+	case deprecatedUsage:
+		gqe.doProcessDeprecatedUsage(s.(deprecatedUsage))
+	}
+}
+
+func (gqe *GQLEngine) doProcessDeprecatedUsage(du deprecatedUsage) {
+	gqe.statisticsMux.Lock()
+	defer gqe.statisticsMux.Unlock()
+	if gqe.deprecatedUsage == nil {
+		return
 	}
+	st, ok := gqe.deprecatedUsage[du.name]
+	if !ok {
+		st = &deprecatedUsageStatistics{name: du.name, firstUsedAt: du.at}
+		gqe.deprecatedUsage[du.name] = st
+		if gqe.log != nil {
+			gqe.log.Warn("deprecated field is used", zap.String("field", du.name))
+		} else {
+			fmt.Printf("deprecated field is used: %s\n", du.name)
+		}
+	}
+	st.count++
+	st.lastUsedAt = du.at
 }
 
 func (gqe *GQLEngine) doProcessQueryStatistics(qs queryStatistics) {
@@ -356,6 +402,42 @@ func (gqe *GQLEngine) getStatisticsSchema() (graphql.Schema, error) {
 			},
 		},
 	)
+	var deprecatedUsageType = graphql.NewObject(
+		graphql.ObjectConfig{
+			Name: "DeprecatedUsage",
+			Fields: graphql.Fields{
+				"name": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "Type and field (query and mutation are types Query and Mutation)",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						s := p.Source.(deprecatedUsageStatistics)
+						return s.name, nil
+					},
+				},
+				"count": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						s := p.Source.(deprecatedUsageStatistics)
+						return s.count, nil
+					},
+				},
+				"firstUsedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.DateTime),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						s := p.Source.(deprecatedUsageStatistics)
+						return s.firstUsedAt, nil
+					},
+				},
+				"lastUsedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.DateTime),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						s := p.Source.(deprecatedUsageStatistics)
+						return s.lastUsedAt, nil
+					},
+				},
+			},
+		},
+	)
 	var optionsType = graphql.NewObject(
 		graphql.ObjectConfig{
 			Name: "Options",
@@ -441,6 +523,19 @@ func (gqe *GQLEngine) getStatisticsSchema() (graphql.Schema, error) {
 					return res, nil
 				},
 			},
+			"deprecated": &graphql.Field{
+				Type:        graphql.NewList(deprecatedUsageType),
+				Description: "List usage of deprecated fields and operations since statistics collection start",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					gqe.statisticsMux.RLock()
+					defer gqe.statisticsMux.RUnlock()
+					res := make([]deprecatedUsageStatistics, 0, len(gqe.deprecatedUsage))
+					for _, st := range gqe.deprecatedUsage {
+						res = append(res, *st)
+					}
+					return res, nil
+				},
+			},
 		},
 	}
 	mutation := graphql.ObjectConfig{
@@ -0,0 +1,212 @@
+// Package gqlclient is the runtime for Go clients generated by vivgen (GoClient plugin):
+// it sends GraphQL requests over HTTP and decodes results and GraphQL errors
+package gqlclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client sends GraphQL requests to the endpoint
+type Client struct {
+	url        string
+	httpClient *http.Client
+	header     http.Header
+	editors    []RequestEditor
+}
+
+// Option configures Client
+type Option func(c *Client)
+
+// RequestEditor may change request before it is sent (e.g. for adding authorization)
+type RequestEditor func(ctx context.Context, req *http.Request) error
+
+// Error is GraphQL error returned by server
+type Error struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Errors is the list of GraphQL errors returned by server
+type Errors []Error
+
+// HTTPError is returned when server responds with not successful status and without GraphQL errors
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+// KeyValue is item of list that represents map in GraphQL API
+type KeyValue struct {
+	Key interface{} `json:"key"`
+	Val interface{} `json:"val"`
+}
+
+// Duration is time.Duration encoded as string like "1h30m0s" (as vivard Duration scalar does)
+type Duration time.Duration
+
+type request struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors Errors                     `json:"errors"`
+}
+
+// New creates client for GraphQL endpoint url; http.DefaultClient is used if WithHTTPClient option is not given
+func New(url string, opts ...Option) *Client {
+	c := &Client{
+		url:        url,
+		httpClient: http.DefaultClient,
+		header:     http.Header{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithHTTPClient sets http.Client (and so transport, timeouts etc.) for requests
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithHeader adds header to each request
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithRequestEditor adds function that is called for each request before it is sent
+func WithRequestEditor(fn RequestEditor) Option {
+	return func(c *Client) {
+		c.editors = append(c.editors, fn)
+	}
+}
+
+// Do sends query with variables and decodes field of response data into result;
+// GraphQL errors are returned as Errors
+func (c *Client) Do(ctx context.Context, query string, variables map[string]interface{}, field string, result interface{}) error {
+	body, err := json.Marshal(request{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("gqlclient: encoding request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for _, edit := range c.editors {
+		if err = edit(ctx, req); err != nil {
+			return err
+		}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var res response
+	if err = json.Unmarshal(data, &res); err != nil {
+		if resp.StatusCode/100 != 2 {
+			return &HTTPError{StatusCode: resp.StatusCode, Body: string(data)}
+		}
+		return fmt.Errorf("gqlclient: decoding response: %w", err)
+	}
+	if len(res.Errors) > 0 {
+		return res.Errors
+	}
+	if resp.StatusCode/100 != 2 {
+		return &HTTPError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+	if result == nil {
+		return nil
+	}
+	val, ok := res.Data[field]
+	if !ok {
+		return fmt.Errorf("gqlclient: there is no '%s' in response", field)
+	}
+	if err = json.Unmarshal(val, result); err != nil {
+		return fmt.Errorf("gqlclient: decoding '%s': %w", field, err)
+	}
+	return nil
+}
+
+func (e Error) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	path := make([]string, len(e.Path))
+	for i, p := range e.Path {
+		path[i] = fmt.Sprint(p)
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(path, "."), e.Message)
+}
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("gqlclient: unexpected HTTP status %d: %s", e.StatusCode, e.Body)
+}
+
+// MarshalJSON encodes Duration as string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON accepts string like "1h30m0s" or number of seconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*d = 0
+		return nil
+	}
+	if strings.HasPrefix(s, "\"") {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		val, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = Duration(val)
+		return nil
+	}
+	var secs float64
+	if err := json.Unmarshal(data, &secs); err != nil {
+		return err
+	}
+	*d = Duration(secs * float64(time.Second))
+	return nil
+}
+
+// Ptr returns pointer to v; it is helpful for filling input types
+func Ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,82 @@
+package gqlclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_Do(t *testing.T) {
+	type item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch req.Variables["id"] {
+		case 1.:
+			w.Write([]byte(`{"data":{"getItem":{"id":1,"name":"first"}}}`))
+		case 2.:
+			w.Write([]byte(`{"data":{"getItem":null},"errors":[{"message":"not found","path":["getItem"]}]}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("bad gateway"))
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithHTTPClient(srv.Client()), WithHeader("Authorization", "Bearer token"))
+	ctx := context.Background()
+	query := "query getItem($id: Int!) { getItem(id: $id) { id name } }"
+
+	var res *item
+	if err := c.Do(ctx, query, map[string]interface{}{"id": 1}, "getItem", &res); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if res == nil || res.ID != 1 || res.Name != "first" {
+		t.Errorf("Do() result = %+v", res)
+	}
+
+	err := c.Do(ctx, query, map[string]interface{}{"id": 2}, "getItem", &res)
+	var gqlErrs Errors
+	if !errors.As(err, &gqlErrs) || len(gqlErrs) != 1 || gqlErrs[0].Message != "not found" {
+		t.Errorf("Do() error = %v, want GraphQL error", err)
+	}
+	if got := err.Error(); got != "getItem: not found" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	err = c.Do(ctx, query, map[string]interface{}{"id": 3}, "getItem", &res)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Do() error = %v, want HTTPError", err)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	d := Duration(90 * time.Minute)
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != `"1h30m0s"` {
+		t.Errorf("Marshal() = %s", data)
+	}
+	var back Duration
+	if err = json.Unmarshal(data, &back); err != nil || back != d {
+		t.Errorf("Unmarshal() = %v, %v", back, err)
+	}
+	if err = json.Unmarshal([]byte("30"), &back); err != nil || time.Duration(back) != 30*time.Second {
+		t.Errorf("Unmarshal(seconds) = %v, %v", back, err)
+	}
+}
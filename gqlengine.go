@@ -19,10 +19,11 @@ type GQLEngine struct {
 	schema            *graphql.Schema
 	descriptor        *GQLDescriptor
 	log               *zap.Logger
-	statisticsChannel chan queryStatistics
+	statisticsChannel chan interface{}
 	collectStatistics bool
 	statisticsSchema  *graphql.Schema
 	statistics        map[uint32]*statistics
+	deprecatedUsage   map[string]*deprecatedUsageStatistics
 	statisticsMux     sync.RWMutex
 	runningSince      time.Time
 	options           GQLOptions
@@ -45,6 +46,8 @@ type GQLDescriptor struct {
 	inputsGenerators    map[string]GQLInputTypeGenerator
 	queriesGenerators   map[string]GQLQueryGenerator
 	mutationsGenerators map[string]GQLQueryGenerator
+	// deprecations - reasons of deprecation by type name and field name (operations are fields of Query or Mutation)
+	deprecations map[string]map[string]string
 }
 
 const (
@@ -80,12 +83,14 @@ func (gqe *GQLEngine) CollectStatistics(collect bool) *GQLEngine {
 	if collect && !gqe.collectStatistics {
 		gqe.collectStatistics = true
 		gqe.statistics = map[uint32]*statistics{}
-		gqe.statisticsChannel = make(chan queryStatistics, cAdminStatisticsChannelLen)
+		gqe.deprecatedUsage = map[string]*deprecatedUsageStatistics{}
+		gqe.statisticsChannel = make(chan interface{}, cAdminStatisticsChannelLen)
 		go gqe.statisticsProcessor()
 	}
 	if !collect && gqe.collectStatistics {
 		gqe.collectStatistics = false
 		gqe.statistics = nil
+		gqe.deprecatedUsage = nil
 		close(gqe.statisticsChannel)
 	}
 	return gqe
@@ -115,6 +120,7 @@ func createGQLDescriptor() *GQLDescriptor {
 		inputsGenerators:    map[string]GQLInputTypeGenerator{},
 		queriesGenerators:   map[string]GQLQueryGenerator{},
 		mutationsGenerators: map[string]GQLQueryGenerator{},
+		deprecations:        map[string]map[string]string{},
 	}
 }
 
@@ -135,18 +141,61 @@ func (gqe *GQLEngine) generate(_ *Engine) error {
 		mutations[mn] = mg()
 	}
 	rootMutations := graphql.ObjectConfig{Name: "Mutation", Fields: mutations}
+	// implementations of interfaces may be unreachable from root types
+	var types []graphql.Type
+	for _, t := range gqld.types {
+		if o, ok := t.(*graphql.Object); ok && len(o.Interfaces()) > 0 {
+			types = append(types, o)
+		}
+	}
 	schemaConfig := graphql.SchemaConfig{
 		Query:    graphql.NewObject(rootQuery),
 		Mutation: graphql.NewObject(rootMutations),
+		Types:    types,
 	}
 	sch, err := graphql.NewSchema(schemaConfig)
 	if err != nil {
 		return err
 	}
+	gqe.applyDeprecations(&sch)
 	gqe.schema = &sch
 	return nil
 }
 
+// applyDeprecations sets deprecation reasons for fields registered with Deprecate* and wraps their resolvers
+// for counting usage
+func (gqe *GQLEngine) applyDeprecations(sch *graphql.Schema) {
+	for typeName, fields := range gqe.descriptor.deprecations {
+		var defs graphql.FieldDefinitionMap
+		switch t := sch.Type(typeName).(type) {
+		case *graphql.Object:
+			defs = t.Fields()
+		case *graphql.Interface:
+			defs = t.Fields()
+		}
+		for fieldName, reason := range fields {
+			def, ok := defs[fieldName]
+			if !ok {
+				// e.g. operation was not generated for the type
+				continue
+			}
+			if reason == "" {
+				reason = graphql.DefaultDeprecationReason
+			}
+			def.DeprecationReason = reason
+			resolve := def.Resolve
+			if resolve == nil {
+				resolve = graphql.DefaultResolveFn
+			}
+			name := typeName + "." + fieldName
+			def.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+				gqe.deprecatedUsed(name)
+				return resolve(p)
+			}
+		}
+	}
+}
+
 func (gqe *GQLEngine) Prepare(_ *Engine, _ dep.Provider) error {
 	gqe.descriptor = createGQLDescriptor()
 	return nil
@@ -311,6 +360,10 @@ func (gqld *GQLDescriptor) GetType(name string) graphql.Output {
 		gqld.types[name] = t
 		return t
 	default:
+		if s, ok := getGQLScalar(name); ok {
+			gqld.types[name] = s
+			return s
+		}
 		panic(fmt.Sprintf("undefined gql type '%s'", name))
 	}
 }
@@ -333,6 +386,10 @@ func (gqld *GQLDescriptor) GetInputType(name string) graphql.Input {
 		gqld.inputs[name] = t
 		return t
 	default:
+		if s, ok := getGQLScalar(name); ok {
+			gqld.inputs[name] = s
+			return s
+		}
 		panic(fmt.Sprintf("undefined gql input type '%s'", name))
 	}
 }
@@ -365,6 +422,26 @@ func (gqld *GQLDescriptor) AddMutationGenerator(name string, g GQLQueryGenerator
 	gqld.mutationsGenerators[name] = g
 }
 
+// DeprecateField marks field of type (object or interface) as deprecated; empty reason means default one
+func (gqld *GQLDescriptor) DeprecateField(typeName string, fieldName string, reason string) {
+	fields, ok := gqld.deprecations[typeName]
+	if !ok {
+		fields = map[string]string{}
+		gqld.deprecations[typeName] = fields
+	}
+	fields[fieldName] = reason
+}
+
+// DeprecateQuery marks query as deprecated
+func (gqld *GQLDescriptor) DeprecateQuery(name string, reason string) {
+	gqld.DeprecateField("Query", name, reason)
+}
+
+// DeprecateMutation marks mutation as deprecated
+func (gqld *GQLDescriptor) DeprecateMutation(name string, reason string) {
+	gqld.DeprecateField("Mutation", name, reason)
+}
+
 func (gqld *GQLDescriptor) getKVStringStringType() *graphql.Object {
 	return graphql.NewObject(
 		graphql.ObjectConfig{
@@ -0,0 +1,553 @@
+package vivard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrJSONSchemaValidation is wrapped by JSONSchemaErrors returned by JSONSchema.Validate
+	ErrJSONSchemaValidation = errors.New("JSON schema validation failed")
+	// ErrUnknownJSONSchema is returned by JSONSchemaSet for the name it does not have schema for
+	ErrUnknownJSONSchema = errors.New("unknown JSON schema")
+)
+
+// maxJSONSchemaDepth limits nesting of $ref resolution (e.g. for recursive schemas)
+const maxJSONSchemaDepth = 64
+
+// JSONSchema is JSON Schema (draft 2020-12) prepared for validation of JSON documents.
+// It supports the keywords used in schemas generated by JSONSchema plugin of vivgen:
+// $ref (inside the same document), type, enum, const, allOf, anyOf, oneOf, not,
+// properties, required, additionalProperties, minProperties, maxProperties, items, minItems, maxItems, uniqueItems,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf, minLength, maxLength, pattern
+// and formats date-time, date and uuid (other formats are ignored)
+type JSONSchema struct {
+	root     interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+// JSONSchemaError describes one violation of the schema
+type JSONSchemaError struct {
+	// Path is JSON pointer to invalid value (e.g. /lines/0/qty); empty for the document itself
+	Path    string
+	Message string
+}
+
+// JSONSchemaErrors is returned by JSONSchema.Validate for invalid document
+type JSONSchemaErrors []JSONSchemaError
+
+// JSONSchemaSet keeps schemas (e.g. generated ones) by name and parses them on first use
+type JSONSchemaSet struct {
+	raw    map[string]string
+	parsed map[string]*JSONSchema
+	lock   sync.Mutex
+}
+
+// ParseJSONSchema parses schema and compiles its patterns
+func ParseJSONSchema(data []byte) (*JSONSchema, error) {
+	s := &JSONSchema{patterns: map[string]*regexp.Regexp{}}
+	if err := json.Unmarshal(data, &s.root); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	if err := s.compilePatterns(s.root); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks JSON document; returns JSONSchemaErrors if document does not conform to schema
+func (s *JSONSchema) Validate(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var val interface{}
+	if err := dec.Decode(&val); err != nil {
+		return fmt.Errorf("%w: %v", ErrJSONSchemaValidation, err)
+	}
+	var errs JSONSchemaErrors
+	s.validate(s.root, val, "", &errs, 0)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateValue checks JSON representation of the value (e.g. generated Go struct)
+func (s *JSONSchema) ValidateValue(val interface{}) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return s.Validate(data)
+}
+
+func (s *JSONSchema) compilePatterns(node interface{}) error {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for k, v := range n {
+			if p, ok := v.(string); ok && k == "pattern" {
+				re, err := regexp.Compile(p)
+				if err != nil {
+					return fmt.Errorf("invalid JSON schema: pattern %q: %w", p, err)
+				}
+				s.patterns[p] = re
+				continue
+			}
+			if err := s.compilePatterns(v); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, v := range n {
+			if err := s.compilePatterns(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *JSONSchema) validate(schema interface{}, val interface{}, path string, errs *JSONSchemaErrors, depth int) {
+	addError := func(format string, args ...interface{}) {
+		*errs = append(*errs, JSONSchemaError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if depth > maxJSONSchemaDepth {
+		addError("schema is nested too deep")
+		return
+	}
+	switch sch := schema.(type) {
+	case bool:
+		if !sch {
+			addError("value is not allowed")
+		}
+		return
+	case map[string]interface{}:
+		if ref, ok := sch["$ref"].(string); ok {
+			target, err := s.resolve(ref)
+			if err != nil {
+				addError("%v", err)
+				return
+			}
+			s.validate(target, val, path, errs, depth+1)
+		}
+		if t, ok := sch["type"]; ok && !jsonSchemaTypeMatches(t, val) {
+			addError("expected %s, got %s", jsonSchemaTypeString(t), jsonSchemaValueType(val))
+			return
+		}
+		if enum, ok := sch["enum"].([]interface{}); ok {
+			found := false
+			for _, e := range enum {
+				if jsonSchemaEqual(e, val) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				addError("value is not one of %s", jsonSchemaString(enum))
+			}
+		}
+		if c, ok := sch["const"]; ok && !jsonSchemaEqual(c, val) {
+			addError("value should be %s", jsonSchemaString(c))
+		}
+		s.validateCombinations(sch, val, path, errs, depth)
+		switch v := val.(type) {
+		case json.Number:
+			s.validateNumber(sch, v, addError)
+		case string:
+			s.validateString(sch, v, addError)
+		case []interface{}:
+			s.validateArray(sch, v, path, errs, depth)
+		case map[string]interface{}:
+			s.validateObject(sch, v, path, errs, depth)
+		}
+	}
+}
+
+func (s *JSONSchema) validateCombinations(
+	sch map[string]interface{},
+	val interface{},
+	path string,
+	errs *JSONSchemaErrors,
+	depth int,
+) {
+	if all, ok := sch["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			s.validate(sub, val, path, errs, depth+1)
+		}
+	}
+	matches := func(list []interface{}) (count int, firstErrs JSONSchemaErrors) {
+		for _, sub := range list {
+			var subErrs JSONSchemaErrors
+			s.validate(sub, val, path, &subErrs, depth+1)
+			if len(subErrs) == 0 {
+				count++
+			} else if firstErrs == nil {
+				firstErrs = subErrs
+			}
+		}
+		return
+	}
+	if anyOf, ok := sch["anyOf"].([]interface{}); ok {
+		if count, _ := matches(anyOf); count == 0 {
+			*errs = append(*errs, JSONSchemaError{Path: path, Message: "value does not match any of allowed schemas"})
+		}
+	}
+	if one, ok := sch["oneOf"].([]interface{}); ok {
+		count, subErrs := matches(one)
+		switch {
+		case count == 0 && len(one) == 1:
+			*errs = append(*errs, subErrs...)
+		case count == 0:
+			*errs = append(*errs, JSONSchemaError{Path: path, Message: "value does not match any of allowed schemas"})
+		case count > 1:
+			*errs = append(*errs, JSONSchemaError{Path: path, Message: "value matches more than one schema"})
+		}
+	}
+	if not, ok := sch["not"]; ok {
+		var subErrs JSONSchemaErrors
+		s.validate(not, val, path, &subErrs, depth+1)
+		if len(subErrs) == 0 {
+			*errs = append(*errs, JSONSchemaError{Path: path, Message: "value matches the schema it should not"})
+		}
+	}
+}
+
+func (s *JSONSchema) validateNumber(sch map[string]interface{}, v json.Number, addError func(string, ...interface{})) {
+	n, err := v.Float64()
+	if err != nil {
+		addError("invalid number: %s", v)
+		return
+	}
+	if min, ok := sch["minimum"].(float64); ok && n < min {
+		addError("value should be >= %v", min)
+	}
+	if max, ok := sch["maximum"].(float64); ok && n > max {
+		addError("value should be <= %v", max)
+	}
+	if min, ok := sch["exclusiveMinimum"].(float64); ok && n <= min {
+		addError("value should be > %v", min)
+	}
+	if max, ok := sch["exclusiveMaximum"].(float64); ok && n >= max {
+		addError("value should be < %v", max)
+	}
+	if m, ok := sch["multipleOf"].(float64); ok && m > 0 {
+		if q := n / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			addError("value should be multiple of %v", m)
+		}
+	}
+}
+
+func (s *JSONSchema) validateString(sch map[string]interface{}, v string, addError func(string, ...interface{})) {
+	length := float64(utf8.RuneCountInString(v))
+	if min, ok := sch["minLength"].(float64); ok && length < min {
+		addError("length should be >= %v", min)
+	}
+	if max, ok := sch["maxLength"].(float64); ok && length > max {
+		addError("length should be <= %v", max)
+	}
+	if p, ok := sch["pattern"].(string); ok && !s.patterns[p].MatchString(v) {
+		addError("value does not match pattern %q", p)
+	}
+	if format, ok := sch["format"].(string); ok {
+		var err error
+		switch format {
+		case "date-time":
+			_, err = time.Parse(time.RFC3339, v)
+		case "date":
+			_, err = time.Parse("2006-01-02", v)
+		case "uuid":
+			_, err = ParseUUID(v)
+		}
+		if err != nil {
+			addError("value is not valid %s", format)
+		}
+	}
+}
+
+func (s *JSONSchema) validateArray(
+	sch map[string]interface{},
+	v []interface{},
+	path string,
+	errs *JSONSchemaErrors,
+	depth int,
+) {
+	if items, ok := sch["items"]; ok {
+		for i, item := range v {
+			s.validate(items, item, path+"/"+strconv.Itoa(i), errs, depth+1)
+		}
+	}
+	length := float64(len(v))
+	if min, ok := sch["minItems"].(float64); ok && length < min {
+		*errs = append(*errs, JSONSchemaError{Path: path, Message: fmt.Sprintf("number of items should be >= %v", min)})
+	}
+	if max, ok := sch["maxItems"].(float64); ok && length > max {
+		*errs = append(*errs, JSONSchemaError{Path: path, Message: fmt.Sprintf("number of items should be <= %v", max)})
+	}
+	if unique, ok := sch["uniqueItems"].(bool); ok && unique {
+		for i := 1; i < len(v); i++ {
+			for j := 0; j < i; j++ {
+				if jsonSchemaEqual(v[i], v[j]) {
+					*errs = append(*errs, JSONSchemaError{Path: path, Message: fmt.Sprintf("items %d and %d are equal", j, i)})
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *JSONSchema) validateObject(
+	sch map[string]interface{},
+	v map[string]interface{},
+	path string,
+	errs *JSONSchemaErrors,
+	depth int,
+) {
+	if required, ok := sch["required"].([]interface{}); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, ok := v[name]; !ok {
+					*errs = append(*errs, JSONSchemaError{Path: path, Message: fmt.Sprintf("property %q is required", name)})
+				}
+			}
+		}
+	}
+	props, _ := sch["properties"].(map[string]interface{})
+	additional, hasAdditional := sch["additionalProperties"]
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		propPath := path + "/" + strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1")
+		if p, ok := props[k]; ok {
+			s.validate(p, v[k], propPath, errs, depth+1)
+		} else if hasAdditional {
+			s.validate(additional, v[k], propPath, errs, depth+1)
+		}
+	}
+	count := float64(len(v))
+	if min, ok := sch["minProperties"].(float64); ok && count < min {
+		*errs = append(*errs, JSONSchemaError{Path: path, Message: fmt.Sprintf("number of properties should be >= %v", min)})
+	}
+	if max, ok := sch["maxProperties"].(float64); ok && count > max {
+		*errs = append(*errs, JSONSchemaError{Path: path, Message: fmt.Sprintf("number of properties should be <= %v", max)})
+	}
+}
+
+// resolve returns subschema for local reference (e.g. #/$defs/Order)
+func (s *JSONSchema) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("only local references are supported: %s", ref)
+	}
+	node := s.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("reference not found: %s", ref)
+		}
+		if node, ok = m[token]; !ok {
+			return nil, fmt.Errorf("reference not found: %s", ref)
+		}
+	}
+	return node, nil
+}
+
+func jsonSchemaTypeMatches(t interface{}, val interface{}) bool {
+	switch tt := t.(type) {
+	case string:
+		switch tt {
+		case "null":
+			return val == nil
+		case "boolean":
+			_, ok := val.(bool)
+			return ok
+		case "object":
+			_, ok := val.(map[string]interface{})
+			return ok
+		case "array":
+			_, ok := val.([]interface{})
+			return ok
+		case "string":
+			_, ok := val.(string)
+			return ok
+		case "number":
+			_, ok := val.(json.Number)
+			return ok
+		case "integer":
+			n, ok := val.(json.Number)
+			if !ok {
+				return false
+			}
+			if _, err := n.Int64(); err == nil {
+				return true
+			}
+			f, err := n.Float64()
+			return err == nil && f == math.Trunc(f)
+		}
+	case []interface{}:
+		for _, t := range tt {
+			if jsonSchemaTypeMatches(t, val) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func jsonSchemaTypeString(t interface{}) string {
+	if list, ok := t.([]interface{}); ok {
+		names := make([]string, len(list))
+		for i, n := range list {
+			names[i] = fmt.Sprint(n)
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+func jsonSchemaValueType(val interface{}) string {
+	switch val.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	}
+	return fmt.Sprintf("%T", val)
+}
+
+// jsonSchemaEqual compares values from schema (numbers are float64) and from document (numbers are json.Number)
+func jsonSchemaEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(jsonSchemaNormalize(a), jsonSchemaNormalize(b))
+}
+
+func jsonSchemaNormalize(val interface{}) interface{} {
+	switch v := val.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		return f
+	case []interface{}:
+		ret := make([]interface{}, len(v))
+		for i, item := range v {
+			ret[i] = jsonSchemaNormalize(item)
+		}
+		return ret
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			ret[k] = jsonSchemaNormalize(item)
+		}
+		return ret
+	}
+	return val
+}
+
+func jsonSchemaString(val interface{}) string {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprint(val)
+	}
+	return string(data)
+}
+
+func (e JSONSchemaErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%v: %s", ErrJSONSchemaValidation, strings.Join(msgs, "; "))
+}
+
+func (e JSONSchemaErrors) Unwrap() error {
+	return ErrJSONSchemaValidation
+}
+
+func (e JSONSchemaError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return path + ": " + e.Message
+}
+
+// NewJSONSchemaSet creates set for schemas given as JSON documents by name
+func NewJSONSchemaSet(schemas map[string]string) *JSONSchemaSet {
+	return &JSONSchemaSet{raw: schemas, parsed: map[string]*JSONSchema{}}
+}
+
+// Names returns sorted names of schemas
+func (ss *JSONSchemaSet) Names() []string {
+	names := make([]string, 0, len(ss.raw))
+	for name := range ss.raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Raw returns JSON document of schema; nil if there is no schema with the name
+func (ss *JSONSchemaSet) Raw(name string) []byte {
+	if s, ok := ss.raw[name]; ok {
+		return []byte(s)
+	}
+	return nil
+}
+
+// Get returns parsed schema
+func (ss *JSONSchemaSet) Get(name string) (*JSONSchema, error) {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	if s, ok := ss.parsed[name]; ok {
+		return s, nil
+	}
+	raw, ok := ss.raw[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJSONSchema, name)
+	}
+	s, err := ParseJSONSchema([]byte(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	ss.parsed[name] = s
+	return s, nil
+}
+
+// Validate checks JSON document with the schema
+func (ss *JSONSchemaSet) Validate(name string, data []byte) error {
+	s, err := ss.Get(name)
+	if err != nil {
+		return err
+	}
+	return s.Validate(data)
+}
+
+// ValidateValue checks JSON representation of the value with the schema
+func (ss *JSONSchemaSet) ValidateValue(name string, val interface{}) error {
+	s, err := ss.Get(name)
+	if err != nil {
+		return err
+	}
+	return s.ValidateValue(val)
+}
@@ -0,0 +1,112 @@
+package vivard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testOrderSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Order",
+  "type": "object",
+  "properties": {
+    "id": {"type": "integer"},
+    "number": {"type": "string", "minLength": 1, "pattern": "^[A-Z]"},
+    "qty": {"type": ["integer", "null"], "minimum": 1, "maximum": 10},
+    "status": {"anyOf": [{"$ref": "#/$defs/Status"}, {"type": "null"}]},
+    "created": {"type": ["string", "null"], "format": "date-time"},
+    "tags": {"type": ["array", "null"], "items": {"type": "string"}, "maxItems": 2, "uniqueItems": true},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+    "payment": {
+      "oneOf": [
+        {"$ref": "#/$defs/Card", "properties": {"_t": {"const": "Card"}}, "required": ["_t"]},
+        {"$ref": "#/$defs/Cash", "properties": {"_t": {"const": "Cash"}}, "required": ["_t"]}
+      ]
+    },
+    "parent": {"anyOf": [{"$ref": "#"}, {"type": "null"}]}
+  },
+  "required": ["id", "number"],
+  "$defs": {
+    "Status": {"type": "integer", "enum": [1, 2, 3]},
+    "Card": {"type": "object", "properties": {"number": {"type": "string"}}},
+    "Cash": {"type": "object", "properties": {"change": {"type": ["string", "number"]}}}
+  }
+}`
+
+func TestJSONSchema_Validate(t *testing.T) {
+	s, err := ParseJSONSchema([]byte(testOrderSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		doc  string
+		// errs - expected paths of errors
+		errs []string
+	}{
+		{"valid", `{"id": 1, "number": "A-1", "qty": 2, "status": 1, "created": "2024-01-02T10:00:00Z"}`, nil},
+		{"nulls", `{"id": 1, "number": "A-1", "qty": null, "status": null, "tags": null, "parent": null}`, nil},
+		{"required", `{"number": "A-1"}`, []string{""}},
+		{"not an object", `[1]`, []string{""}},
+		{"type", `{"id": "1", "number": "A-1"}`, []string{"/id"}},
+		{"integer", `{"id": 1.5, "number": "A-1"}`, []string{"/id"}},
+		{"string constraints", `{"id": 1, "number": ""}`, []string{"/number", "/number"}},
+		{"range", `{"id": 1, "number": "A", "qty": 11}`, []string{"/qty"}},
+		{"enum", `{"id": 1, "number": "A", "status": 5}`, []string{"/status"}},
+		{"format", `{"id": 1, "number": "A", "created": "yesterday"}`, []string{"/created"}},
+		{"items", `{"id": 1, "number": "A", "tags": ["a", 2]}`, []string{"/tags/1"}},
+		{"max items", `{"id": 1, "number": "A", "tags": ["a", "b", "c"]}`, []string{"/tags"}},
+		{"unique items", `{"id": 1, "number": "A", "tags": ["a", "a"]}`, []string{"/tags"}},
+		{"map values", `{"id": 1, "number": "A", "labels": {"a": "b", "c": 1}}`, []string{"/labels/c"}},
+		{"polymorphic", `{"id": 1, "number": "A", "payment": {"_t": "Cash", "change": "1.5"}}`, nil},
+		{"polymorphic unknown", `{"id": 1, "number": "A", "payment": {"_t": "Coupon"}}`, []string{"/payment"}},
+		{"recursive", `{"id": 1, "number": "A", "parent": {"id": 2, "number": "a"}}`, []string{"/parent"}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := s.Validate([]byte(tt.doc))
+				if len(tt.errs) == 0 {
+					if err != nil {
+						t.Fatalf("Validate() error = %v", err)
+					}
+					return
+				}
+				var errs JSONSchemaErrors
+				if !errors.As(err, &errs) {
+					t.Fatalf("Validate() error = %v, want JSONSchemaErrors", err)
+				}
+				if !errors.Is(err, ErrJSONSchemaValidation) {
+					t.Errorf("Validate() error does not wrap ErrJSONSchemaValidation")
+				}
+				var paths []string
+				for _, e := range errs {
+					paths = append(paths, e.Path)
+				}
+				if strings.Join(paths, ",") != strings.Join(tt.errs, ",") {
+					t.Errorf("Validate() errors = %v, want paths %v", err, tt.errs)
+				}
+			},
+		)
+	}
+}
+
+func TestJSONSchemaSet(t *testing.T) {
+	ss := NewJSONSchemaSet(map[string]string{"Order": testOrderSchema, "Broken": `{"pattern": "("}`})
+	if err := ss.Validate("Order", []byte(`{"id": 1, "number": "A"}`)); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := ss.ValidateValue("Order", map[string]interface{}{"id": 1}); !errors.Is(err, ErrJSONSchemaValidation) {
+		t.Errorf("ValidateValue() error = %v, want validation error", err)
+	}
+	if err := ss.Validate("Item", []byte(`{}`)); !errors.Is(err, ErrUnknownJSONSchema) {
+		t.Errorf("Validate() error = %v, want ErrUnknownJSONSchema", err)
+	}
+	if _, err := ss.Get("Broken"); err == nil {
+		t.Errorf("Get() for invalid pattern should fail")
+	}
+	if names := ss.Names(); strings.Join(names, ",") != "Broken,Order" {
+		t.Errorf("Names() = %v", names)
+	}
+}
@@ -0,0 +1,235 @@
+package vivard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	dep "github.com/vc2402/vivard/dependencies"
+	"github.com/vc2402/vivard/resource"
+	"go.uber.org/zap"
+)
+
+// RESTEngine is the service for REST handlers generated by REST plugin of vivgen;
+// generated code registers routes in Prepare of SubEngine, HTTPHandler serves them
+type RESTEngine struct {
+	routes []*restRoute
+	log    *zap.Logger
+	pretty bool
+}
+
+// RESTError is returned by generated handlers for problems with request (e.g. invalid body)
+type RESTError struct {
+	Status int
+	Err    error
+}
+
+type restRoute struct {
+	method   string
+	pattern  string
+	segments []string
+	name     string
+	handler  http.HandlerFunc
+}
+
+type restParamsKey struct{}
+
+type restErrorResponse struct {
+	Errors []restErrorMessage `json:"errors"`
+}
+
+type restErrorMessage struct {
+	Message string `json:"message"`
+}
+
+func (re *RESTEngine) Prepare(_ *Engine, _ dep.Provider) error {
+	re.routes = nil
+	return nil
+}
+
+func (re *RESTEngine) Start(_ *Engine, _ dep.Provider) error {
+	// routes with literal segments take precedence over ones with params (e.g. /orders/lookup over /orders/{id})
+	sort.SliceStable(
+		re.routes, func(i, j int) bool {
+			return re.routes[i].weight() > re.routes[j].weight()
+		},
+	)
+	return nil
+}
+
+func (re *RESTEngine) Provide() interface{} {
+	return re
+}
+
+func (re *RESTEngine) SetLogger(logger *zap.Logger) *RESTEngine {
+	re.log = logger
+	return re
+}
+
+// Pretty sets indentation for JSON responses
+func (re *RESTEngine) Pretty(pretty bool) *RESTEngine {
+	re.pretty = pretty
+	return re
+}
+
+// AddRoute registers handler for method and pattern; pattern may contain params in form {name}
+// that may be read with RESTParam; name is the name of operation (the same as GraphQL one)
+func (re *RESTEngine) AddRoute(method string, pattern string, name string, handler http.HandlerFunc) {
+	for _, r := range re.routes {
+		if r.method == method && r.pattern == pattern {
+			panic(fmt.Sprintf("duplicate rest route '%s %s'", method, pattern))
+		}
+	}
+	re.routes = append(
+		re.routes,
+		&restRoute{
+			method:   method,
+			pattern:  pattern,
+			segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+			name:     name,
+			handler:  handler,
+		},
+	)
+}
+
+// HTTPHandler returns handler for all the registered routes; prefix (e.g. "/api") is stripped from request path
+func (re *RESTEngine) HTTPHandler(prefix string) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			path := strings.TrimPrefix(r.URL.Path, prefix)
+			segments := strings.Split(strings.Trim(path, "/"), "/")
+			pathFound := false
+			for _, route := range re.routes {
+				params, ok := route.match(segments)
+				if !ok {
+					continue
+				}
+				pathFound = true
+				if route.method != r.Method {
+					continue
+				}
+				if len(params) > 0 {
+					r = r.WithContext(context.WithValue(r.Context(), restParamsKey{}, params))
+				}
+				route.handler(w, r)
+				return
+			}
+			if pathFound {
+				re.WriteError(w, &RESTError{Status: http.StatusMethodNotAllowed, Err: errors.New("method not allowed")})
+			} else {
+				re.WriteError(w, &RESTError{Status: http.StatusNotFound, Err: errors.New("not found")})
+			}
+		},
+	)
+}
+
+// WriteResult writes val as JSON response
+func (re *RESTEngine) WriteResult(w http.ResponseWriter, val interface{}) {
+	re.writeJSON(w, http.StatusOK, val)
+}
+
+// WriteError writes error response with status chosen by RESTStatus
+func (re *RESTEngine) WriteError(w http.ResponseWriter, err error) {
+	status := RESTStatus(err)
+	if status == http.StatusInternalServerError && re.log != nil {
+		re.log.Error("rest request failed", zap.Error(err))
+	}
+	re.writeJSON(w, status, restErrorResponse{Errors: []restErrorMessage{{Message: err.Error()}}})
+}
+
+func (re *RESTEngine) writeJSON(w http.ResponseWriter, status int, val interface{}) {
+	var buff []byte
+	var err error
+	if re.pretty {
+		buff, err = json.MarshalIndent(val, "", "\t")
+	} else {
+		buff, err = json.Marshal(val)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(buff)
+}
+
+// RESTParam returns value of path param (e.g. id for pattern /orders/{id})
+func RESTParam(r *http.Request, name string) string {
+	if params, ok := r.Context().Value(restParamsKey{}).(map[string]string); ok {
+		return params[name]
+	}
+	return ""
+}
+
+// RESTDecodeBody decodes JSON body of request into val
+func RESTDecodeBody(r *http.Request, val interface{}) error {
+	if r.Body == nil {
+		return &RESTError{Status: http.StatusBadRequest, Err: errors.New("empty body")}
+	}
+	if err := json.NewDecoder(r.Body).Decode(val); err != nil {
+		return &RESTError{Status: http.StatusBadRequest, Err: err}
+	}
+	return nil
+}
+
+// RESTBadRequest wraps err to be returned with status 400
+func RESTBadRequest(err error) error {
+	return &RESTError{Status: http.StatusBadRequest, Err: err}
+}
+
+// RESTStatus maps error to HTTP status: RESTError keeps its status, ErrItemNotFound is 404,
+// resource.ErrForbidden is 403, resource.ErrDuplicate is 409; all the others are 500
+func RESTStatus(err error) int {
+	var re *RESTError
+	switch {
+	case errors.As(err, &re):
+		return re.Status
+	case errors.Is(err, ErrItemNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, resource.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, resource.ErrDuplicate):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func (e *RESTError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RESTError) Unwrap() error {
+	return e.Err
+}
+
+func (r *restRoute) match(segments []string) (params map[string]string, ok bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	for i, s := range r.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			if params == nil {
+				params = map[string]string{}
+			}
+			params[s[1:len(s)-1]] = segments[i]
+		} else if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (r *restRoute) weight() int {
+	ret := 0
+	for _, s := range r.segments {
+		if !strings.HasPrefix(s, "{") {
+			ret++
+		}
+	}
+	return ret
+}
@@ -0,0 +1,99 @@
+package vivard
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vc2402/vivard/resource"
+)
+
+func TestRESTEngine_HTTPHandler(t *testing.T) {
+	re := &RESTEngine{}
+	if err := re.Prepare(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	re.AddRoute(
+		http.MethodGet, "/shop/orders/{id}", "getOrder", func(w http.ResponseWriter, r *http.Request) {
+			switch id := RESTParam(r, "id"); id {
+			case "1":
+				re.WriteResult(w, map[string]interface{}{"id": 1})
+			case "2":
+				re.WriteError(w, fmt.Errorf("order 2: %w", ErrItemNotFound))
+			default:
+				re.WriteError(w, resource.ErrForbidden)
+			}
+		},
+	)
+	re.AddRoute(
+		http.MethodGet, "/shop/orders/lookup", "lookupOrder", func(w http.ResponseWriter, r *http.Request) {
+			re.WriteResult(w, []string{r.URL.Query().Get("query")})
+		},
+	)
+	re.AddRoute(
+		http.MethodPost, "/shop/orders", "createOrder", func(w http.ResponseWriter, r *http.Request) {
+			var val map[string]interface{}
+			if err := RESTDecodeBody(r, &val); err != nil {
+				re.WriteError(w, err)
+				return
+			}
+			re.WriteResult(w, val)
+		},
+	)
+	if err := re.Start(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	h := re.HTTPHandler("/api")
+
+	tests := []struct {
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{http.MethodGet, "/api/shop/orders/1", "", http.StatusOK, `{"id":1}`},
+		{http.MethodGet, "/api/shop/orders/2", "", http.StatusNotFound, `{"errors":[{"message":"order 2: item not found"}]}`},
+		{http.MethodGet, "/api/shop/orders/3", "", http.StatusForbidden, `{"errors":[{"message":"forbidden"}]}`},
+		{http.MethodGet, "/api/shop/orders/lookup?query=abc", "", http.StatusOK, `["abc"]`},
+		{http.MethodPost, "/api/shop/orders", `{"number":"A1"}`, http.StatusOK, `{"number":"A1"}`},
+		{http.MethodPost, "/api/shop/orders", `{"number":`, http.StatusBadRequest, ""},
+		{http.MethodDelete, "/api/shop/orders", "", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "/api/shop/customers", "", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.method+" "+tt.path, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+				if w.Code != tt.wantStatus {
+					t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+				}
+				if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+					t.Errorf("body = %s, want %s", w.Body.String(), tt.wantBody)
+				}
+			},
+		)
+	}
+}
+
+func TestRESTStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{RESTBadRequest(errors.New("invalid id")), http.StatusBadRequest},
+		{fmt.Errorf("dict: %w", ErrItemNotFound), http.StatusNotFound},
+		{resource.ErrForbidden, http.StatusForbidden},
+		{resource.ErrDuplicate, http.StatusConflict},
+		{errors.New("db is down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := RESTStatus(tt.err); got != tt.want {
+			t.Errorf("RESTStatus(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,438 @@
+package vivard
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Decimal is exact decimal number (coefficient * 10^exponent); it is used for `decimal` DSL type
+//
+//	and stored in mongo as Decimal128.
+//
+// Decimal is immutable: coefficient is never changed after creation, so values may be copied freely
+type Decimal struct {
+	// coef - nil means zero
+	coef *big.Int
+	exp  int
+}
+
+// TimeOfDay is time of day in seconds since midnight; it is used for `time` DSL type
+type TimeOfDay int
+
+// UUID is used for `uuid` DSL type; it is stored in mongo as Binary with UUID subtype
+type UUID [16]byte
+
+// bsonBinaryUUID is bson binary subtype for UUID
+const bsonBinaryUUID byte = 0x04
+
+// decimalMaxExponent limits exponent of Decimal to the range of Decimal128;
+// otherwise short input like "1e300000000" makes String and arithmetic build huge numbers
+const decimalMaxExponent = 6143
+
+var (
+	// ErrInvalidDecimal returned when string can not be parsed as Decimal
+	ErrInvalidDecimal = errors.New("invalid decimal value")
+	// ErrInvalidTimeOfDay returned when string can not be parsed as TimeOfDay
+	ErrInvalidTimeOfDay = errors.New("invalid time of day value")
+	// ErrInvalidUUID returned when string can not be parsed as UUID
+	ErrInvalidUUID = errors.New("invalid uuid value")
+)
+
+// ParseDecimal parses string like "-123.45" or "1.5e3" into Decimal
+func ParseDecimal(s string) (Decimal, error) {
+	var d Decimal
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return d, ErrInvalidDecimal
+	}
+	if idx := strings.IndexAny(s, "eE"); idx != -1 {
+		e, err := strconv.Atoi(s[idx+1:])
+		if err != nil || e < -decimalMaxExponent || e > decimalMaxExponent+len(s) {
+			return d, ErrInvalidDecimal
+		}
+		d.exp = e
+		s = s[:idx]
+	}
+	if idx := strings.Index(s, "."); idx != -1 {
+		d.exp -= len(s) - idx - 1
+		s = s[:idx] + s[idx+1:]
+	}
+	if err := checkDecimalExponent(d.exp); err != nil {
+		return Decimal{}, err
+	}
+	coef, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return Decimal{}, ErrInvalidDecimal
+	}
+	d.coef = coef
+	return d, nil
+}
+
+// checkDecimalExponent returns error if exp is out of range of Decimal128
+func checkDecimalExponent(exp int) error {
+	if exp < -decimalMaxExponent || exp > decimalMaxExponent {
+		return fmt.Errorf("%w: exponent %d is out of range", ErrInvalidDecimal, exp)
+	}
+	return nil
+}
+
+// MustParseDecimal parses string as Decimal and panics on error
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(fmt.Sprintf("%v: %s", err, s))
+	}
+	return d
+}
+
+// DecimalFromInt creates Decimal for integer value
+func DecimalFromInt(i int64) Decimal {
+	return Decimal{coef: big.NewInt(i)}
+}
+
+// DecimalFromFloat creates Decimal from float value (using shortest representation)
+func DecimalFromFloat(f float64) Decimal {
+	d, _ := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	return d
+}
+
+// IsZero returns true if value is zero
+func (d Decimal) IsZero() bool {
+	return d.coef == nil || d.coef.Sign() == 0
+}
+
+// Sign returns -1, 0 or 1
+func (d Decimal) Sign() int {
+	if d.coef == nil {
+		return 0
+	}
+	return d.coef.Sign()
+}
+
+// Cmp compares d with another and returns -1, 0 or 1
+func (d Decimal) Cmp(another Decimal) int {
+	a, b := d.align(another)
+	return a.Cmp(b)
+}
+
+// Add returns d + another
+func (d Decimal) Add(another Decimal) Decimal {
+	a, b := d.align(another)
+	return Decimal{coef: a.Add(a, b), exp: minInt(d.exp, another.exp)}
+}
+
+// Sub returns d - another
+func (d Decimal) Sub(another Decimal) Decimal {
+	a, b := d.align(another)
+	return Decimal{coef: a.Sub(a, b), exp: minInt(d.exp, another.exp)}
+}
+
+// Mul returns d * another
+func (d Decimal) Mul(another Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.bigInt(), another.bigInt()), exp: d.exp + another.exp}
+}
+
+// Round rounds value to given number of digits after point (half away from zero)
+func (d Decimal) Round(places int) Decimal {
+	if -d.exp <= places {
+		return d
+	}
+	div := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-d.exp-places)), nil)
+	q, r := new(big.Int).QuoRem(d.bigInt(), div, new(big.Int))
+	r.Abs(r).Mul(r, big.NewInt(2))
+	if r.Cmp(div) >= 0 {
+		q.Add(q, big.NewInt(int64(d.Sign())))
+	}
+	return Decimal{coef: q, exp: -places}
+}
+
+// Float64 returns nearest float value
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns value in plain notation (without exponent)
+func (d Decimal) String() string {
+	s := d.bigInt().String()
+	if d.exp >= 0 {
+		if d.IsZero() {
+			return "0"
+		}
+		return s + strings.Repeat("0", d.exp)
+	}
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	if len(s) <= -d.exp {
+		s = strings.Repeat("0", -d.exp-len(s)+1) + s
+	}
+	point := len(s) + d.exp
+	s = s[:point] + "." + s[point:]
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// bigInt returns coefficient; result should not be modified
+func (d Decimal) bigInt() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// align returns copies of coefficients of d and another scaled to the same exponent
+func (d Decimal) align(another Decimal) (*big.Int, *big.Int) {
+	a := new(big.Int).Set(d.bigInt())
+	b := new(big.Int).Set(another.bigInt())
+	if d.exp > another.exp {
+		a.Mul(a, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.exp-another.exp)), nil))
+	} else if another.exp > d.exp {
+		b.Mul(b, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(another.exp-d.exp)), nil))
+	}
+	return a, b
+}
+
+// MarshalJSON encodes Decimal as string to keep precision
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts string or number
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), "\"")
+	if s == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	val, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = val
+	return nil
+}
+
+// MarshalBSONValue stores Decimal as Decimal128
+func (d Decimal) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	dec, ok := primitive.ParseDecimal128FromBigInt(d.bigInt(), d.exp)
+	if !ok {
+		return 0, nil, fmt.Errorf("%w: %s can not be stored as Decimal128", ErrInvalidDecimal, d.String())
+	}
+	return bson.MarshalValue(dec)
+}
+
+// UnmarshalBSONValue reads Decimal from Decimal128 (double, int and string values are accepted too)
+func (d *Decimal) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	rv := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bsontype.Decimal128:
+		coef, exp, err := rv.Decimal128().BigInt()
+		if err != nil {
+			return err
+		}
+		if err = checkDecimalExponent(exp); err != nil {
+			return err
+		}
+		*d = Decimal{coef: coef, exp: exp}
+	case bsontype.Double:
+		*d = DecimalFromFloat(rv.Double())
+	case bsontype.Int32:
+		*d = DecimalFromInt(int64(rv.Int32()))
+	case bsontype.Int64:
+		*d = DecimalFromInt(rv.Int64())
+	case bsontype.String:
+		val, err := ParseDecimal(rv.StringValue())
+		if err != nil {
+			return err
+		}
+		*d = val
+	case bsontype.Null:
+		*d = Decimal{}
+	default:
+		return fmt.Errorf("can not unmarshal %v to Decimal", t)
+	}
+	return nil
+}
+
+// ParseTimeOfDay parses strings like "15:04" or "15:04:05"
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, ErrInvalidTimeOfDay
+	}
+	limits := []int{24, 60, 60}
+	ret := 0
+	for i := 0; i < 3; i++ {
+		val := 0
+		if i < len(parts) {
+			v, err := strconv.Atoi(parts[i])
+			if err != nil || v < 0 || v >= limits[i] {
+				return 0, ErrInvalidTimeOfDay
+			}
+			val = v
+		}
+		ret = ret*60 + val
+	}
+	return TimeOfDay(ret), nil
+}
+
+// TimeOfDayFromTime returns TimeOfDay for given time (in its location)
+func TimeOfDayFromTime(t time.Time) TimeOfDay {
+	return TimeOfDay(t.Hour()*3600 + t.Minute()*60 + t.Second())
+}
+
+// On returns time for given date with t as time of day
+func (t TimeOfDay) On(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 0, 0, int(t), 0, date.Location())
+}
+
+// Hour returns hour part
+func (t TimeOfDay) Hour() int { return int(t) / 3600 }
+
+// Minute returns minute part
+func (t TimeOfDay) Minute() int { return int(t) % 3600 / 60 }
+
+// Second returns second part
+func (t TimeOfDay) Second() int { return int(t) % 60 }
+
+// String returns time in form "15:04:05"
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+}
+
+// MarshalJSON encodes TimeOfDay as string
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON accepts string in form "15:04:05" or number of seconds
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*t = 0
+		return nil
+	}
+	if !strings.HasPrefix(s, "\"") {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return ErrInvalidTimeOfDay
+		}
+		*t = TimeOfDay(v)
+		return nil
+	}
+	val, err := ParseTimeOfDay(strings.Trim(s, "\""))
+	if err != nil {
+		return err
+	}
+	*t = val
+	return nil
+}
+
+// NewUUID generates random (version 4) UUID
+func NewUUID() UUID {
+	var u UUID
+	_, err := rand.Read(u[:])
+	if err != nil {
+		panic(fmt.Sprintf("can not generate uuid: %v", err))
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return u
+}
+
+// ParseUUID parses UUID in canonical form (with or without dashes)
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	s = strings.ReplaceAll(strings.Trim(strings.TrimSpace(s), "{}"), "-", "")
+	if len(s) != 32 {
+		return u, ErrInvalidUUID
+	}
+	_, err := hex.Decode(u[:], []byte(s))
+	if err != nil {
+		return u, ErrInvalidUUID
+	}
+	return u, nil
+}
+
+// IsZero returns true if UUID is empty
+func (u UUID) IsZero() bool {
+	return u == UUID{}
+}
+
+// String returns UUID in canonical form
+func (u UUID) String() string {
+	s := hex.EncodeToString(u[:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s", s[:8], s[8:12], s[12:16], s[16:20], s[20:])
+}
+
+// MarshalJSON encodes UUID as string
+func (u UUID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON decodes UUID from string
+func (u *UUID) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" || s == "\"\"" {
+		*u = UUID{}
+		return nil
+	}
+	val, err := ParseUUID(strings.Trim(s, "\""))
+	if err != nil {
+		return err
+	}
+	*u = val
+	return nil
+}
+
+// MarshalBSONValue stores UUID as Binary with UUID subtype
+func (u UUID) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(primitive.Binary{Subtype: bsonBinaryUUID, Data: u[:]})
+}
+
+// UnmarshalBSONValue reads UUID from Binary (string values are accepted too)
+func (u *UUID) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	rv := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bsontype.Binary:
+		_, bin := rv.Binary()
+		if len(bin) != len(u) {
+			return ErrInvalidUUID
+		}
+		copy(u[:], bin)
+	case bsontype.String:
+		val, err := ParseUUID(rv.StringValue())
+		if err != nil {
+			return err
+		}
+		*u = val
+	case bsontype.Null:
+		*u = UUID{}
+	default:
+		return fmt.Errorf("can not unmarshal %v to UUID", t)
+	}
+	return nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
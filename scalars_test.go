@@ -0,0 +1,143 @@
+package vivard
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"int", "123", "123"},
+		{"fraction", "123.45", "123.45"},
+		{"negative", "-0.05", "-0.05"},
+		{"exponent", "1.5e3", "1500"},
+		{"small", ".001", "0.001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseDecimal(tt.in)
+			if err != nil {
+				t.Fatalf("ParseDecimal() error = %v", err)
+			}
+			if got := d.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+			typ, data, err := d.MarshalBSONValue()
+			if err != nil {
+				t.Fatalf("MarshalBSONValue() error = %v", err)
+			}
+			var back Decimal
+			if err = back.UnmarshalBSONValue(typ, data); err != nil {
+				t.Fatalf("UnmarshalBSONValue() error = %v", err)
+			}
+			if back.Cmp(d) != 0 {
+				t.Errorf("bson round trip = %v, want %v", back, d)
+			}
+		})
+	}
+	sum := MustParseDecimal("0.1").Add(MustParseDecimal("0.2"))
+	if sum.Cmp(MustParseDecimal("0.3")) != 0 {
+		t.Errorf("0.1 + 0.2 = %v", sum)
+	}
+	if got := MustParseDecimal("2.345").Round(2).String(); got != "2.35" {
+		t.Errorf("Round() = %v, want 2.35", got)
+	}
+}
+
+func TestDecimalCopy(t *testing.T) {
+	orig := MustParseDecimal("12.5")
+	cp := orig
+	typ, data, err := MustParseDecimal("7").MarshalBSONValue()
+	if err != nil {
+		t.Fatalf("MarshalBSONValue() error = %v", err)
+	}
+	if err = cp.UnmarshalBSONValue(typ, data); err != nil {
+		t.Fatalf("UnmarshalBSONValue() error = %v", err)
+	}
+	if orig.String() != "12.5" || cp.String() != "7" {
+		t.Errorf("copies are not independent: %v, %v", orig, cp)
+	}
+	if got := (Decimal{}).Add(orig).String(); got != "12.5" {
+		t.Errorf("zero + 12.5 = %v", got)
+	}
+}
+
+func TestDecimalExponent(t *testing.T) {
+	for _, in := range []string{"1e300000000", "1e-300000000", "1e6144", "0.5e-6143", "1e99999999999999999999"} {
+		if _, err := ParseDecimal(in); !errors.Is(err, ErrInvalidDecimal) {
+			t.Errorf("ParseDecimal(%s) error = %v, want %v", in, err, ErrInvalidDecimal)
+		}
+		var d Decimal
+		if err := json.Unmarshal([]byte(`"`+in+`"`), &d); err == nil {
+			t.Errorf("json.Unmarshal(%s) = %v, want error", in, d)
+		}
+		if v := GQLDecimal.ParseLiteral(&ast.FloatValue{Value: in}); v != nil {
+			t.Errorf("GQL ParseLiteral(%s) = %v, want nil", in, v)
+		}
+		if v := GQLDecimal.ParseValue(in); v != nil {
+			t.Errorf("GQL ParseValue(%s) = %v, want nil", in, v)
+		}
+	}
+	for _, in := range []string{"1e6143", "1e-6143", "123.45e-6141"} {
+		if _, err := ParseDecimal(in); err != nil {
+			t.Errorf("ParseDecimal(%s) error = %v", in, err)
+		}
+	}
+	dec, ok := primitive.ParseDecimal128FromBigInt(big.NewInt(1), -6170)
+	if !ok {
+		t.Fatal("can not create Decimal128")
+	}
+	typ, data, err := bson.MarshalValue(dec)
+	if err != nil {
+		t.Fatalf("MarshalValue() error = %v", err)
+	}
+	var d Decimal
+	if err = d.UnmarshalBSONValue(typ, data); !errors.Is(err, ErrInvalidDecimal) {
+		t.Errorf("UnmarshalBSONValue() error = %v, want %v", err, ErrInvalidDecimal)
+	}
+}
+
+func TestTimeOfDay(t *testing.T) {
+	tod, err := ParseTimeOfDay("09:30")
+	if err != nil {
+		t.Fatalf("ParseTimeOfDay() error = %v", err)
+	}
+	if tod.String() != "09:30:00" {
+		t.Errorf("String() = %v, want 09:30:00", tod)
+	}
+	if _, err = ParseTimeOfDay("25:00"); err == nil {
+		t.Errorf("ParseTimeOfDay() expected error for 25:00")
+	}
+}
+
+func TestUUID(t *testing.T) {
+	u := NewUUID()
+	parsed, err := ParseUUID(u.String())
+	if err != nil {
+		t.Fatalf("ParseUUID() error = %v", err)
+	}
+	if parsed != u {
+		t.Errorf("ParseUUID() = %v, want %v", parsed, u)
+	}
+	doc, err := bson.Marshal(struct{ ID UUID }{u})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	var back struct{ ID UUID }
+	if err = bson.Unmarshal(doc, &back); err != nil {
+		t.Fatalf("bson.Unmarshal() error = %v", err)
+	}
+	if back.ID != u {
+		t.Errorf("bson round trip = %v, want %v", back.ID, u)
+	}
+}
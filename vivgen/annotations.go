@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vc2402/vivard/gen"
+)
+
+// annotationInfo is description of annotation for json output
+type annotationInfo struct {
+	Name      string    `json:"name"`
+	Qualified bool      `json:"qualified,omitempty"`
+	Plugin    string    `json:"plugin,omitempty"`
+	Targets   []string  `json:"targets,omitempty"`
+	Tags      []tagInfo `json:"tags,omitempty"`
+	NameTag   string    `json:"nameTag,omitempty"`
+	AnyTags   bool      `json:"anyTags,omitempty"`
+	Doc       string    `json:"doc,omitempty"`
+}
+
+type tagInfo struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Default any      `json:"default,omitempty"`
+	Values  []string `json:"values,omitempty"`
+	Doc     string   `json:"doc,omitempty"`
+}
+
+// printAnnotations prints reference of annotations of all the registered plugins; returns exit code
+func printAnnotations(out io.Writer, format string) int {
+	schemas := gen.AnnotationsReference()
+	switch format {
+	case "json":
+		infos := make([]annotationInfo, len(schemas))
+		for i, s := range schemas {
+			infos[i] = annotationInfo{
+				Name:      s.Name,
+				Qualified: s.Qualified,
+				Plugin:    s.Plugin,
+				Targets:   strings.Split(s.Targets.String(), ", "),
+				NameTag:   s.NameTag,
+				AnyTags:   s.AnyTags,
+				Doc:       s.Doc,
+			}
+			for _, t := range s.Tags {
+				infos[i].Tags = append(
+					infos[i].Tags,
+					tagInfo{Name: t.Name, Type: t.Type.String(), Default: t.Default, Values: t.Values, Doc: t.Doc},
+				)
+			}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(infos); err != nil {
+			fmt.Printf("error while encoding result: %v\n", err)
+			return 2
+		}
+	case "text":
+		tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ANNOTATION\tPLUGIN\tTARGETS\tDESCRIPTION")
+		for _, s := range schemas {
+			name := "$" + s.Name
+			if s.Qualified {
+				name += "[:qualifier]"
+			}
+			plugin := s.Plugin
+			if plugin == "" {
+				plugin = "(standard)"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, plugin, s.Targets, s.Doc)
+			for _, t := range s.Tags {
+				fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", t.Name, t.Type, tagDefault(t), tagDoc(s, t))
+			}
+		}
+		tw.Flush()
+	default:
+		fmt.Printf("unknown format: %s\n", format)
+		return 2
+	}
+	return 0
+}
+
+func tagDefault(t gen.AnnotationTagSchema) string {
+	if t.Default == nil || t.Default == "" {
+		return ""
+	}
+	return fmt.Sprintf("default: %v", t.Default)
+}
+
+func tagDoc(s gen.AnnotationSchema, t gen.AnnotationTagSchema) string {
+	doc := t.Doc
+	if len(t.Values) > 0 {
+		doc += fmt.Sprintf(" (%s)", strings.Join(t.Values, ", "))
+	}
+	if t.Name == s.NameTag {
+		doc += "; may be given without name as the first tag"
+	}
+	return doc
+}
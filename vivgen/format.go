@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vc2402/vivard/gen"
+)
+
+// formatFiles rewrites files in canonical form; with list or diff files are not changed, but the names of files that
+// differ from canonical form (or the diffs) are printed;
+// returns exit code: 1 if there are errors or (in list or diff mode) files that are not formatted
+func formatFiles(files []string, list bool, diff bool) int {
+	ret := 0
+	for _, name := range files {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error reading file: %v\n", err)
+			ret = 1
+			continue
+		}
+		res, err := gen.Format(name, src)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			ret = 1
+			continue
+		}
+		if string(res) == string(src) {
+			continue
+		}
+		if list {
+			fmt.Println(name)
+		}
+		if diff {
+			fmt.Print(unifiedDiff(name+".orig", name, src, res))
+		}
+		if list || diff {
+			ret = 1
+			continue
+		}
+		err = os.WriteFile(name, res, 0644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error writing file: %v\n", err)
+			ret = 1
+		}
+	}
+	return ret
+}
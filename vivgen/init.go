@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/vc2402/vivard/gen/lsp"
+)
+
+// initParams - values used in templates of project skeleton
+type initParams struct {
+	// Package - name of package generated from sample file
+	Package string
+	// Module - import path prefix of generated packages
+	Module string
+	// DSLFile - name of sample file: <Package>.vvf (lsp.DefaultExtension, the extension language server looks for)
+	DSLFile string
+	// Plugins - names of default plugins
+	Plugins []string
+}
+
+// initFile is a file of project skeleton
+type initFile struct {
+	name string
+	tmpl string
+	// goSource - result should be formatted as Go source
+	goSource bool
+}
+
+var initFiles = []initFile{
+	{name: "{{.DSLFile}}", tmpl: initDSLTemplate},
+	{name: ".vivgen.yaml", tmpl: initVivgenConfigTemplate},
+	{name: "config.yaml", tmpl: initAppConfigTemplate},
+	{name: "main.go", tmpl: initMainTemplate, goSource: true},
+	{name: "Makefile", tmpl: initMakefileTemplate},
+}
+
+// initProject creates skeleton of runnable project in dir: sample DSL file <package>.vvf,
+// vivgen config with default plugins, main.go that starts Engine with services and Makefile with 'generate' target;
+// existing files are not overwritten. Returns exit code
+func initProject(dir string) int {
+	params, err := newInitParams(dir)
+	if err != nil {
+		fmt.Println("init: ", err)
+		return 1
+	}
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		fmt.Println("init: ", err)
+		return 1
+	}
+	for _, f := range initFiles {
+		name, err := executeInitTemplate(f.name, params)
+		if err == nil {
+			err = writeInitFile(filepath.Join(dir, string(name)), f, params)
+		}
+		if err != nil {
+			fmt.Printf("init: %s: %v\n", f.name, err)
+			return 1
+		}
+	}
+	fmt.Printf("project '%s' initialized in %s; run 'make generate' and then 'go run .'\n", params.Package, dir)
+	if _, err = os.Stat(filepath.Join(dir, "go.mod")); os.IsNotExist(err) {
+		fmt.Printf("there is no go.mod yet: run 'go mod init %s' and 'go mod tidy' after generation\n", params.Module)
+	}
+	return 0
+}
+
+func newInitParams(dir string) (*initParams, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	params := &initParams{Package: filepath.Base(abs), Module: viper.GetString("pkgPrefix")}
+	if pflag.CommandLine.Changed("package") {
+		params.Package = viper.GetString("package")
+	}
+	params.Package = strings.ToLower(strings.Map(
+		func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+				return r
+			}
+			return -1
+		},
+		params.Package,
+	))
+	if params.Package == "" || params.Package[0] >= '0' && params.Package[0] <= '9' {
+		return nil, fmt.Errorf("can not make package name for '%s'; use --package to set it", abs)
+	}
+	if params.Module == "" {
+		params.Module = params.Package
+	}
+	params.DSLFile = params.Package + lsp.DefaultExtension
+	for _, g := range defaultGenerators() {
+		params.Plugins = append(params.Plugins, g.Name())
+	}
+	return params, nil
+}
+
+func executeInitTemplate(tmpl string, params *initParams) ([]byte, error) {
+	t, err := template.New("init").Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	err = t.Execute(buf, params)
+	return buf.Bytes(), err
+}
+
+// writeInitFile writes file f to name if it does not exist
+func writeInitFile(name string, f initFile, params *initParams) error {
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		if err == nil {
+			fmt.Println("skipped (exists): ", name)
+		}
+		return err
+	}
+	content, err := executeInitTemplate(f.tmpl, params)
+	if err != nil {
+		return err
+	}
+	if f.goSource {
+		if content, err = format.Source(content); err != nil {
+			return err
+		}
+	}
+	if err = os.WriteFile(name, content, 0644); err != nil {
+		return err
+	}
+	fmt.Println("created: ", name)
+	return nil
+}
+
+const initDSLTemplate = `package {{.Package}};
+
+// Customer is a sample type; run 'make generate' after changing the file
+type Customer {
+  CustomerID: int <id auto>;
+  Name: string!;
+  Email: string;
+  CreatedAt: date;
+}
+`
+
+const initVivgenConfigTemplate = `# vivgen config: vivgen looks for .vivgen.yaml in current, input and --cfgPath directories
+out: .
+clientOut: client
+pkgPrefix: {{.Module}}
+plugins:
+{{- range .Plugins}}
+{{- if eq . "Mongo"}}
+  - name: {{.}}
+    options:
+      idGenerator: false
+{{- else}}
+  - {{.}}
+{{- end}}
+{{- end}}
+options:
+  code-generator:
+    AllowEmbeddedArraysForDictionary: true
+`
+
+const initAppConfigTemplate = `# config of application
+http:
+  addr: ":8080"
+mongo:
+  aliases:
+    default:
+      connectstring: mongodb://localhost:27017
+      dbname: {{.Package}}
+`
+
+const initMainTemplate = `package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/spf13/viper"
+	"github.com/vc2402/vivard"
+	"github.com/vc2402/vivard/mongo"
+
+	"{{.Module}}/{{.Package}}"
+)
+
+func main() {
+	viper.SetConfigName("config")
+	viper.AddConfigPath(".")
+	if err := viper.ReadInConfig(); err != nil {
+		log.Fatalf("reading config: %v", err)
+	}
+	viper.SetDefault("http.addr", ":8080")
+
+	ms, err := mongo.New()
+	if err != nil {
+		log.Fatalf("creating mongo service: %v", err)
+	}
+	gql := &vivard.GQLEngine{}
+	eng := vivard.NewEngine()
+	eng.RegisterConfigProvider(vivard.NewViperConfig(), 0)
+	eng.
+		WithService(vivard.ServiceLoggingZap, vivard.NewLoggerService(nil)).
+		WithService(mongo.ServiceMongo, ms).
+		WithService(vivard.ServiceSequenceProvider, vivard.NewSequenceService(mongo.SequenceForService(ms))).
+		WithService(vivard.ServiceCRON, vivard.NewCronService(context.Background())).
+		WithService(vivard.ServiceGQL, gql).
+		WithEngine(&{{.Package}}.Engine{})
+	if err = eng.Start(); err != nil {
+		log.Fatalf("starting engine: %v", err)
+	}
+
+	http.Handle("/graphql", gql.HTTPHandler())
+	addr := viper.GetString("http.addr")
+	log.Printf("listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
+`
+
+const initMakefileTemplate = `VIVGEN ?= vivgen
+
+.PHONY: generate build run
+
+# regenerate code from DSL files
+generate:
+	$(VIVGEN) {{.DSLFile}}
+
+build: generate
+	go build ./...
+
+run: generate
+	go run .
+`
@@ -1,22 +1,54 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/vc2402/vivard/gen/js"
 	"github.com/vc2402/vivard/gen/vue"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/vc2402/vivard/gen"
+	"github.com/vc2402/vivard/gen/external"
 	_ "github.com/vc2402/vivard/gen/js"
+	"github.com/vc2402/vivard/gen/lsp"
+	_ "github.com/vc2402/vivard/gen/react"
 	_ "github.com/vc2402/vivard/gen/vue"
 )
 
 const version = "0.1.11"
 
+const (
+	// commandCheck parses and checks files without generation
+	commandCheck = "check"
+	// commandLSP runs language server over stdin/stdout
+	commandLSP = "lsp"
+	// commandFmt rewrites files in canonical form
+	commandFmt = "fmt"
+	// commandAnnotations prints reference of annotations
+	commandAnnotations = "annotations"
+	// commandInit creates skeleton of runnable project
+	commandInit = "init"
+)
+
 func main() {
+	command := ""
+	if len(os.Args) > 1 && (os.Args[1] == commandCheck || os.Args[1] == commandLSP || os.Args[1] == commandFmt ||
+		os.Args[1] == commandAnnotations || os.Args[1] == commandInit) {
+		command = os.Args[1]
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+	protocolOut := os.Stdout
+	if command == commandLSP {
+		// stdout is used by language server protocol, so everything else goes to stderr
+		os.Stdout = os.Stderr
+	}
 	pflag.String("package", "test", "default package name")
 	pflag.String("in", ".", "Input directory")
 	pflag.String("out", ".", "Output directory")
@@ -25,6 +57,13 @@ func main() {
 	pflag.String("cfgPath", ".", "Path to config file")
 	pflag.String("cfg", ".vivgen", "Config file name")
 	pflag.String("pkgPrefix", "", "Package prefix")
+	pflag.String("diagram", "", "Write entity-relationship diagram in given formats (comma separated: mermaid, plantuml, dot)")
+	pflag.String("diagramPackages", "", "Packages shown on diagram (comma separated; all by default)")
+	pflag.String("format", "text", "Output format of check and annotations commands (text or json)")
+	pflag.BoolP("list", "l", false, "fmt: list files whose formatting differs from canonical instead of rewriting them")
+	pflag.BoolP("diff", "d", false, "Print diffs against existing files instead of writing them (exit code is 1 if they differ)")
+	pflag.String("sourcePositions", "", "Refer to DSL positions in generated Go code: none, comment ('// from file:line') or line ('//line' directives)")
+	pflag.Bool("watch", false, "Watch input files and config and regenerate on change")
 	pflag.Bool("v", false, "verbose")
 	pflag.Bool("version", false, "Show version")
 
@@ -40,8 +79,23 @@ func main() {
 		return
 	}
 
+	if command == commandAnnotations {
+		os.Exit(printAnnotations(os.Stdout, viper.GetString("format")))
+	}
+	if command == commandInit {
+		dir := "."
+		if len(pflag.Args()) > 0 {
+			dir = pflag.Arg(0)
+		}
+		os.Exit(initProject(dir))
+	}
+
 	viper.AutomaticEnv()
-	err := viper.ReadInConfig()
+	var err error
+	if command != commandFmt {
+		// formatting does not depend on config
+		err = viper.ReadInConfig()
+	}
 	if err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			fmt.Printf("warning: config file not found: %v\n", err)
@@ -54,193 +108,407 @@ func main() {
 		fmt.Println("config file used: ", viper.ConfigFileUsed())
 	}
 	args := pflag.Args()
+	if command == commandLSP {
+		err = runLSP(args, protocolOut, verbose)
+		if err != nil {
+			fmt.Println("language server error: ", err)
+			os.Exit(1)
+		}
+		return
+	}
 	if len(args) == 0 {
 		fmt.Println("no files to parse given")
 		return
 	}
-	in := viper.GetString("in")
 	if verbose {
 		wd, _ := os.Getwd()
-		fmt.Println("in is: ", in, "; cwd is: ", wd)
+		fmt.Println("in is: ", viper.GetString("in"), "; cwd is: ", wd)
+	}
+	patterns := inputPatterns(args)
+	files, err := inputFiles(patterns)
+	if err != nil {
+		fmt.Println(err)
+		return
 	}
-	files := make([]string, 0, len(args))
+	if verbose {
+		fmt.Printf("found %d files: %v\n", len(files), files)
+	}
+	switch command {
+	case commandCheck:
+		os.Exit(check(files, verbose))
+	case commandFmt:
+		os.Exit(formatFiles(files, viper.GetBool("list"), viper.GetBool("diff")))
+	}
+	if viper.GetBool("watch") {
+		err = watch(patterns, verbose)
+		if err != nil {
+			fmt.Println("watch error: ", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if viper.GetBool("diff") {
+		os.Exit(diffGenerated(files, verbose))
+	}
+	_, err = generate(files, verbose)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+// inputPatterns returns file masks of args relative to input directory
+func inputPatterns(args []string) []string {
+	in := viper.GetString("in")
 	if in == "" {
 		in = "."
 	}
-	for _, fn := range args {
-		var name string
+	patterns := make([]string, len(args))
+	for i, fn := range args {
 		if !filepath.IsAbs(fn) {
-			name = filepath.Join(in, fn)
+			patterns[i] = filepath.Join(in, fn)
 		} else {
-			name = fn
+			patterns[i] = fn
 		}
-		matches, err := filepath.Glob(name)
+	}
+	return patterns
+}
+
+// inputFiles returns files matching patterns
+func inputFiles(patterns []string) ([]string, error) {
+	files := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
 		if err != nil {
-			fmt.Printf("invalid input file name/mask: %s\n", fn)
-			return
+			return nil, fmt.Errorf("invalid input file name/mask: %s", pattern)
 		}
 		files = append(files, matches...)
 	}
-	if verbose {
-		fmt.Printf("found %d files: %v\n", len(files), files)
+	return files, nil
+}
+
+// generate parses files, generates code and writes (or prints) the result; returns project if it was created
+func generate(files []string, verbose bool) (*gen.Project, error) {
+	res, err := gen.Parse(files)
+	if err != nil {
+		return nil, fmt.Errorf("errors found: %w", err)
 	}
+	proj, err := newProject(res, verbose)
+	if err != nil {
+		return nil, err
+	}
+	// desc.OutputDir = viper.GetString("out")
+	err = proj.Generate()
+	if err != nil {
+		return proj, fmt.Errorf("error found: %w", err)
+	}
+	printWarnings(proj)
+	if viper.GetBool("print") {
+		proj.Print()
+	} else {
+		err = proj.WriteToFiles()
+		if err != nil {
+			return proj, fmt.Errorf("error writing result: %w", err)
+		}
+	}
+	return proj, nil
+}
+
+// diffGenerated generates code in memory and prints diffs against files on disk;
+// returns exit code: 1 if there are errors or generated files differ from existing ones
+func diffGenerated(files []string, verbose bool) int {
 	res, err := gen.Parse(files)
 	if err != nil {
 		fmt.Printf("errors found: %v\n", err)
-	} else {
-		// fmt.Printf("parsed: %#+v: \n", res)
-		opts := gen.Options(viper.GetString("out")).
-			With(gen.UnknownAnnotationWarning).
-			With(gen.NullablePointers)
-
-		if options := viper.Get("options"); options != nil {
-			err = opts.FromAny(options)
-			if err != nil {
-				fmt.Printf("config file error: invalid option: %v\n", err)
-				return
-			}
-			if verbose {
-				fmt.Println("got options:", options)
-			}
+		return 1
+	}
+	proj, err := newProject(res, verbose)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	generated, err := proj.GenerateToMemory()
+	if err != nil {
+		fmt.Println("error found: ", err)
+		return 1
+	}
+	printWarnings(proj)
+	names := make([]string, 0, len(generated))
+	for name := range generated {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ret := 0
+	for _, name := range names {
+		existing, err := os.ReadFile(name)
+		oldName := name
+		if errors.Is(err, os.ErrNotExist) {
+			oldName = os.DevNull
+		} else if err != nil {
+			fmt.Printf("error reading file: %v\n", err)
+			return 1
 		}
-		if viper.GetString("pkgPrefix") != "" {
-			opts.With(gen.PackagePrefixOption(viper.GetString("pkgPrefix")))
+		if diff := unifiedDiff(oldName, name, existing, generated[name]); diff != "" {
+			fmt.Print(diff)
+			ret = 1
 		}
-		if viper.GetString("clientOut") != "" {
-			opts.SetClientOutputDir(viper.GetString("clientOut"))
+	}
+	if verbose {
+		fmt.Printf("%d file(s) generated\n", len(names))
+	}
+	return ret
+}
+
+func printWarnings(proj *gen.Project) {
+	if len(proj.Warnings) > 0 {
+		fmt.Println("\nWarnings found: ")
+		for _, w := range proj.Warnings {
+			fmt.Println("\t", w)
 		}
-		var proj *gen.Project
-		if pls := viper.Get("plugins"); pls != nil {
-			plugins, ok := pls.([]interface{})
-			if !ok {
-				fmt.Println("config file error: invalid 'plugins' value (should be array)")
-				return
+		fmt.Println("")
+	}
+}
+
+// defaultGenerators returns generators used when config does not list plugins
+func defaultGenerators() []gen.Generator {
+	return []gen.Generator{
+		&gen.GQLGenerator{},
+		&gen.LoggerGenerator{},
+		&gen.HistoryGenerator{},
+		&gen.BitSetChangeDetectorGenerator{},
+		&gen.NoCacheGenerator{},
+		&gen.DictionariesGenerator{},
+		&gen.MongoGenerator{},
+		&gen.SequnceIDGenerator{},
+		&js.GQLCLientGenerator{},
+		&js.TSValidatorGenerator{},
+		&vue.ClientGenerator{},
+		&gen.CroneGenerator{},
+		&gen.ResourceGenerator{},
+		&gen.ServiceGenerator{},
+		&gen.Validator{},
+	}
+}
+
+// newProject creates project for parsed files with options and plugins from config and flags
+func newProject(res []*gen.File, verbose bool) (proj *gen.Project, err error) {
+	opts := gen.Options(viper.GetString("out")).
+		With(gen.UnknownAnnotationWarning).
+		With(gen.NullablePointers)
+
+	if options := viper.Get("options"); options != nil {
+		err = opts.FromAny(options)
+		if err != nil {
+			return nil, fmt.Errorf("config file error: invalid option: %w", err)
+		}
+		if verbose {
+			fmt.Println("got options:", options)
+		}
+	}
+	if viper.GetString("pkgPrefix") != "" {
+		opts.With(gen.PackagePrefixOption(viper.GetString("pkgPrefix")))
+	}
+	if viper.GetString("clientOut") != "" {
+		opts.SetClientOutputDir(viper.GetString("clientOut"))
+	}
+	if sp := viper.GetString("sourcePositions"); sp != "" {
+		if err = opts.FromAny(map[string]any{"source-positions": sp}); err != nil {
+			return nil, err
+		}
+	}
+	// external plugins are running processes: they should be stopped if project can not be created
+	var started []*external.Generator
+	defer func() {
+		if err != nil {
+			for _, ext := range started {
+				ext.Close()
 			}
-			proj = gen.New(res, opts)
-			for _, pl := range plugins {
-				if name, ok := pl.(string); ok {
-					if verbose {
-						fmt.Println("adding plugin ", name)
-					}
-					err = proj.WithPlugin(name, nil)
-					if err != nil {
-						fmt.Printf("error while creating plugin: %v\n", err)
-						return
+		}
+	}()
+	if pls := viper.Get("plugins"); pls != nil {
+		plugins, ok := pls.([]interface{})
+		if !ok {
+			return nil, errors.New("config file error: invalid 'plugins' value (should be array)")
+		}
+		proj = gen.New(res, opts)
+		for _, pl := range plugins {
+			if name, ok := pl.(string); ok {
+				if verbose {
+					fmt.Println("adding plugin ", name)
+				}
+				err = proj.WithPlugin(name, nil)
+				if err != nil {
+					return nil, fmt.Errorf("error while creating plugin: %w", err)
+				}
+			} else if plugin, ok := pl.(map[string]any); ok {
+				opts := map[string]any{}
+				addOptions := func(options map[string]any) {
+					for o, v := range options {
+						opts[o] = v
 					}
-				} else if plugin, ok := pl.(map[string]any); ok {
-					opts := map[string]any{}
-					addOptions := func(options map[string]any) {
-						for o, v := range options {
-							opts[o] = v
+				}
+				var name, command string
+				var args []string
+				for k, v := range plugin {
+					switch k {
+					case "name":
+						name, ok = v.(string)
+						if !ok {
+							return nil, fmt.Errorf("config file error: plugin name should be a string: %v", v)
 						}
-					}
-					var name string
-					for k, v := range plugin {
-						switch k {
-						case "name":
-							name, ok = v.(string)
-							if !ok {
-								fmt.Printf("config file error: plugin name should be a string: %v\n", v)
-								return
-							}
-						case "options":
-							if op, ok := v.(map[string]any); ok {
+					case "command":
+						command, ok = v.(string)
+						if !ok {
+							return nil, fmt.Errorf("config file error: plugin command should be a string: %v", v)
+						}
+					case "args":
+						list, ok := v.([]any)
+						if !ok {
+							return nil, fmt.Errorf("config file error: plugin args should be an array: %v", v)
+						}
+						for _, arg := range list {
+							args = append(args, fmt.Sprint(arg))
+						}
+					case "options":
+						if op, ok := v.(map[string]any); ok {
+							addOptions(op)
+						} else if ops, ok := v.([]map[string]any); ok {
+							for _, op := range ops {
 								addOptions(op)
-							} else if ops, ok := v.([]map[string]any); ok {
-								for _, op := range ops {
-									addOptions(op)
-								}
-							} else {
-								fmt.Printf("config file error: plugin options should be amap or an array of maps: %v\n", v)
-								return
 							}
-
-						default:
-							opts[k] = v
+						} else {
+							return nil, fmt.Errorf("config file error: plugin options should be amap or an array of maps: %v", v)
 						}
+
+					default:
+						opts[k] = v
 					}
-					if name == "" {
-						fmt.Printf("config file error: there is no name for plugin: %+v", pl)
-						return
-					}
-					if len(opts) == 0 {
-						opts = nil
-					}
+				}
+				if name == "" {
+					return nil, fmt.Errorf("config file error: there is no name for plugin: %+v", pl)
+				}
+				if len(opts) == 0 {
+					opts = nil
+				}
+				if command != "" {
 					if verbose {
-						fmt.Println("adding plugin ", name, " with options ", opts)
+						fmt.Println("starting external plugin ", name, ": ", command, args)
 					}
-
-					err = proj.WithPlugin(name, opts)
+					ext, err := external.Start(name, command, args, opts)
 					if err != nil {
-						fmt.Printf("error while creating plugin: %v", err)
-						return
+						return nil, fmt.Errorf("error while starting plugin: %w", err)
 					}
-				} else {
-					fmt.Printf("config file error: invalid plugin descriptor: %+v", pl)
-					return
+					started = append(started, ext)
+					proj.With(ext)
+					continue
 				}
+				if verbose {
+					fmt.Println("adding plugin ", name, " with options ", opts)
+				}
+
+				err = proj.WithPlugin(name, opts)
+				if err != nil {
+					return nil, fmt.Errorf("error while creating plugin: %w", err)
+				}
+			} else {
+				return nil, fmt.Errorf("config file error: invalid plugin descriptor: %+v", pl)
 			}
-		} else {
-			if verbose {
-				fmt.Println("no plugins found; using default")
-			}
-			proj = gen.New(
-				res,
-				opts.
-					WithCustom("mongo", map[string]interface{}{"idGenerator": false}).
-					WithCustom("gql-ts", map[string]interface{}{"path": viper.GetString("clientOut")}).
-					WithCustom(
-						"vue", &vue.ClientOptions{
-							Components: map[string]vue.VCOptionComponentSpec{
-								vue.VCOptionDateComponent: {Name: "InputDateComponent", Import: "@/components/DateComponent.vue"},
-								vue.VCOptionMapComponent:  {Name: "KeyValueComponent", Import: "@/components/KVComponent.vue"},
-								vue.VCOptionColorComponent: {
-									Name:   "ColorPickerComponent",
-									Import: "@/components/ColorPickerComponent.vue",
-								},
+		}
+	} else {
+		if verbose {
+			fmt.Println("no plugins found; using default")
+		}
+		proj = gen.New(
+			res,
+			opts.
+				WithCustom("mongo", map[string]interface{}{"idGenerator": false}).
+				WithCustom("gql-ts", map[string]interface{}{"path": viper.GetString("clientOut")}).
+				WithCustom(
+					"vue", &vue.ClientOptions{
+						Components: map[string]vue.VCOptionComponentSpec{
+							vue.VCOptionDateComponent: {Name: "InputDateComponent", Import: "@/components/DateComponent.vue"},
+							vue.VCOptionMapComponent:  {Name: "KeyValueComponent", Import: "@/components/KVComponent.vue"},
+							vue.VCOptionColorComponent: {
+								Name:   "ColorPickerComponent",
+								Import: "@/components/ColorPickerComponent.vue",
 							},
-							//ApolloClientVar: "this.$apolloProvider.clients['statistics']",
 						},
-					).
-					WithCustom(gen.CodeGeneratorOptionsName, map[string]interface{}{"AllowEmbeddedArraysForDictionary": true}),
-			)
-			proj.With(&gen.GQLGenerator{}).
-				With(&gen.LoggerGenerator{}).
-				With(&gen.HistoryGenerator{}).
-				With(&gen.BitSetChangeDetectorGenerator{}).
-				With(&gen.NoCacheGenerator{}).
-				With(&gen.DictionariesGenerator{}).
-				With(&gen.MongoGenerator{}).
-				With(&gen.SequnceIDGenerator{}).
-				With(&js.GQLCLientGenerator{}).
-				With(&js.TSValidatorGenerator{}).
-				With(&vue.ClientGenerator{}).
-				With(&gen.CroneGenerator{}).
-				With(&gen.ResourceGenerator{}).
-				With(&gen.ServiceGenerator{}).
-				With(&gen.Validator{})
-		}
-
-		// desc.OutputDir = viper.GetString("out")
-		err = proj.Generate()
+						//ApolloClientVar: "this.$apolloProvider.clients['statistics']",
+					},
+				).
+				WithCustom(gen.CodeGeneratorOptionsName, map[string]interface{}{"AllowEmbeddedArraysForDictionary": true}),
+		)
+		for _, g := range defaultGenerators() {
+			proj.With(g)
+		}
+	}
+
+	if formats := viper.GetString("diagram"); formats != "" {
+		diagramOpts := map[string]any{"formats": strings.Split(formats, ",")}
+		if pckgs := viper.GetString("diagramPackages"); pckgs != "" {
+			diagramOpts["packages"] = strings.Split(pckgs, ",")
+		}
+		err = proj.WithPlugin(gen.DiagramGeneratorName, diagramOpts)
 		if err != nil {
-			fmt.Println("error found: ", err)
-			return
+			return nil, fmt.Errorf("error while creating plugin: %w", err)
 		}
-		if len(proj.Warnings) > 0 {
-			fmt.Println("\nWarnings found: ")
-			for _, w := range proj.Warnings {
-				fmt.Println("\t", w)
-			}
-			fmt.Println("")
+	}
+	return
+}
+
+// check parses and checks files without writing anything and prints all the errors and warnings found;
+// returns exit code: 1 if there are errors
+func check(files []string, verbose bool) int {
+	diagnostics := gen.CheckFiles(
+		files,
+		func(res []*gen.File) (*gen.Project, error) {
+			return newProject(res, verbose)
+		},
+	)
+	errorsFound := 0
+	for _, d := range diagnostics {
+		if d.Severity == gen.SeverityError {
+			errorsFound++
 		}
-		if viper.GetBool("print") {
-			proj.Print()
-		} else {
-			err = proj.WriteToFiles()
-			if err != nil {
-				fmt.Println("error writing result: ", err)
-			}
+	}
+	switch format := viper.GetString("format"); format {
+	case "json":
+		if diagnostics == nil {
+			diagnostics = []gen.Diagnostic{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diagnostics); err != nil {
+			fmt.Fprintf(os.Stderr, "error while encoding result: %v\n", err)
+			return 2
 		}
+	case "text":
+		for _, d := range diagnostics {
+			fmt.Println(d)
+		}
+		if verbose || len(diagnostics) > 0 {
+			fmt.Printf("%d error(s), %d warning(s)\n", errorsFound, len(diagnostics)-errorsFound)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format: %s\n", format)
+		return 2
+	}
+	if errorsFound > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runLSP runs language server for files matching patterns (all the DSL files of workspace if not given)
+func runLSP(patterns []string, out io.Writer, verbose bool) error {
+	options := lsp.Options{
+		Patterns: patterns,
+		Version:  version,
+		InitProject: func(res []*gen.File) (*gen.Project, error) {
+			return newProject(res, false)
+		},
+	}
+	if verbose {
+		options.Log = os.Stderr
 	}
+	return lsp.NewServer(options).Run(os.Stdin, out)
 }
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// watchDebounce is delay after the last change before regeneration (editors often save file in several steps)
+const watchDebounce = 300 * time.Millisecond
+
+// watcher regenerates code when input files (including imported ones) or config file are changed
+type watcher struct {
+	patterns []string
+	verbose  bool
+	fsw      *fsnotify.Watcher
+	// files - absolute paths of files that trigger regeneration
+	files map[string]bool
+	dirs  map[string]bool
+}
+
+// watch generates code and then regenerates it on every change until watcher fails;
+// errors of generation are printed and do not stop watching
+func watch(patterns []string, verbose bool) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fsw.Close()
+	w := &watcher{patterns: patterns, verbose: verbose, fsw: fsw, files: map[string]bool{}, dirs: map[string]bool{}}
+	w.regenerate(false)
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	for {
+		select {
+		case ev, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 && w.triggers(ev.Name) {
+				if verbose {
+					fmt.Println("changed: ", ev.Name)
+				}
+				timer.Reset(watchDebounce)
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Println("watch error: ", err)
+		case <-timer.C:
+			w.regenerate(true)
+		}
+	}
+}
+
+// regenerate rereads config (if reread is true) and generates code; updates list of watched files
+func (w *watcher) regenerate(reread bool) {
+	start := time.Now()
+	if reread && viper.ConfigFileUsed() != "" {
+		if err := viper.ReadInConfig(); err != nil {
+			fmt.Printf("error reading config file: %v\n", err)
+			return
+		}
+	}
+	files, err := inputFiles(w.patterns)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	w.add(viper.ConfigFileUsed())
+	for _, pattern := range w.patterns {
+		// new files matching pattern should trigger regeneration too
+		w.watchDir(filepath.Dir(pattern))
+	}
+	for _, f := range files {
+		w.add(f)
+	}
+	proj, err := generate(files, w.verbose)
+	if proj != nil {
+		for _, f := range proj.Files {
+			w.add(f.Path)
+		}
+	}
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("waiting for changes...")
+		return
+	}
+	written, unchanged := proj.WriteStats()
+	fmt.Printf(
+		"%s generated in %v: %d file(s) written, %d unchanged; waiting for changes...\n",
+		time.Now().Format("15:04:05"),
+		time.Since(start).Round(time.Millisecond),
+		written,
+		unchanged,
+	)
+}
+
+// add adds file to the list of watched ones
+func (w *watcher) add(file string) {
+	if file == "" {
+		return
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	w.files[file] = true
+	// directory is watched instead of file as editors often replace file on save
+	w.watchDir(filepath.Dir(file))
+}
+
+func (w *watcher) watchDir(dir string) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	if w.dirs[dir] {
+		return
+	}
+	if err := w.fsw.Add(dir); err != nil {
+		fmt.Printf("can not watch directory %s: %v\n", dir, err)
+		return
+	}
+	w.dirs[dir] = true
+}
+
+// triggers returns true if change of the file should trigger regeneration
+func (w *watcher) triggers(file string) bool {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		abs = file
+	}
+	if w.files[abs] {
+		return true
+	}
+	for _, pattern := range w.patterns {
+		if ok, _ := filepath.Match(pattern, file); ok {
+			return true
+		}
+		if absPattern, err := filepath.Abs(pattern); err == nil {
+			if ok, _ := filepath.Match(absPattern, abs); ok {
+				return true
+			}
+		}
+	}
+	return false
+}